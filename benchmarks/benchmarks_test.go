@@ -0,0 +1,86 @@
+package benchmarks_test
+
+import (
+	"testing"
+
+	"github.com/karupanerura/gqlparser"
+	"github.com/karupanerura/gqlparser/benchmarks"
+)
+
+func TestCorpus(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range benchmarks.Queries {
+		if _, err := gqlparser.ParseQuery(gqlparser.NewLexer(c.Source)); err != nil {
+			t.Errorf("%s: ParseQuery() error = %v", c.Name, err)
+		}
+	}
+	for _, c := range benchmarks.AggregationQueries {
+		if _, err := gqlparser.ParseAggregationQuery(gqlparser.NewLexer(c.Source)); err != nil {
+			t.Errorf("%s: ParseAggregationQuery() error = %v", c.Name, err)
+		}
+	}
+}
+
+func BenchmarkLexer(b *testing.B) {
+	for _, c := range benchmarks.Queries {
+		c := c
+		b.Run(c.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(c.Source)))
+			for i := 0; i < b.N; i++ {
+				if _, err := gqlparser.ReadAllTokens(gqlparser.NewLexer(c.Source)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParseQuery(b *testing.B) {
+	for _, c := range benchmarks.Queries {
+		c := c
+		b.Run(c.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(c.Source)))
+			for i := 0; i < b.N; i++ {
+				if _, err := gqlparser.ParseQuery(gqlparser.NewLexer(c.Source)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParseQuery_Pool(b *testing.B) {
+	var pool gqlparser.Pool
+	for _, c := range benchmarks.Queries {
+		c := c
+		b.Run(c.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(c.Source)))
+			for i := 0; i < b.N; i++ {
+				q, err := gqlparser.ParseQuery(gqlparser.NewLexer(c.Source), gqlparser.WithPool(&pool))
+				if err != nil {
+					b.Fatal(err)
+				}
+				pool.Release(q)
+			}
+		})
+	}
+}
+
+func BenchmarkParseAggregationQuery(b *testing.B) {
+	for _, c := range benchmarks.AggregationQueries {
+		c := c
+		b.Run(c.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(c.Source)))
+			for i := 0; i < b.N; i++ {
+				if _, err := gqlparser.ParseAggregationQuery(gqlparser.NewLexer(c.Source)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
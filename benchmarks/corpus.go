@@ -0,0 +1,54 @@
+// Package benchmarks provides the representative query corpora to track the performance of the lexer and the parser.
+// Run the benchmarks by `go test -run '^$' -bench . -benchmem ./benchmarks/`.
+package benchmarks
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Case is the query in the corpus.
+type Case struct {
+	Name   string
+	Source string
+}
+
+// Queries is the corpus of the queries from the simple ones to the pathological ones.
+var Queries = []Case{
+	{"Simple", "SELECT * FROM Kind"},
+	{"Projection", "SELECT DISTINCT ON (a) a, b.c, `d e` FROM Kind WHERE a = 1 ORDER BY a DESC, b LIMIT 10 OFFSET 5"},
+	{"Typical", "SELECT * FROM Kind WHERE __key__ HAS ANCESTOR KEY(Parent, 'foo') AND a >= @min AND a < @max AND b = TRUE ORDER BY a LIMIT 100"},
+	{"Literals", "SELECT * FROM Kind WHERE a = DATETIME('2006-01-02T15:04:05.999999Z') AND b = BLOB('aGVsbG8') AND c IN ARRAY(1, 2.5, 'x', NULL) AND d = KEY(PROJECT('p'), NAMESPACE('n'), A, 1, B, 'b')"},
+	{"ComplexOr", complexOr(32)},
+	{"DeepNesting", deepNesting(32)},
+	{"HugeIn", hugeIn(1000)},
+}
+
+// AggregationQueries is the corpus of the aggregation queries.
+var AggregationQueries = []Case{
+	{"Count", "SELECT COUNT(*) FROM Kind WHERE a = 1"},
+	{"Aggregate", "AGGREGATE COUNT(*) AS c, SUM(a) AS s, AVG(b) AS v OVER (SELECT * FROM Kind WHERE a > 1 ORDER BY a LIMIT 100)"},
+}
+
+// complexOr builds the disjunction of n conjunctions. e.g. (a = 0 AND b = '0') OR (a = 1 AND b = '1')
+func complexOr(n int) string {
+	branches := make([]string, n)
+	for i := range branches {
+		branches[i] = "(a = " + strconv.Itoa(i) + " AND b = '" + strconv.Itoa(i) + "')"
+	}
+	return "SELECT * FROM Kind WHERE " + strings.Join(branches, " OR ")
+}
+
+// deepNesting builds the condition nested in n parentheses. e.g. ((a = 1))
+func deepNesting(n int) string {
+	return "SELECT * FROM Kind WHERE " + strings.Repeat("(", n) + "a = 1" + strings.Repeat(")", n)
+}
+
+// hugeIn builds the IN condition with n values. e.g. a IN ARRAY(0, 1, 2)
+func hugeIn(n int) string {
+	values := make([]string, n)
+	for i := range values {
+		values[i] = strconv.Itoa(i)
+	}
+	return "SELECT * FROM Kind WHERE a IN ARRAY(" + strings.Join(values, ", ") + ")"
+}
@@ -0,0 +1,323 @@
+// Package bigquery renders the GQL AST into the standard SQL of BigQuery.
+// The rendered SQL is expected to run against the tables of the Cloud Datastore exports.
+package bigquery
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/karupanerura/gqlparser"
+)
+
+var ErrUnsupported = errors.New("unsupported")
+
+const keyProperty = "__key__"
+
+// Emitter renders the GQL AST into the standard SQL.
+type Emitter struct {
+	// TableName returns the table expression for the kind. It defaults to the quoted kind name.
+	TableName func(gqlparser.Kind) string
+}
+
+func (e *Emitter) tableName(kind gqlparser.Kind) string {
+	if e.TableName == nil {
+		return QuoteIdentifier(string(kind))
+	}
+	return e.TableName(kind)
+}
+
+// EmitQuery renders the query into the SELECT statement.
+// The query is expected to be bound already.
+func (e *Emitter) EmitQuery(q *gqlparser.Query) (string, error) {
+	if len(q.DistinctOn) != 0 {
+		return "", fmt.Errorf("%w: DISTINCT ON", ErrUnsupported)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("SELECT ")
+	if q.Distinct {
+		sb.WriteString("DISTINCT ")
+	}
+	if len(q.Properties) == 0 {
+		sb.WriteByte('*')
+	} else {
+		for i, item := range q.Projection() {
+			if i != 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(quotePropertyPath(string(item.Property)))
+			if item.Alias != "" {
+				sb.WriteString(" AS ")
+				sb.WriteString(QuoteIdentifier(item.Alias))
+			}
+		}
+	}
+	sb.WriteString(" FROM ")
+	sb.WriteString(e.tableName(q.Kind))
+	if q.Where != nil {
+		where, err := e.EmitCondition(q.Where)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(" WHERE ")
+		sb.WriteString(where)
+	}
+	for i, o := range q.OrderBy {
+		if i == 0 {
+			sb.WriteString(" ORDER BY ")
+		} else {
+			sb.WriteString(", ")
+		}
+		if o.Property == keyProperty {
+			// it's an approximation because the paths are compared as strings
+			sb.WriteString(quotePropertyPath(keyProperty + ".path"))
+		} else {
+			sb.WriteString(quotePropertyPath(string(o.Property)))
+		}
+		if o.Descending {
+			sb.WriteString(" DESC")
+		}
+	}
+	if q.Limit != nil {
+		if q.Limit.Cursor != nil {
+			return "", fmt.Errorf("%w: LIMIT with cursor", ErrUnsupported)
+		}
+		sb.WriteString(" LIMIT ")
+		sb.WriteString(strconv.FormatInt(q.Limit.Position, 10))
+	}
+	if q.Offset != nil {
+		if q.Offset.Cursor != nil {
+			return "", fmt.Errorf("%w: OFFSET with cursor", ErrUnsupported)
+		}
+		if q.Limit == nil {
+			// OFFSET requires LIMIT in BigQuery
+			sb.WriteString(" LIMIT ")
+			sb.WriteString(strconv.FormatInt(1<<63-1, 10))
+		}
+		sb.WriteString(" OFFSET ")
+		sb.WriteString(strconv.FormatInt(q.Offset.Position, 10))
+	}
+	return sb.String(), nil
+}
+
+// EmitCondition renders the condition into the boolean expression.
+func (e *Emitter) EmitCondition(cond gqlparser.Condition) (string, error) {
+	// the membership of the arrays must be emitted before normalizing it into the equality
+	switch c := cond.(type) {
+	case *gqlparser.AndCompoundCondition:
+		return e.emitCompoundCondition("AND", c.Left, c.Right)
+	case *gqlparser.OrCompoundCondition:
+		return e.emitCompoundCondition("OR", c.Left, c.Right)
+	case *gqlparser.ForwardComparatorCondition:
+		if c.Comparator == gqlparser.ContainsForwardComparator {
+			return emitContains(c.Property, c.Value)
+		}
+	case *gqlparser.BackwardComparatorCondition:
+		if c.Comparator == gqlparser.InBackwardComparator {
+			return emitContains(c.Property, c.Value)
+		}
+	case *gqlparser.QuantifiedComparatorCondition:
+		expanded, err := c.Expand()
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrUnsupported, err)
+		}
+		return e.EmitCondition(expanded)
+	}
+
+	switch c := cond.Normalize().(type) {
+	case *gqlparser.EitherComparatorCondition:
+		return e.emitEitherComparatorCondition(c)
+	case *gqlparser.ForwardComparatorCondition:
+		return e.emitForwardComparatorCondition(c)
+	case *gqlparser.BackwardComparatorCondition:
+		return "", fmt.Errorf("%w: comparator %s", ErrUnsupported, c.Comparator)
+	default:
+		return "", fmt.Errorf("%w: condition %T", ErrUnsupported, cond)
+	}
+}
+
+func (e *Emitter) emitCompoundCondition(op string, left, right gqlparser.Condition) (string, error) {
+	l, err := e.EmitCondition(left)
+	if err != nil {
+		return "", err
+	}
+	r, err := e.EmitCondition(right)
+	if err != nil {
+		return "", err
+	}
+	return "(" + l + " " + op + " " + r + ")", nil
+}
+
+func (e *Emitter) emitEitherComparatorCondition(c *gqlparser.EitherComparatorCondition) (string, error) {
+	if c.Value == nil {
+		switch c.Comparator {
+		case gqlparser.EqualsEitherComparator:
+			return quotePropertyPath(c.Property) + " IS NULL", nil
+		case gqlparser.NotEqualsEitherComparator:
+			return quotePropertyPath(c.Property) + " IS NOT NULL", nil
+		default:
+			return "", fmt.Errorf("%w: %s NULL", ErrUnsupported, c.Comparator)
+		}
+	}
+
+	if key, ok := c.Value.(*gqlparser.Key); ok {
+		switch c.Comparator {
+		case gqlparser.EqualsEitherComparator:
+			return keyPredicate(c.Property, key, false), nil
+		case gqlparser.NotEqualsEitherComparator:
+			return "NOT " + keyPredicate(c.Property, key, false), nil
+		default:
+			return "", fmt.Errorf("%w: key comparison by %s", ErrUnsupported, c.Comparator)
+		}
+	}
+
+	v, err := literal(c.Value)
+	if err != nil {
+		return "", err
+	}
+	return quotePropertyPath(c.Property) + " " + string(c.Comparator) + " " + v, nil
+}
+
+func (e *Emitter) emitForwardComparatorCondition(c *gqlparser.ForwardComparatorCondition) (string, error) {
+	switch c.Comparator {
+	case gqlparser.HasAncestorForwardComparator:
+		key, ok := c.Value.(*gqlparser.Key)
+		if !ok {
+			return "", fmt.Errorf("%w: HAS ANCESTOR with %T", ErrUnsupported, c.Value)
+		}
+		return keyPredicate(c.Property, key, true), nil
+	case gqlparser.InForwardComparator, gqlparser.NotInForwardComparator:
+		values, ok := c.Value.([]any)
+		if !ok {
+			return "", fmt.Errorf("%w: %s with %T", ErrUnsupported, c.Comparator, c.Value)
+		}
+		items := make([]string, len(values))
+		for i, v := range values {
+			if _, isKey := v.(*gqlparser.Key); isKey {
+				return "", fmt.Errorf("%w: %s with keys", ErrUnsupported, c.Comparator)
+			}
+			s, err := literal(v)
+			if err != nil {
+				return "", err
+			}
+			items[i] = s
+		}
+		return quotePropertyPath(c.Property) + " " + string(c.Comparator) + " (" + strings.Join(items, ", ") + ")", nil
+	default:
+		return "", fmt.Errorf("%w: comparator %s", ErrUnsupported, c.Comparator)
+	}
+}
+
+// emitContains renders the membership of the value in the array property.
+func emitContains(property string, value any) (string, error) {
+	v, err := literal(value)
+	if err != nil {
+		return "", err
+	}
+	return v + " IN UNNEST(" + quotePropertyPath(property) + ")", nil
+}
+
+// keyPredicate renders the predicate for the key record of the exported entities.
+// The path field of the key record is formatted like `"Parent", 1, "Kind", "name"`.
+func keyPredicate(property string, key *gqlparser.Key, ancestor bool) string {
+	var path strings.Builder
+	for i, p := range key.Path {
+		if i != 0 {
+			path.WriteString(", ")
+		}
+		path.WriteString(strconv.Quote(string(p.Kind)))
+		path.WriteString(", ")
+		if p.Name != "" {
+			path.WriteString(strconv.Quote(p.Name))
+		} else {
+			path.WriteString(strconv.FormatInt(p.ID, 10))
+		}
+	}
+
+	record := quotePropertyPath(property)
+	var predicates []string
+	if key.Namespace != "" {
+		predicates = append(predicates, record+".namespace = "+QuoteString(key.Namespace))
+	}
+	if ancestor {
+		predicates = append(predicates, "("+record+".path = "+QuoteString(path.String())+" OR STARTS_WITH("+record+".path, "+QuoteString(path.String()+", ")+"))")
+	} else {
+		predicates = append(predicates, record+".path = "+QuoteString(path.String()))
+	}
+	if len(predicates) == 1 {
+		return predicates[0]
+	}
+	return "(" + strings.Join(predicates, " AND ") + ")"
+}
+
+func literal(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "NULL", nil
+	case bool:
+		if v {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		// the non-finite doubles have no literals, so they're cast from the strings
+		switch {
+		case math.IsNaN(v):
+			return "CAST('NaN' AS FLOAT64)", nil
+		case math.IsInf(v, 1):
+			return "CAST('inf' AS FLOAT64)", nil
+		case math.IsInf(v, -1):
+			return "CAST('-inf' AS FLOAT64)", nil
+		}
+		s := strconv.FormatFloat(v, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".eE") {
+			s += ".0"
+		}
+		return s, nil
+	case string:
+		return QuoteString(v), nil
+	case []byte:
+		return "FROM_BASE64(" + QuoteString(base64.StdEncoding.EncodeToString(v)) + ")", nil
+	case time.Time:
+		return "TIMESTAMP " + QuoteString(v.Format(time.RFC3339Nano)), nil
+	case gqlparser.BindingVariable:
+		return "", fmt.Errorf("%w: unbound variable %v", ErrUnsupported, v)
+	default:
+		return "", fmt.Errorf("%w: value %T", ErrUnsupported, value)
+	}
+}
+
+// QuoteIdentifier quotes the identifier by backquotes.
+func QuoteIdentifier(s string) string {
+	return "`" + identifierEscaper.Replace(s) + "`"
+}
+
+// QuoteString quotes the string literal by single quotes.
+func QuoteString(s string) string {
+	return "'" + stringEscaper.Replace(s) + "'"
+}
+
+var identifierEscaper = strings.NewReplacer("\\", "\\\\", "`", "\\`")
+
+var stringEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"'", "\\'",
+	"\n", "\\n",
+	"\r", "\\r",
+	"\t", "\\t",
+)
+
+func quotePropertyPath(property string) string {
+	segments := strings.Split(property, ".")
+	for i, s := range segments {
+		segments[i] = QuoteIdentifier(s)
+	}
+	return strings.Join(segments, ".")
+}
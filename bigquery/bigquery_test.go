@@ -0,0 +1,148 @@
+package bigquery_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/karupanerura/gqlparser"
+	"github.com/karupanerura/gqlparser/bigquery"
+)
+
+func TestEmitQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		source  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "Simple",
+			source: "SELECT * FROM Kind",
+			want:   "SELECT * FROM `Kind`",
+		},
+		{
+			name:   "Projection",
+			source: "SELECT DISTINCT a, `b.c` FROM `My Kind` ORDER BY a DESC, __key__ LIMIT 10 OFFSET 5",
+			want:   "SELECT DISTINCT `a`, `b`.`c` FROM `My Kind` ORDER BY `a` DESC, `__key__`.`path` LIMIT 10 OFFSET 5",
+		},
+		{
+			name:   "Alias",
+			source: "SELECT a AS x, b FROM Kind",
+			want:   "SELECT `a` AS `x`, `b` FROM `Kind`",
+		},
+		{
+			name:   "OffsetOnly",
+			source: "SELECT * FROM Kind OFFSET 5",
+			want:   "SELECT * FROM `Kind` LIMIT 9223372036854775807 OFFSET 5",
+		},
+		{
+			name:   "Where",
+			source: "SELECT * FROM Kind WHERE a = 'it\\'s' AND b > 1.0 AND c IS NULL OR d IN ARRAY(1, 2) AND e = DATETIME('2013-09-29T09:30:20.00002Z')",
+			want:   "SELECT * FROM `Kind` WHERE (((`a` = 'it\\'s' AND `b` > 1.0) AND `c` IS NULL) OR (`d` IN (1, 2) AND `e` = TIMESTAMP '2013-09-29T09:30:20.00002Z'))",
+		},
+		{
+			name:   "Contains",
+			source: "SELECT * FROM Kind WHERE tags CONTAINS 'a' AND 1 IN nums",
+			want:   "SELECT * FROM `Kind` WHERE ('a' IN UNNEST(`tags`) AND 1 IN UNNEST(`nums`))",
+		},
+		{
+			name:   "Key",
+			source: "SELECT * FROM Kind WHERE __key__ = KEY(NAMESPACE('ns'), Parent, 1, Kind, 'foo')",
+			want:   "SELECT * FROM `Kind` WHERE (`__key__`.namespace = 'ns' AND `__key__`.path = '\"Parent\", 1, \"Kind\", \"foo\"')",
+		},
+		{
+			name:   "Ancestor",
+			source: "SELECT * FROM Kind WHERE __key__ HAS ANCESTOR KEY(Parent, 1)",
+			want:   "SELECT * FROM `Kind` WHERE (`__key__`.path = '\"Parent\", 1' OR STARTS_WITH(`__key__`.path, '\"Parent\", 1, '))",
+		},
+		{
+			name:    "KeyInequality",
+			source:  "SELECT * FROM Kind WHERE __key__ > KEY(Kind, 1)",
+			wantErr: true,
+		},
+		{
+			name:    "DistinctOn",
+			source:  "SELECT DISTINCT ON (a) a FROM Kind",
+			wantErr: true,
+		},
+		{
+			name:    "Unbound",
+			source:  "SELECT * FROM Kind WHERE a = @a",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source))
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+
+			got, err := (&bigquery.Emitter{}).EmitQuery(query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EmitQuery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !errors.Is(err, bigquery.ErrUnsupported) {
+					t.Errorf("EmitQuery() error = %v, want ErrUnsupported", err)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("EmitQuery() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmitCondition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		condition gqlparser.Condition
+		want      string
+	}{
+		{
+			name:      "NaN",
+			condition: &gqlparser.EitherComparatorCondition{Comparator: gqlparser.EqualsEitherComparator, Property: "a", Value: math.NaN()},
+			want:      "`a` = CAST('NaN' AS FLOAT64)",
+		},
+		{
+			name:      "Infinity",
+			condition: &gqlparser.EitherComparatorCondition{Comparator: gqlparser.LesserThanEitherComparator, Property: "a", Value: math.Inf(1)},
+			want:      "`a` < CAST('inf' AS FLOAT64)",
+		},
+		{
+			name:      "NegativeInfinity",
+			condition: &gqlparser.EitherComparatorCondition{Comparator: gqlparser.GreaterThanEitherComparator, Property: "a", Value: math.Inf(-1)},
+			want:      "`a` > CAST('-inf' AS FLOAT64)",
+		},
+		{
+			name:      "ContainsAny",
+			condition: &gqlparser.QuantifiedComparatorCondition{Comparator: gqlparser.ContainsAnyQuantifiedComparator, Property: "tags", Value: []any{"a", "b"}},
+			want:      "('a' IN UNNEST(`tags`) OR 'b' IN UNNEST(`tags`))",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := (&bigquery.Emitter{}).EmitCondition(tt.condition)
+			if err != nil {
+				t.Fatalf("EmitCondition() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("EmitCondition() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
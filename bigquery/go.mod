@@ -0,0 +1,9 @@
+module github.com/karupanerura/gqlparser/bigquery
+
+go 1.22.0
+
+require github.com/karupanerura/gqlparser v0.0.0
+
+require github.com/karupanerura/runetrie v0.0.0-20240410000052-ea8ffbef19db // indirect
+
+replace github.com/karupanerura/gqlparser => ../
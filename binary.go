@@ -0,0 +1,174 @@
+package gqlparser
+
+import (
+	"encoding/gob"
+	"fmt"
+	"sync"
+)
+
+// binaryVersion is the first byte of the binary encoding to reject the data encoded by the incompatible versions.
+const binaryVersion byte = 1
+
+// The syntaxes implement encoding.BinaryMarshaler and encoding.BinaryUnmarshaler to cache them without re-parsing.
+// The binary encoding is the s-expression by EncodeSExpr with the version prefix.
+// The unmarshaling returns ErrInvalidSExpr for the broken data and the data of the other syntax types.
+
+func marshalBinary(s Syntax) ([]byte, error) {
+	encoded, err := EncodeSExpr(s)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{binaryVersion}, encoded...), nil
+}
+
+func unmarshalBinary[T any](data []byte, dst *T) error {
+	if len(data) == 0 || data[0] != binaryVersion {
+		return fmt.Errorf("%w: unsupported binary version", ErrInvalidSExpr)
+	}
+	decoded, err := DecodeSExpr(string(data[1:]))
+	if err != nil {
+		return err
+	}
+	v, ok := any(decoded).(*T)
+	if !ok {
+		return fmt.Errorf("%w: %T is decoded but want %T", ErrInvalidSExpr, decoded, dst)
+	}
+	*dst = *v
+	return nil
+}
+
+var registerGobOnce sync.Once
+
+// RegisterGob registers the syntax types into encoding/gob to encode the structs that have the fields of
+// the interface types such as Syntax, Condition and Aggregation. It's safe to call it multiple times.
+func RegisterGob() {
+	registerGobOnce.Do(func() {
+		gob.Register(&Key{})
+		gob.Register(&Query{})
+		gob.Register(&OrderBy{})
+		gob.Register(&Limit{})
+		gob.Register(&Offset{})
+		gob.Register(&AggregationQuery{})
+		gob.Register(&CountAggregation{})
+		gob.Register(&CountUpToAggregation{})
+		gob.Register(&SumAggregation{})
+		gob.Register(&AvgAggregation{})
+		gob.Register(&AndCompoundCondition{})
+		gob.Register(&OrCompoundCondition{})
+		gob.Register(&IsNullCondition{})
+		gob.Register(&ForwardComparatorCondition{})
+		gob.Register(&BackwardComparatorCondition{})
+		gob.Register(&EitherComparatorCondition{})
+		gob.Register(&QuantifiedComparatorCondition{})
+	})
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (k *Key) MarshalBinary() ([]byte, error) { return marshalBinary(k) }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (k *Key) UnmarshalBinary(data []byte) error { return unmarshalBinary(data, k) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (q *Query) MarshalBinary() ([]byte, error) { return marshalBinary(q) }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (q *Query) UnmarshalBinary(data []byte) error { return unmarshalBinary(data, q) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (o *OrderBy) MarshalBinary() ([]byte, error) { return marshalBinary(o) }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (o *OrderBy) UnmarshalBinary(data []byte) error { return unmarshalBinary(data, o) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (l *Limit) MarshalBinary() ([]byte, error) { return marshalBinary(l) }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (l *Limit) UnmarshalBinary(data []byte) error { return unmarshalBinary(data, l) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (o *Offset) MarshalBinary() ([]byte, error) { return marshalBinary(o) }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (o *Offset) UnmarshalBinary(data []byte) error { return unmarshalBinary(data, o) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (q *AggregationQuery) MarshalBinary() ([]byte, error) { return marshalBinary(q) }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (q *AggregationQuery) UnmarshalBinary(data []byte) error { return unmarshalBinary(data, q) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (a *CountAggregation) MarshalBinary() ([]byte, error) { return marshalBinary(a) }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (a *CountAggregation) UnmarshalBinary(data []byte) error { return unmarshalBinary(data, a) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (a *CountUpToAggregation) MarshalBinary() ([]byte, error) { return marshalBinary(a) }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (a *CountUpToAggregation) UnmarshalBinary(data []byte) error { return unmarshalBinary(data, a) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (a *SumAggregation) MarshalBinary() ([]byte, error) { return marshalBinary(a) }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (a *SumAggregation) UnmarshalBinary(data []byte) error { return unmarshalBinary(data, a) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (a *AvgAggregation) MarshalBinary() ([]byte, error) { return marshalBinary(a) }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (a *AvgAggregation) UnmarshalBinary(data []byte) error { return unmarshalBinary(data, a) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (c *AndCompoundCondition) MarshalBinary() ([]byte, error) { return marshalBinary(c) }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (c *AndCompoundCondition) UnmarshalBinary(data []byte) error { return unmarshalBinary(data, c) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (c *OrCompoundCondition) MarshalBinary() ([]byte, error) { return marshalBinary(c) }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (c *OrCompoundCondition) UnmarshalBinary(data []byte) error { return unmarshalBinary(data, c) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (c *IsNullCondition) MarshalBinary() ([]byte, error) { return marshalBinary(c) }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (c *IsNullCondition) UnmarshalBinary(data []byte) error { return unmarshalBinary(data, c) }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (c *ForwardComparatorCondition) MarshalBinary() ([]byte, error) { return marshalBinary(c) }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (c *ForwardComparatorCondition) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, c)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (c *BackwardComparatorCondition) MarshalBinary() ([]byte, error) { return marshalBinary(c) }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (c *BackwardComparatorCondition) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, c)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (c *EitherComparatorCondition) MarshalBinary() ([]byte, error) { return marshalBinary(c) }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (c *EitherComparatorCondition) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, c)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (c *QuantifiedComparatorCondition) MarshalBinary() ([]byte, error) { return marshalBinary(c) }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (c *QuantifiedComparatorCondition) UnmarshalBinary(data []byte) error {
+	return unmarshalBinary(data, c)
+}
@@ -0,0 +1,120 @@
+package gqlparser_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range queryTests {
+		tt := tt
+		if tt.wantErr {
+			continue
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := tt.want.(*gqlparser.Query).MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got gqlparser.Query
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, &got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+	for _, tt := range aggregationQueryTests {
+		tt := tt
+		if tt.wantErr {
+			continue
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := tt.want.(*gqlparser.AggregationQuery).MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got gqlparser.AggregationQuery
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, &got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRegisterGob(t *testing.T) {
+	t.Parallel()
+
+	gqlparser.RegisterGob()
+	gqlparser.RegisterGob()
+
+	type cached struct {
+		Where gqlparser.Condition
+		Query *gqlparser.Query
+	}
+	want := cached{
+		Where: &gqlparser.AndCompoundCondition{
+			Left:  &gqlparser.IsNullCondition{Property: "a"},
+			Right: &gqlparser.EitherComparatorCondition{Comparator: gqlparser.EqualsEitherComparator, Property: "b", Value: 1.5},
+		},
+		Query: &gqlparser.Query{Kind: "Kind", Limit: &gqlparser.Limit{Position: 10}},
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(want); err != nil {
+		t.Fatal(err)
+	}
+	var got cached
+	if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestUnmarshalBinary_Error(t *testing.T) {
+	t.Parallel()
+
+	condition, err := (&gqlparser.IsNullCondition{Property: "a"}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "Empty", data: nil},
+		{name: "UnknownVersion", data: append([]byte{0}, condition[1:]...)},
+		{name: "Broken", data: condition[:len(condition)-1]},
+		{name: "OtherType", data: condition},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got gqlparser.Query
+			if err := got.UnmarshalBinary(tt.data); !errors.Is(err, gqlparser.ErrInvalidSExpr) {
+				t.Errorf("UnmarshalBinary() error = %v, want %v", err, gqlparser.ErrInvalidSExpr)
+			}
+		})
+	}
+}
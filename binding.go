@@ -3,17 +3,188 @@ package gqlparser
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 )
 
-var ErrBindValue = errors.New("no bind value")
+var (
+	ErrBindValue       = errors.New("no bind value")
+	ErrUnusedBindValue = errors.New("unused bind value")
+)
 
 type BindingResolver struct {
 	Indexed []any
 	Named   map[string]any
+	// RejectUnused reports the values not referenced by the binding variables as ParameterError in Bind.
+	RejectUnused bool
+}
+
+// ParameterError is returned by Bind when the bound values don't match the binding variables.
+// It wraps ErrBindValue if any value is missing, and ErrUnusedBindValue if any value is unused.
+type ParameterError struct {
+	// Missing are the binding variables without the values in the order of appearance.
+	Missing []BindingVariable
+	// Unused are the variables of the values not referenced. They are reported only if BindingResolver.RejectUnused is true.
+	Unused []BindingVariable
+}
+
+func (e *ParameterError) Error() string {
+	var parts []string
+	if len(e.Missing) != 0 {
+		parts = append(parts, fmt.Sprintf("%s: %s", ErrBindValue, formatBindingVariables(e.Missing)))
+	}
+	if len(e.Unused) != 0 {
+		parts = append(parts, fmt.Sprintf("%s: %s", ErrUnusedBindValue, formatBindingVariables(e.Unused)))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (e *ParameterError) Unwrap() []error {
+	var errs []error
+	if len(e.Missing) != 0 {
+		errs = append(errs, ErrBindValue)
+	}
+	if len(e.Unused) != 0 {
+		errs = append(errs, ErrUnusedBindValue)
+	}
+	return errs
 }
 
+func formatBindingVariables(variables []BindingVariable) string {
+	names := make([]string, len(variables))
+	for i, v := range variables {
+		switch b := v.(type) {
+		case *NamedBinding:
+			names[i] = "@" + b.Name
+		case *IndexedBinding:
+			names[i] = "@" + strconv.FormatInt(b.Index, 10)
+		default:
+			names[i] = fmt.Sprint(v)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// checkVariables reports the missing values of the variables and the unused values at once.
+func (r *BindingResolver) checkVariables(variables []BindingVariable) error {
+	var err ParameterError
+	usedNames := map[string]bool{}
+	usedIndexes := map[int64]bool{}
+	for _, v := range variables {
+		switch b := v.(type) {
+		case *NamedBinding:
+			if _, ok := r.Named[b.Name]; !ok && !usedNames[b.Name] {
+				err.Missing = append(err.Missing, b)
+			}
+			usedNames[b.Name] = true
+		case *IndexedBinding:
+			if (b.Index < 1 || b.Index > int64(len(r.Indexed))) && !usedIndexes[b.Index] {
+				err.Missing = append(err.Missing, b)
+			}
+			usedIndexes[b.Index] = true
+		}
+	}
+
+	if r.RejectUnused {
+		for i := range r.Indexed {
+			if index := int64(i + 1); !usedIndexes[index] {
+				err.Unused = append(err.Unused, &IndexedBinding{Index: index})
+			}
+		}
+		names := make([]string, 0, len(r.Named))
+		for name := range r.Named {
+			if !usedNames[name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			err.Unused = append(err.Unused, &NamedBinding{Name: name})
+		}
+	}
+
+	if len(err.Missing) == 0 && len(err.Unused) == 0 {
+		return nil
+	}
+	return &err
+}
+
+// bindCondition checks the variables in the condition before binding them not to leave the condition half-bound.
+func bindCondition(cond Condition, br *BindingResolver) error {
+	if err := br.checkVariables(conditionVariables(nil, cond)); err != nil {
+		return err
+	}
+	return cond.bind(br)
+}
+
+// conditionVariables appends the binding variables in the condition to the variables.
+// The variables in the elements of the array are appended too. e.g. @x of a IN ARRAY(@x, 1)
+func conditionVariables(variables []BindingVariable, cond Condition) []BindingVariable {
+	walkConditions(cond, func(leaf Condition) {
+		value, binding := comparatorValue(leaf)
+		if bv := boundVariable(value, binding); bv != nil {
+			variables = append(variables, bv)
+		} else if values, ok := value.([]any); ok {
+			for _, v := range values {
+				if bv, ok := v.(BindingVariable); ok {
+					variables = append(variables, bv)
+				}
+			}
+		}
+		walkValueKeys(value, func(k *Key) {
+			for _, path := range k.Path {
+				if path.Binding != nil {
+					variables = append(variables, path.Binding)
+				}
+			}
+		})
+	})
+	return variables
+}
+
+// bindComparatorValue resolves the value of the comparator and returns it with the variable it has been bound from.
+// The variables in the elements of the array are resolved into the copy of the array, so the array isn't bound from any variable
+// and its elements cannot be re-bound. The keys in the value are bound too.
+func bindComparatorValue(value any, binding BindingVariable, br *BindingResolver) (any, BindingVariable, error) {
+	if bv := boundVariable(value, binding); bv != nil {
+		v, err := br.Resolve(bv)
+		if err != nil {
+			return nil, nil, err
+		}
+		return v, bv, bindValueKeys(v, br)
+	}
+
+	if values, ok := value.([]any); ok {
+		var resolved []any
+		for i, v := range values {
+			bv, ok := v.(BindingVariable)
+			if !ok {
+				continue
+			}
+			v, err := br.Resolve(bv)
+			if err != nil {
+				return nil, nil, err
+			}
+			if resolved == nil {
+				resolved = append([]any(nil), values...)
+			}
+			resolved[i] = v
+		}
+		if resolved != nil {
+			value = resolved
+		}
+	}
+	return value, nil, bindValueKeys(value, br)
+}
+
+// Resolve returns the bound value of the variable. The error is reported as BindError.
 func (r *BindingResolver) Resolve(value BindingVariable) (any, error) {
-	return value.resolveBy(r)
+	v, err := value.resolveBy(r)
+	if err != nil {
+		return nil, &BindError{Variable: value, Err: err}
+	}
+	return v, nil
 }
 
 func (r *BindingResolver) getNamed(name string) (any, error) {
@@ -55,3 +226,62 @@ type IndexedBinding struct {
 func (b *IndexedBinding) resolveBy(resolver *BindingResolver) (any, error) {
 	return resolver.getIndexed(b.Index)
 }
+
+// boundVariable returns the variable to resolve the value. The variable that the value has been bound from is preferred to re-bind.
+func boundVariable(value any, binding BindingVariable) BindingVariable {
+	if binding != nil {
+		return binding
+	}
+	if bv, ok := value.(BindingVariable); ok {
+		return bv
+	}
+	return nil
+}
+
+func comparatorValue(cond Condition) (value any, binding BindingVariable) {
+	switch c := cond.(type) {
+	case *ForwardComparatorCondition:
+		return c.Value, c.Binding
+	case *BackwardComparatorCondition:
+		return c.Value, c.Binding
+	case *EitherComparatorCondition:
+		return c.Value, c.Binding
+	case *QuantifiedComparatorCondition:
+		return c.Value, c.Binding
+	default:
+		return nil, nil
+	}
+}
+
+// BoundValue is the value of the condition bound from the binding variable.
+type BoundValue struct {
+	Property string
+	Variable BindingVariable
+	Value    any
+}
+
+// BoundValues returns the values bound by Bind in the order of appearance to tell where the values came from.
+// e.g. the value 18 of the property age came from @age
+// The IDs and the names of the key paths are reported too with the property compared with the key.
+func BoundValues(cond Condition) []BoundValue {
+	var values []BoundValue
+	walkConditions(cond, func(leaf Condition) {
+		value, binding := comparatorValue(leaf)
+		property, _ := describeCondition(leaf)
+		if binding != nil {
+			values = append(values, BoundValue{Property: property, Variable: binding, Value: value})
+		}
+		walkValueKeys(value, func(k *Key) {
+			for _, path := range k.Path {
+				switch {
+				case path.Binding == nil:
+				case path.Name != "":
+					values = append(values, BoundValue{Property: property, Variable: path.Binding, Value: path.Name})
+				case path.ID != 0:
+					values = append(values, BoundValue{Property: property, Variable: path.Binding, Value: path.ID})
+				}
+			}
+		})
+	})
+	return values
+}
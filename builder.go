@@ -0,0 +1,366 @@
+package gqlparser
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidCondition = errors.New("invalid condition")
+
+// And combines the conditions with AND from left to right. It returns nil if no conditions are given.
+// It builds the same left-deep tree as the parser does for `a AND b AND c`, so the built conditions are equal to
+// the parsed ones. Use MergeAnd to merge the many or the nested conditions into the shallow tree instead.
+func And(conditions ...Condition) Condition {
+	return foldConditions(conditions, func(left, right Condition) Condition {
+		return &AndCompoundCondition{Left: left, Right: right}
+	})
+}
+
+// Or combines the conditions with OR from left to right. It returns nil if no conditions are given.
+// It builds the same tree as the parser like And. Use MergeOr to merge the conditions into the shallow tree instead.
+func Or(conditions ...Condition) Condition {
+	return foldConditions(conditions, func(left, right Condition) Condition {
+		return &OrCompoundCondition{Left: left, Right: right}
+	})
+}
+
+func foldConditions(conditions []Condition, combine func(left, right Condition) Condition) Condition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	result := conditions[0]
+	for _, c := range conditions[1:] {
+		result = combine(result, c)
+	}
+	return result
+}
+
+// MergeAnd combines the conditions with AND into the balanced tree. The nil conditions are skipped, and the nested
+// AND compounds are flattened into the operands. It returns nil if no conditions remain.
+// The depth of the tree grows logarithmically unlike And to keep the recursions over the tree shallow.
+// e.g. merging the filters collected from the requests
+func MergeAnd(conditions ...Condition) Condition {
+	var operands []Condition
+	for _, c := range conditions {
+		operands = appendAndOperands(operands, c)
+	}
+	return balanceConditions(operands, func(left, right Condition) Condition {
+		return &AndCompoundCondition{Left: left, Right: right}
+	})
+}
+
+// MergeOr combines the conditions with OR into the balanced tree like MergeAnd.
+func MergeOr(conditions ...Condition) Condition {
+	var operands []Condition
+	for _, c := range conditions {
+		operands = appendOrOperands(operands, c)
+	}
+	return balanceConditions(operands, func(left, right Condition) Condition {
+		return &OrCompoundCondition{Left: left, Right: right}
+	})
+}
+
+// appendAndOperands appends the operands of the AND compounds from left to right. nil is skipped.
+func appendAndOperands(operands []Condition, cond Condition) []Condition {
+	switch c := cond.(type) {
+	case nil:
+		return operands
+	case *AndCompoundCondition:
+		return appendAndOperands(appendAndOperands(operands, c.Left), c.Right)
+	default:
+		return append(operands, cond)
+	}
+}
+
+// appendOrOperands appends the operands of the OR compounds from left to right. nil is skipped.
+func appendOrOperands(operands []Condition, cond Condition) []Condition {
+	switch c := cond.(type) {
+	case nil:
+		return operands
+	case *OrCompoundCondition:
+		return appendOrOperands(appendOrOperands(operands, c.Left), c.Right)
+	default:
+		return append(operands, cond)
+	}
+}
+
+func balanceConditions(conditions []Condition, combine func(left, right Condition) Condition) Condition {
+	switch len(conditions) {
+	case 0:
+		return nil
+	case 1:
+		return conditions[0]
+	default:
+		mid := len(conditions) / 2
+		return combine(balanceConditions(conditions[:mid], combine), balanceConditions(conditions[mid:], combine))
+	}
+}
+
+// Eq builds `property = value`.
+func Eq(property string, value any) Condition {
+	return &EitherComparatorCondition{Comparator: EqualsEitherComparator, Property: property, Value: value}
+}
+
+// Ne builds `property != value`.
+func Ne(property string, value any) Condition {
+	return &EitherComparatorCondition{Comparator: NotEqualsEitherComparator, Property: property, Value: value}
+}
+
+// Gt builds `property > value`.
+func Gt(property string, value any) Condition {
+	return &EitherComparatorCondition{Comparator: GreaterThanEitherComparator, Property: property, Value: value}
+}
+
+// Ge builds `property >= value`.
+func Ge(property string, value any) Condition {
+	return &EitherComparatorCondition{Comparator: GreaterThanOrEqualsThanEitherComparator, Property: property, Value: value}
+}
+
+// Lt builds `property < value`.
+func Lt(property string, value any) Condition {
+	return &EitherComparatorCondition{Comparator: LesserThanEitherComparator, Property: property, Value: value}
+}
+
+// Le builds `property <= value`.
+func Le(property string, value any) Condition {
+	return &EitherComparatorCondition{Comparator: LesserThanOrEqualsEitherComparator, Property: property, Value: value}
+}
+
+// IsNull builds `property IS NULL`.
+func IsNull(property string) Condition {
+	return &IsNullCondition{Property: property}
+}
+
+// In builds `property IN ARRAY(values...)`.
+func In(property string, values ...any) Condition {
+	return &ForwardComparatorCondition{Comparator: InForwardComparator, Property: property, Value: values}
+}
+
+// NotIn builds `property NOT IN ARRAY(values...)`.
+func NotIn(property string, values ...any) Condition {
+	return &ForwardComparatorCondition{Comparator: NotInForwardComparator, Property: property, Value: values}
+}
+
+// Contains builds `property CONTAINS value`.
+func Contains(property string, value any) Condition {
+	return &ForwardComparatorCondition{Comparator: ContainsForwardComparator, Property: property, Value: value}
+}
+
+// ContainsAny builds `property CONTAINS ANY ARRAY(values...)`.
+func ContainsAny(property string, values ...any) Condition {
+	return &QuantifiedComparatorCondition{Comparator: ContainsAnyQuantifiedComparator, Property: property, Value: values}
+}
+
+// ContainsAll builds `property CONTAINS ALL ARRAY(values...)`.
+func ContainsAll(property string, values ...any) Condition {
+	return &QuantifiedComparatorCondition{Comparator: ContainsAllQuantifiedComparator, Property: property, Value: values}
+}
+
+// HasAncestor builds `__key__ HAS ANCESTOR key`. The key can be a *Key or a BindingVariable.
+func HasAncestor(key any) Condition {
+	return &ForwardComparatorCondition{Comparator: HasAncestorForwardComparator, Property: keyProperty, Value: key}
+}
+
+// WithAncestor restricts the query to the descendants of the key by `__key__ HAS ANCESTOR key`.
+// It replaces the keys of the ancestor conditions ANDed at the top level of the WHERE clause if any,
+// or ANDs the new ancestor condition with the existing conditions by MergeAnd otherwise.
+// The key can be a *Key or a BindingVariable. The query is modified in place and returned.
+func WithAncestor(q *Query, key any) *Query {
+	if !replaceAncestor(q.Where, key) {
+		q.Where = MergeAnd(q.Where, HasAncestor(key))
+	}
+	return q
+}
+
+// replaceAncestor replaces the keys of the ancestor conditions in the AND compounds.
+// The conditions in the OR compounds aren't replaced because they don't restrict the whole query.
+func replaceAncestor(cond Condition, key any) bool {
+	switch c := cond.(type) {
+	case *AndCompoundCondition:
+		left := replaceAncestor(c.Left, key)
+		right := replaceAncestor(c.Right, key)
+		return left || right
+	case *ForwardComparatorCondition:
+		if c.Comparator != HasAncestorForwardComparator || c.Property != keyProperty {
+			return false
+		}
+		c.Value = key
+		c.Binding = nil
+		return true
+	default:
+		return false
+	}
+}
+
+// ExpandQuantifiers rewrites CONTAINS ANY and CONTAINS ALL in the condition by QuantifiedComparatorCondition.Expand
+// to execute it by the backends without the extension. The given condition isn't modified, and the other conditions are shared with it.
+func ExpandQuantifiers(cond Condition) (Condition, error) {
+	switch c := cond.(type) {
+	case *AndCompoundCondition:
+		left, err := ExpandQuantifiers(c.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := ExpandQuantifiers(c.Right)
+		if err != nil {
+			return nil, err
+		}
+		return &AndCompoundCondition{Left: left, Right: right}, nil
+	case *OrCompoundCondition:
+		left, err := ExpandQuantifiers(c.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := ExpandQuantifiers(c.Right)
+		if err != nil {
+			return nil, err
+		}
+		return &OrCompoundCondition{Left: left, Right: right}, nil
+	case *QuantifiedComparatorCondition:
+		return c.Expand()
+	default:
+		return cond, nil
+	}
+}
+
+// ValidateCondition validates the condition built programmatically.
+// It reports the missing operands, the unknown comparators, the invalid properties as PropertyError
+// and the values that cannot be used with the comparators.
+// The strings and the blobs longer than MaxIndexedPropertyLength are reported as LimitViolationError too
+// because they never match the indexed values.
+// The range comparisons with the keys are allowed only on __key__ because the key properties are known only by the schema,
+// so use Query.Validate with the schema to allow them on the properties typed as KeyValueType.
+// The conditions have no positions, so parse the query with WithWarningHandler to locate them by KeyComparisonWarning.
+func ValidateCondition(cond Condition) error {
+	return validateCondition(cond, false)
+}
+
+// validateCondition validates the condition as ValidateCondition.
+// The range comparisons with the keys are left to typeCheckCondition if typed is true.
+func validateCondition(cond Condition, typed bool) error {
+	if value, _ := comparatorValue(cond); value != nil {
+		if err := validateIndexedValue(value); err != nil {
+			return err
+		}
+	}
+
+	switch c := cond.(type) {
+	case nil:
+		return fmt.Errorf("%w: nil condition", ErrInvalidCondition)
+	case *AndCompoundCondition:
+		return validateCompoundCondition(c.Left, c.Right, typed)
+	case *OrCompoundCondition:
+		return validateCompoundCondition(c.Left, c.Right, typed)
+	case *IsNullCondition:
+		return validateConditionProperty(c.Property)
+	case *EitherComparatorCondition:
+		if !isComparatorOf(c.Comparator) {
+			return fmt.Errorf("%w: comparator %q", ErrInvalidCondition, c.Comparator)
+		}
+		if err := validateConditionProperty(c.Property); err != nil {
+			return err
+		}
+		if _, isKey := c.Value.(*Key); isKey && c.Comparator.isRange() && c.Property != keyProperty && !typed {
+			return fmt.Errorf("%w: %v with the key requires %s but got %s", ErrInvalidCondition, c.Comparator, keyProperty, c.Property)
+		}
+		return nil
+	case *ForwardComparatorCondition:
+		if !isComparatorOf(c.Comparator) {
+			return fmt.Errorf("%w: comparator %q", ErrInvalidCondition, c.Comparator)
+		}
+		if err := validateConditionProperty(c.Property); err != nil {
+			return err
+		}
+		switch c.Comparator {
+		case InForwardComparator, NotInForwardComparator:
+			return validateArrayValue(c.Comparator, c.Value)
+		case HasAncestorForwardComparator:
+			return validateKeyValue(c.Comparator, c.Value)
+		}
+		return nil
+	case *BackwardComparatorCondition:
+		if c.Comparator != InBackwardComparator && c.Comparator != HasDescendantBackwardComparator {
+			return fmt.Errorf("%w: comparator %q", ErrInvalidCondition, c.Comparator)
+		}
+		if err := validateConditionProperty(c.Property); err != nil {
+			return err
+		}
+		if c.Comparator == HasDescendantBackwardComparator {
+			return validateKeyValue(c.Comparator, c.Value)
+		}
+		return nil
+	case *QuantifiedComparatorCondition:
+		if !c.Comparator.Valid() {
+			return fmt.Errorf("%w: comparator %q", ErrInvalidCondition, c.Comparator)
+		}
+		if err := validateConditionProperty(c.Property); err != nil {
+			return err
+		}
+		return validateArrayValue(c.Comparator, c.Value)
+	default:
+		return fmt.Errorf("%w: unknown condition %T", ErrInvalidCondition, cond)
+	}
+}
+
+func validateCompoundCondition(left, right Condition, typed bool) error {
+	if err := validateCondition(left, typed); err != nil {
+		return err
+	}
+	return validateCondition(right, typed)
+}
+
+// isComparatorOf reports whether the comparator is exactly one of the defined constants of its type.
+func isComparatorOf[T EitherComparator | ForwardComparator](comparator T) bool {
+	c, ok := comparators[string(comparator)].(T)
+	return ok && c == comparator
+}
+
+func validateConditionProperty(property string) error {
+	if property == "" {
+		return fmt.Errorf("%w: empty property", ErrInvalidCondition)
+	}
+	if err := Property(property).Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCondition, err)
+	}
+	return nil
+}
+
+// validateIndexedValue checks the lengths of the strings and the blobs including the elements of the arrays.
+func validateIndexedValue(value any) error {
+	var n int
+	switch v := value.(type) {
+	case string:
+		n = len(v)
+	case []byte:
+		n = len(v)
+	case []any:
+		for _, elem := range v {
+			if err := validateIndexedValue(elem); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if n > MaxIndexedPropertyLength {
+		return fmt.Errorf("%w: %w", ErrInvalidCondition, &LimitViolationError{Limit: "MaxIndexedPropertyLength", Max: MaxIndexedPropertyLength, Actual: n})
+	}
+	return nil
+}
+
+func validateArrayValue(comparator Comparator, value any) error {
+	switch value.(type) {
+	case []any, BindingVariable:
+		return nil
+	default:
+		return fmt.Errorf("%w: %v requires an array but got %T", ErrInvalidCondition, comparator, value)
+	}
+}
+
+func validateKeyValue(comparator Comparator, value any) error {
+	switch value.(type) {
+	case *Key, BindingVariable:
+		return nil
+	default:
+		return fmt.Errorf("%w: %v requires a key but got %T", ErrInvalidCondition, comparator, value)
+	}
+}
@@ -0,0 +1,181 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestConditionBuilders(t *testing.T) {
+	t.Parallel()
+
+	parent := &gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "Parent", ID: 1}}}
+	got := gqlparser.And(
+		gqlparser.HasAncestor(parent),
+		gqlparser.Or(gqlparser.Eq("a", int64(1)), gqlparser.Gt("b", 0.5), gqlparser.IsNull("c")),
+		gqlparser.In("d", "x", "y"),
+		gqlparser.NotIn("e", true),
+		gqlparser.Contains("f", "z"),
+		gqlparser.Ne("g", nil),
+		gqlparser.Ge("h", int64(1)),
+		gqlparser.Lt("i", int64(2)),
+		gqlparser.Le("j", int64(3)),
+	)
+
+	source := "__key__ HAS ANCESTOR KEY(Parent, 1) AND (a = 1 OR b > 0.5 OR c IS NULL) AND d IN ARRAY('x', 'y') AND e NOT IN ARRAY(true) AND f CONTAINS 'z' AND g != NULL AND h >= 1 AND i < 2 AND j <= 3"
+	want, err := gqlparser.ParseCondition(gqlparser.NewLexer(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if err := gqlparser.ValidateCondition(got); err != nil {
+		t.Errorf("ValidateCondition() error = %v", err)
+	}
+
+	if gqlparser.And() != nil || gqlparser.Or() != nil {
+		t.Error("empty compound conditions should be nil")
+	}
+	if c := gqlparser.Eq("a", int64(1)); gqlparser.And(c) != c {
+		t.Error("single condition should be returned as is")
+	}
+}
+
+func TestMergeConditions(t *testing.T) {
+	t.Parallel()
+
+	a, b, c, d := gqlparser.Eq("a", int64(1)), gqlparser.Eq("b", int64(2)), gqlparser.Eq("c", int64(3)), gqlparser.Eq("d", int64(4))
+
+	got := gqlparser.MergeAnd(nil, gqlparser.And(a, b, c), nil, gqlparser.Or(c, d), d)
+	var want gqlparser.Condition = &gqlparser.AndCompoundCondition{
+		Left: &gqlparser.AndCompoundCondition{Left: a, Right: b},
+		Right: &gqlparser.AndCompoundCondition{
+			Left:  c,
+			Right: &gqlparser.AndCompoundCondition{Left: &gqlparser.OrCompoundCondition{Left: c, Right: d}, Right: d},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("MergeAnd(): (-want, +got)\n%s", diff)
+	}
+
+	got = gqlparser.MergeOr(gqlparser.Or(a, b, c), d)
+	want = &gqlparser.OrCompoundCondition{
+		Left:  &gqlparser.OrCompoundCondition{Left: a, Right: b},
+		Right: &gqlparser.OrCompoundCondition{Left: c, Right: d},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("MergeOr(): (-want, +got)\n%s", diff)
+	}
+
+	if gqlparser.MergeAnd() != nil || gqlparser.MergeOr(nil, nil) != nil {
+		t.Error("empty merged conditions should be nil")
+	}
+	if gqlparser.MergeAnd(nil, a) != a {
+		t.Error("single condition should be returned as is")
+	}
+
+	// And and Or build the same trees as the parser unlike MergeAnd and MergeOr
+	parsed, err := gqlparser.ParseCondition(gqlparser.NewLexer("a = 1 AND b = 2 AND c = 3 OR d = 4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(parsed, gqlparser.Or(gqlparser.And(a, b, c), d)); diff != "" {
+		t.Errorf("And(): (-want, +got)\n%s", diff)
+	}
+}
+
+func TestValidateCondition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cond gqlparser.Condition
+	}{
+		{"Nil", nil},
+		{"NilOperand", gqlparser.And(gqlparser.Eq("a", 1), nil)},
+		{"EmptyProperty", gqlparser.Eq("", 1)},
+		{"UnknownComparator", &gqlparser.EitherComparatorCondition{Comparator: "==", Property: "a"}},
+		{"MisplacedComparator", &gqlparser.ForwardComparatorCondition{Comparator: "HAS DESCENDANT", Property: "a"}},
+		{"NonArrayIn", &gqlparser.ForwardComparatorCondition{Comparator: gqlparser.InForwardComparator, Property: "a", Value: 1}},
+		{"NonKeyAncestor", gqlparser.HasAncestor("key")},
+		{"NonKeyDescendant", &gqlparser.BackwardComparatorCondition{Comparator: gqlparser.HasDescendantBackwardComparator, Property: "__key__", Value: 1}},
+		{"TooLongString", gqlparser.Eq("a", strings.Repeat("x", gqlparser.MaxIndexedPropertyLength+1))},
+		{"TooLongElement", gqlparser.In("a", []any{"x", []byte(strings.Repeat("x", gqlparser.MaxIndexedPropertyLength+1))})},
+		{"KeyRangeOnProperty", gqlparser.And(gqlparser.Gt("__key__", &gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "Kind", ID: 1}}}), gqlparser.Lt("owner", &gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "User", ID: 1}}}))},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := gqlparser.ValidateCondition(tt.cond); !errors.Is(err, gqlparser.ErrInvalidCondition) {
+				t.Errorf("ValidateCondition() error = %v, want %v", err, gqlparser.ErrInvalidCondition)
+			}
+		})
+	}
+
+	if err := gqlparser.ValidateCondition(gqlparser.In("a", &gqlparser.NamedBinding{Name: "x"})); err != nil {
+		t.Errorf("ValidateCondition() error = %v", err)
+	}
+	if err := gqlparser.ValidateCondition(gqlparser.Eq("a", strings.Repeat("x", gqlparser.MaxIndexedPropertyLength))); err != nil {
+		t.Errorf("ValidateCondition() error = %v", err)
+	}
+	if err := gqlparser.ValidateCondition(gqlparser.Eq("a", strings.Repeat("x", gqlparser.MaxIndexedPropertyLength+1))); !errors.Is(err, gqlparser.ErrLimitExceeded) {
+		t.Errorf("ValidateCondition() error = %v, want %v", err, gqlparser.ErrLimitExceeded)
+	}
+}
+
+func TestWithAncestor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "NoWhere",
+			source: "SELECT * FROM Kind",
+			want:   "SELECT * FROM Kind WHERE __key__ HAS ANCESTOR KEY(Parent, 2)",
+		},
+		{
+			name:   "Append",
+			source: "SELECT * FROM Kind WHERE a = 1 OR b = 2",
+			want:   "SELECT * FROM Kind WHERE (a = 1 OR b = 2) AND __key__ HAS ANCESTOR KEY(Parent, 2)",
+		},
+		{
+			name:   "Replace",
+			source: "SELECT * FROM Kind WHERE a = 1 AND __key__ HAS ANCESTOR @parent AND b = 2",
+			want:   "SELECT * FROM Kind WHERE a = 1 AND __key__ HAS ANCESTOR KEY(Parent, 2) AND b = 2",
+		},
+		{
+			name:   "InOr",
+			source: "SELECT * FROM Kind WHERE a = 1 OR __key__ HAS ANCESTOR KEY(Parent, 1)",
+			want:   "SELECT * FROM Kind WHERE (a = 1 OR __key__ HAS ANCESTOR KEY(Parent, 1)) AND __key__ HAS ANCESTOR KEY(Parent, 2)",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.want))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := gqlparser.WithAncestor(query, &gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "Parent", ID: 2}}})
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package gqlparser
+
+// CanonicalizeInValues returns the condition that has the values of IN, NOT IN, CONTAINS ANY and CONTAINS ALL sorted by CompareValues without duplicates.
+// e.g. a IN ARRAY(3, 1, 3) is rewritten to a IN ARRAY(1, 3)
+// The values are duplicates if they are of the same ValueType and compared equally, so 1 and 1.0 are kept both.
+// The arrays that have the values of UnknownValueType, e.g. the unbound binding variables, are kept as is.
+// It reduces the fan-out of the queries and stabilizes the formatted queries to be used as the cache keys.
+// The given condition isn't modified, and the other conditions are shared with it.
+func CanonicalizeInValues(cond Condition) Condition {
+	switch c := cond.(type) {
+	case *AndCompoundCondition:
+		return &AndCompoundCondition{
+			Left:  CanonicalizeInValues(c.Left),
+			Right: CanonicalizeInValues(c.Right),
+		}
+	case *OrCompoundCondition:
+		return &OrCompoundCondition{
+			Left:  CanonicalizeInValues(c.Left),
+			Right: CanonicalizeInValues(c.Right),
+		}
+	case *ForwardComparatorCondition:
+		values, ok := c.Value.([]any)
+		if !ok || (c.Comparator != InForwardComparator && c.Comparator != NotInForwardComparator) {
+			return c
+		}
+		canonicalized := *c
+		canonicalized.Value = uniqueValues(sortedValues(values))
+		return &canonicalized
+	case *QuantifiedComparatorCondition:
+		values, ok := c.Value.([]any)
+		if !ok {
+			return c
+		}
+		canonicalized := *c
+		canonicalized.Value = uniqueValues(sortedValues(values))
+		return &canonicalized
+	default:
+		return cond
+	}
+}
+
+// uniqueValues returns the sorted values without the duplicates, or the values as is if they cannot be compared.
+func uniqueValues(sorted []any) []any {
+	unique := sorted[:0:0]
+	for _, v := range sorted {
+		typ := ValueTypeOf(v)
+		if typ == UnknownValueType {
+			return sorted
+		}
+		if !containsEqualValue(unique, v, typ) {
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+// containsEqualValue reports whether the tail of the sorted values compared equally to the value has the value of the type.
+func containsEqualValue(sorted []any, v any, typ ValueType) bool {
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if c, _ := CompareValues(sorted[i], v); c != 0 {
+			return false
+		}
+		if ValueTypeOf(sorted[i]) == typ {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package gqlparser_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestCanonicalizeInValues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "Sorted",
+			source: "a IN ARRAY(3, 'x', 1, NULL, 3, 'x')",
+			want:   "a IN ARRAY(NULL, 1, 3, 'x')",
+		},
+		{
+			name:   "NotIn",
+			source: "a NOT IN ARRAY(KEY(Kind, 2), KEY(Kind, 1), KEY(Kind, 2))",
+			want:   "a NOT IN ARRAY(KEY(Kind, 1), KEY(Kind, 2))",
+		},
+		{
+			name:   "Numbers",
+			source: "a IN ARRAY(1.0, 1, 2, 1.0, 1)",
+			want:   "a IN ARRAY(1.0, 1, 2)",
+		},
+		{
+			name:   "Compound",
+			source: "a IN ARRAY(2, 1) AND (b = 1 OR c NOT IN ARRAY('b', 'a', 'b'))",
+			want:   "a IN ARRAY(1, 2) AND (b = 1 OR c NOT IN ARRAY('a', 'b'))",
+		},
+		{
+			name:   "Binding",
+			source: "a IN ARRAY(2, @x, 2) AND b IN @y",
+			want:   "a IN ARRAY(2, @x, 2) AND b IN @y",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cond, err := gqlparser.ParseCondition(gqlparser.NewLexer(tt.source))
+			if err != nil {
+				t.Fatal(err)
+			}
+			original, err := gqlparser.FormatCondition(cond)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := gqlparser.FormatCondition(gqlparser.CanonicalizeInValues(cond))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+
+			// the given condition is kept as is
+			if after, err := gqlparser.FormatCondition(cond); err != nil {
+				t.Fatal(err)
+			} else if after != original {
+				t.Errorf("FormatCondition() = %q, want %q", after, original)
+			}
+		})
+	}
+}
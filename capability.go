@@ -0,0 +1,125 @@
+package gqlparser
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrUnsupportedFeature = errors.New("unsupported feature")
+
+// DialectCapabilities are the features of GQL supported by the backend of the dialect.
+// They are checked by Dialect.Validate to target the backend before executing the query.
+type DialectCapabilities struct {
+	// Or is true if the OR conditions are supported.
+	Or bool
+	// NotIn is true if the NOT IN conditions are supported.
+	NotIn bool
+	// NotEquals is true if the != conditions are supported.
+	NotEquals bool
+	// Aggregations is true if the aggregation queries are supported. e.g. SELECT COUNT(*) FROM Kind
+	Aggregations bool
+	// MaxInSize is the max number of the values of IN and NOT IN. It's unlimited if zero.
+	MaxInSize int
+}
+
+var (
+	// FirestoreInDatastoreModeCapabilities are the capabilities of Firestore in Datastore mode.
+	FirestoreInDatastoreModeCapabilities = DialectCapabilities{Or: true, NotIn: true, NotEquals: true, Aggregations: true, MaxInSize: 30}
+	// LegacyDatastoreCapabilities are the capabilities of the legacy Cloud Datastore without the Firestore features.
+	LegacyDatastoreCapabilities = DialectCapabilities{}
+)
+
+// WithCapabilities limits the features of the queries validated by Dialect.Validate.
+// e.g. NewDialect(WithCapabilities(LegacyDatastoreCapabilities))
+// The parser accepts the unsupported features regardless of the capabilities.
+func WithCapabilities(capabilities DialectCapabilities) DialectOption {
+	return func(d *Dialect) error {
+		if capabilities.MaxInSize < 0 {
+			return fmt.Errorf("%w: negative MaxInSize %d", ErrInvalidDialect, capabilities.MaxInSize)
+		}
+		d.capabilities = &capabilities
+		return nil
+	}
+}
+
+// UnsupportedFeatureError is returned by Dialect.Validate when the syntax uses the feature not supported by the dialect.
+// It wraps ErrUnsupportedFeature.
+type UnsupportedFeatureError struct {
+	// Feature is the unsupported feature. e.g. "OR", "NOT IN", "!=", "aggregation", "IN with 31 values"
+	Feature string
+}
+
+func (e *UnsupportedFeatureError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrUnsupportedFeature, e.Feature)
+}
+
+func (e *UnsupportedFeatureError) Unwrap() error {
+	return ErrUnsupportedFeature
+}
+
+// Validate checks the query, the aggregation query or the condition uses only the features supported by the dialect.
+// The first unsupported feature is reported as UnsupportedFeatureError. Any syntax is valid without WithCapabilities.
+func (d *Dialect) Validate(s Syntax) error {
+	c := d.capabilities
+	if c == nil {
+		return nil
+	}
+
+	switch s := s.(type) {
+	case *Query:
+		return c.validateCondition(s.Where)
+	case *AggregationQuery:
+		if !c.Aggregations {
+			return &UnsupportedFeatureError{Feature: "aggregation"}
+		}
+		if err := c.validateCondition(s.Where); err != nil {
+			return err
+		}
+		return c.validateCondition(s.Having)
+	case Condition:
+		return c.validateCondition(s)
+	default:
+		return nil
+	}
+}
+
+func (c *DialectCapabilities) validateCondition(cond Condition) error {
+	if !c.Or && hasOrCondition(cond) {
+		return &UnsupportedFeatureError{Feature: "OR"}
+	}
+
+	var err error
+	walkConditions(cond, func(leaf Condition) {
+		if err == nil {
+			err = c.validateComparison(leaf)
+		}
+	})
+	return err
+}
+
+func (c *DialectCapabilities) validateComparison(cond Condition) error {
+	switch cond := cond.(type) {
+	case *EitherComparatorCondition:
+		if cond.Comparator == NotEqualsEitherComparator && !c.NotEquals {
+			return &UnsupportedFeatureError{Feature: string(NotEqualsEitherComparator)}
+		}
+	case *ForwardComparatorCondition:
+		switch cond.Comparator {
+		case NotInForwardComparator:
+			if !c.NotIn {
+				return &UnsupportedFeatureError{Feature: string(NotInForwardComparator)}
+			}
+			fallthrough
+		case InForwardComparator:
+			if values, ok := cond.Value.([]any); ok && c.MaxInSize > 0 && len(values) > c.MaxInSize {
+				return &UnsupportedFeatureError{Feature: fmt.Sprintf("%s with %d values", cond.Comparator, len(values))}
+			}
+		}
+	case *QuantifiedComparatorCondition:
+		// CONTAINS ANY is executed as OR of CONTAINS by Expand
+		if cond.Comparator == ContainsAnyQuantifiedComparator && !c.Or {
+			return &UnsupportedFeatureError{Feature: string(ContainsAnyQuantifiedComparator)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+// Package cel converts the GQL conditions into the expressions of Common Expression Language.
+package cel
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/karupanerura/gqlparser"
+)
+
+var ErrUnsupported = errors.New("unsupported")
+
+// Converter converts the bound conditions into the CEL expression strings.
+type Converter struct {
+	// Variable is the name of the variable that holds the entity.
+	// The properties are referred as the top-level identifiers if it's empty.
+	Variable string
+}
+
+var eitherComparatorOperators = map[gqlparser.EitherComparator]string{
+	gqlparser.EqualsEitherComparator:                  "==",
+	gqlparser.NotEqualsEitherComparator:               "!=",
+	gqlparser.GreaterThanEitherComparator:             ">",
+	gqlparser.GreaterThanOrEqualsThanEitherComparator: ">=",
+	gqlparser.LesserThanEitherComparator:              "<",
+	gqlparser.LesserThanOrEqualsEitherComparator:      "<=",
+}
+
+// ConvertCondition converts the condition into the CEL expression.
+// The condition is expected to be bound already.
+func (c *Converter) ConvertCondition(cond gqlparser.Condition) (string, error) {
+	switch v := cond.(type) {
+	case *gqlparser.AndCompoundCondition:
+		return c.convertCompoundCondition("&&", v.Left, v.Right)
+	case *gqlparser.OrCompoundCondition:
+		return c.convertCompoundCondition("||", v.Left, v.Right)
+	case *gqlparser.IsNullCondition:
+		prop, err := c.property(v.Property)
+		if err != nil {
+			return "", err
+		}
+		return prop + " == null", nil
+	case *gqlparser.EitherComparatorCondition:
+		op, ok := eitherComparatorOperators[v.Comparator]
+		if !ok {
+			return "", fmt.Errorf("%w: comparator %s", ErrUnsupported, v.Comparator)
+		}
+		return c.binary(v.Property, op, v.Value, false)
+	case *gqlparser.ForwardComparatorCondition:
+		switch v.Comparator {
+		case gqlparser.InForwardComparator:
+			return c.binary(v.Property, "in", v.Value, false)
+		case gqlparser.NotInForwardComparator:
+			expr, err := c.binary(v.Property, "in", v.Value, false)
+			if err != nil {
+				return "", err
+			}
+			return "!(" + expr + ")", nil
+		case gqlparser.ContainsForwardComparator:
+			return c.binary(v.Property, "in", v.Value, true)
+		default:
+			return "", fmt.Errorf("%w: comparator %s", ErrUnsupported, v.Comparator)
+		}
+	case *gqlparser.BackwardComparatorCondition:
+		switch v.Comparator {
+		case gqlparser.InBackwardComparator:
+			return c.binary(v.Property, "in", v.Value, true)
+		default:
+			return "", fmt.Errorf("%w: comparator %s", ErrUnsupported, v.Comparator)
+		}
+	case *gqlparser.QuantifiedComparatorCondition:
+		switch v.Comparator {
+		case gqlparser.ContainsAnyQuantifiedComparator:
+			return c.quantified(v.Property, "exists", v.Value)
+		case gqlparser.ContainsAllQuantifiedComparator:
+			return c.quantified(v.Property, "all", v.Value)
+		default:
+			return "", fmt.Errorf("%w: comparator %s", ErrUnsupported, v.Comparator)
+		}
+	default:
+		return "", fmt.Errorf("%w: condition %T", ErrUnsupported, cond)
+	}
+}
+
+// quantified renders the macro testing the membership of the values in the array property.
+// e.g. [1, 2].exists(v, v in tags)
+func (c *Converter) quantified(property, macro string, value any) (string, error) {
+	prop, err := c.property(property)
+	if err != nil {
+		return "", err
+	}
+	v, err := literal(value)
+	if err != nil {
+		return "", err
+	}
+
+	// the iteration variable must not shadow the root of the property
+	root := prop
+	if i := strings.IndexAny(root, ".["); i >= 0 {
+		root = root[:i]
+	}
+	iter := "v"
+	for iter == root {
+		iter += "_"
+	}
+	return v + "." + macro + "(" + iter + ", " + iter + " in " + prop + ")", nil
+}
+
+func (c *Converter) convertCompoundCondition(op string, left, right gqlparser.Condition) (string, error) {
+	l, err := c.ConvertCondition(left)
+	if err != nil {
+		return "", err
+	}
+	r, err := c.ConvertCondition(right)
+	if err != nil {
+		return "", err
+	}
+	return "(" + l + " " + op + " " + r + ")", nil
+}
+
+// binary renders the binary expression. The operands are swapped if the reversed is true.
+func (c *Converter) binary(property, op string, value any, reversed bool) (string, error) {
+	prop, err := c.property(property)
+	if err != nil {
+		return "", err
+	}
+	v, err := literal(value)
+	if err != nil {
+		return "", err
+	}
+	if reversed {
+		return v + " " + op + " " + prop, nil
+	}
+	return prop + " " + op + " " + v, nil
+}
+
+func (c *Converter) property(property string) (string, error) {
+	if property == "__key__" {
+		return "", fmt.Errorf("%w: property %s", ErrUnsupported, property)
+	}
+
+	segments := strings.Split(property, ".")
+	var sb strings.Builder
+	if c.Variable != "" {
+		sb.WriteString(c.Variable)
+	}
+	for i, s := range segments {
+		switch {
+		case isIdentifier(s) && !reservedWords[s]:
+			if i != 0 || c.Variable != "" {
+				sb.WriteByte('.')
+			}
+			sb.WriteString(s)
+		case i != 0 || c.Variable != "":
+			sb.WriteByte('[')
+			sb.WriteString(strconv.Quote(s))
+			sb.WriteByte(']')
+		default:
+			return "", fmt.Errorf("%w: property name %q without variable", ErrUnsupported, s)
+		}
+	}
+	return sb.String(), nil
+}
+
+var reservedWords = map[string]bool{
+	"true": true, "false": true, "null": true, "in": true,
+	"as": true, "break": true, "const": true, "continue": true, "else": true,
+	"for": true, "function": true, "if": true, "import": true, "let": true,
+	"loop": true, "package": true, "namespace": true, "return": true, "var": true, "void": true, "while": true,
+}
+
+// isIdentifier matches the regular expression `[_a-zA-Z][_a-zA-Z0-9]*`.
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if b == '_' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || i != 0 && '0' <= b && b <= '9' {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func literal(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "null", nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		// the non-finite doubles have no literals, so they're converted from the strings
+		switch {
+		case math.IsNaN(v):
+			return `double("NaN")`, nil
+		case math.IsInf(v, 1):
+			return `double("Infinity")`, nil
+		case math.IsInf(v, -1):
+			return `double("-Infinity")`, nil
+		}
+		s := strconv.FormatFloat(v, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".eEnN") {
+			s += ".0"
+		}
+		return s, nil
+	case string:
+		return strconv.Quote(v), nil
+	case []byte:
+		var sb strings.Builder
+		sb.WriteString(`b"`)
+		for _, b := range v {
+			fmt.Fprintf(&sb, `\x%02x`, b)
+		}
+		sb.WriteByte('"')
+		return sb.String(), nil
+	case time.Time:
+		return "timestamp(" + strconv.Quote(v.Format(time.RFC3339Nano)) + ")", nil
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			s, err := literal(item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = s
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case *gqlparser.Key:
+		return "", fmt.Errorf("%w: key value", ErrUnsupported)
+	case gqlparser.BindingVariable:
+		return "", fmt.Errorf("%w: unbound variable %v", ErrUnsupported, v)
+	default:
+		return "", fmt.Errorf("%w: value %T", ErrUnsupported, value)
+	}
+}
@@ -0,0 +1,167 @@
+package cel_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/karupanerura/gqlparser"
+	"github.com/karupanerura/gqlparser/cel"
+)
+
+func TestConvertCondition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		variable string
+		source   string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:   "Compound",
+			source: "a = 1 AND (`b.c` > 0.5 OR d IS NULL) AND e != 'x'",
+			want:   `((a == 1 && (b.c > 0.5 || d == null)) && e != "x")`,
+		},
+		{
+			name:   "In",
+			source: `a IN ARRAY(1, 2) AND b NOT IN ARRAY(true) AND c CONTAINS 'x' AND 3 IN d`,
+			want:   `(((a in [1, 2] && !(b in [true])) && "x" in c) && 3 in d)`,
+		},
+		{
+			name:     "Variable",
+			variable: "entity",
+			source:   "`a b.c` = BLOB('AP8') AND `x.in` <= DATETIME('2013-09-29T09:30:20Z')",
+			want:     `(entity["a b"].c == b"\x00\xff" && entity.x["in"] <= timestamp("2013-09-29T09:30:20Z"))`,
+		},
+		{
+			name:    "QuotedPropertyWithoutVariable",
+			source:  "`a b` = 1",
+			wantErr: true,
+		},
+		{
+			name:    "Key",
+			source:  "__key__ HAS ANCESTOR KEY(Parent, 1)",
+			wantErr: true,
+		},
+		{
+			name:    "Unbound",
+			source:  "a = @a",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cond, err := gqlparser.ParseCondition(gqlparser.NewLexer(tt.source))
+			if err != nil {
+				t.Fatalf("ParseCondition() error = %v", err)
+			}
+
+			got, err := (&cel.Converter{Variable: tt.variable}).ConvertCondition(cond)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ConvertCondition() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !errors.Is(err, cel.ErrUnsupported) {
+					t.Errorf("ConvertCondition() error = %v, want ErrUnsupported", err)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ConvertCondition() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertCondition_NonFinite(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value float64
+		want  string
+	}{
+		{name: "NaN", value: math.NaN(), want: `a == double("NaN")`},
+		{name: "PositiveInfinity", value: math.Inf(1), want: `a == double("Infinity")`},
+		{name: "NegativeInfinity", value: math.Inf(-1), want: `a == double("-Infinity")`},
+		{name: "Integral", value: 2, want: `a == 2.0`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cond := &gqlparser.EitherComparatorCondition{Comparator: gqlparser.EqualsEitherComparator, Property: "a", Value: tt.value}
+			got, err := (&cel.Converter{}).ConvertCondition(cond)
+			if err != nil {
+				t.Fatalf("ConvertCondition() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ConvertCondition() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertCondition_Quantifiers(t *testing.T) {
+	t.Parallel()
+
+	dialect, err := gqlparser.NewDialect(gqlparser.WithContainsQuantifiers())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		variable string
+		source   string
+		want     string
+	}{
+		{
+			name:   "Any",
+			source: "tags CONTAINS ANY ARRAY('a', 'b')",
+			want:   `["a", "b"].exists(v, v in tags)`,
+		},
+		{
+			name:   "All",
+			source: "a = 1 AND tags CONTAINS ALL ARRAY(1, 2)",
+			want:   `(a == 1 && [1, 2].all(v, v in tags))`,
+		},
+		{
+			name:   "ShadowedProperty",
+			source: "v.w CONTAINS ANY ARRAY(1)",
+			want:   `[1].exists(v_, v_ in v.w)`,
+		},
+		{
+			name:     "Variable",
+			variable: "v",
+			source:   "tags CONTAINS ALL ARRAY(1)",
+			want:     `[1].all(v_, v_ in v.tags)`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cond, err := gqlparser.ParseCondition(gqlparser.NewLexer(tt.source), gqlparser.WithDialect(dialect))
+			if err != nil {
+				t.Fatalf("ParseCondition() error = %v", err)
+			}
+
+			got, err := (&cel.Converter{Variable: tt.variable}).ConvertCondition(cond)
+			if err != nil {
+				t.Fatalf("ConvertCondition() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ConvertCondition() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
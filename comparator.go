@@ -0,0 +1,77 @@
+package gqlparser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidComparator = errors.New("invalid comparator")
+
+// Comparator is any of EitherComparator, ForwardComparator, BackwardComparator and QuantifiedComparator.
+type Comparator interface {
+	Valid() bool
+	isComparator()
+}
+
+func (EitherComparator) isComparator()     {}
+func (ForwardComparator) isComparator()    {}
+func (BackwardComparator) isComparator()   {}
+func (QuantifiedComparator) isComparator() {}
+
+var comparators = map[string]Comparator{
+	string(EqualsEitherComparator):                  EqualsEitherComparator,
+	string(NotEqualsEitherComparator):               NotEqualsEitherComparator,
+	string(GreaterThanEitherComparator):             GreaterThanEitherComparator,
+	string(GreaterThanOrEqualsThanEitherComparator): GreaterThanOrEqualsThanEitherComparator,
+	string(LesserThanEitherComparator):              LesserThanEitherComparator,
+	string(LesserThanOrEqualsEitherComparator):      LesserThanOrEqualsEitherComparator,
+	string(ContainsForwardComparator):               ContainsForwardComparator,
+	string(HasAncestorForwardComparator):            HasAncestorForwardComparator,
+	string(InForwardComparator):                     InForwardComparator,
+	string(NotInForwardComparator):                  NotInForwardComparator,
+	string(HasDescendantBackwardComparator):         HasDescendantBackwardComparator,
+}
+
+// ParseComparator parses the comparator case-insensitively and returns the typed comparator.
+// IN is parsed as InForwardComparator because it's the canonical form of IN conditions.
+// Use ParseBackwardComparator to get InBackwardComparator.
+func ParseComparator(s string) (Comparator, error) {
+	if c, ok := comparators[canonicalComparator(s)]; ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrInvalidComparator, s)
+}
+
+// ParseEitherComparator parses the comparator as EitherComparator.
+func ParseEitherComparator(s string) (EitherComparator, error) {
+	if c, ok := comparators[canonicalComparator(s)].(EitherComparator); ok {
+		return c, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidComparator, s)
+}
+
+// ParseForwardComparator parses the comparator case-insensitively as ForwardComparator.
+func ParseForwardComparator(s string) (ForwardComparator, error) {
+	if c, ok := comparators[canonicalComparator(s)].(ForwardComparator); ok {
+		return c, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidComparator, s)
+}
+
+// ParseBackwardComparator parses the comparator case-insensitively as BackwardComparator.
+func ParseBackwardComparator(s string) (BackwardComparator, error) {
+	c := canonicalComparator(s)
+	if c == string(InBackwardComparator) {
+		return InBackwardComparator, nil
+	}
+	if c, ok := comparators[c].(BackwardComparator); ok {
+		return c, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidComparator, s)
+}
+
+// canonicalComparator converts the comparator to upper case and squashes the whitespaces. e.g. "not  in" to "NOT IN"
+func canonicalComparator(s string) string {
+	return strings.Join(strings.Fields(strings.ToUpper(s)), " ")
+}
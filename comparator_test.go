@@ -0,0 +1,52 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestParseComparator(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		source string
+		want   gqlparser.Comparator
+	}{
+		{"=", gqlparser.EqualsEitherComparator},
+		{"!=", gqlparser.NotEqualsEitherComparator},
+		{">", gqlparser.GreaterThanEitherComparator},
+		{">=", gqlparser.GreaterThanOrEqualsThanEitherComparator},
+		{"<", gqlparser.LesserThanEitherComparator},
+		{"<=", gqlparser.LesserThanOrEqualsEitherComparator},
+		{"IN", gqlparser.InForwardComparator},
+		{"not  in", gqlparser.NotInForwardComparator},
+		{"Contains", gqlparser.ContainsForwardComparator},
+		{"HAS ANCESTOR", gqlparser.HasAncestorForwardComparator},
+		{" has descendant ", gqlparser.HasDescendantBackwardComparator},
+	}
+	for _, tt := range tests {
+		got, err := gqlparser.ParseComparator(tt.source)
+		if err != nil {
+			t.Errorf("ParseComparator(%q) error = %v", tt.source, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseComparator(%q) = %#v, want %#v", tt.source, got, tt.want)
+		}
+	}
+
+	for _, source := range []string{"", "==", "=>", "IS", "HAS", "NOT", "INN"} {
+		if _, err := gqlparser.ParseComparator(source); !errors.Is(err, gqlparser.ErrInvalidComparator) {
+			t.Errorf("ParseComparator(%q) error = %v, want %v", source, err, gqlparser.ErrInvalidComparator)
+		}
+	}
+
+	if got, err := gqlparser.ParseBackwardComparator("in"); err != nil || got != gqlparser.InBackwardComparator {
+		t.Errorf("ParseBackwardComparator() = %v, %v", got, err)
+	}
+	if _, err := gqlparser.ParseEitherComparator("IN"); !errors.Is(err, gqlparser.ErrInvalidComparator) {
+		t.Errorf("ParseEitherComparator() error = %v, want %v", err, gqlparser.ErrInvalidComparator)
+	}
+}
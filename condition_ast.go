@@ -2,16 +2,19 @@ package gqlparser
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
+var errEmptyConditionValue = fmt.Errorf("%w: empty condition value", ErrUnexpectedToken)
+
 type conditionAST interface {
-	toCondition() (Condition, error)
+	toCondition(pool *Pool) (Condition, error)
 	toUnexpectedTokenError() error
 }
 
 type conditionValuer interface {
-	value() any
+	value() (any, error)
 	toUnexpectedTokenError() error
 }
 
@@ -19,30 +22,62 @@ type forwardComparatorCondition struct {
 	left   *conditionField
 	op     *OperatorToken
 	opType string
-	right  conditionValuer
+	// quantifier is ANY or ALL following CONTAINS if any.
+	quantifier *SymbolToken
+	right      conditionValuer
 }
 
-func (c *forwardComparatorCondition) toCondition() (Condition, error) {
+func (c *forwardComparatorCondition) toCondition(pool *Pool) (Condition, error) {
 	if _, isEitherOP := infixEitherOperatorInvertMap[c.opType]; isEitherOP {
 		// not invert op to canonical
 		comparator := EitherComparator(c.opType)
 		if !comparator.Valid() {
-			return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, c.op.GetContent(), c.op.GetPosition())
+			return nil, &SyntaxError{Token: c.op}
+		}
+		value, err := c.right.value()
+		if err != nil {
+			return nil, err
 		}
-		return &EitherComparatorCondition{Comparator: comparator, Property: c.left.name(), Value: c.right.value()}, nil
+		cond := pool.newEitherComparatorCondition()
+		cond.Comparator, cond.Property, cond.Value = comparator, c.left.name(), value
+		return cond, nil
 	}
 	if c.opType == "IS" {
-		if c.right.value() != nil {
+		if value, err := c.right.value(); err != nil {
+			return nil, err
+		} else if value != nil {
 			return nil, c.right.toUnexpectedTokenError()
 		}
-		return &IsNullCondition{Property: c.left.name()}, nil
+		cond := pool.newIsNullCondition()
+		cond.Property = c.left.name()
+		return cond, nil
+	}
+
+	if c.quantifier != nil {
+		value, err := c.right.value()
+		if err != nil {
+			return nil, err
+		}
+		cond := pool.newQuantifiedComparatorCondition()
+		cond.Comparator, cond.Property, cond.Value = c.quantifiedComparator(), c.left.name(), value
+		return cond, nil
 	}
 
 	comparator := ForwardComparator(c.opType)
 	if !comparator.Valid() {
-		return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, c.op.GetContent(), c.op.GetPosition())
+		return nil, &SyntaxError{Token: c.op}
 	}
-	return &ForwardComparatorCondition{Comparator: comparator, Property: c.left.name(), Value: c.right.value()}, nil
+	value, err := c.right.value()
+	if err != nil {
+		return nil, err
+	}
+	cond := pool.newForwardComparatorCondition()
+	cond.Comparator, cond.Property, cond.Value = comparator, c.left.name(), value
+	return cond, nil
+}
+
+func (c *forwardComparatorCondition) quantifiedComparator() QuantifiedComparator {
+	return QuantifiedComparator(c.opType + " " + strings.ToUpper(c.quantifier.Content))
 }
 
 func (c *forwardComparatorCondition) toUnexpectedTokenError() error {
@@ -56,21 +91,33 @@ type backwardComparatorCondition struct {
 	right  *conditionField
 }
 
-func (c *backwardComparatorCondition) toCondition() (Condition, error) {
+func (c *backwardComparatorCondition) toCondition(pool *Pool) (Condition, error) {
 	if op, isEitherOP := infixEitherOperatorInvertMap[c.opType]; isEitherOP {
 		// invert op to canonical
 		comparator := EitherComparator(op)
 		if !comparator.Valid() {
-			return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, c.op.GetContent(), c.op.GetPosition())
+			return nil, &SyntaxError{Token: c.op}
+		}
+		value, err := c.left.value()
+		if err != nil {
+			return nil, err
 		}
-		return &EitherComparatorCondition{Comparator: comparator, Property: c.right.name(), Value: c.left.value()}, nil
+		cond := pool.newEitherComparatorCondition()
+		cond.Comparator, cond.Property, cond.Value = comparator, c.right.name(), value
+		return cond, nil
 	}
 
 	comparator := BackwardComparator(c.opType)
 	if !comparator.Valid() {
-		return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, c.op.GetContent(), c.op.GetPosition())
+		return nil, &SyntaxError{Token: c.op}
+	}
+	value, err := c.left.value()
+	if err != nil {
+		return nil, err
 	}
-	return &BackwardComparatorCondition{Comparator: comparator, Property: c.right.name(), Value: c.left.value()}, nil
+	cond := pool.newBackwardComparatorCondition()
+	cond.Comparator, cond.Property, cond.Value = comparator, c.right.name(), value
+	return cond, nil
 }
 
 func (c *backwardComparatorCondition) toUnexpectedTokenError() error {
@@ -83,24 +130,28 @@ type compoundComparatorCondition struct {
 	right conditionAST
 }
 
-func (c *compoundComparatorCondition) toCondition() (Condition, error) {
-	left, err := c.left.toCondition()
+func (c *compoundComparatorCondition) toCondition(pool *Pool) (Condition, error) {
+	left, err := c.left.toCondition(pool)
 	if err != nil {
 		return nil, err
 	}
 
-	right, err := c.right.toCondition()
+	right, err := c.right.toCondition(pool)
 	if err != nil {
 		return nil, err
 	}
 
 	switch c.op.Type {
 	case "AND":
-		return &AndCompoundCondition{Left: left, Right: right}, nil
+		cond := pool.newAndCompoundCondition()
+		cond.Left, cond.Right = left, right
+		return cond, nil
 	case "OR":
-		return &OrCompoundCondition{Left: left, Right: right}, nil
+		cond := pool.newOrCompoundCondition()
+		cond.Left, cond.Right = left, right
+		return cond, nil
 	default:
-		return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, c.op.GetContent(), c.op.GetPosition())
+		return nil, &SyntaxError{Token: c.op}
 	}
 }
 
@@ -127,12 +178,12 @@ func (c *conditionField) token() Token {
 	return c.str
 }
 
-func (c *conditionField) toCondition() (Condition, error) {
+func (c *conditionField) toCondition(pool *Pool) (Condition, error) {
 	return nil, c.toUnexpectedTokenError()
 }
 
 func (c *conditionField) toUnexpectedTokenError() error {
-	return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, c.token().GetContent(), c.token().GetPosition())
+	return &SyntaxError{Token: c.token()}
 }
 
 type conditionValue struct {
@@ -147,53 +198,57 @@ func (c *conditionValue) isNumericValue() bool {
 	return c.n != nil
 }
 
-func (c *conditionValue) token() Token {
+func (c *conditionValue) token() (Token, error) {
 	if c.b != nil {
-		return c.b
+		return c.b, nil
 	}
 	if c.s != nil {
-		return c.s
+		return c.s, nil
 	}
 	if c.n != nil {
-		return c.n
+		return c.n, nil
 	}
 	if c.null != nil {
-		return c.null
+		return c.null, nil
 	}
 	if c.bind != nil {
-		return c.bind
+		return c.bind, nil
 	}
-	panic("every token is nil")
+	return nil, errEmptyConditionValue
 }
 
-func (c *conditionValue) value() any {
+func (c *conditionValue) value() (any, error) {
 	if c.b != nil {
-		return c.b.Value
+		return c.b.Value, nil
 	}
 	if c.s != nil {
-		return c.s.Content
+		return c.s.Content, nil
 	}
 	if c.n != nil {
 		if c.n.Floating {
-			return c.n.Float64
+			return c.n.Float64, nil
 		}
-		return c.n.Int64
+		return c.n.Int64, nil
 	}
 	if c.null != nil {
-		return nil
+		return nil, nil
 	}
 	if c.bind != nil {
-		return parseBindingToken(c.bind)
+		return parseBindingToken(c.bind), nil
 	}
-	panic("every token is nil")
+	return nil, errEmptyConditionValue
 }
 
-func (c *conditionValue) toCondition() (Condition, error) {
+func (c *conditionValue) toCondition(pool *Pool) (Condition, error) {
 	return nil, c.toUnexpectedTokenError()
 }
 
 func (c *conditionValue) toUnexpectedTokenError() error {
-	return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, c.token().GetContent(), c.token().GetPosition())
+	tok, err := c.token()
+	if err != nil {
+		return err
+	}
+	return &SyntaxError{Token: tok}
 }
 
 type conditionKey struct {
@@ -201,37 +256,44 @@ type conditionKey struct {
 	key        *Key
 }
 
-func (c *conditionKey) value() any {
-	return c.key
+func (c *conditionKey) value() (any, error) {
+	return c.key, nil
 }
 
-func (c *conditionKey) toCondition() (Condition, error) {
+func (c *conditionKey) toCondition(pool *Pool) (Condition, error) {
 	return nil, c.toUnexpectedTokenError()
 }
 
 func (c *conditionKey) toUnexpectedTokenError() error {
-	return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, c.keyKeyword.GetContent(), c.keyKeyword.GetPosition())
+	return &SyntaxError{Token: c.keyKeyword}
 }
 
 type conditionArray struct {
-	arrayKeyword *KeywordToken
-	values       []conditionValuer
+	// arrayToken is the ARRAY keyword or the opening parenthesis of the bare value list after IN and NOT IN.
+	arrayToken Token
+	values     []conditionValuer
+	// trailingComma is the comma just before the closing parenthesis if any.
+	trailingComma *OperatorToken
 }
 
-func (c *conditionArray) value() any {
+func (c *conditionArray) value() (any, error) {
 	values := make([]any, len(c.values))
 	for i, v := range c.values {
-		values[i] = v.value()
+		value, err := v.value()
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
 	}
-	return values
+	return values, nil
 }
 
-func (c *conditionArray) toCondition() (Condition, error) {
+func (c *conditionArray) toCondition(pool *Pool) (Condition, error) {
 	return nil, c.toUnexpectedTokenError()
 }
 
 func (c *conditionArray) toUnexpectedTokenError() error {
-	return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, c.arrayKeyword.GetContent(), c.arrayKeyword.GetPosition())
+	return &SyntaxError{Token: c.arrayToken}
 }
 
 type conditionBlob struct {
@@ -239,16 +301,16 @@ type conditionBlob struct {
 	b           []byte
 }
 
-func (c *conditionBlob) value() any {
-	return c.b
+func (c *conditionBlob) value() (any, error) {
+	return c.b, nil
 }
 
-func (c *conditionBlob) toCondition() (Condition, error) {
+func (c *conditionBlob) toCondition(pool *Pool) (Condition, error) {
 	return nil, c.toUnexpectedTokenError()
 }
 
 func (c *conditionBlob) toUnexpectedTokenError() error {
-	return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, c.blobKeyword.GetContent(), c.blobKeyword.GetPosition())
+	return &SyntaxError{Token: c.blobKeyword}
 }
 
 type conditionDateTime struct {
@@ -256,14 +318,14 @@ type conditionDateTime struct {
 	t               time.Time
 }
 
-func (c *conditionDateTime) value() any {
-	return c.t
+func (c *conditionDateTime) value() (any, error) {
+	return c.t, nil
 }
 
-func (c *conditionDateTime) toCondition() (Condition, error) {
+func (c *conditionDateTime) toCondition(pool *Pool) (Condition, error) {
 	return nil, c.toUnexpectedTokenError()
 }
 
 func (c *conditionDateTime) toUnexpectedTokenError() error {
-	return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, c.dateTimeKeyword.GetContent(), c.dateTimeKeyword.GetPosition())
+	return &SyntaxError{Token: c.dateTimeKeyword}
 }
@@ -3,9 +3,36 @@ package gqlparser
 import (
 	"errors"
 	"fmt"
-	"time"
+	"strings"
 )
 
+// DefaultMaxNestingDepth is the default limit of the nesting depth of the conditions.
+const DefaultMaxNestingDepth = 256
+
+var ErrTooDeepNesting = errors.New("too deep nesting")
+
+// conditionTrailingKeywords are the operators and the keywords of the clauses that may follow the operands of the conditions.
+// They're expected at the unexpected symbol to suggest the misspelled ones. e.g. a = 1 ADN b = 2, a = 1 ODRER BY a
+var conditionTrailingKeywords = []string{"AND", "OR", "ORDER", "LIMIT", "OFFSET"}
+
+// nestingLimiter limits the nesting depth of the conditions to prevent the stack exhaustion.
+type nestingLimiter struct {
+	max   int
+	depth int
+}
+
+func (l *nestingLimiter) enter(tok Token) error {
+	l.depth++
+	if l.max > 0 && l.depth > l.max {
+		return fmt.Errorf("%w: %s at %d (exceeds %d)", ErrTooDeepNesting, tok.GetContent(), tok.GetPosition(), l.max)
+	}
+	return nil
+}
+
+func (l *nestingLimiter) leave() {
+	l.depth--
+}
+
 var infixCompoundOperatorBindingPowerMap = map[string]uint8{
 	"AND": 2,
 	"OR":  1,
@@ -52,7 +79,8 @@ var specialOpMap = map[string]map[string]string{
 	},
 }
 
-func constructAST(tr tokenReader, minBP uint8) (conditionAST, error) {
+// constructAST parses the condition by the binding powers of the operators. The dialect enables the extension operators if any.
+func constructAST(tr tokenReader, minBP uint8, limiter *nestingLimiter, dialect *Dialect) (conditionAST, error) {
 	tok, err := tr.Read()
 	if errors.Is(err, ErrEndOfToken) {
 		return nil, ErrNoTokens
@@ -60,6 +88,11 @@ func constructAST(tr tokenReader, minBP uint8) (conditionAST, error) {
 		return nil, err
 	}
 
+	if err := limiter.enter(tok); err != nil {
+		return nil, err
+	}
+	defer limiter.leave()
+
 	var left conditionAST
 	switch v := tok.(type) {
 	case *SymbolToken:
@@ -77,46 +110,46 @@ func constructAST(tr tokenReader, minBP uint8) (conditionAST, error) {
 	case *BindingToken:
 		left = &conditionValue{bind: v}
 	case *OperatorToken:
-		left, err = parseGroupedCondition(tr, v)
+		left, err = parseGroupedCondition(tr, v, limiter, dialect)
 		if err != nil {
 			return nil, err
 		}
 	case *KeywordToken:
 		switch v.Name {
 		case "KEY":
-			var key Key
-			if err := acceptKeyBody(&key).accept(tr); err != nil {
+			key, err := parseKeyBody(tr)
+			if err != nil {
 				return nil, err
 			}
-			left = &conditionKey{keyKeyword: v, key: &key}
+			left = &conditionKey{keyKeyword: v, key: key}
 		case "ARRAY":
-			var values []conditionValuer
-			if err := acceptArrayBody(&values).accept(tr); err != nil {
+			array := &conditionArray{arrayToken: v}
+			if err := parseArrayBody(tr, array, limiter); err != nil {
 				return nil, err
 			}
-			left = &conditionArray{arrayKeyword: v, values: values}
+			left = array
 		case "BLOB":
-			var b []byte
-			if err := acceptBlobBody(&b).accept(tr); err != nil {
+			b, err := parseBlobBody(tr)
+			if err != nil {
 				return nil, err
 			}
 			left = &conditionBlob{blobKeyword: v, b: b}
 		case "DATETIME":
-			var t time.Time
-			if err := acceptDateTimeBody(&t).accept(tr); err != nil {
+			t, err := parseDateTimeBody(tr)
+			if err != nil {
 				return nil, err
 			}
 			left = &conditionDateTime{dateTimeKeyword: v, t: t}
 		case "NULL":
 			left = &conditionValue{null: v}
 		default:
-			return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, tok.GetContent(), tok.GetPosition())
+			return nil, &SyntaxError{Token: tok}
 		}
 	default:
-		return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, tok.GetContent(), tok.GetPosition())
+		return nil, &SyntaxError{Token: tok}
 	}
 
-	rtr := asResettableTokenReader(tr)
+	rtr, offset := markTokenReader(tr)
 	for {
 		if err := skipWhitespaceToken.accept(rtr); err != nil {
 			return nil, err
@@ -131,11 +164,19 @@ func constructAST(tr tokenReader, minBP uint8) (conditionAST, error) {
 
 		op, isOP := tok.(*OperatorToken)
 		if !isOP {
-			if _, isKeyword := tok.(*KeywordToken); isKeyword {
-				rtr.Reset()
+			switch t := tok.(type) {
+			case *KeywordToken:
+				// the following clause. e.g. ORDER BY
+				rtr.resetTo(offset)
 				return left, nil
+			case *SymbolToken:
+				if dialect.beginsClause(t) {
+					// the following clause of the dialect. e.g. GROUP BY
+					rtr.resetTo(offset)
+					return left, nil
+				}
 			}
-			return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, tok.GetContent(), tok.GetPosition())
+			return nil, &SyntaxError{Token: tok, Expected: conditionTrailingKeywords}
 		}
 
 		typ := op.Type
@@ -146,19 +187,27 @@ func constructAST(tr tokenReader, minBP uint8) (conditionAST, error) {
 
 			nextToken, err := rtr.Read()
 			if errors.Is(err, ErrEndOfToken) {
-				return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, tok.GetContent(), tok.GetPosition())
+				return nil, &SyntaxError{Token: tok}
 			} else if err != nil {
 				return nil, err
 			}
 
 			nextOP, isOP := nextToken.(*OperatorToken)
 			if !isOP {
-				return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, nextToken.GetContent(), nextToken.GetPosition())
+				return nil, &SyntaxError{Token: nextToken}
 			}
 
 			typ = m[nextOP.Type]
 			if typ == "" {
-				return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, nextToken.GetContent(), nextToken.GetPosition())
+				return nil, &SyntaxError{Token: nextToken}
+			}
+		}
+
+		var quantifier *SymbolToken
+		if typ == "CONTAINS" && dialect != nil && dialect.containsQuantifiers {
+			quantifier, err = acceptContainsQuantifier(rtr)
+			if err != nil {
+				return nil, err
 			}
 		}
 
@@ -191,22 +240,31 @@ func constructAST(tr tokenReader, minBP uint8) (conditionAST, error) {
 			} else if allowBackwardOP {
 				bp = infixBackwardOperatorBindingPowerMap[typ]
 			} else {
-				panic("broken pattern")
+				return nil, &SyntaxError{Token: tok}
 			}
 		}
 		if bp == 0 || bp < minBP {
-			rtr.Reset()
+			rtr.resetTo(offset)
 			return left, nil
 		}
 
-		right, err := constructAST(tr, bp+1)
+		var right conditionAST
+		if allowForwardOP && (typ == "IN" || typ == "NOT IN" || quantifier != nil) {
+			right, err = parseParenthesizedArray(tr, limiter)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if right == nil {
+			right, err = constructAST(tr, bp+1, limiter, dialect)
+		}
 		if errors.Is(err, ErrEndOfToken) {
 			// ok: ignore it
 		} else if err != nil {
 			return nil, err
 		}
 		if right == nil {
-			return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, tok.GetContent(), tok.GetPosition())
+			return nil, &SyntaxError{Token: tok}
 		}
 
 		if isEitherOP {
@@ -237,7 +295,7 @@ func constructAST(tr tokenReader, minBP uint8) (conditionAST, error) {
 			if !isValue {
 				return nil, right.toUnexpectedTokenError()
 			}
-			left = &forwardComparatorCondition{left: fv, op: op, opType: typ, right: cv}
+			left = &forwardComparatorCondition{left: fv, op: op, opType: typ, quantifier: quantifier, right: cv}
 		} else if allowBackwardOP {
 			cv, isValue := left.(conditionValuer)
 			if !isValue {
@@ -250,28 +308,28 @@ func constructAST(tr tokenReader, minBP uint8) (conditionAST, error) {
 			}
 			left = &backwardComparatorCondition{left: cv, op: op, opType: typ, right: fv}
 		} else {
-			panic("broken pattern")
+			return nil, &SyntaxError{Token: tok}
 		}
 
-		rtr = asResettableTokenReader(tr) // new offset
+		rtr, offset = markTokenReader(tr) // new offset
 		if err := skipWhitespaceToken.accept(rtr); err != nil {
 			return nil, err
 		}
 	}
 }
 
-func parseGroupedCondition(tr tokenReader, op *OperatorToken) (conditionAST, error) {
+func parseGroupedCondition(tr tokenReader, op *OperatorToken, limiter *nestingLimiter, dialect *Dialect) (conditionAST, error) {
 	if op.Type != "(" {
-		return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, op.GetContent(), op.GetPosition())
+		return nil, &SyntaxError{Token: op}
 	}
 
 	if err := skipWhitespaceToken.accept(tr); err != nil {
 		return nil, err
 	}
 
-	children, err := constructAST(tr, 0)
+	children, err := constructAST(tr, 0, limiter, dialect)
 	if errors.Is(err, ErrEndOfToken) {
-		return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, op.GetContent(), op.GetPosition())
+		return nil, &SyntaxError{Token: op}
 	} else if err != nil {
 		return nil, err
 	}
@@ -282,84 +340,127 @@ func parseGroupedCondition(tr tokenReader, op *OperatorToken) (conditionAST, err
 
 	nextToken, err := tr.Read()
 	if errors.Is(err, ErrEndOfToken) {
-		return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, op.GetContent(), op.GetPosition())
+		return nil, &SyntaxError{Token: op}
 	} else if err != nil {
 		return nil, err
 	}
 
 	if t, isOp := nextToken.(*OperatorToken); !isOp {
-		return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, op.GetContent(), op.GetPosition())
+		return nil, &SyntaxError{Token: op}
 	} else if t.Type != ")" {
-		return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, op.GetContent(), op.GetPosition())
+		return nil, &SyntaxError{Token: op}
 	}
 
 	return children, nil
 }
 
-func acceptConditionValue(result *conditionValuer) tokenAcceptor {
-	return tokenAcceptorFn(func(tr tokenReader) error {
-		tok, err := tr.Read()
-		if errors.Is(err, ErrEndOfToken) {
-			return ErrNoTokens
-		} else if err != nil {
-			return err
+// acceptContainsQuantifier accepts ANY or ALL following CONTAINS. They aren't reserved, so they're read as the symbols.
+// It returns nil without consuming any tokens if neither of them follows.
+func acceptContainsQuantifier(tr tokenReader) (*SymbolToken, error) {
+	rtr, offset := markTokenReader(tr)
+	if err := acceptWhitespaceToken.accept(rtr); errors.Is(err, ErrUnexpectedToken) || errors.Is(err, ErrNoTokens) {
+		rtr.resetTo(offset)
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	tok, err := rtr.Read()
+	if errors.Is(err, ErrEndOfToken) {
+		rtr.resetTo(offset)
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if sym, ok := tok.(*SymbolToken); ok && (strings.EqualFold(sym.Content, "ANY") || strings.EqualFold(sym.Content, "ALL")) {
+		return sym, nil
+	}
+	rtr.resetTo(offset)
+	return nil, nil
+}
+
+// parseParenthesizedArray parses the bare parenthesized value list after IN and NOT IN as the array like ARRAY(...).
+// It returns nil without consuming any tokens if the next token isn't the opening parenthesis.
+func parseParenthesizedArray(tr tokenReader, limiter *nestingLimiter) (conditionAST, error) {
+	tok, err := peekToken(tr)
+	if errors.Is(err, ErrEndOfToken) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	op, isOP := tok.(*OperatorToken)
+	if !isOP || op.Type != "(" {
+		return nil, nil
+	}
+
+	if err := limiter.enter(op); err != nil {
+		return nil, err
+	}
+	defer limiter.leave()
+
+	array := &conditionArray{arrayToken: op}
+	if err := parseArrayBody(tr, array, limiter); err != nil {
+		return nil, err
+	}
+	return array, nil
+}
+
+// parseConditionValue parses the value of the array element.
+func parseConditionValue(tr tokenReader, limiter *nestingLimiter) (conditionValuer, error) {
+	tok, err := tr.Read()
+	if errors.Is(err, ErrEndOfToken) {
+		return nil, ErrNoTokens
+	} else if err != nil {
+		return nil, err
+	}
+
+	switch v := tok.(type) {
+	case *BooleanToken:
+		return &conditionValue{b: v}, nil
+	case *StringToken:
+		if v.Quote == '`' {
+			return nil, &SyntaxError{Token: tok}
 		}
+		return &conditionValue{s: v}, nil
+	case *NumericToken:
+		return &conditionValue{n: v}, nil
+	case *BindingToken:
+		return &conditionValue{bind: v}, nil
+	case *KeywordToken:
+		switch v.Name {
+		case "KEY":
+			key, err := parseKeyBody(tr)
+			if err != nil {
+				return nil, err
+			}
+			return &conditionKey{keyKeyword: v, key: key}, nil
+		case "ARRAY":
+			if err := limiter.enter(v); err != nil {
+				return nil, err
+			}
+			defer limiter.leave()
 
-		switch v := tok.(type) {
-		case *BooleanToken:
-			*result = &conditionValue{b: v}
-			return nil
-		case *StringToken:
-			if v.Quote == '`' {
-				return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, tok.GetContent(), tok.GetPosition())
-			} else {
-				*result = &conditionValue{s: v}
-				return nil
+			array := &conditionArray{arrayToken: v}
+			if err := parseArrayBody(tr, array, limiter); err != nil {
+				return nil, err
 			}
-		case *NumericToken:
-			*result = &conditionValue{n: v}
-			return nil
-		case *BindingToken:
-			*result = &conditionValue{bind: v}
-			return nil
-		case *KeywordToken:
-			switch v.Name {
-			case "KEY":
-				var key Key
-				if err := acceptKeyBody(&key).accept(tr); err != nil {
-					return err
-				}
-				*result = &conditionKey{keyKeyword: v, key: &key}
-				return nil
-			case "ARRAY":
-				var values []conditionValuer
-				if err := acceptArrayBody(&values).accept(tr); err != nil {
-					return err
-				}
-				*result = &conditionArray{arrayKeyword: v, values: values}
-				return nil
-			case "BLOB":
-				var b []byte
-				if err := acceptBlobBody(&b).accept(tr); err != nil {
-					return err
-				}
-				*result = &conditionBlob{blobKeyword: v, b: b}
-				return nil
-			case "DATETIME":
-				var t time.Time
-				if err := acceptDateTimeBody(&t).accept(tr); err != nil {
-					return err
-				}
-				*result = &conditionDateTime{dateTimeKeyword: v, t: t}
-				return nil
-			case "NULL":
-				*result = &conditionValue{null: v}
-				return nil
-			default:
-				return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, tok.GetContent(), tok.GetPosition())
+			return array, nil
+		case "BLOB":
+			b, err := parseBlobBody(tr)
+			if err != nil {
+				return nil, err
 			}
-		default:
-			return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, tok.GetContent(), tok.GetPosition())
+			return &conditionBlob{blobKeyword: v, b: b}, nil
+		case "DATETIME":
+			t, err := parseDateTimeBody(tr)
+			if err != nil {
+				return nil, err
+			}
+			return &conditionDateTime{dateTimeKeyword: v, t: t}, nil
+		case "NULL":
+			return &conditionValue{null: v}, nil
 		}
-	})
+	}
+	return nil, &SyntaxError{Token: tok}
 }
@@ -0,0 +1,38 @@
+package gqlparser
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+var cursorEncodingReplacer = strings.NewReplacer("-", "+", "_", "/")
+
+// ParseCursor validates the base64-encoded cursor. e.g. the content of CURSOR('Cg0SB2tleS0xMDAY')
+// Both of the standard and the URL-safe encodings are accepted with or without the paddings.
+func ParseCursor(s string) (Cursor, error) {
+	if s == "" {
+		return "", fmt.Errorf("%w: empty", ErrInvalidCursor)
+	}
+	if _, err := Cursor(s).Bytes(); err != nil {
+		return "", err
+	}
+	return Cursor(s), nil
+}
+
+// Bytes decodes the cursor.
+func (c Cursor) Bytes() ([]byte, error) {
+	b, err := base64.RawStdEncoding.DecodeString(cursorEncodingReplacer.Replace(strings.TrimRight(string(c), "=")))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	return b, nil
+}
+
+// resolveBy makes the cursor literal usable as Limit.Cursor and Offset.Cursor. It's resolved into itself.
+func (c Cursor) resolveBy(*BindingResolver) (any, error) {
+	return c, nil
+}
@@ -0,0 +1,138 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestParseCursor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		source  string
+		want    []byte
+		wantErr bool
+	}{
+		{"Standard", "AQID/w==", []byte{1, 2, 3, 255}, false},
+		{"URLSafe", "AQID_w", []byte{1, 2, 3, 255}, false},
+		{"Empty", "", nil, true},
+		{"Invalid", "!!!", nil, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := gqlparser.ParseCursor(tt.source)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCursor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, gqlparser.ErrInvalidCursor) {
+					t.Errorf("ParseCursor() error = %v, want %v", err, gqlparser.ErrInvalidCursor)
+				}
+				return
+			}
+
+			got, err := cursor.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithCursorLiterals(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		source  string
+		want    *gqlparser.Query
+		wantErr bool
+	}{
+		{
+			name:   "LimitFirst",
+			source: "SELECT * FROM Kind LIMIT FIRST(10, CURSOR('AQID'))",
+			want: &gqlparser.Query{
+				Kind:  "Kind",
+				Limit: &gqlparser.Limit{Position: 10, Cursor: gqlparser.Cursor("AQID")},
+			},
+		},
+		{
+			name:   "LimitFirstCursorFirst",
+			source: "SELECT * FROM Kind LIMIT FIRST(cursor ( 'AQID' ), 10)",
+			want: &gqlparser.Query{
+				Kind:  "Kind",
+				Limit: &gqlparser.Limit{Position: 10, Cursor: gqlparser.Cursor("AQID")},
+			},
+		},
+		{
+			name:   "Offset",
+			source: "SELECT * FROM Kind OFFSET CURSOR('AQID') + 5",
+			want: &gqlparser.Query{
+				Kind:   "Kind",
+				Offset: &gqlparser.Offset{Position: 5, Cursor: gqlparser.Cursor("AQID")},
+			},
+		},
+		{
+			name:   "CursorAsProperty",
+			source: "SELECT cursor FROM Kind WHERE cursor = 1",
+			want: &gqlparser.Query{
+				Properties: []gqlparser.Property{"cursor"},
+				Kind:       "Kind",
+				Where: &gqlparser.EitherComparatorCondition{
+					Comparator: gqlparser.EqualsEitherComparator,
+					Property:   "cursor",
+					Value:      int64(1),
+				},
+			},
+		},
+		{
+			name:    "TwoCursors",
+			source:  "SELECT * FROM Kind LIMIT FIRST(CURSOR('AQID'), CURSOR('AQID'))",
+			wantErr: true,
+		},
+		{
+			name:    "InvalidCursor",
+			source:  "SELECT * FROM Kind OFFSET CURSOR('!!!')",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source), gqlparser.WithCursorLiterals())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM Kind OFFSET CURSOR('AQID')")); !errors.Is(err, gqlparser.ErrUnexpectedToken) {
+		t.Errorf("ParseQuery() error = %v, want %v without the option", err, gqlparser.ErrUnexpectedToken)
+	}
+
+	resolved, err := (&gqlparser.BindingResolver{}).Resolve(gqlparser.Cursor("AQID"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved != gqlparser.Cursor("AQID") {
+		t.Errorf("Resolve() = %v, want the cursor itself", resolved)
+	}
+}
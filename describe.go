@@ -0,0 +1,108 @@
+package gqlparser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Describe renders the syntax as the indented tree for debugging like go/ast.Print. e.g.
+//
+//	*gqlparser.Query {
+//	  Kind: "Kind"
+//	  Where: *gqlparser.EitherComparatorCondition {
+//	    Comparator: "="
+//	    Property: "a"
+//	    Value: int64(1)
+//	  }
+//	}
+//
+// The fields of the zero values are omitted. The values in the interfaces are rendered with the types
+// unless they are strings or booleans to distinguish the integers and the doubles.
+func Describe(s Syntax) string {
+	var sb strings.Builder
+	describeValue(&sb, reflect.ValueOf(s), 0)
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+func describeValue(sb *strings.Builder, v reflect.Value, depth int) {
+	indent := strings.Repeat("  ", depth+1)
+	switch v.Kind() {
+	case reflect.Invalid:
+		sb.WriteString("nil")
+	case reflect.Interface:
+		if v.IsNil() {
+			sb.WriteString("nil")
+			return
+		}
+		elem := v.Elem()
+		switch elem.Kind() {
+		case reflect.Pointer, reflect.Struct, reflect.Slice, reflect.Map, reflect.Interface:
+			describeValue(sb, elem, depth)
+		default:
+			if elem.Type() == reflect.TypeOf("") || elem.Type() == reflect.TypeOf(false) {
+				describeValue(sb, elem, depth)
+				return
+			}
+			sb.WriteString(elem.Type().String())
+			sb.WriteString("(")
+			describeValue(sb, elem, depth)
+			sb.WriteString(")")
+		}
+	case reflect.Pointer:
+		if v.IsNil() {
+			sb.WriteString("nil")
+			return
+		}
+		sb.WriteString("*")
+		describeValue(sb, v.Elem(), depth)
+	case reflect.Struct:
+		if v.Type() == timeType {
+			sb.WriteString(v.Type().String())
+			sb.WriteString("(")
+			sb.WriteString(v.Interface().(time.Time).Format(time.RFC3339Nano))
+			sb.WriteString(")")
+			return
+		}
+		sb.WriteString(v.Type().String())
+		sb.WriteString(" {\n")
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() || v.Field(i).IsZero() {
+				continue
+			}
+			sb.WriteString(indent)
+			sb.WriteString(field.Name)
+			sb.WriteString(": ")
+			describeValue(sb, v.Field(i), depth+1)
+			sb.WriteString("\n")
+		}
+		sb.WriteString(indent[2:])
+		sb.WriteString("}")
+	case reflect.Slice:
+		if v.IsNil() {
+			sb.WriteString("nil")
+			return
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			fmt.Fprintf(sb, "[]byte(%q)", v.Bytes())
+			return
+		}
+		fmt.Fprintf(sb, "%s (len = %d) {\n", v.Type(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			fmt.Fprintf(sb, "%s%d: ", indent, i)
+			describeValue(sb, v.Index(i), depth+1)
+			sb.WriteString("\n")
+		}
+		sb.WriteString(indent[2:])
+		sb.WriteString("}")
+	case reflect.String:
+		fmt.Fprintf(sb, "%q", v.String())
+	default:
+		fmt.Fprint(sb, v.Interface())
+	}
+}
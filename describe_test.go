@@ -0,0 +1,101 @@
+package gqlparser_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestDescribe(t *testing.T) {
+	t.Parallel()
+
+	query, err := gqlparser.ParseQuery(gqlparser.NewLexer(
+		"SELECT a FROM Kind WHERE a = 1 AND b IN ARRAY('x', 1.5, NULL, BLOB('YQ'), DATETIME('2013-09-29T09:30:20.00002-08:00'))" +
+			" AND __key__ HAS ANCESTOR KEY(Parent, 'p') ORDER BY a DESC LIMIT @1",
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `*gqlparser.Query {
+  Properties: []gqlparser.Property (len = 1) {
+    0: "a"
+  }
+  Kind: "Kind"
+  Where: *gqlparser.AndCompoundCondition {
+    Left: *gqlparser.AndCompoundCondition {
+      Left: *gqlparser.EitherComparatorCondition {
+        Comparator: "="
+        Property: "a"
+        Value: int64(1)
+      }
+      Right: *gqlparser.ForwardComparatorCondition {
+        Comparator: "IN"
+        Property: "b"
+        Value: []interface {} (len = 5) {
+          0: "x"
+          1: float64(1.5)
+          2: nil
+          3: []byte("a")
+          4: time.Time(2013-09-29T09:30:20.00002-08:00)
+        }
+      }
+    }
+    Right: *gqlparser.ForwardComparatorCondition {
+      Comparator: "HAS ANCESTOR"
+      Property: "__key__"
+      Value: *gqlparser.Key {
+        Path: []*gqlparser.KeyPath (len = 1) {
+          0: *gqlparser.KeyPath {
+            Kind: "Parent"
+            Name: "p"
+          }
+        }
+      }
+    }
+  }
+  OrderBy: []gqlparser.OrderBy (len = 1) {
+    0: gqlparser.OrderBy {
+      Descending: true
+      Property: "a"
+    }
+  }
+  Limit: *gqlparser.Limit {
+    Cursor: *gqlparser.IndexedBinding {
+      Index: 1
+    }
+  }
+}
+`
+	if diff := cmp.Diff(want, gqlparser.Describe(query)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestDescribe_Aggregation(t *testing.T) {
+	t.Parallel()
+
+	query, err := gqlparser.ParseAggregationQuery(gqlparser.NewLexer("AGGREGATE COUNT(*) AS total OVER (SELECT * FROM Kind)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `*gqlparser.AggregationQuery {
+  Aggregations: []gqlparser.Aggregation (len = 1) {
+    0: *gqlparser.CountAggregation {
+      Alias: "total"
+    }
+  }
+  Query: gqlparser.Query {
+    Kind: "Kind"
+  }
+}
+`
+	if diff := cmp.Diff(want, gqlparser.Describe(query)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff("nil\n", gqlparser.Describe(nil)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,121 @@
+package gqlparser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/karupanerura/runetrie"
+)
+
+var ErrInvalidDialect = errors.New("invalid dialect")
+
+// Dialect is the customization of the syntax for the query languages derived from GQL.
+type Dialect struct {
+	keywordAliases map[string]keywordAlias
+	aliasTrie      *runetrie.Trie[string]
+	groupBy        bool
+	having         bool
+	// containsQuantifiers permits CONTAINS ANY and CONTAINS ALL.
+	containsQuantifiers bool
+	// capabilities are the features supported by the backend. All the features are supported if nil.
+	capabilities *DialectCapabilities
+}
+
+type keywordAlias struct {
+	canonical string
+	// trie is the trie of the canonical keyword to determine the token type.
+	trie *runetrie.Trie[string]
+}
+
+// DialectOption configures the Dialect.
+type DialectOption func(*Dialect) error
+
+// NewDialect creates the Dialect with the options.
+func NewDialect(opts ...DialectOption) (*Dialect, error) {
+	d := &Dialect{keywordAliases: map[string]keywordAlias{}}
+	for _, opt := range opts {
+		if err := opt(d); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(d.keywordAliases) != 0 {
+		d.aliasTrie = runetrie.Must(runetrie.NewCaseInsensitiveTrie[string]())
+		for alias := range d.keywordAliases {
+			_ = d.aliasTrie.Add(alias)
+		}
+	}
+	return d, nil
+}
+
+// WithKeywordAlias permits the alternate spelling of the keyword. e.g. WithKeywordAlias("SKIP", "OFFSET")
+// The alias is lexed as the canonical keyword case-insensitively, so the parsed AST is the same as the canonical one.
+// The canonical keyword must be a single word, including the operators (e.g. AND), the orders and the booleans.
+func WithKeywordAlias(alias, canonical string) DialectOption {
+	return func(d *Dialect) error {
+		alias, canonical = strings.ToUpper(alias), strings.ToUpper(canonical)
+		if alias == "" || '0' <= alias[0] && alias[0] <= '9' {
+			return fmt.Errorf("%w: alias %q must be a symbol", ErrInvalidDialect, alias)
+		}
+		for i := 0; i < len(alias); i++ {
+			if !isSymbolByte(alias[i]) {
+				return fmt.Errorf("%w: alias %q must be a symbol", ErrInvalidDialect, alias)
+			}
+		}
+
+		for _, trie := range []*runetrie.Trie[string]{keywordTrie, operatorTrie, orderTrie, booleanTrie} {
+			if m, ok := trie.LongestMatchPrefixOf(canonical); ok && m == canonical {
+				d.keywordAliases[alias] = keywordAlias{canonical: canonical, trie: trie}
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: unknown keyword %q", ErrInvalidDialect, canonical)
+	}
+}
+
+// WithGroupBy permits the GROUP BY clause after WHERE. e.g. SELECT a FROM Kind GROUP BY a
+// The properties are kept as Query.GroupBy. GROUP isn't reserved by this option.
+// The dialect must be passed to the parser by WithDialect, and the clause is rejected in strict mode.
+func WithGroupBy() DialectOption {
+	return func(d *Dialect) error {
+		d.groupBy = true
+		return nil
+	}
+}
+
+// WithHaving permits the HAVING clause of the aggregation queries. e.g. AGGREGATE COUNT(*) AS c OVER (SELECT * FROM Kind) HAVING c > 1
+// The condition is kept as AggregationQuery.Having. HAVING isn't reserved by this option.
+// The dialect must be passed to the parser by WithDialect, and the clause is rejected in strict mode.
+func WithHaving() DialectOption {
+	return func(d *Dialect) error {
+		d.having = true
+		return nil
+	}
+}
+
+// beginsClause reports whether the symbol begins the clause permitted by the dialect. e.g. GROUP of GROUP BY
+func (d *Dialect) beginsClause(token *SymbolToken) bool {
+	if d == nil {
+		return false
+	}
+	return d.groupBy && strings.EqualFold(token.Content, "GROUP") || d.having && strings.EqualFold(token.Content, "HAVING")
+}
+
+// WithContainsQuantifiers permits CONTAINS ANY and CONTAINS ALL with the array. e.g. tags CONTAINS ANY ARRAY('a', 'b')
+// They're kept as QuantifiedComparatorCondition. ANY and ALL aren't reserved by this option.
+// The dialect must be passed to the parser by WithDialect, and they're rewritten into OR and AND of CONTAINS by
+// QuantifiedComparatorCondition.Expand in strict mode, which requires the non-empty array literal.
+func WithContainsQuantifiers() DialectOption {
+	return func(d *Dialect) error {
+		d.containsQuantifiers = true
+		return nil
+	}
+}
+
+// NewDialectLexer creates the Lexer for the dialect.
+func NewDialectLexer(source string, dialect *Dialect, opts ...LexerOption) *Lexer {
+	l := NewLexer(source, opts...)
+	l.dialect = dialect
+	return l
+}
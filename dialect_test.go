@@ -0,0 +1,444 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestNewDialectLexer(t *testing.T) {
+	t.Parallel()
+
+	dialect, err := gqlparser.NewDialect(
+		gqlparser.WithKeywordAlias("SKIP", "OFFSET"),
+		gqlparser.WithKeywordAlias("take", "limit"),
+		gqlparser.WithKeywordAlias("ASCENDING", "ASC"),
+		gqlparser.WithKeywordAlias("ALSO", "AND"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := gqlparser.ParseQuery(gqlparser.NewDialectLexer("SELECT * FROM Kind WHERE a = 1 also b = 2 ORDER BY a ascending TAKE 10 skip 5", dialect))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM Kind WHERE a = 1 AND b = 2 ORDER BY a ASC LIMIT 10 OFFSET 5"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	// the aliases are not the keywords of the standard lexer
+	if _, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM Kind SKIP 5")); err == nil {
+		t.Error("ParseQuery() error = nil for the alias without the dialect")
+	}
+}
+
+func TestNewDialect_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, opt := range []gqlparser.DialectOption{
+		gqlparser.WithKeywordAlias("SKIP", "UNKNOWN"),
+		gqlparser.WithKeywordAlias("SKIP", "HAS ANCESTOR"),
+		gqlparser.WithKeywordAlias("", "OFFSET"),
+		gqlparser.WithKeywordAlias("1SKIP", "OFFSET"),
+		gqlparser.WithKeywordAlias("SK IP", "OFFSET"),
+		gqlparser.WithCapabilities(gqlparser.DialectCapabilities{MaxInSize: -1}),
+	} {
+		if _, err := gqlparser.NewDialect(opt); !errors.Is(err, gqlparser.ErrInvalidDialect) {
+			t.Errorf("NewDialect() error = %v, want %v", err, gqlparser.ErrInvalidDialect)
+		}
+	}
+}
+
+func TestWithGroupBy(t *testing.T) {
+	t.Parallel()
+
+	dialect, err := gqlparser.NewDialect(gqlparser.WithGroupBy())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		opts    []gqlparser.ParseOption
+		want    *gqlparser.Query
+		wantErr bool
+	}{
+		{
+			name:   "GroupBy",
+			source: "SELECT a, b FROM Kind WHERE c = 1 group by a, `b` ORDER BY a",
+			opts:   []gqlparser.ParseOption{gqlparser.WithDialect(dialect)},
+			want: &gqlparser.Query{
+				Properties: []gqlparser.Property{"a", "b"},
+				Kind:       "Kind",
+				Where:      &gqlparser.EitherComparatorCondition{Comparator: gqlparser.EqualsEitherComparator, Property: "c", Value: int64(1)},
+				GroupBy:    []gqlparser.Property{"a", "b"},
+				OrderBy:    []gqlparser.OrderBy{{Property: "a"}},
+			},
+		},
+		{
+			name:   "WithoutWhere",
+			source: "SELECT a FROM Kind GROUP BY a LIMIT 1",
+			opts:   []gqlparser.ParseOption{gqlparser.WithDialect(dialect)},
+			want: &gqlparser.Query{
+				Properties: []gqlparser.Property{"a"},
+				Kind:       "Kind",
+				GroupBy:    []gqlparser.Property{"a"},
+				Limit:      &gqlparser.Limit{Position: 1},
+			},
+		},
+		{
+			name:    "WithoutDialect",
+			source:  "SELECT a FROM Kind GROUP BY a",
+			wantErr: true,
+		},
+		{
+			name:    "StrictMode",
+			source:  "SELECT a FROM Kind GROUP BY a",
+			opts:    []gqlparser.ParseOption{gqlparser.WithDialect(dialect), gqlparser.WithStrictMode()},
+			wantErr: true,
+		},
+		{
+			name:    "NoProperties",
+			source:  "SELECT a FROM Kind GROUP BY ORDER BY a",
+			opts:    []gqlparser.ParseOption{gqlparser.WithDialect(dialect)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source), tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithHaving(t *testing.T) {
+	t.Parallel()
+
+	dialect, err := gqlparser.NewDialect(gqlparser.WithGroupBy(), gqlparser.WithHaving())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		opts    []gqlparser.ParseOption
+		want    *gqlparser.AggregationQuery
+		wantErr bool
+	}{
+		{
+			name:   "SelectSyntax",
+			source: "SELECT COUNT(*) AS c FROM Kind WHERE a = 1 GROUP BY b HAVING c > 1",
+			opts:   []gqlparser.ParseOption{gqlparser.WithDialect(dialect)},
+			want: &gqlparser.AggregationQuery{
+				Aggregations: []gqlparser.Aggregation{&gqlparser.CountAggregation{Alias: "c"}},
+				Query: gqlparser.Query{
+					Kind:    "Kind",
+					Where:   &gqlparser.EitherComparatorCondition{Comparator: gqlparser.EqualsEitherComparator, Property: "a", Value: int64(1)},
+					GroupBy: []gqlparser.Property{"b"},
+				},
+				Having: &gqlparser.EitherComparatorCondition{Comparator: gqlparser.GreaterThanEitherComparator, Property: "c", Value: int64(1)},
+			},
+		},
+		{
+			name:   "AggregateSyntax",
+			source: "AGGREGATE SUM(a) AS s OVER (SELECT * FROM Kind GROUP BY b) having s >= 10 AND s < 100;",
+			opts:   []gqlparser.ParseOption{gqlparser.WithDialect(dialect)},
+			want: &gqlparser.AggregationQuery{
+				Aggregations: []gqlparser.Aggregation{&gqlparser.SumAggregation{Property: "a", Alias: "s"}},
+				Query:        gqlparser.Query{Kind: "Kind", GroupBy: []gqlparser.Property{"b"}},
+				Having: &gqlparser.AndCompoundCondition{
+					Left:  &gqlparser.EitherComparatorCondition{Comparator: gqlparser.GreaterThanOrEqualsThanEitherComparator, Property: "s", Value: int64(10)},
+					Right: &gqlparser.EitherComparatorCondition{Comparator: gqlparser.LesserThanEitherComparator, Property: "s", Value: int64(100)},
+				},
+			},
+		},
+		{
+			name:    "WithoutDialect",
+			source:  "SELECT COUNT(*) AS c FROM Kind HAVING c > 1",
+			wantErr: true,
+		},
+		{
+			name:    "StrictMode",
+			source:  "SELECT COUNT(*) AS c FROM Kind HAVING c > 1",
+			opts:    []gqlparser.ParseOption{gqlparser.WithDialect(dialect), gqlparser.WithStrictMode()},
+			wantErr: true,
+		},
+		{
+			name:    "NoCondition",
+			source:  "SELECT COUNT(*) AS c FROM Kind HAVING",
+			opts:    []gqlparser.ParseOption{gqlparser.WithDialect(dialect)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := gqlparser.ParseAggregationQuery(gqlparser.NewLexer(tt.source), tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAggregationQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithContainsQuantifiers(t *testing.T) {
+	t.Parallel()
+
+	dialect, err := gqlparser.NewDialect(gqlparser.WithContainsQuantifiers())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		opts    []gqlparser.ParseOption
+		want    gqlparser.Condition
+		wantErr bool
+	}{
+		{
+			name:   "Any",
+			source: "tags CONTAINS ANY ARRAY('a', 'b') AND a = 1",
+			opts:   []gqlparser.ParseOption{gqlparser.WithDialect(dialect)},
+			want: &gqlparser.AndCompoundCondition{
+				Left:  &gqlparser.QuantifiedComparatorCondition{Comparator: gqlparser.ContainsAnyQuantifiedComparator, Property: "tags", Value: []any{"a", "b"}},
+				Right: &gqlparser.EitherComparatorCondition{Comparator: gqlparser.EqualsEitherComparator, Property: "a", Value: int64(1)},
+			},
+		},
+		{
+			name:   "All",
+			source: "tags contains all ('a', 'b') OR tags CONTAINS ANY @tags",
+			opts:   []gqlparser.ParseOption{gqlparser.WithDialect(dialect)},
+			want: &gqlparser.OrCompoundCondition{
+				Left:  &gqlparser.QuantifiedComparatorCondition{Comparator: gqlparser.ContainsAllQuantifiedComparator, Property: "tags", Value: []any{"a", "b"}},
+				Right: &gqlparser.QuantifiedComparatorCondition{Comparator: gqlparser.ContainsAnyQuantifiedComparator, Property: "tags", Value: &gqlparser.NamedBinding{Name: "tags"}},
+			},
+		},
+		{
+			name:   "Contains",
+			source: "tags CONTAINS 'a'",
+			opts:   []gqlparser.ParseOption{gqlparser.WithDialect(dialect)},
+			want:   &gqlparser.ForwardComparatorCondition{Comparator: gqlparser.ContainsForwardComparator, Property: "tags", Value: "a"},
+		},
+		{
+			name:   "StrictMode",
+			source: "tags CONTAINS ANY ARRAY('a', 'b') AND tags CONTAINS ALL ARRAY('c', 'd')",
+			opts:   []gqlparser.ParseOption{gqlparser.WithDialect(dialect), gqlparser.WithStrictMode()},
+			want: &gqlparser.AndCompoundCondition{
+				Left: &gqlparser.OrCompoundCondition{
+					Left:  &gqlparser.ForwardComparatorCondition{Comparator: gqlparser.ContainsForwardComparator, Property: "tags", Value: "a"},
+					Right: &gqlparser.ForwardComparatorCondition{Comparator: gqlparser.ContainsForwardComparator, Property: "tags", Value: "b"},
+				},
+				Right: &gqlparser.AndCompoundCondition{
+					Left:  &gqlparser.ForwardComparatorCondition{Comparator: gqlparser.ContainsForwardComparator, Property: "tags", Value: "c"},
+					Right: &gqlparser.ForwardComparatorCondition{Comparator: gqlparser.ContainsForwardComparator, Property: "tags", Value: "d"},
+				},
+			},
+		},
+		{
+			name:    "StrictModeWithBinding",
+			source:  "tags CONTAINS ANY @tags",
+			opts:    []gqlparser.ParseOption{gqlparser.WithDialect(dialect), gqlparser.WithStrictMode()},
+			wantErr: true,
+		},
+		{
+			name:    "StrictModeWithEmptyArray",
+			source:  "tags CONTAINS ALL ARRAY()",
+			opts:    []gqlparser.ParseOption{gqlparser.WithDialect(dialect), gqlparser.WithStrictMode()},
+			wantErr: true,
+		},
+		{
+			name:    "WithoutDialect",
+			source:  "tags CONTAINS ANY ARRAY('a', 'b')",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := gqlparser.ParseCondition(gqlparser.NewLexer(tt.source), tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCondition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuantifiedComparatorCondition(t *testing.T) {
+	t.Parallel()
+
+	cond := gqlparser.And(gqlparser.ContainsAny("tags", "b", "a", "b"), gqlparser.ContainsAll("labels", int64(1)))
+	if err := gqlparser.ValidateCondition(cond); err != nil {
+		t.Fatal(err)
+	}
+
+	formatted, err := gqlparser.FormatCondition(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "tags CONTAINS ANY ARRAY('b', 'a', 'b') AND labels CONTAINS ALL ARRAY(1)"; formatted != want {
+		t.Errorf("FormatCondition() = %q, want %q", formatted, want)
+	}
+	if got, err := gqlparser.FormatCondition(gqlparser.CanonicalizeInValues(cond)); err != nil {
+		t.Fatal(err)
+	} else if want := "tags CONTAINS ANY ARRAY('a', 'b') AND labels CONTAINS ALL ARRAY(1)"; got != want {
+		t.Errorf("FormatCondition() = %q, want %q", got, want)
+	}
+
+	// the formatted condition and the encodings are parsed again
+	dialect, err := gqlparser.NewDialect(gqlparser.WithContainsQuantifiers())
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := gqlparser.ParseCondition(gqlparser.NewLexer(formatted), gqlparser.WithDialect(dialect))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(cond, parsed); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	sexpr, err := gqlparser.EncodeSExpr(cond.(gqlparser.Syntax))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := gqlparser.DecodeSExpr(sexpr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(cond, decoded); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	expanded, err := gqlparser.ExpandQuantifiers(cond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := gqlparser.FormatCondition(expanded); err != nil {
+		t.Fatal(err)
+	} else if want := "(tags CONTAINS 'b' OR tags CONTAINS 'a' OR tags CONTAINS 'b') AND labels CONTAINS 1"; got != want {
+		t.Errorf("FormatCondition() = %q, want %q", got, want)
+	}
+	if _, err := gqlparser.ExpandQuantifiers(gqlparser.ContainsAny("tags")); !errors.Is(err, gqlparser.ErrInvalidCondition) {
+		t.Errorf("ExpandQuantifiers() error = %v, want %v", err, gqlparser.ErrInvalidCondition)
+	}
+
+	capabilities, err := gqlparser.NewDialect(gqlparser.WithCapabilities(gqlparser.LegacyDatastoreCapabilities))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := capabilities.Validate(&gqlparser.Query{Kind: "Kind", Where: cond}); !errors.Is(err, gqlparser.ErrUnsupportedFeature) {
+		t.Errorf("Validate() error = %v, want %v", err, gqlparser.ErrUnsupportedFeature)
+	}
+}
+
+func TestDialectValidate(t *testing.T) {
+	t.Parallel()
+
+	firestore, err := gqlparser.NewDialect(gqlparser.WithCapabilities(gqlparser.FirestoreInDatastoreModeCapabilities))
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy, err := gqlparser.NewDialect(gqlparser.WithCapabilities(gqlparser.LegacyDatastoreCapabilities))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlimited, err := gqlparser.NewDialect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := make([]string, 31)
+	for i := range values {
+		values[i] = strconv.Itoa(i)
+	}
+	hugeIn := "SELECT * FROM Kind WHERE a IN ARRAY(" + strings.Join(values, ", ") + ")"
+
+	tests := []struct {
+		name          string
+		source        string
+		wantFirestore string
+		wantLegacy    string
+	}{
+		{name: "Simple", source: "SELECT * FROM Kind WHERE a = 1 AND b IN ARRAY(1, 2)"},
+		{name: "Or", source: "SELECT * FROM Kind WHERE a = 1 OR b = 2", wantLegacy: "unsupported feature: OR"},
+		{name: "NotIn", source: "SELECT * FROM Kind WHERE a NOT IN ARRAY(1)", wantLegacy: "unsupported feature: NOT IN"},
+		{name: "NotEquals", source: "SELECT * FROM Kind WHERE a = 1 AND b != 2", wantLegacy: "unsupported feature: !="},
+		{name: "Aggregation", source: "SELECT COUNT(*) FROM Kind", wantLegacy: "unsupported feature: aggregation"},
+		{name: "HugeIn", source: hugeIn, wantFirestore: "unsupported feature: IN with 31 values"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, aggregationQuery, err := gqlparser.ParseQueryOrAggregationQuery(gqlparser.NewLexer(tt.source))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var s gqlparser.Syntax = query
+			if query == nil {
+				s = aggregationQuery
+			}
+
+			for _, d := range []struct {
+				name    string
+				dialect *gqlparser.Dialect
+				want    string
+			}{
+				{"Firestore", firestore, tt.wantFirestore},
+				{"Legacy", legacy, tt.wantLegacy},
+				{"Unlimited", unlimited, ""},
+			} {
+				err := d.dialect.Validate(s)
+				if d.want == "" {
+					if err != nil {
+						t.Errorf("%s: Validate() error = %v", d.name, err)
+					}
+					continue
+				}
+				if !errors.Is(err, gqlparser.ErrUnsupportedFeature) || err.Error() != d.want {
+					t.Errorf("%s: Validate() error = %v, want %q", d.name, err, d.want)
+				}
+			}
+		})
+	}
+}
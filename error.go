@@ -0,0 +1,274 @@
+package gqlparser
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// UnexpectedEOFError is returned when the tokens end in the middle of the query.
+// It wraps ErrNoTokens.
+type UnexpectedEOFError struct {
+	// Clause is the clause being parsed. e.g. "FROM", "WHERE", "ORDER BY"
+	// It's empty if no clause keyword has been consumed.
+	Clause string
+
+	// After is the last consumed token except whitespaces. It's nil if no token has been consumed.
+	After Token
+}
+
+func (e *UnexpectedEOFError) Error() string {
+	return e.format(tokenContent)
+}
+
+// format formats the error with the content of the token given by content.
+func (e *UnexpectedEOFError) format(content func(Token) string) string {
+	if e.After == nil {
+		return "unexpected end of query"
+	}
+	msg := fmt.Sprintf("unexpected end of query after %s at %d", content(e.After), e.After.GetPosition())
+	if kw, ok := e.After.(*KeywordToken); e.Clause != "" && (!ok || clauseKeywords[kw.Name] != e.Clause) {
+		msg += " in " + e.Clause + " clause"
+	}
+	return msg
+}
+
+func (e *UnexpectedEOFError) Unwrap() error {
+	return ErrNoTokens
+}
+
+// SyntaxError is returned when the parser finds the unexpected token.
+// It wraps ErrUnexpectedToken and the cause if any.
+type SyntaxError struct {
+	Token Token
+	// Reason is the supplementary explanation. e.g. expect to be ","
+	Reason string
+	// Cause is the underlying error. e.g. the error of parsing the DATETIME literal
+	Cause error
+	// Expected is the keywords expected at the token. It's nil unless the keyword is expected.
+	Expected []string
+}
+
+func (e *SyntaxError) Error() string {
+	return e.format(tokenContent, e.Cause)
+}
+
+// format formats the error with the content of the token given by content and the cause if it isn't nil.
+func (e *SyntaxError) format(content func(Token) string, cause error) string {
+	msg := fmt.Sprintf("%s: %s at %d", ErrUnexpectedToken, content(e.Token), e.Token.GetPosition())
+	if e.Reason != "" {
+		msg += " (" + e.Reason + ")"
+	}
+	if suggestion := e.Suggestion(); suggestion != "" {
+		msg += " (did you mean " + suggestion + "?)"
+	}
+	if cause != nil {
+		msg += " (" + cause.Error() + ")"
+	}
+	return msg
+}
+
+func tokenContent(token Token) string {
+	return token.GetContent()
+}
+
+// unexpectedTokenErrors is shared by the errors without the cause not to allocate them on every errors.Is in the parser.
+var unexpectedTokenErrors = []error{ErrUnexpectedToken}
+
+func (e *SyntaxError) Unwrap() []error {
+	if e.Cause == nil {
+		return unexpectedTokenErrors
+	}
+	return []error{ErrUnexpectedToken, e.Cause}
+}
+
+// DuplicateClauseError is returned when the clause appears twice in the query. e.g. LIMIT 5 LIMIT 10
+// It wraps ErrUnexpectedToken.
+type DuplicateClauseError struct {
+	// Clause is the duplicated clause. e.g. "WHERE", "ORDER BY", "LIMIT", "OFFSET"
+	Clause string
+	// Token is the keyword of the second clause.
+	Token Token
+}
+
+func (e *DuplicateClauseError) Error() string {
+	return fmt.Sprintf("duplicate %s clause at %d", e.Clause, e.Token.GetPosition())
+}
+
+func (e *DuplicateClauseError) Unwrap() error {
+	return ErrUnexpectedToken
+}
+
+// LexError is returned when the lexer cannot take the token from the source.
+// It wraps ErrUnexpectedToken and the cause if any.
+type LexError struct {
+	// Content is the malformed part of the source.
+	Content  string
+	Position int
+	// Reason is the supplementary explanation. e.g. invalid binding site
+	Reason string
+	// Cause is the underlying error. e.g. the error of strconv
+	Cause error
+}
+
+func (e *LexError) Error() string {
+	return e.format(e.Cause)
+}
+
+// format formats the error with the cause if it isn't nil.
+func (e *LexError) format(cause error) string {
+	msg := fmt.Sprintf("%s: %s at %d", ErrUnexpectedToken, e.Content, e.Position)
+	if e.Reason != "" {
+		msg += " (" + e.Reason + ")"
+	}
+	if cause != nil {
+		msg += " (" + cause.Error() + ")"
+	}
+	return msg
+}
+
+func (e *LexError) Unwrap() []error {
+	if e.Cause == nil {
+		return []error{ErrUnexpectedToken}
+	}
+	return []error{ErrUnexpectedToken, e.Cause}
+}
+
+// BindError is returned when the binding variable cannot be resolved or the resolved value is invalid.
+// It wraps ErrBindValue, ErrBindKeyPath or ErrBindTemplate.
+type BindError struct {
+	Variable BindingVariable
+	Err      error
+}
+
+func (e *BindError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// ClauseError is returned when the unexpected token is found in the clause.
+// It wraps the error from the acceptor of the innermost clause.
+type ClauseError struct {
+	// Clause is the clause being parsed. e.g. "FROM", "WHERE", "ORDER BY"
+	Clause string
+	Err    error
+}
+
+func (e *ClauseError) Error() string {
+	return e.Err.Error() + " in " + e.Clause + " clause"
+}
+
+func (e *ClauseError) Unwrap() error {
+	return e.Err
+}
+
+// wrapClauseError names the clause in the unexpected token error unless the inner clause has been named.
+func wrapClauseError(clause string, err error) error {
+	if clause == "" || !errors.Is(err, ErrUnexpectedToken) {
+		return err
+	}
+
+	var clauseErr *ClauseError
+	if errors.As(err, &clauseErr) {
+		return err
+	}
+	return &ClauseError{Clause: clause, Err: err}
+}
+
+var errNilToken = fmt.Errorf("%w: nil token", ErrUnexpectedToken)
+
+var clauseKeywords = map[string]string{
+	"SELECT":    "SELECT",
+	"AGGREGATE": "AGGREGATE",
+	"OVER":      "OVER",
+	"FROM":      "FROM",
+	"WHERE":     "WHERE",
+	"ORDER":     "ORDER BY",
+	"BY":        "ORDER BY",
+	"LIMIT":     "LIMIT",
+	"OFFSET":    "OFFSET",
+}
+
+// eofTrackingTokenSource remembers the last consumed token and clause to report UnexpectedEOFError.
+// It also rejects the nil tokens from the malformed token source.
+type eofTrackingTokenSource struct {
+	TokenSource
+	last   Token
+	clause *KeywordToken
+}
+
+func (ts *eofTrackingTokenSource) Read() (Token, error) {
+	token, err := ts.TokenSource.Read()
+	if err != nil {
+		return nil, err
+	}
+	if token == nil || reflect.ValueOf(token).IsNil() {
+		return nil, errNilToken
+	}
+
+	if _, ok := token.(*WhitespaceToken); !ok && (ts.last == nil || ts.last.GetPosition() <= token.GetPosition()) {
+		ts.last = token
+	}
+	if kw, ok := token.(*KeywordToken); ok {
+		if _, isClause := clauseKeywords[kw.Name]; isClause && (ts.clause == nil || ts.clause.GetPosition() <= kw.GetPosition()) {
+			ts.clause = kw
+		}
+	}
+	return token, nil
+}
+
+// wrapError converts ErrNoTokens into UnexpectedEOFError with the tracked context.
+func (ts *eofTrackingTokenSource) wrapError(err error) error {
+	if !errors.Is(err, ErrNoTokens) {
+		return err
+	}
+
+	var eofErr *UnexpectedEOFError
+	if errors.As(err, &eofErr) {
+		return err
+	}
+
+	eofErr = &UnexpectedEOFError{After: ts.last}
+	if ts.clause != nil {
+		eofErr.Clause = clauseKeywords[ts.clause.Name]
+	}
+	return eofErr
+}
+
+// ParseError is returned by the Parse* functions when the parsing fails.
+// It carries the partially-populated syntax for the valid prefix of the tokens.
+type ParseError struct {
+	// Partial is the partially-populated syntax. It may be nil if nothing has been parsed.
+	Partial Syntax
+	Err     error
+
+	// RedactedSource is the query with the string literals replaced by placeholders.
+	// It's set only if WithRedactedSource is given.
+	RedactedSource  string
+	redactedMessage string
+}
+
+func (e *ParseError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func partialQueryOrAggregationQuery(query *AggregationQuery) Syntax {
+	if len(query.Aggregations) == 0 {
+		return &query.Query
+	}
+	return query
+}
+
+func partialCondition(condition Condition) Syntax {
+	if s, ok := condition.(Syntax); ok {
+		return s
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestUnexpectedEOFError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		parse      func(gqlparser.TokenSource) error
+		source     string
+		wantClause string
+		wantError  string
+	}{
+		{
+			name:      "Empty",
+			parse:     func(ts gqlparser.TokenSource) error { _, err := gqlparser.ParseQuery(ts); return err },
+			source:    "",
+			wantError: "unexpected end of query",
+		},
+		{
+			name:       "AfterFrom",
+			parse:      func(ts gqlparser.TokenSource) error { _, err := gqlparser.ParseQuery(ts); return err },
+			source:     "SELECT * FROM ",
+			wantClause: "FROM",
+			wantError:  "unexpected end of query after FROM at 9",
+		},
+		{
+			name:       "InWhere",
+			parse:      func(ts gqlparser.TokenSource) error { _, err := gqlparser.ParseQuery(ts); return err },
+			source:     "SELECT * FROM Kind WHERE a =",
+			wantClause: "WHERE",
+			wantError:  "unexpected end of query after = at 27 in WHERE clause",
+		},
+		{
+			name: "InOrderBy",
+			parse: func(ts gqlparser.TokenSource) error {
+				_, _, err := gqlparser.ParseQueryOrAggregationQuery(ts)
+				return err
+			},
+			source:     "SELECT * FROM Kind ORDER BY a,",
+			wantClause: "ORDER BY",
+			wantError:  "unexpected end of query after , at 29 in ORDER BY clause",
+		},
+		{
+			name:      "Key",
+			parse:     func(ts gqlparser.TokenSource) error { _, err := gqlparser.ParseKey(ts); return err },
+			source:    "KEY(Kind, ",
+			wantError: "unexpected end of query after , at 8",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.parse(gqlparser.NewLexer(tt.source))
+			if !errors.Is(err, gqlparser.ErrNoTokens) {
+				t.Fatalf("error = %v, want %v", err, gqlparser.ErrNoTokens)
+			}
+
+			var eofErr *gqlparser.UnexpectedEOFError
+			if !errors.As(err, &eofErr) {
+				t.Fatalf("error = %T, want %T", err, eofErr)
+			}
+			if eofErr.Clause != tt.wantClause {
+				t.Errorf("Clause = %q, want %q", eofErr.Clause, tt.wantClause)
+			}
+			if err.Error() != tt.wantError {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.wantError)
+			}
+		})
+	}
+}
+
+func TestClauseError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		source     string
+		wantClause string
+	}{
+		{name: "Projection", source: "SELECT 1 FROM Kind", wantClause: "SELECT"},
+		{name: "Kind", source: "SELECT * FROM 'Kind'", wantClause: "FROM"},
+		{name: "Where", source: "SELECT * FROM Kind WHERE a = = 1", wantClause: "WHERE"},
+		{name: "OrderBy", source: "SELECT * FROM Kind ORDER BY 1", wantClause: "ORDER BY"},
+		{name: "Limit", source: "SELECT * FROM Kind LIMIT 'a'", wantClause: "LIMIT"},
+		{name: "Offset", source: "SELECT * FROM Kind OFFSET 1.5", wantClause: "OFFSET"},
+		{name: "Distinct", source: "SELECT DISTINCT ON a FROM Kind", wantClause: "DISTINCT"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source))
+			if !errors.Is(err, gqlparser.ErrUnexpectedToken) {
+				t.Fatalf("error = %v, want %v", err, gqlparser.ErrUnexpectedToken)
+			}
+
+			var clauseErr *gqlparser.ClauseError
+			if !errors.As(err, &clauseErr) {
+				t.Fatalf("error = %T, want %T", err, clauseErr)
+			}
+			if clauseErr.Clause != tt.wantClause {
+				t.Errorf("Clause = %q, want %q (%v)", clauseErr.Clause, tt.wantClause, err)
+			}
+		})
+	}
+}
+
+func TestDuplicateClauseError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{name: "Where", source: "SELECT * FROM Kind WHERE a = 1 WHERE b = 2", wantErr: "duplicate WHERE clause at 31"},
+		{name: "OrderBy", source: "SELECT * FROM Kind ORDER BY a ORDER BY b", wantErr: "duplicate ORDER BY clause at 30"},
+		{name: "Limit", source: "SELECT * FROM Kind LIMIT 5 LIMIT 10", wantErr: "duplicate LIMIT clause at 27"},
+		{name: "Offset", source: "SELECT * FROM Kind LIMIT 5 OFFSET 1 OFFSET 2", wantErr: "duplicate OFFSET clause at 36"},
+		{name: "LimitAfterOffset", source: "SELECT * FROM Kind LIMIT 5 OFFSET 1 LIMIT 2", wantErr: "duplicate LIMIT clause at 36"},
+		{name: "Subquery", source: "AGGREGATE COUNT(*) OVER (SELECT * FROM Kind LIMIT 5 LIMIT 10)", wantErr: "duplicate LIMIT clause at 52"},
+		{name: "SelectAggregation", source: "SELECT COUNT(*) FROM Kind WHERE a = 1 WHERE b = 2", wantErr: "duplicate WHERE clause at 38"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, err := gqlparser.ParseQueryOrAggregationQuery(gqlparser.NewLexer(tt.source))
+			if !errors.Is(err, gqlparser.ErrUnexpectedToken) {
+				t.Fatalf("error = %v, want %v", err, gqlparser.ErrUnexpectedToken)
+			}
+
+			var duplicateErr *gqlparser.DuplicateClauseError
+			if !errors.As(err, &duplicateErr) {
+				t.Fatalf("error = %v, want %T", err, duplicateErr)
+			}
+			if got := duplicateErr.Error(); got != tt.wantErr {
+				t.Errorf("Error() = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+
+	// the clauses out of order are not duplicated
+	_, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM Kind ORDER BY a WHERE b = 1"))
+	var duplicateErr *gqlparser.DuplicateClauseError
+	if err == nil || errors.As(err, &duplicateErr) {
+		t.Errorf("error = %v, want the unexpected token", err)
+	}
+}
+
+func TestOuterClauseOfAggregationQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{name: "Limit", source: "AGGREGATE COUNT(*) OVER (SELECT * FROM Kind) LIMIT 10", wantErr: "unexpected token: LIMIT at 45 (LIMIT must be inside OVER (...) of the aggregation query)"},
+		{name: "OrderBy", source: "AGGREGATE COUNT(*) OVER (SELECT * FROM Kind) ORDER BY a", wantErr: "unexpected token: ORDER at 45 (ORDER BY must be inside OVER (...) of the aggregation query)"},
+		{name: "Where", source: "AGGREGATE COUNT(*) OVER (SELECT * FROM Kind)\nWHERE a = 1", wantErr: "unexpected token: WHERE at 45 (WHERE must be inside OVER (...) of the aggregation query)"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := gqlparser.ParseAggregationQuery(gqlparser.NewLexer(tt.source))
+			if !errors.Is(err, gqlparser.ErrUnexpectedToken) {
+				t.Fatalf("error = %v, want %v", err, gqlparser.ErrUnexpectedToken)
+			}
+			if got := err.Error(); got != tt.wantErr {
+				t.Errorf("Error() = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseErrorPartial(t *testing.T) {
+	t.Parallel()
+
+	_, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT a, b FROM Kind WHERE a = 1 ORDER BY"))
+	var parseErr *gqlparser.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("error = %T, want %T", err, parseErr)
+	}
+	want := &gqlparser.Query{
+		Properties: []gqlparser.Property{"a", "b"},
+		Kind:       "Kind",
+		Where: &gqlparser.EitherComparatorCondition{
+			Comparator: gqlparser.EqualsEitherComparator,
+			Property:   "a",
+			Value:      int64(1),
+		},
+	}
+	if diff := cmp.Diff(want, parseErr.Partial); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	_, _, err = gqlparser.ParseQueryOrAggregationQuery(gqlparser.NewLexer("SELECT COUNT(*) AS c FROM"))
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("error = %T, want %T", err, parseErr)
+	}
+	wantAggregation := &gqlparser.AggregationQuery{
+		Aggregations: []gqlparser.Aggregation{&gqlparser.CountAggregation{Alias: "c"}},
+	}
+	if diff := cmp.Diff(wantAggregation, parseErr.Partial); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestSyntaxError(t *testing.T) {
+	t.Parallel()
+
+	_, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM Kind WHERE a = DATETIME('x')"))
+	if !errors.Is(err, gqlparser.ErrUnexpectedToken) {
+		t.Fatalf("error = %v, want %v", err, gqlparser.ErrUnexpectedToken)
+	}
+	var syntaxErr *gqlparser.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("error = %T, want %T", err, syntaxErr)
+	}
+	if got := syntaxErr.Token.GetPosition(); got != 38 {
+		t.Errorf("Token.GetPosition() = %d, want 38", got)
+	}
+	var timeErr *time.ParseError
+	if !errors.As(err, &timeErr) {
+		t.Errorf("error = %v, want to wrap %T", err, timeErr)
+	}
+}
+
+func TestLexError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		source    string
+		wantError string
+		wantCause error
+	}{
+		{name: "UnterminatedString", source: "SELECT * FROM Kind WHERE a = 'x", wantError: "unexpected token: ' at 29 (unterminated string) in WHERE clause"},
+		{name: "InvalidBindingSite", source: "SELECT * FROM Kind WHERE a = @0", wantError: "unexpected token: @ at 29 (invalid binding site) in WHERE clause"},
+		{name: "OutOfRange", source: "SELECT * FROM Kind LIMIT 99999999999999999999", wantError: `unexpected token: 99999999999999999999 at 25 (strconv.ParseInt: parsing "99999999999999999999": value out of range) in LIMIT clause`, wantCause: strconv.ErrRange},
+		{name: "UnknownCharacter", source: "SELECT * FROM Kind WHERE a = 1 ？", wantError: "unexpected token: ？ at 31 in WHERE clause"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source))
+			if !errors.Is(err, gqlparser.ErrUnexpectedToken) {
+				t.Fatalf("error = %v, want %v", err, gqlparser.ErrUnexpectedToken)
+			}
+			var lexErr *gqlparser.LexError
+			if !errors.As(err, &lexErr) {
+				t.Fatalf("error = %T, want %T", err, lexErr)
+			}
+			if err.Error() != tt.wantError {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.wantError)
+			}
+			if tt.wantCause != nil && !errors.Is(err, tt.wantCause) {
+				t.Errorf("error = %v, want to wrap %v", err, tt.wantCause)
+			}
+		})
+	}
+}
+
+func TestBindError(t *testing.T) {
+	t.Parallel()
+
+	cond, err := gqlparser.ParseCondition(gqlparser.NewLexer("a = @x AND __key__ = KEY(Kind, @id)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cond.Bind(&gqlparser.BindingResolver{Named: map[string]any{"x": 1, "id": 1.5}})
+	if !errors.Is(err, gqlparser.ErrBindKeyPath) {
+		t.Fatalf("error = %v, want %v", err, gqlparser.ErrBindKeyPath)
+	}
+	var bindErr *gqlparser.BindError
+	if !errors.As(err, &bindErr) {
+		t.Fatalf("error = %T, want %T", err, bindErr)
+	}
+	if diff := cmp.Diff(&gqlparser.NamedBinding{Name: "id"}, bindErr.Variable); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestSyntaxError_SymbolInCondition(t *testing.T) {
+	t.Parallel()
+
+	dialect, err := gqlparser.NewDialect(gqlparser.WithGroupBy(), gqlparser.WithHaving())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, opts := range [][]gqlparser.ParseOption{nil, {gqlparser.WithDialect(dialect)}} {
+		_, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM Kind WHERE a = 1 AND b foo 2"), opts...)
+		if want := "unexpected token: foo at 37 in WHERE clause"; err == nil || err.Error() != want {
+			t.Errorf("Error() = %v, want %q", err, want)
+		}
+	}
+
+	// GROUP isn't the clause without the dialect
+	_, err = gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM Kind WHERE a = 1 GROUP BY a"))
+	if want := "unexpected token: GROUP at 31 in WHERE clause"; err == nil || err.Error() != want {
+		t.Errorf("Error() = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,179 @@
+package gqlparser
+
+import (
+	"strings"
+)
+
+// Explanation is the summary of the query for the debugging dashboards and the CLI output.
+type Explanation struct {
+	Kind Kind
+	// Projection is the projected properties. It's nil for the full projection. e.g. SELECT * FROM Kind
+	Projection []Property
+	KeysOnly   bool
+	Distinct   bool
+	DistinctOn []Property
+	// Filters are the filters grouped by the properties in the order of appearance.
+	Filters []PropertyFilter
+	// Ancestor is true if the query is restricted by the ancestor. e.g. __key__ HAS ANCESTOR KEY(Parent, 1)
+	Ancestor bool
+	OrderBy  []OrderBy
+	Limit    *Limit
+	Offset   *Offset
+	// CompositeIndexes are the composite indexes needed to execute the query like Query.RequiredIndexes.
+	// It is nil if the query has more disjunctions than MaxDisjunctions.
+	CompositeIndexes []*Index
+}
+
+// PropertyFilter is the filters of the property in the WHERE clause.
+type PropertyFilter struct {
+	Property Property
+	// Operators are the comparators of the filters without duplicates. e.g. ["=", "IN", "IS NULL"]
+	Operators []string
+}
+
+// Explain summarizes the query. The String method of the summary renders it as the plain text.
+func Explain(q *Query) *Explanation {
+	e := &Explanation{
+		Kind:       q.Kind,
+		Projection: q.Properties,
+		KeysOnly:   q.KeysOnly,
+		Distinct:   q.Distinct || len(q.DistinctOn) != 0,
+		DistinctOn: q.DistinctOn,
+		OrderBy:    q.OrderBy,
+		Limit:      q.Limit,
+		Offset:     q.Offset,
+	}
+	if indexes, err := q.RequiredIndexes(); err == nil {
+		e.CompositeIndexes = indexes
+	}
+	if q.Where != nil {
+		e.explainCondition(q.Where)
+	}
+	return e
+}
+
+func (e *Explanation) explainCondition(cond Condition) {
+	walkConditions(cond, func(leaf Condition) {
+		switch c := leaf.(type) {
+		case *ForwardComparatorCondition:
+			e.Ancestor = e.Ancestor || c.Comparator == HasAncestorForwardComparator
+		case *BackwardComparatorCondition:
+			e.Ancestor = e.Ancestor || c.Comparator == HasDescendantBackwardComparator
+		}
+		if property, operator := describeCondition(leaf); operator != "" {
+			e.addFilter(property, operator)
+		}
+	})
+}
+
+func (e *Explanation) addFilter(property string, operator string) {
+	for i := range e.Filters {
+		f := &e.Filters[i]
+		if f.Property != Property(property) {
+			continue
+		}
+		for _, op := range f.Operators {
+			if op == operator {
+				return
+			}
+		}
+		f.Operators = append(f.Operators, operator)
+		return
+	}
+	e.Filters = append(e.Filters, PropertyFilter{Property: Property(property), Operators: []string{operator}})
+}
+
+// String renders the summary as the plain text line by line. The empty clauses are omitted. e.g.
+//
+//	Kind: Task
+//	Projection: *
+//	Filters:
+//	  done: =
+//	  priority: >=, <
+//	Order By: priority DESC
+//	Limit: 10
+//	Composite Indexes:
+//	  Task: done, priority DESC
+func (e *Explanation) String() string {
+	var sb strings.Builder
+	sb.WriteString("Kind: ")
+	sb.WriteString(formatIdentifier(string(e.Kind)))
+	sb.WriteString("\nProjection: ")
+	if len(e.Projection) == 0 {
+		sb.WriteString("*")
+	} else {
+		writeJoined(&sb, e.Projection, func(p Property) string { return formatPropertyPath(p) })
+	}
+	if e.KeysOnly {
+		sb.WriteString(" (keys only)")
+	}
+	sb.WriteString("\n")
+
+	if len(e.DistinctOn) != 0 {
+		sb.WriteString("Distinct On: ")
+		writeJoined(&sb, e.DistinctOn, func(p Property) string { return formatPropertyPath(p) })
+		sb.WriteString("\n")
+	} else if e.Distinct {
+		sb.WriteString("Distinct: true\n")
+	}
+	if len(e.Filters) != 0 {
+		sb.WriteString("Filters:\n")
+		for _, f := range e.Filters {
+			sb.WriteString("  ")
+			sb.WriteString(formatPropertyPath(f.Property))
+			sb.WriteString(": ")
+			sb.WriteString(strings.Join(f.Operators, ", "))
+			sb.WriteString("\n")
+		}
+	}
+	if e.Ancestor {
+		sb.WriteString("Ancestor: true\n")
+	}
+	if len(e.OrderBy) != 0 {
+		sb.WriteString("Order By: ")
+		writeJoined(&sb, e.OrderBy, OrderBy.String)
+		sb.WriteString("\n")
+	}
+	if e.Limit != nil {
+		sb.WriteString("Limit: ")
+		sb.WriteString(explainResultPosition(e.Limit.Position, e.Limit.Cursor))
+		sb.WriteString("\n")
+	}
+	if e.Offset != nil {
+		sb.WriteString("Offset: ")
+		sb.WriteString(explainResultPosition(e.Offset.Position, e.Offset.Cursor))
+		sb.WriteString("\n")
+	}
+	if len(e.CompositeIndexes) != 0 {
+		sb.WriteString("Composite Indexes:\n")
+		for _, idx := range e.CompositeIndexes {
+			sb.WriteString("  ")
+			sb.WriteString(formatIdentifier(string(idx.Kind)))
+			if idx.Ancestor {
+				sb.WriteString(" (ancestor)")
+			}
+			sb.WriteString(": ")
+			writeJoined(&sb, idx.Properties, func(p IndexProperty) string {
+				return OrderBy{Property: p.Name, Descending: p.Direction == DescendingIndexDirection}.String()
+			})
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+func writeJoined[T any](sb *strings.Builder, items []T, format func(T) string) {
+	for i, item := range items {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(format(item))
+	}
+}
+
+// explainResultPosition renders the position and the cursor of LIMIT and OFFSET. e.g. 10, @cursor + 10
+func explainResultPosition(position int64, cursor BindingVariable) string {
+	var sb strings.Builder
+	_ = formatResultPosition(&sb, position, cursor)
+	return sb.String()
+}
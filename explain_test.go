@@ -0,0 +1,80 @@
+package gqlparser_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+
+	query, err := gqlparser.ParseQuery(gqlparser.NewLexer(
+		"SELECT DISTINCT ON (a) a, b FROM Task WHERE done = FALSE AND (priority >= 4 OR priority < 1 OR a IS NULL) AND __key__ HAS ANCESTOR KEY(List, 1) ORDER BY priority DESC LIMIT 10 OFFSET @cursor + 5",
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	indexes, err := query.RequiredIndexes()
+	if err != nil {
+		t.Fatalf("RequiredIndexes() error = %v", err)
+	}
+
+	got := gqlparser.Explain(query)
+	want := &gqlparser.Explanation{
+		Kind:       "Task",
+		Projection: []gqlparser.Property{"a", "b"},
+		Distinct:   true,
+		DistinctOn: []gqlparser.Property{"a"},
+		Filters: []gqlparser.PropertyFilter{
+			{Property: "done", Operators: []string{"="}},
+			{Property: "priority", Operators: []string{">=", "<"}},
+			{Property: "a", Operators: []string{"IS NULL"}},
+			{Property: "__key__", Operators: []string{"HAS ANCESTOR"}},
+		},
+		Ancestor:         true,
+		OrderBy:          []gqlparser.OrderBy{{Property: "priority", Descending: true}},
+		Limit:            &gqlparser.Limit{Position: 10},
+		Offset:           &gqlparser.Offset{Position: 5, Cursor: &gqlparser.NamedBinding{Name: "cursor"}},
+		CompositeIndexes: indexes,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	wantText := `Kind: Task
+Projection: a, b
+Distinct On: a
+Filters:
+  done: =
+  priority: >=, <
+  a: IS NULL
+  __key__: HAS ANCESTOR
+Ancestor: true
+Order By: priority DESC
+Limit: 10
+Offset: @cursor + 5
+Composite Indexes:
+  Task (ancestor): done, priority DESC, a, b
+  Task (ancestor): done, a, priority DESC, b
+`
+	if diff := cmp.Diff(wantText, got.String()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestExplain_Simple(t *testing.T) {
+	t.Parallel()
+
+	query, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT __key__ FROM `Task List` WHERE done = TRUE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Kind: `Task List`\nProjection: __key__ (keys only)\nFilters:\n  done: =\n"
+	if diff := cmp.Diff(want, gqlparser.Explain(query).String()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
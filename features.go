@@ -0,0 +1,120 @@
+package gqlparser
+
+// Features are the features of GQL used by the query. They're detected by Query.Features and AggregationQuery.Features
+// for the routing layers to decide the execution strategy without walking the AST.
+type Features struct {
+	// UsesOr is true if the condition has OR.
+	UsesOr bool
+	// UsesIn is true if the condition has IN of the property. e.g. a IN ARRAY(1, 2)
+	UsesIn bool
+	// UsesNotIn is true if the condition has NOT IN.
+	UsesNotIn bool
+	// UsesNotEquals is true if the condition has !=.
+	UsesNotEquals bool
+	// UsesInequality is true if the condition has the range comparisons. e.g. a > 1
+	UsesInequality bool
+	// UsesContains is true if the condition has the membership of the array property. e.g. a CONTAINS 1, 1 IN a
+	UsesContains bool
+	// UsesQuantifiers is true if the condition has CONTAINS ANY or CONTAINS ALL.
+	UsesQuantifiers bool
+	// UsesAncestor is true if the condition has HAS ANCESTOR or HAS DESCENDANT.
+	UsesAncestor bool
+	// UsesIsNull is true if the condition has IS NULL.
+	UsesIsNull bool
+	// UsesBindings is true if the query has any binding variable including the template placeholders and the cursors.
+	UsesBindings bool
+	// UsesCursors is true if LIMIT or OFFSET has the cursor.
+	UsesCursors bool
+	// UsesDistinct is true if the query has DISTINCT or DISTINCT ON.
+	UsesDistinct bool
+	// IsAggregation is true if the query is the aggregation query.
+	IsAggregation bool
+}
+
+// Features returns the features of GQL used by the query.
+func (q *Query) Features() Features {
+	var f Features
+	f.detectQuery(q)
+	return f
+}
+
+// Features returns the features of GQL used by the aggregation query including HAVING.
+func (q *AggregationQuery) Features() Features {
+	var f Features
+	f.detectQuery(&q.Query)
+	f.detectCondition(q.Having)
+	f.IsAggregation = true
+	return f
+}
+
+func (f *Features) detectQuery(q *Query) {
+	f.detectCondition(q.Where)
+	f.UsesDistinct = q.Distinct || len(q.DistinctOn) != 0
+	if q.KindBinding != nil || len(q.PropertyBindings) != 0 {
+		f.UsesBindings = true
+	}
+	if q.Limit != nil {
+		f.detectCursor(q.Limit.Cursor)
+	}
+	if q.Offset != nil {
+		f.detectCursor(q.Offset.Cursor)
+	}
+}
+
+func (f *Features) detectCursor(cursor BindingVariable) {
+	switch cursor.(type) {
+	case nil:
+	case Cursor:
+		f.UsesCursors = true
+	default:
+		f.UsesCursors = true
+		f.UsesBindings = true
+	}
+}
+
+func (f *Features) detectCondition(cond Condition) {
+	if cond == nil {
+		return
+	}
+	if len(conditionVariables(nil, cond)) != 0 {
+		f.UsesBindings = true
+	}
+	if hasOrCondition(cond) {
+		f.UsesOr = true
+	}
+	walkConditions(cond, f.detectComparison)
+}
+
+func (f *Features) detectComparison(cond Condition) {
+	switch c := cond.(type) {
+	case *IsNullCondition:
+		f.UsesIsNull = true
+	case *ForwardComparatorCondition:
+		switch c.Comparator {
+		case InForwardComparator:
+			f.UsesIn = true
+		case NotInForwardComparator:
+			f.UsesNotIn = true
+		case ContainsForwardComparator:
+			f.UsesContains = true
+		case HasAncestorForwardComparator:
+			f.UsesAncestor = true
+		}
+	case *BackwardComparatorCondition:
+		switch c.Comparator {
+		case InBackwardComparator:
+			f.UsesContains = true
+		case HasDescendantBackwardComparator:
+			f.UsesAncestor = true
+		}
+	case *QuantifiedComparatorCondition:
+		f.UsesQuantifiers = true
+	case *EitherComparatorCondition:
+		switch {
+		case c.Comparator == NotEqualsEitherComparator:
+			f.UsesNotEquals = true
+		case c.Comparator.isRange():
+			f.UsesInequality = true
+		}
+	}
+}
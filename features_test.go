@@ -0,0 +1,79 @@
+package gqlparser_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestQueryFeatures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		source string
+		opts   []gqlparser.ParseOption
+		want   gqlparser.Features
+	}{
+		{
+			name:   "Plain",
+			source: "SELECT * FROM Kind WHERE a = 1",
+			want:   gqlparser.Features{},
+		},
+		{
+			name:   "Comparisons",
+			source: "SELECT DISTINCT a FROM Kind WHERE a IN ARRAY(1, 2) AND (b NOT IN ARRAY(1) OR c != 1) AND d > 1 AND e IS NULL",
+			want:   gqlparser.Features{UsesOr: true, UsesIn: true, UsesNotIn: true, UsesNotEquals: true, UsesInequality: true, UsesIsNull: true, UsesDistinct: true},
+		},
+		{
+			name:   "Contains",
+			source: "SELECT * FROM Kind WHERE tags CONTAINS 'a' AND 'b' IN tags AND __key__ HAS ANCESTOR KEY(Parent, 1)",
+			want:   gqlparser.Features{UsesContains: true, UsesAncestor: true},
+		},
+		{
+			name:   "Bindings",
+			source: "SELECT * FROM Kind WHERE __key__ = KEY(Kind, @id) LIMIT @cursor",
+			want:   gqlparser.Features{UsesBindings: true, UsesCursors: true},
+		},
+		{
+			name:   "CursorLiteral",
+			source: "SELECT * FROM Kind OFFSET CURSOR('Cg0SB2tleS0xMDAY')",
+			opts:   []gqlparser.ParseOption{gqlparser.WithCursorLiterals()},
+			want:   gqlparser.Features{UsesCursors: true},
+		},
+		{
+			name:   "Template",
+			source: "SELECT * FROM @kind",
+			opts:   []gqlparser.ParseOption{gqlparser.WithTemplatePlaceholders()},
+			want:   gqlparser.Features{UsesBindings: true},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source), tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, query.Features()); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAggregationQueryFeatures(t *testing.T) {
+	t.Parallel()
+
+	query, err := gqlparser.ParseAggregationQuery(gqlparser.NewLexer("AGGREGATE COUNT(*) OVER (SELECT * FROM Kind WHERE a = @a)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := gqlparser.Features{UsesBindings: true, IsAggregation: true}
+	if diff := cmp.Diff(want, query.Features()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
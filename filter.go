@@ -0,0 +1,53 @@
+package gqlparser
+
+// FilterTerm is the comparison of a condition in the canonical direction that has the property on the left side.
+type FilterTerm struct {
+	Property Property
+	// Operator is EitherComparator, ForwardComparator or QuantifiedComparator. It's never BackwardComparator.
+	// e.g. 1 IN prop is CONTAINS, KEY(Kind, 1) HAS DESCENDANT __key__ is HAS ANCESTOR and prop IS NULL is = with the nil value.
+	Operator Comparator
+	Value    any
+	// Binding is the binding variable that Value has been bound from. It's nil if Value isn't bound.
+	Binding BindingVariable
+}
+
+// FilterTerms returns the comparisons in the condition as FilterTerm from left to right.
+// The compound conditions are flattened regardless of AND and OR, so use it for the terms and not for the logic.
+func FilterTerms(cond Condition) []FilterTerm {
+	var terms []FilterTerm
+	walkFilterTerms(cond, func(term FilterTerm) {
+		terms = append(terms, term)
+	})
+	return terms
+}
+
+func walkFilterTerms(cond Condition, f func(FilterTerm)) {
+	walkConditions(cond, func(leaf Condition) {
+		value, binding := comparatorValue(leaf)
+		switch c := leaf.(type) {
+		case *IsNullCondition:
+			f(FilterTerm{Property: Property(c.Property), Operator: EqualsEitherComparator})
+		case *EitherComparatorCondition:
+			f(FilterTerm{Property: Property(c.Property), Operator: c.Comparator, Value: value, Binding: binding})
+		case *ForwardComparatorCondition:
+			f(FilterTerm{Property: Property(c.Property), Operator: c.Comparator, Value: value, Binding: binding})
+		case *BackwardComparatorCondition:
+			f(FilterTerm{Property: Property(c.Property), Operator: c.Comparator.forward(), Value: value, Binding: binding})
+		case *QuantifiedComparatorCondition:
+			f(FilterTerm{Property: Property(c.Property), Operator: c.Comparator, Value: value, Binding: binding})
+		}
+	})
+}
+
+// forward returns the comparator that has the same meaning with the operands swapped.
+func (c BackwardComparator) forward() Comparator {
+	switch c {
+	case InBackwardComparator:
+		return ContainsForwardComparator
+	case HasDescendantBackwardComparator:
+		return HasAncestorForwardComparator
+	default:
+		// the invalid comparator is kept as is to be reported by the consumers
+		return ForwardComparator(c)
+	}
+}
@@ -0,0 +1,39 @@
+package gqlparser_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestFilterTerms(t *testing.T) {
+	t.Parallel()
+
+	source := "a = 1 AND (2 IN tags OR b IS NULL) AND KEY(Parent, 1) HAS DESCENDANT __key__ AND 3 < c AND d NOT IN ARRAY(4) AND tags CONTAINS @tag"
+	cond, err := gqlparser.ParseCondition(gqlparser.NewLexer(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []gqlparser.FilterTerm{
+		{Property: "a", Operator: gqlparser.EqualsEitherComparator, Value: int64(1)},
+		{Property: "tags", Operator: gqlparser.ContainsForwardComparator, Value: int64(2)},
+		{Property: "b", Operator: gqlparser.EqualsEitherComparator},
+		{Property: "__key__", Operator: gqlparser.HasAncestorForwardComparator, Value: &gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "Parent", ID: 1}}}},
+		{Property: "c", Operator: gqlparser.GreaterThanEitherComparator, Value: int64(3)},
+		{Property: "d", Operator: gqlparser.NotInForwardComparator, Value: []any{int64(4)}},
+		{Property: "tags", Operator: gqlparser.ContainsForwardComparator, Value: &gqlparser.NamedBinding{Name: "tag"}},
+	}
+	if diff := cmp.Diff(want, gqlparser.FilterTerms(cond)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	if err := cond.Bind(&gqlparser.BindingResolver{Named: map[string]any{"tag": "x"}}); err != nil {
+		t.Fatal(err)
+	}
+	got := gqlparser.FilterTerms(cond)[6]
+	if diff := cmp.Diff(gqlparser.FilterTerm{Property: "tags", Operator: gqlparser.ContainsForwardComparator, Value: "x", Binding: &gqlparser.NamedBinding{Name: "tag"}}, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
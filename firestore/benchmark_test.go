@@ -0,0 +1,48 @@
+package firestore_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/karupanerura/gqlparser"
+	"github.com/karupanerura/gqlparser/benchmarks"
+	"github.com/karupanerura/gqlparser/firestore"
+)
+
+// BenchmarkTranslateFirestoreQuery measures the whole path from the source to the structured query of cloud.google.com/go/firestore
+// to compare the cost of parsing with the cost of building the request. The untranslatable queries are skipped.
+func BenchmarkTranslateFirestoreQuery(b *testing.B) {
+	translator := &firestore.Translator{ProjectID: "project"}
+	resolver := &gqlparser.BindingResolver{Named: map[string]any{"min": int64(1), "max": int64(10)}}
+	translate := func(source string) error {
+		q, err := gqlparser.ParseQuery(gqlparser.NewLexer(source))
+		if err != nil {
+			return err
+		}
+		if q.Where != nil {
+			if err := q.Where.Bind(resolver); err != nil {
+				return err
+			}
+		}
+		_, err = translator.TranslateQuery(q)
+		return err
+	}
+	for _, c := range benchmarks.Queries {
+		c := c
+		b.Run(c.Name, func(b *testing.B) {
+			if err := translate(c.Source); errors.Is(err, firestore.ErrUntranslatable) {
+				b.Skip(err)
+			} else if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := translate(c.Source); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
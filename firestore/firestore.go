@@ -0,0 +1,364 @@
+// Package firestore translates the GQL AST into the structured queries of Firestore in native mode.
+package firestore
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
+	"github.com/karupanerura/gqlparser"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var ErrUntranslatable = errors.New("untranslatable")
+
+const defaultDatabaseID = "(default)"
+
+// Translator translates the GQL AST into firestore.v1 StructuredQuery.
+// Kinds are mapped to the collection IDs, and keys are mapped to the document references.
+type Translator struct {
+	// ProjectID is used for the document references of the keys without PROJECT().
+	ProjectID string
+	// DatabaseID is used for the document references. It defaults to "(default)".
+	DatabaseID string
+}
+
+func (t *Translator) databaseID() string {
+	if t.DatabaseID == "" {
+		return defaultDatabaseID
+	}
+	return t.DatabaseID
+}
+
+// TranslateQuery translates the query into StructuredQuery.
+// The query is expected to be bound already.
+func (t *Translator) TranslateQuery(q *gqlparser.Query) (*firestorepb.StructuredQuery, error) {
+	if q.Distinct || len(q.DistinctOn) != 0 {
+		return nil, fmt.Errorf("%w: DISTINCT", ErrUntranslatable)
+	}
+
+	sq := &firestorepb.StructuredQuery{
+		From: []*firestorepb.StructuredQuery_CollectionSelector{
+			{CollectionId: string(q.Kind)},
+		},
+	}
+	if len(q.Properties) != 0 {
+		sq.Select = &firestorepb.StructuredQuery_Projection{}
+		for _, p := range q.Properties {
+			sq.Select.Fields = append(sq.Select.Fields, fieldReference(string(p)))
+		}
+	}
+	if q.Where != nil {
+		filter, err := t.translateCondition(q.Where)
+		if err != nil {
+			return nil, err
+		}
+		sq.Where = filter
+	}
+	for _, o := range q.OrderBy {
+		direction := firestorepb.StructuredQuery_ASCENDING
+		if o.Descending {
+			direction = firestorepb.StructuredQuery_DESCENDING
+		}
+		sq.OrderBy = append(sq.OrderBy, &firestorepb.StructuredQuery_Order{
+			Field:     fieldReference(string(o.Property)),
+			Direction: direction,
+		})
+	}
+	if q.Limit != nil {
+		if q.Limit.Cursor != nil {
+			return nil, fmt.Errorf("%w: LIMIT with cursor", ErrUntranslatable)
+		}
+		if q.Limit.Position > math.MaxInt32 {
+			return nil, fmt.Errorf("%w: LIMIT %d is too large", ErrUntranslatable, q.Limit.Position)
+		}
+		sq.Limit = wrapperspb.Int32(int32(q.Limit.Position))
+	}
+	if q.Offset != nil {
+		if q.Offset.Cursor != nil {
+			return nil, fmt.Errorf("%w: OFFSET with cursor", ErrUntranslatable)
+		}
+		if q.Offset.Position > math.MaxInt32 {
+			return nil, fmt.Errorf("%w: OFFSET %d is too large", ErrUntranslatable, q.Offset.Position)
+		}
+		sq.Offset = int32(q.Offset.Position)
+	}
+	return sq, nil
+}
+
+// TranslateAggregationQuery translates the aggregation query into StructuredAggregationQuery.
+func (t *Translator) TranslateAggregationQuery(q *gqlparser.AggregationQuery) (*firestorepb.StructuredAggregationQuery, error) {
+	sq, err := t.TranslateQuery(&q.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	saq := &firestorepb.StructuredAggregationQuery{
+		QueryType: &firestorepb.StructuredAggregationQuery_StructuredQuery{StructuredQuery: sq},
+	}
+	for _, aggregation := range q.Aggregations {
+		switch a := aggregation.(type) {
+		case *gqlparser.CountAggregation:
+			saq.Aggregations = append(saq.Aggregations, &firestorepb.StructuredAggregationQuery_Aggregation{
+				Operator: &firestorepb.StructuredAggregationQuery_Aggregation_Count_{
+					Count: &firestorepb.StructuredAggregationQuery_Aggregation_Count{},
+				},
+				Alias: a.Alias,
+			})
+		case *gqlparser.CountUpToAggregation:
+			saq.Aggregations = append(saq.Aggregations, &firestorepb.StructuredAggregationQuery_Aggregation{
+				Operator: &firestorepb.StructuredAggregationQuery_Aggregation_Count_{
+					Count: &firestorepb.StructuredAggregationQuery_Aggregation_Count{UpTo: wrapperspb.Int64(a.Limit)},
+				},
+				Alias: a.Alias,
+			})
+		case *gqlparser.SumAggregation:
+			saq.Aggregations = append(saq.Aggregations, &firestorepb.StructuredAggregationQuery_Aggregation{
+				Operator: &firestorepb.StructuredAggregationQuery_Aggregation_Sum_{
+					Sum: &firestorepb.StructuredAggregationQuery_Aggregation_Sum{Field: fieldReference(a.Property)},
+				},
+				Alias: a.Alias,
+			})
+		case *gqlparser.AvgAggregation:
+			saq.Aggregations = append(saq.Aggregations, &firestorepb.StructuredAggregationQuery_Aggregation{
+				Operator: &firestorepb.StructuredAggregationQuery_Aggregation_Avg_{
+					Avg: &firestorepb.StructuredAggregationQuery_Aggregation_Avg{Field: fieldReference(a.Property)},
+				},
+				Alias: a.Alias,
+			})
+		default:
+			return nil, fmt.Errorf("%w: aggregation %T", ErrUntranslatable, aggregation)
+		}
+	}
+	return saq, nil
+}
+
+// TranslateCondition translates the condition into the filter of StructuredQuery.
+func (t *Translator) TranslateCondition(cond gqlparser.Condition) (*firestorepb.StructuredQuery_Filter, error) {
+	return t.translateCondition(cond)
+}
+
+func (t *Translator) translateCondition(cond gqlparser.Condition) (*firestorepb.StructuredQuery_Filter, error) {
+	// the membership of the arrays must be translated before normalizing it into the equality
+	switch c := cond.(type) {
+	case *gqlparser.AndCompoundCondition:
+		return t.translateCompoundCondition(firestorepb.StructuredQuery_CompositeFilter_AND, c.Left, c.Right)
+	case *gqlparser.OrCompoundCondition:
+		return t.translateCompoundCondition(firestorepb.StructuredQuery_CompositeFilter_OR, c.Left, c.Right)
+	case *gqlparser.ForwardComparatorCondition:
+		if c.Comparator == gqlparser.ContainsForwardComparator {
+			return t.fieldFilter(c.Property, firestorepb.StructuredQuery_FieldFilter_ARRAY_CONTAINS, c.Value)
+		}
+	case *gqlparser.BackwardComparatorCondition:
+		if c.Comparator == gqlparser.InBackwardComparator {
+			return t.fieldFilter(c.Property, firestorepb.StructuredQuery_FieldFilter_ARRAY_CONTAINS, c.Value)
+		}
+	case *gqlparser.QuantifiedComparatorCondition:
+		if c.Comparator == gqlparser.ContainsAnyQuantifiedComparator {
+			return t.fieldFilter(c.Property, firestorepb.StructuredQuery_FieldFilter_ARRAY_CONTAINS_ANY, c.Value)
+		}
+		expanded, err := c.Expand()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUntranslatable, err)
+		}
+		return t.translateCondition(expanded)
+	}
+
+	switch c := cond.Normalize().(type) {
+	case *gqlparser.EitherComparatorCondition:
+		if c.Value == nil {
+			switch c.Comparator {
+			case gqlparser.EqualsEitherComparator:
+				return unaryFilter(c.Property, firestorepb.StructuredQuery_UnaryFilter_IS_NULL), nil
+			case gqlparser.NotEqualsEitherComparator:
+				return unaryFilter(c.Property, firestorepb.StructuredQuery_UnaryFilter_IS_NOT_NULL), nil
+			}
+		}
+		op, ok := eitherComparatorOperators[c.Comparator]
+		if !ok {
+			return nil, fmt.Errorf("%w: comparator %s", ErrUntranslatable, c.Comparator)
+		}
+		return t.fieldFilter(c.Property, op, c.Value)
+	case *gqlparser.ForwardComparatorCondition:
+		switch c.Comparator {
+		case gqlparser.InForwardComparator:
+			return t.fieldFilter(c.Property, firestorepb.StructuredQuery_FieldFilter_IN, c.Value)
+		case gqlparser.NotInForwardComparator:
+			return t.fieldFilter(c.Property, firestorepb.StructuredQuery_FieldFilter_NOT_IN, c.Value)
+		case gqlparser.HasAncestorForwardComparator:
+			return nil, fmt.Errorf("%w: HAS ANCESTOR (use the parent path of the request instead)", ErrUntranslatable)
+		default:
+			return nil, fmt.Errorf("%w: comparator %s", ErrUntranslatable, c.Comparator)
+		}
+	case *gqlparser.BackwardComparatorCondition:
+		return nil, fmt.Errorf("%w: comparator %s", ErrUntranslatable, c.Comparator)
+	default:
+		return nil, fmt.Errorf("%w: condition %T", ErrUntranslatable, cond)
+	}
+}
+
+var eitherComparatorOperators = map[gqlparser.EitherComparator]firestorepb.StructuredQuery_FieldFilter_Operator{
+	gqlparser.EqualsEitherComparator:                  firestorepb.StructuredQuery_FieldFilter_EQUAL,
+	gqlparser.NotEqualsEitherComparator:               firestorepb.StructuredQuery_FieldFilter_NOT_EQUAL,
+	gqlparser.GreaterThanEitherComparator:             firestorepb.StructuredQuery_FieldFilter_GREATER_THAN,
+	gqlparser.GreaterThanOrEqualsThanEitherComparator: firestorepb.StructuredQuery_FieldFilter_GREATER_THAN_OR_EQUAL,
+	gqlparser.LesserThanEitherComparator:              firestorepb.StructuredQuery_FieldFilter_LESS_THAN,
+	gqlparser.LesserThanOrEqualsEitherComparator:      firestorepb.StructuredQuery_FieldFilter_LESS_THAN_OR_EQUAL,
+}
+
+func (t *Translator) translateCompoundCondition(op firestorepb.StructuredQuery_CompositeFilter_Operator, left, right gqlparser.Condition) (*firestorepb.StructuredQuery_Filter, error) {
+	composite := &firestorepb.StructuredQuery_CompositeFilter{Op: op}
+	for _, cond := range []gqlparser.Condition{left, right} {
+		filter, err := t.translateCondition(cond)
+		if err != nil {
+			return nil, err
+		}
+
+		// flatten the nested filters that have the same operator
+		if cf, ok := filter.FilterType.(*firestorepb.StructuredQuery_Filter_CompositeFilter); ok && cf.CompositeFilter.Op == op {
+			composite.Filters = append(composite.Filters, cf.CompositeFilter.Filters...)
+		} else {
+			composite.Filters = append(composite.Filters, filter)
+		}
+	}
+	return &firestorepb.StructuredQuery_Filter{
+		FilterType: &firestorepb.StructuredQuery_Filter_CompositeFilter{CompositeFilter: composite},
+	}, nil
+}
+
+func (t *Translator) fieldFilter(property string, op firestorepb.StructuredQuery_FieldFilter_Operator, value any) (*firestorepb.StructuredQuery_Filter, error) {
+	v, err := t.translateValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return &firestorepb.StructuredQuery_Filter{
+		FilterType: &firestorepb.StructuredQuery_Filter_FieldFilter{
+			FieldFilter: &firestorepb.StructuredQuery_FieldFilter{
+				Field: fieldReference(property),
+				Op:    op,
+				Value: v,
+			},
+		},
+	}, nil
+}
+
+func unaryFilter(property string, op firestorepb.StructuredQuery_UnaryFilter_Operator) *firestorepb.StructuredQuery_Filter {
+	return &firestorepb.StructuredQuery_Filter{
+		FilterType: &firestorepb.StructuredQuery_Filter_UnaryFilter{
+			UnaryFilter: &firestorepb.StructuredQuery_UnaryFilter{
+				Op:          op,
+				OperandType: &firestorepb.StructuredQuery_UnaryFilter_Field{Field: fieldReference(property)},
+			},
+		},
+	}
+}
+
+func (t *Translator) translateValue(value any) (*firestorepb.Value, error) {
+	switch v := value.(type) {
+	case nil:
+		return &firestorepb.Value{ValueType: &firestorepb.Value_NullValue{NullValue: structpb.NullValue_NULL_VALUE}}, nil
+	case bool:
+		return &firestorepb.Value{ValueType: &firestorepb.Value_BooleanValue{BooleanValue: v}}, nil
+	case int64:
+		return &firestorepb.Value{ValueType: &firestorepb.Value_IntegerValue{IntegerValue: v}}, nil
+	case float64:
+		return &firestorepb.Value{ValueType: &firestorepb.Value_DoubleValue{DoubleValue: v}}, nil
+	case string:
+		return &firestorepb.Value{ValueType: &firestorepb.Value_StringValue{StringValue: v}}, nil
+	case []byte:
+		return &firestorepb.Value{ValueType: &firestorepb.Value_BytesValue{BytesValue: v}}, nil
+	case time.Time:
+		return &firestorepb.Value{ValueType: &firestorepb.Value_TimestampValue{TimestampValue: timestamppb.New(v)}}, nil
+	case *gqlparser.Key:
+		ref, err := t.documentReference(v)
+		if err != nil {
+			return nil, err
+		}
+		return &firestorepb.Value{ValueType: &firestorepb.Value_ReferenceValue{ReferenceValue: ref}}, nil
+	case []any:
+		values := make([]*firestorepb.Value, len(v))
+		for i, item := range v {
+			fv, err := t.translateValue(item)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = fv
+		}
+		return &firestorepb.Value{ValueType: &firestorepb.Value_ArrayValue{ArrayValue: &firestorepb.ArrayValue{Values: values}}}, nil
+	case gqlparser.BindingVariable:
+		return nil, fmt.Errorf("%w: unbound variable %v", ErrUntranslatable, v)
+	default:
+		return nil, fmt.Errorf("%w: value %T", ErrUntranslatable, value)
+	}
+}
+
+// documentReference translates the key into the resource name of the document.
+func (t *Translator) documentReference(key *gqlparser.Key) (string, error) {
+	if key.Namespace != "" {
+		return "", fmt.Errorf("%w: NAMESPACE(%q)", ErrUntranslatable, key.Namespace)
+	}
+
+	projectID := string(key.ProjectID)
+	if projectID == "" {
+		projectID = t.ProjectID
+	}
+	if projectID == "" {
+		return "", fmt.Errorf("%w: key without project id", ErrUntranslatable)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("projects/")
+	sb.WriteString(projectID)
+	sb.WriteString("/databases/")
+	sb.WriteString(t.databaseID())
+	sb.WriteString("/documents")
+	for _, path := range key.Path {
+		sb.WriteByte('/')
+		sb.WriteString(string(path.Kind))
+		sb.WriteByte('/')
+		if path.Name != "" {
+			if strings.ContainsRune(path.Name, '/') {
+				return "", fmt.Errorf("%w: key name %q", ErrUntranslatable, path.Name)
+			}
+			sb.WriteString(path.Name)
+		} else {
+			sb.WriteString(strconv.FormatInt(path.ID, 10))
+		}
+	}
+	return sb.String(), nil
+}
+
+func fieldReference(property string) *firestorepb.StructuredQuery_FieldReference {
+	if property == "__key__" {
+		return &firestorepb.StructuredQuery_FieldReference{FieldPath: "__name__"}
+	}
+
+	segments := strings.Split(property, ".")
+	for i, s := range segments {
+		if !isSimpleFieldName(s) {
+			segments[i] = "`" + strings.NewReplacer("\\", "\\\\", "`", "\\`").Replace(s) + "`"
+		}
+	}
+	return &firestorepb.StructuredQuery_FieldReference{FieldPath: strings.Join(segments, ".")}
+}
+
+// isSimpleFieldName matches the regular expression `[a-zA-Z_][a-zA-Z_0-9]*`.
+func isSimpleFieldName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if b == '_' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || i != 0 && '0' <= b && b <= '9' {
+			continue
+		}
+		return false
+	}
+	return true
+}
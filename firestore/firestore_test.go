@@ -0,0 +1,249 @@
+package firestore_test
+
+import (
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+	"github.com/karupanerura/gqlparser/firestore"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestTranslateQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		source  string
+		want    *firestorepb.StructuredQuery
+		wantErr bool
+	}{
+		{
+			name:   "Simple",
+			source: "SELECT a, `b c` FROM Kind ORDER BY __key__ DESC LIMIT 10 OFFSET 5",
+			want: &firestorepb.StructuredQuery{
+				Select: &firestorepb.StructuredQuery_Projection{
+					Fields: []*firestorepb.StructuredQuery_FieldReference{
+						{FieldPath: "a"},
+						{FieldPath: "`b c`"},
+					},
+				},
+				From: []*firestorepb.StructuredQuery_CollectionSelector{
+					{CollectionId: "Kind"},
+				},
+				OrderBy: []*firestorepb.StructuredQuery_Order{
+					{Field: &firestorepb.StructuredQuery_FieldReference{FieldPath: "__name__"}, Direction: firestorepb.StructuredQuery_DESCENDING},
+				},
+				Limit:  wrapperspb.Int32(10),
+				Offset: 5,
+			},
+		},
+		{
+			name:   "Where",
+			source: "SELECT * FROM Kind WHERE a = 1 AND b IS NULL AND c IN ARRAY('x', 'y') AND __key__ > KEY(Kind, 'foo')",
+			want: &firestorepb.StructuredQuery{
+				From: []*firestorepb.StructuredQuery_CollectionSelector{
+					{CollectionId: "Kind"},
+				},
+				Where: &firestorepb.StructuredQuery_Filter{
+					FilterType: &firestorepb.StructuredQuery_Filter_CompositeFilter{
+						CompositeFilter: &firestorepb.StructuredQuery_CompositeFilter{
+							Op: firestorepb.StructuredQuery_CompositeFilter_AND,
+							Filters: []*firestorepb.StructuredQuery_Filter{
+								{
+									FilterType: &firestorepb.StructuredQuery_Filter_FieldFilter{
+										FieldFilter: &firestorepb.StructuredQuery_FieldFilter{
+											Field: &firestorepb.StructuredQuery_FieldReference{FieldPath: "a"},
+											Op:    firestorepb.StructuredQuery_FieldFilter_EQUAL,
+											Value: &firestorepb.Value{ValueType: &firestorepb.Value_IntegerValue{IntegerValue: 1}},
+										},
+									},
+								},
+								{
+									FilterType: &firestorepb.StructuredQuery_Filter_UnaryFilter{
+										UnaryFilter: &firestorepb.StructuredQuery_UnaryFilter{
+											Op: firestorepb.StructuredQuery_UnaryFilter_IS_NULL,
+											OperandType: &firestorepb.StructuredQuery_UnaryFilter_Field{
+												Field: &firestorepb.StructuredQuery_FieldReference{FieldPath: "b"},
+											},
+										},
+									},
+								},
+								{
+									FilterType: &firestorepb.StructuredQuery_Filter_FieldFilter{
+										FieldFilter: &firestorepb.StructuredQuery_FieldFilter{
+											Field: &firestorepb.StructuredQuery_FieldReference{FieldPath: "c"},
+											Op:    firestorepb.StructuredQuery_FieldFilter_IN,
+											Value: &firestorepb.Value{
+												ValueType: &firestorepb.Value_ArrayValue{
+													ArrayValue: &firestorepb.ArrayValue{
+														Values: []*firestorepb.Value{
+															{ValueType: &firestorepb.Value_StringValue{StringValue: "x"}},
+															{ValueType: &firestorepb.Value_StringValue{StringValue: "y"}},
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+								{
+									FilterType: &firestorepb.StructuredQuery_Filter_FieldFilter{
+										FieldFilter: &firestorepb.StructuredQuery_FieldFilter{
+											Field: &firestorepb.StructuredQuery_FieldReference{FieldPath: "__name__"},
+											Op:    firestorepb.StructuredQuery_FieldFilter_GREATER_THAN,
+											Value: &firestorepb.Value{
+												ValueType: &firestorepb.Value_ReferenceValue{
+													ReferenceValue: "projects/my-project/databases/(default)/documents/Kind/foo",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:   "Contains",
+			source: "SELECT * FROM Kind WHERE tags CONTAINS 'x'",
+			want: &firestorepb.StructuredQuery{
+				From: []*firestorepb.StructuredQuery_CollectionSelector{
+					{CollectionId: "Kind"},
+				},
+				Where: &firestorepb.StructuredQuery_Filter{
+					FilterType: &firestorepb.StructuredQuery_Filter_FieldFilter{
+						FieldFilter: &firestorepb.StructuredQuery_FieldFilter{
+							Field: &firestorepb.StructuredQuery_FieldReference{FieldPath: "tags"},
+							Op:    firestorepb.StructuredQuery_FieldFilter_ARRAY_CONTAINS,
+							Value: &firestorepb.Value{ValueType: &firestorepb.Value_StringValue{StringValue: "x"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:   "BackwardIn",
+			source: "SELECT * FROM Kind WHERE 'x' IN tags",
+			want: &firestorepb.StructuredQuery{
+				From: []*firestorepb.StructuredQuery_CollectionSelector{
+					{CollectionId: "Kind"},
+				},
+				Where: &firestorepb.StructuredQuery_Filter{
+					FilterType: &firestorepb.StructuredQuery_Filter_FieldFilter{
+						FieldFilter: &firestorepb.StructuredQuery_FieldFilter{
+							Field: &firestorepb.StructuredQuery_FieldReference{FieldPath: "tags"},
+							Op:    firestorepb.StructuredQuery_FieldFilter_ARRAY_CONTAINS,
+							Value: &firestorepb.Value{ValueType: &firestorepb.Value_StringValue{StringValue: "x"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "Ancestor",
+			source:  "SELECT * FROM Kind WHERE __key__ HAS ANCESTOR KEY(Parent, 1)",
+			wantErr: true,
+		},
+		{
+			name:    "Namespace",
+			source:  "SELECT * FROM Kind WHERE __key__ = KEY(NAMESPACE('ns'), Kind, 1)",
+			wantErr: true,
+		},
+		{
+			name:    "Distinct",
+			source:  "SELECT DISTINCT a FROM Kind",
+			wantErr: true,
+		},
+		{
+			name:    "Unbound",
+			source:  "SELECT * FROM Kind WHERE a = @1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source))
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+
+			translator := &firestore.Translator{ProjectID: "my-project"}
+			got, err := translator.TranslateQuery(query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TranslateQuery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !errors.Is(err, firestore.ErrUntranslatable) {
+					t.Errorf("TranslateQuery() error = %v, want ErrUntranslatable", err)
+				}
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("TranslateQuery() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTranslateAggregationQuery(t *testing.T) {
+	t.Parallel()
+
+	query, err := gqlparser.ParseAggregationQuery(gqlparser.NewLexer("AGGREGATE COUNT_UP_TO(5) AS c, SUM(a) OVER (SELECT * FROM Kind WHERE b != NULL)"))
+	if err != nil {
+		t.Fatalf("ParseAggregationQuery() error = %v", err)
+	}
+
+	got, err := (&firestore.Translator{}).TranslateAggregationQuery(query)
+	if err != nil {
+		t.Fatalf("TranslateAggregationQuery() error = %v", err)
+	}
+
+	want := &firestorepb.StructuredAggregationQuery{
+		QueryType: &firestorepb.StructuredAggregationQuery_StructuredQuery{
+			StructuredQuery: &firestorepb.StructuredQuery{
+				From: []*firestorepb.StructuredQuery_CollectionSelector{
+					{CollectionId: "Kind"},
+				},
+				Where: &firestorepb.StructuredQuery_Filter{
+					FilterType: &firestorepb.StructuredQuery_Filter_UnaryFilter{
+						UnaryFilter: &firestorepb.StructuredQuery_UnaryFilter{
+							Op: firestorepb.StructuredQuery_UnaryFilter_IS_NOT_NULL,
+							OperandType: &firestorepb.StructuredQuery_UnaryFilter_Field{
+								Field: &firestorepb.StructuredQuery_FieldReference{FieldPath: "b"},
+							},
+						},
+					},
+				},
+			},
+		},
+		Aggregations: []*firestorepb.StructuredAggregationQuery_Aggregation{
+			{
+				Operator: &firestorepb.StructuredAggregationQuery_Aggregation_Count_{
+					Count: &firestorepb.StructuredAggregationQuery_Aggregation_Count{UpTo: wrapperspb.Int64(5)},
+				},
+				Alias: "c",
+			},
+			{
+				Operator: &firestorepb.StructuredAggregationQuery_Aggregation_Sum_{
+					Sum: &firestorepb.StructuredAggregationQuery_Aggregation_Sum{
+						Field: &firestorepb.StructuredQuery_FieldReference{FieldPath: "a"},
+					},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("TranslateAggregationQuery() mismatch (-want +got):\n%s", diff)
+	}
+}
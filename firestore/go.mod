@@ -0,0 +1,23 @@
+module github.com/karupanerura/gqlparser/firestore
+
+go 1.22.0
+
+require (
+	cloud.google.com/go/firestore v1.17.0
+	github.com/google/go-cmp v0.6.0
+	github.com/karupanerura/gqlparser v0.0.0
+	google.golang.org/protobuf v1.34.2
+)
+
+require (
+	github.com/karupanerura/runetrie v0.0.0-20240410000052-ea8ffbef19db // indirect
+	golang.org/x/net v0.28.0 // indirect
+	golang.org/x/sys v0.24.0 // indirect
+	golang.org/x/text v0.17.0 // indirect
+	google.golang.org/genproto v0.0.0-20240903143218-8af14fe29dc1 // indirect
+	google.golang.org/genproto/googleapis/api v0.0.0-20240903143218-8af14fe29dc1 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240903143218-8af14fe29dc1 // indirect
+	google.golang.org/grpc v1.66.0 // indirect
+)
+
+replace github.com/karupanerura/gqlparser => ../
@@ -0,0 +1,244 @@
+package gqlparser
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NeedsQuoting reports whether the name must be quoted with backticks to be used as an identifier.
+// e.g. the names containing dots or spaces, or starting with the reserved keywords.
+func NeedsQuoting(name string) bool {
+	if name == "" {
+		return true
+	}
+	if '0' <= name[0] && name[0] <= '9' {
+		return true
+	}
+	for i := 0; i < len(name); i++ {
+		if !isSymbolByte(name[i]) {
+			return true
+		}
+	}
+
+	// the lexer takes the longest reserved word at the beginning of the identifier
+	if _, ok := keywordTrie.LongestMatchPrefixOf(name); ok {
+		return true
+	}
+	if _, ok := operatorTrie.LongestMatchPrefixOf(name); ok {
+		return true
+	}
+	if _, ok := orderTrie.LongestMatchPrefixOf(name); ok {
+		return true
+	}
+	if _, ok := booleanTrie.LongestMatchPrefixOf(name); ok {
+		return true
+	}
+	return false
+}
+
+// String returns the property name as GQL identifier. It's quoted with backticks if needed.
+func (p Property) String() string {
+	return formatIdentifier(string(p))
+}
+
+// String returns the ORDER BY item as GQL. The nested property path is quoted segment by segment.
+func (o OrderBy) String() string {
+	s := formatPropertyPath(o.Property)
+	if o.Descending {
+		s += " DESC"
+	}
+	return s
+}
+
+// formatPropertyPath formats the property as the nested path. e.g. a.`b c`
+// It's quoted as a whole if it has any empty segment.
+func formatPropertyPath(p Property) string {
+	segments := strings.Split(string(p), ".")
+	for i, s := range segments {
+		if s == "" {
+			return formatIdentifier(string(p))
+		}
+		segments[i] = formatIdentifier(s)
+	}
+	return strings.Join(segments, ".")
+}
+
+// formatIdentifier quotes the name only if needed.
+func formatIdentifier(name string) string {
+	if !NeedsQuoting(name) {
+		return name
+	}
+	return QuoteIdentifier(name)
+}
+
+// FormatValue renders the value as GQL literal to be parsed as the same value again. e.g.
+//   - nil: NULL
+//   - bool: TRUE or FALSE
+//   - int64: 42 (the other integer types are rendered as int64, and the unsigned integers over math.MaxInt64 are rejected)
+//   - float64: 1.5 (the integral doubles are rendered with the decimal point like 1.0, and float32 is rendered as float64)
+//   - string: 'it\'s' (the backslashes and the quotes are escaped)
+//   - []byte: BLOB('aGVsbG8')
+//   - time.Time: DATETIME('2006-01-02T15:04:05.999999999Z')
+//   - *Key: KEY(PROJECT('p'), NAMESPACE('n'), Parent, 'foo', Child, 1)
+//   - []any: ARRAY(1, 'a')
+//   - *NamedBinding and *IndexedBinding: @name and @1
+//
+// It returns ErrTypeMismatch if the value cannot be written as GQL literal, such as NaN or the key without the path.
+func FormatValue(v any) (string, error) {
+	var sb strings.Builder
+	if err := formatValue(&sb, v); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func formatValue(sb *strings.Builder, v any) error {
+	switch v := v.(type) {
+	case nil:
+		sb.WriteString("NULL")
+	case bool:
+		if v {
+			sb.WriteString("TRUE")
+		} else {
+			sb.WriteString("FALSE")
+		}
+	case int, int8, int16, int32, int64:
+		sb.WriteString(strconv.FormatInt(reflect.ValueOf(v).Int(), 10))
+	case uint, uint8, uint16, uint32, uint64:
+		n := reflect.ValueOf(v).Uint()
+		if n > math.MaxInt64 {
+			return fmt.Errorf("%w: %v overflows GQL integer", ErrTypeMismatch, v)
+		}
+		sb.WriteString(strconv.FormatUint(n, 10))
+	case float32, float64:
+		f := reflect.ValueOf(v).Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("%w: %v cannot be written as GQL literal", ErrTypeMismatch, v)
+		}
+		sb.WriteString(formatDouble(f))
+	case string:
+		sb.WriteString(QuoteString(v, '\''))
+	case []byte:
+		sb.WriteString("BLOB(")
+		sb.WriteString(QuoteString(base64.RawURLEncoding.EncodeToString(v), '\''))
+		sb.WriteString(")")
+	case time.Time:
+		sb.WriteString("DATETIME(")
+		sb.WriteString(QuoteString(v.Format(time.RFC3339Nano), '\''))
+		sb.WriteString(")")
+	case *Key:
+		return formatKey(sb, v)
+	case []any:
+		sb.WriteString("ARRAY(")
+		for i, elem := range v {
+			if i != 0 {
+				sb.WriteString(", ")
+			}
+			if err := formatValue(sb, elem); err != nil {
+				return err
+			}
+		}
+		sb.WriteString(")")
+	case *NamedBinding:
+		if v.Name == "" {
+			return fmt.Errorf("%w: the binding must have the name", ErrTypeMismatch)
+		}
+		sb.WriteString("@")
+		sb.WriteString(v.Name)
+	case *IndexedBinding:
+		sb.WriteString("@")
+		sb.WriteString(strconv.FormatInt(v.Index, 10))
+	default:
+		return fmt.Errorf("%w: unsupported value %T", ErrTypeMismatch, v)
+	}
+	return nil
+}
+
+func formatKey(sb *strings.Builder, key *Key) error {
+	if key == nil || len(key.Path) == 0 {
+		return fmt.Errorf("%w: the key must have the path", ErrTypeMismatch)
+	}
+	sb.WriteString("KEY(")
+	if key.ProjectID != "" {
+		sb.WriteString("PROJECT(")
+		sb.WriteString(QuoteString(string(key.ProjectID), '\''))
+		sb.WriteString("), ")
+	}
+	if key.Namespace != "" {
+		sb.WriteString("NAMESPACE(")
+		sb.WriteString(QuoteString(key.Namespace, '\''))
+		sb.WriteString("), ")
+	}
+	for i, path := range key.Path {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+
+		sb.WriteString(formatIdentifier(string(path.Kind)))
+		sb.WriteString(", ")
+		switch {
+		case path.Name != "":
+			sb.WriteString(QuoteString(path.Name, '\''))
+		case path.Binding != nil && path.ID == 0:
+			if err := formatValue(sb, path.Binding); err != nil {
+				return err
+			}
+		default:
+			sb.WriteString(strconv.FormatInt(path.ID, 10))
+		}
+	}
+	sb.WriteString(")")
+	return nil
+}
+
+// formatDouble formats the double with the decimal point to be lexed as the double again.
+func formatDouble(f float64) string {
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
+
+// QuoteString quotes the string with the quote (', " or `) by the escaping rules of the lexer,
+// so the quoted string is lexed into the same content again.
+// The backslashes, the quote and the control characters like the new lines are escaped. e.g. 'it\'s\n'
+// It panics if the quote is not any of them.
+func QuoteString(s string, quote byte) string {
+	replacer, ok := quoteReplacers[quote]
+	if !ok {
+		panic(fmt.Sprintf("gqlparser: invalid quote %q", quote))
+	}
+	return string(quote) + replacer.Replace(s) + string(quote)
+}
+
+// QuoteIdentifier quotes the name with backticks to be used as an identifier. e.g. `first name`
+// Use NeedsQuoting to quote only the names that cannot be written as they are.
+func QuoteIdentifier(name string) string {
+	return QuoteString(name, '`')
+}
+
+// quoteReplacers are the inverse of unquoteReplacer for each quote.
+var quoteReplacers = map[byte]*strings.Replacer{
+	'\'': newQuoteReplacer('\''),
+	'"':  newQuoteReplacer('"'),
+	'`':  newQuoteReplacer('`'),
+}
+
+func newQuoteReplacer(quote byte) *strings.Replacer {
+	return strings.NewReplacer(
+		"\\", "\\\\",
+		string(quote), "\\"+string(quote),
+		"\u0000", "\\0",
+		"\b", "\\b",
+		"\n", "\\n",
+		"\r", "\\r",
+		"\t", "\\t",
+		"\u001A", "\\Z",
+	)
+}
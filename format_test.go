@@ -0,0 +1,245 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestPropertyString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		property gqlparser.Property
+		want     string
+	}{
+		{"name", "name"},
+		{"__key__", "__key__"},
+		{"_$x1", "_$x1"},
+		{"", "``"},
+		{"a.b", "`a.b`"},
+		{"first name", "`first name`"},
+		{"1st", "`1st`"},
+		{"order", "`order`"},
+		{"ORDER_ID", "`ORDER_ID`"},
+		{"description", "`description`"},
+		{"true", "`true`"},
+		{"a`b\\c", "`a\\`b\\\\c`"},
+		{"名前", "`名前`"},
+	}
+	for _, tt := range tests {
+		if got := tt.property.String(); got != tt.want {
+			t.Errorf("Property(%q).String() = %q, want %q", tt.property, got, tt.want)
+		}
+
+		// the quoted name must be parsed as the original name
+		query, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT " + tt.property.String() + " FROM Kind"))
+		if err != nil {
+			t.Errorf("ParseQuery() error = %v", err)
+			continue
+		}
+		if len(query.Properties) != 1 || query.Properties[0] != tt.property {
+			t.Errorf("Properties = %q, want %q", query.Properties, tt.property)
+		}
+	}
+}
+
+func TestOrderByString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		orderBy gqlparser.OrderBy
+		want    string
+	}{
+		{gqlparser.OrderBy{Property: "a"}, "a"},
+		{gqlparser.OrderBy{Property: "a.b", Descending: true}, "a.b DESC"},
+		{gqlparser.OrderBy{Property: "a b.c"}, "`a b`.c"},
+		{gqlparser.OrderBy{Property: "order.by"}, "`order`.`by`"},
+		{gqlparser.OrderBy{Property: "a..b"}, "`a..b`"},
+	}
+	for _, tt := range tests {
+		got := tt.orderBy.String()
+		if got != tt.want {
+			t.Errorf("OrderBy.String() = %q, want %q", got, tt.want)
+		}
+
+		query, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM Kind ORDER BY " + got))
+		if err != nil {
+			t.Errorf("ParseQuery() error = %v", err)
+			continue
+		}
+		if len(query.OrderBy) != 1 || query.OrderBy[0] != tt.orderBy {
+			t.Errorf("OrderBy = %v, want %v", query.OrderBy, tt.orderBy)
+		}
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"Null", nil, "NULL"},
+		{"True", true, "TRUE"},
+		{"False", false, "FALSE"},
+		{"Integer", int64(-42), "-42"},
+		{"Double", 1.5, "1.5"},
+		{"IntegralDouble", float64(2), "2.0"},
+		{"String", "it's a \\ test", `'it\'s a \\ test'`},
+		{"Blob", []byte("hello"), "BLOB('aGVsbG8')"},
+		{"DateTime", time.Date(2006, 1, 2, 15, 4, 5, 123000000, time.UTC), "DATETIME('2006-01-02T15:04:05.123Z')"},
+		{
+			"Key",
+			&gqlparser.Key{
+				ProjectID: "p",
+				Namespace: "n",
+				Path: []*gqlparser.KeyPath{
+					{Kind: "Parent", Name: "foo"},
+					{Kind: "Child", ID: 1},
+				},
+			},
+			"KEY(PROJECT('p'), NAMESPACE('n'), Parent, 'foo', Child, 1)",
+		},
+		{"QuotedKind", &gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "Task-List", ID: 1}, {Kind: "a `b`", Name: "c"}}}, "KEY(`Task-List`, 1, `a \\`b\\``, 'c')"},
+		{"Array", []any{int64(1), "a", []byte("b")}, "ARRAY(1, 'a', BLOB('Yg'))"},
+		{"NamedBinding", &gqlparser.NamedBinding{Name: "age"}, "@age"},
+		{"IndexedBinding", &gqlparser.IndexedBinding{Index: 1}, "@1"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := gqlparser.FormatValue(tt.value)
+			if err != nil {
+				t.Fatalf("FormatValue() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatValue() = %q, want %q", got, tt.want)
+			}
+
+			// the literal must be parsed as the original value
+			cond, err := gqlparser.ParseCondition(gqlparser.NewLexer("a = " + got))
+			if err != nil {
+				t.Fatalf("ParseCondition() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.value, cond.(*gqlparser.EitherComparatorCondition).Value); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFormatValue_NumericTypes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"Int", -42, "-42"},
+		{"Int8", int8(-8), "-8"},
+		{"Uint32", uint32(32), "32"},
+		{"Uint64", uint64(math.MaxInt64), "9223372036854775807"},
+		{"Float32", float32(0.5), "0.5"},
+		{"IntegralFloat32", float32(2), "2.0"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := gqlparser.FormatValue(tt.value)
+			if err != nil {
+				t.Fatalf("FormatValue() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatValue_Error(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"NaN", math.NaN()},
+		{"Infinity", math.Inf(1)},
+		{"Unsupported", complex(1, 0)},
+		{"UintOverflow", uint64(math.MaxUint64)},
+		{"Float32NaN", float32(math.NaN())},
+		{"EmptyKey", &gqlparser.Key{}},
+		{"NestedUnsupported", []any{int64(1), struct{}{}}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := gqlparser.FormatValue(tt.value); !errors.Is(err, gqlparser.ErrTypeMismatch) {
+				t.Errorf("FormatValue() error = %v, want %v", err, gqlparser.ErrTypeMismatch)
+			}
+		})
+	}
+}
+
+func TestQuoteString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		content string
+		quote   byte
+		want    string
+	}{
+		{"", '\'', "''"},
+		{"it's", '\'', `'it\'s'`},
+		{"it's", '"', `"it's"`},
+		{`say "hi"`, '"', `"say \"hi\""`},
+		{"a`b", '`', "`a\\`b`"},
+		{`\0`, '\'', `'\\0'`},
+		{"a\nb\tc\rd", '\'', `'a\nb\tc\rd'`},
+		{"\x00\b\x1a", '\'', `'\0\b\Z'`},
+		{"100%_", '\'', "'100%_'"},
+		{"名前", '\'', "'名前'"},
+	}
+	for _, tt := range tests {
+		got := gqlparser.QuoteString(tt.content, tt.quote)
+		if got != tt.want {
+			t.Errorf("QuoteString(%q, %q) = %s, want %s", tt.content, tt.quote, got, tt.want)
+		}
+
+		// the quoted string must be lexed into the original content
+		tokens, err := gqlparser.ReadAllTokens(gqlparser.NewLexer(got))
+		if err != nil {
+			t.Errorf("ReadAllTokens() error = %v", err)
+			continue
+		}
+		want := []gqlparser.Token{&gqlparser.StringToken{Quote: tt.quote, Content: tt.content, RawContent: got}}
+		if diff := cmp.Diff(want, tokens); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	t.Parallel()
+
+	if got, want := gqlparser.QuoteIdentifier("name"), "`name`"; got != want {
+		t.Errorf("QuoteIdentifier() = %s, want %s", got, want)
+	}
+	if got, want := gqlparser.QuoteIdentifier("a`b\\c"), "`a\\`b\\\\c`"; got != want {
+		t.Errorf("QuoteIdentifier() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,69 @@
+package gqlparser_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata/golden")
+
+// TestGolden parses the queries in testdata/golden/*.gql and compares the dumps with the .golden files.
+// The dump is the Describe output of the syntax, or the error message if the query is invalid.
+// Add the regression case by adding the .gql file and running `go test -run TestGolden -update`.
+func TestGolden(t *testing.T) {
+	t.Parallel()
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "golden", "*.gql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no golden cases")
+	}
+	for _, path := range paths {
+		path := path
+		name := strings.TrimSuffix(filepath.Base(path), ".gql")
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			source, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := dumpGolden(strings.TrimSpace(string(source)))
+
+			goldenPath := strings.TrimSuffix(path, ".gql") + ".golden"
+			if *update {
+				if err := os.WriteFile(goldenPath, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("%v (run `go test -run TestGolden -update` to create it)", err)
+			}
+			if diff := cmp.Diff(string(want), got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func dumpGolden(source string) string {
+	query, aggregationQuery, err := gqlparser.ParseQueryOrAggregationQuery(gqlparser.NewLexer(source))
+	if err != nil {
+		return "error: " + err.Error() + "\n"
+	}
+	if query != nil {
+		return gqlparser.Describe(query)
+	}
+	return gqlparser.Describe(aggregationQuery)
+}
@@ -0,0 +1,182 @@
+package gqltest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+// ConformanceSource is the query lexed into the tokens given to TokenSourceFactory by RunTokenSourceConformance.
+const ConformanceSource = "SELECT a, b FROM Kind WHERE c = 1 ORDER BY d DESC"
+
+// errConformance is the error injected into the sources by RunTokenSourceConformance.
+var errConformance = errors.New("conformance error")
+
+// TokenSourceFactory creates the TokenSource under the test. The source must return the tokens in the order,
+// and then return err from Read if err isn't nil, or reach the end of the tokens otherwise.
+// The factory can return nil if the source can't inject the error to skip the cases of the error propagation.
+type TokenSourceFactory func(t *testing.T, tokens []gqlparser.Token, err error) gqlparser.TokenSource
+
+// RunTokenSourceConformance tests the TokenSource created by the factory follows the contract of
+// gqlparser.TokenSource like gqlparser.Lexer: the ordering of Unread, the end of the tokens and the error propagation.
+func RunTokenSourceConformance(t *testing.T, factory TokenSourceFactory) {
+	t.Helper()
+
+	tokens, err := ReadAll(gqlparser.NewLexer(ConformanceSource))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("ReadAll", func(t *testing.T) {
+		ts := factory(t, tokens, nil)
+		got := readAll(t, ts)
+		if diff := cmp.Diff(tokens, got); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		ts := factory(t, nil, nil)
+		assertEndOfToken(t, ts)
+	})
+
+	t.Run("EndOfToken", func(t *testing.T) {
+		ts := factory(t, tokens, nil)
+		_ = readAll(t, ts)
+		for i := 0; i < 2; i++ {
+			assertEndOfToken(t, ts)
+		}
+	})
+
+	t.Run("UnreadOrder", func(t *testing.T) {
+		ts := factory(t, tokens, nil)
+		read := make([]gqlparser.Token, 3)
+		for i := range read {
+			read[i] = mustRead(t, ts)
+		}
+		for i := len(read) - 1; i >= 0; i-- {
+			ts.Unread(read[i])
+		}
+
+		got := readAll(t, ts)
+		if diff := cmp.Diff(tokens, got); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+	})
+
+	t.Run("UnreadAfterNext", func(t *testing.T) {
+		ts := factory(t, tokens, nil)
+		tok := mustRead(t, ts)
+		if !ts.Next() {
+			t.Fatal("Next() = false, want true")
+		}
+		ts.Unread(tok)
+
+		got := readAll(t, ts)
+		if diff := cmp.Diff(tokens, got); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+	})
+
+	t.Run("UnreadAtEnd", func(t *testing.T) {
+		ts := factory(t, tokens, nil)
+		got := readAll(t, ts)
+		ts.Unread(got[len(got)-1])
+		if !ts.Next() {
+			t.Fatal("Next() = false after Unread, want true")
+		}
+		if diff := cmp.Diff(tokens[len(tokens)-1], mustRead(t, ts)); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+		assertEndOfToken(t, ts)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		ts := factory(t, tokens[:2], errConformance)
+		if ts == nil {
+			t.Skip("the source can't inject the error")
+		}
+		for i := 0; i < 2; i++ {
+			if diff := cmp.Diff(tokens[i], mustRead(t, ts)); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		}
+		if !ts.Next() {
+			t.Fatal("Next() = false before the error, want true")
+		}
+		if tok, err := ts.Read(); !errors.Is(err, errConformance) {
+			t.Errorf("Read() error = %v, want %v", err, errConformance)
+		} else if tok != nil {
+			t.Errorf("Read() = %v with the error, want nil", tok)
+		}
+	})
+
+	t.Run("Parse", func(t *testing.T) {
+		want, err := gqlparser.ParseQuery(gqlparser.NewLexer(ConformanceSource))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := gqlparser.ParseQuery(factory(t, tokens, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+	})
+
+	t.Run("ParseError", func(t *testing.T) {
+		ts := factory(t, tokens[:4], errConformance)
+		if ts == nil {
+			t.Skip("the source can't inject the error")
+		}
+		if _, err := gqlparser.ParseQuery(ts); !errors.Is(err, errConformance) {
+			t.Errorf("ParseQuery() error = %v, want %v", err, errConformance)
+		}
+	})
+}
+
+func mustRead(t *testing.T, ts gqlparser.TokenSource) gqlparser.Token {
+	t.Helper()
+
+	if !ts.Next() {
+		t.Fatal("Next() = false, want true")
+	}
+	tok, err := ts.Read()
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if tok == nil {
+		t.Fatal("Read() = nil without the error")
+	}
+	return tok
+}
+
+func readAll(t *testing.T, ts gqlparser.TokenSource) []gqlparser.Token {
+	t.Helper()
+
+	var tokens []gqlparser.Token
+	for ts.Next() {
+		tok, err := ts.Read()
+		if err != nil {
+			t.Fatalf("Read() error = %v after Next() = true", err)
+		}
+		tokens = append(tokens, tok)
+	}
+	return tokens
+}
+
+func assertEndOfToken(t *testing.T, ts gqlparser.TokenSource) {
+	t.Helper()
+
+	if ts.Next() {
+		t.Error("Next() = true at the end of the tokens, want false")
+	}
+	if tok, err := ts.Read(); !errors.Is(err, gqlparser.ErrEndOfToken) {
+		t.Errorf("Read() error = %v at the end of the tokens, want %v", err, gqlparser.ErrEndOfToken)
+	} else if tok != nil {
+		t.Errorf("Read() = %v at the end of the tokens, want nil", tok)
+	}
+}
@@ -0,0 +1,91 @@
+// Package gqltest provides the TokenSource implementations to test the code consuming the TokenSource,
+// and the conformance tests of the TokenSource implementations.
+package gqltest
+
+import (
+	"errors"
+
+	"github.com/karupanerura/gqlparser"
+)
+
+// SliceTokenSource reads the tokens from the slice.
+type SliceTokenSource struct {
+	tokens []gqlparser.Token
+}
+
+var _ gqlparser.TokenSource = (*SliceTokenSource)(nil)
+
+// NewSliceTokenSource creates the SliceTokenSource reading the copy of the tokens.
+func NewSliceTokenSource(tokens ...gqlparser.Token) *SliceTokenSource {
+	return &SliceTokenSource{tokens: append([]gqlparser.Token{}, tokens...)}
+}
+
+func (ts *SliceTokenSource) Next() bool {
+	return len(ts.tokens) != 0
+}
+
+func (ts *SliceTokenSource) Read() (gqlparser.Token, error) {
+	if len(ts.tokens) == 0 {
+		return nil, gqlparser.ErrEndOfToken
+	}
+
+	tok := ts.tokens[0]
+	ts.tokens = ts.tokens[1:]
+	return tok, nil
+}
+
+func (ts *SliceTokenSource) Unread(tok gqlparser.Token) {
+	ts.tokens = append([]gqlparser.Token{tok}, ts.tokens...)
+}
+
+// ErrorTokenSource injects the error into the source. It returns the error on every Read after the given number of
+// successful Reads. The re-reads of the unread tokens are counted too.
+type ErrorTokenSource struct {
+	source gqlparser.TokenSource
+	after  int
+	err    error
+	reads  int
+}
+
+var _ gqlparser.TokenSource = (*ErrorTokenSource)(nil)
+
+// NewErrorTokenSource creates the ErrorTokenSource returning err after the source is read the given times.
+func NewErrorTokenSource(source gqlparser.TokenSource, after int, err error) *ErrorTokenSource {
+	return &ErrorTokenSource{source: source, after: after, err: err}
+}
+
+func (ts *ErrorTokenSource) Next() bool {
+	return ts.reads >= ts.after || ts.source.Next()
+}
+
+func (ts *ErrorTokenSource) Read() (gqlparser.Token, error) {
+	if ts.reads >= ts.after {
+		return nil, ts.err
+	}
+
+	tok, err := ts.source.Read()
+	if err != nil {
+		return nil, err
+	}
+	ts.reads++
+	return tok, nil
+}
+
+func (ts *ErrorTokenSource) Unread(tok gqlparser.Token) {
+	ts.source.Unread(tok)
+}
+
+// ReadAll reads the tokens until the end of the source.
+func ReadAll(ts gqlparser.TokenSource) ([]gqlparser.Token, error) {
+	var tokens []gqlparser.Token
+	for ts.Next() {
+		tok, err := ts.Read()
+		if errors.Is(err, gqlparser.ErrEndOfToken) {
+			break
+		} else if err != nil {
+			return tokens, err
+		}
+		tokens = append(tokens, tok)
+	}
+	return tokens, nil
+}
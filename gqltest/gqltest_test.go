@@ -0,0 +1,66 @@
+package gqltest_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+	"github.com/karupanerura/gqlparser/gqltest"
+)
+
+func TestSliceTokenSource(t *testing.T) {
+	t.Parallel()
+
+	tokens := []gqlparser.Token{
+		&gqlparser.KeywordToken{Name: "KEY", RawContent: "KEY", Position: 0},
+		&gqlparser.OperatorToken{Type: "(", RawContent: "(", Position: 3},
+	}
+	ts := gqltest.NewSliceTokenSource(tokens...)
+
+	tok, err := ts.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts.Unread(tok)
+
+	got, err := gqltest.ReadAll(ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(tokens, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if _, err := ts.Read(); !errors.Is(err, gqlparser.ErrEndOfToken) {
+		t.Errorf("Read() error = %v, want %v", err, gqlparser.ErrEndOfToken)
+	}
+}
+
+func TestErrorTokenSource(t *testing.T) {
+	t.Parallel()
+
+	errInjected := errors.New("injected")
+	for _, after := range []int{0, 1, 5, 10} {
+		ts := gqltest.NewErrorTokenSource(gqlparser.NewLexer("SELECT * FROM Kind WHERE a = 1"), after, errInjected)
+		if _, err := gqlparser.ParseQuery(ts); !errors.Is(err, errInjected) {
+			t.Errorf("after %d: ParseQuery() error = %v, want %v", after, err, errInjected)
+		}
+	}
+
+	ts := gqltest.NewErrorTokenSource(gqlparser.NewLexer("KEY(Kind, 1)"), 100, errInjected)
+	if _, err := gqlparser.ParseKey(ts); err != nil {
+		t.Errorf("ParseKey() error = %v", err)
+	}
+}
+
+func TestSliceTokenSource_Conformance(t *testing.T) {
+	t.Parallel()
+
+	gqltest.RunTokenSourceConformance(t, func(t *testing.T, tokens []gqlparser.Token, err error) gqlparser.TokenSource {
+		ts := gqltest.NewSliceTokenSource(tokens...)
+		if err != nil {
+			return gqltest.NewErrorTokenSource(ts, len(tokens), err)
+		}
+		return ts
+	})
+}
@@ -0,0 +1,197 @@
+package gqlparser
+
+import (
+	"sort"
+	"strings"
+)
+
+// Grammar is the machine-readable description of the syntax accepted by the parser with the default options.
+// The lexical terminals (symbol, quoted_name, string, integer, double and binding) are not defined in the rules.
+// The rules accepted only in lenient mode are marked by Lenient, so the strict grammar is the one without them.
+type Grammar struct {
+	Rules     []GrammarRule
+	Keywords  []string
+	Operators []GrammarOperator
+}
+
+// GrammarRule is the production rule written in the ISO EBNF notation.
+type GrammarRule struct {
+	Name       string
+	Definition string
+	// Lenient reports whether the rule is rejected in strict mode. e.g. OFFSET before LIMIT
+	Lenient bool
+}
+
+// OperatorCategory is the category of the infix operators in the conditions.
+type OperatorCategory string
+
+const (
+	// CompoundOperatorCategory combines the conditions. e.g. AND, OR
+	CompoundOperatorCategory OperatorCategory = "compound"
+	// EitherOperatorCategory takes the property on either side. e.g. =, <
+	EitherOperatorCategory OperatorCategory = "either"
+	// ForwardOperatorCategory takes the property on the left side. e.g. CONTAINS, NOT IN
+	ForwardOperatorCategory OperatorCategory = "forward"
+	// BackwardOperatorCategory takes the property on the right side. e.g. IN, HAS DESCENDANT
+	BackwardOperatorCategory OperatorCategory = "backward"
+)
+
+// GrammarOperator is the infix operator in the conditions.
+// The operator with the higher precedence binds more tightly.
+type GrammarOperator struct {
+	Operator   string
+	Category   OperatorCategory
+	Precedence uint8
+}
+
+// GrammarDescription returns the description of the grammar.
+// The keywords, the operators and the condition rules are derived from the tables used by the lexer and the parser.
+func GrammarDescription() *Grammar {
+	var kws []string
+	kws = append(kws, keywords...)
+	kws = append(kws, operatorKeywords...)
+	kws = append(kws, orderKeywords...)
+	kws = append(kws, booleanKeywords...)
+
+	return &Grammar{
+		Rules:     grammarRules(),
+		Keywords:  kws,
+		Operators: grammarOperators(),
+	}
+}
+
+// EBNF returns the rules as the EBNF text. Each rule is written in a line, and the lenient rules are followed by the comment.
+func (g *Grammar) EBNF() string {
+	var sb strings.Builder
+	for _, rule := range g.Rules {
+		sb.WriteString(rule.Name)
+		sb.WriteString(" = ")
+		sb.WriteString(rule.Definition)
+		sb.WriteString(" ;")
+		if rule.Lenient {
+			sb.WriteString(" (* lenient *)")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func grammarOperators() []GrammarOperator {
+	var operators []GrammarOperator
+	for _, table := range []struct {
+		category OperatorCategory
+		powers   map[string]uint8
+	}{
+		{CompoundOperatorCategory, infixCompoundOperatorBindingPowerMap},
+		{EitherOperatorCategory, infixEitherOperatorBindingPowerMap},
+		{ForwardOperatorCategory, infixForwardOperatorBindingPowerMap},
+		{BackwardOperatorCategory, infixBackwardOperatorBindingPowerMap},
+	} {
+		for op, bp := range table.powers {
+			operators = append(operators, GrammarOperator{Operator: op, Category: table.category, Precedence: bp})
+		}
+	}
+	sort.Slice(operators, func(i, j int) bool {
+		if operators[i].Precedence != operators[j].Precedence {
+			return operators[i].Precedence > operators[j].Precedence
+		}
+		if operators[i].Category != operators[j].Category {
+			return operators[i].Category < operators[j].Category
+		}
+		return operators[i].Operator < operators[j].Operator
+	})
+	return operators
+}
+
+func grammarRules() []GrammarRule {
+	rules := []GrammarRule{
+		{Name: "query", Definition: `query_body , [ terminator ]`},
+		{Name: "terminator", Definition: `";"`, Lenient: true},
+		{Name: "query_body", Definition: `"SELECT" , [ distinct ] , projection , "FROM" , kind , [ "WHERE" , condition ] , [ "ORDER" , "BY" , order_by , { "," , order_by } ] , [ result_range ]`},
+		{Name: "result_range", Definition: `"LIMIT" , limit , [ "OFFSET" , result_position ] | "OFFSET" , result_position | offset_limit`},
+		{Name: "offset_limit", Definition: `"OFFSET" , result_position , "LIMIT" , limit`, Lenient: true},
+		{Name: "aggregation_query", Definition: `( "SELECT" , aggregations , "FROM" , kind , [ "WHERE" , condition ] | "AGGREGATE" , aggregations , "OVER" , "(" , query_body , ")" ) , [ terminator ]`},
+		{Name: "aggregations", Definition: `aggregation , { "," , aggregation }`},
+		{Name: "aggregation", Definition: `( "COUNT" , "(" , "*" , ")" | "COUNT_UP_TO" , "(" , integer , ")" | "SUM" , "(" , name , ")" | "AVG" , "(" , name , ")" ) , [ "AS" , name ]`},
+		{Name: "distinct", Definition: `"DISTINCT" , [ "ON" , "(" , name , { "," , name } , ")" ]`},
+		{Name: "projection", Definition: `"*" | projected_property , { "," , projected_property }`},
+		{Name: "projected_property", Definition: `name , [ alias ]`},
+		{Name: "alias", Definition: `"AS" , name`, Lenient: true},
+		{Name: "kind", Definition: `name`},
+		{Name: "name", Definition: `symbol | quoted_name`},
+		// the symbols may have the dots, but only ORDER BY accepts the quoted names after the dots. e.g. a.`b c`
+		{Name: "property_path", Definition: `name , { "." , name }`},
+		{Name: "order_by", Definition: `property_path , [ "ASC" | "DESC" ]`},
+		{Name: "limit", Definition: `"FIRST" , "(" , ( integer , "," , binding | binding , "," , integer ) , ")" | result_position`},
+		{Name: "result_position", Definition: `( integer | binding ) , { "+" , integer }`},
+	}
+
+	// the compound conditions are ordered from the loosest binding
+	compounds := make([]string, 0, len(infixCompoundOperatorBindingPowerMap))
+	for op := range infixCompoundOperatorBindingPowerMap {
+		compounds = append(compounds, op)
+	}
+	sort.Slice(compounds, func(i, j int) bool {
+		return infixCompoundOperatorBindingPowerMap[compounds[i]] < infixCompoundOperatorBindingPowerMap[compounds[j]]
+	})
+	for i, op := range compounds {
+		name := strings.ToLower(op) + "_condition"
+		operand := "comparison"
+		if i+1 < len(compounds) {
+			operand = strings.ToLower(compounds[i+1]) + "_condition"
+		}
+		if i == 0 {
+			rules = append(rules, GrammarRule{Name: "condition", Definition: name})
+		}
+		rules = append(rules, GrammarRule{Name: name, Definition: operand + " , { " + ebnfTerminal(op) + " , " + operand + " }"})
+	}
+
+	rules = append(rules,
+		GrammarRule{Name: "comparison", Definition: `"(" , condition , ")" | name , "IS" , "NULL" | name , ( either_comparator | forward_comparator ) , value | name , ( "IN" | "NOT" , "IN" ) , "(" , values , ")" | value , ( either_comparator | backward_comparator ) , name`},
+		GrammarRule{Name: "either_comparator", Definition: ebnfAlternatives(sortedOperators(infixEitherOperatorBindingPowerMap))},
+		GrammarRule{Name: "forward_comparator", Definition: ebnfAlternatives(sortedOperators(infixForwardOperatorBindingPowerMap), "IS")},
+		GrammarRule{Name: "backward_comparator", Definition: ebnfAlternatives(sortedOperators(infixBackwardOperatorBindingPowerMap))},
+		GrammarRule{Name: "value", Definition: `"NULL" | boolean | integer | double | string | binding | key | "ARRAY" , "(" , values , ")" | "BLOB" , "(" , string , ")" | "DATETIME" , "(" , string , ")"`},
+		GrammarRule{Name: "values", Definition: `[ value , { "," , value } , [ trailing_comma ] ]`},
+		GrammarRule{Name: "trailing_comma", Definition: `","`, Lenient: true},
+		GrammarRule{Name: "boolean", Definition: ebnfAlternatives(booleanKeywords)},
+		GrammarRule{Name: "key", Definition: `"KEY" , "(" , [ "PROJECT" , "(" , string , ")" , "," ] , [ "NAMESPACE" , "(" , string , ")" , "," ] , key_path , { "," , key_path } , ")"`},
+		GrammarRule{Name: "key_path", Definition: `name , "," , ( string | integer | binding )`},
+	)
+	return rules
+}
+
+func sortedOperators(table map[string]uint8) []string {
+	ops := make([]string, 0, len(table))
+	for op := range table {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+// ebnfAlternatives joins the operators as the alternatives except the excluded ones.
+func ebnfAlternatives(ops []string, excludes ...string) string {
+	var alternatives []string
+	for _, op := range ops {
+		excluded := false
+		for _, exclude := range excludes {
+			if op == exclude {
+				excluded = true
+			}
+		}
+		if !excluded {
+			alternatives = append(alternatives, ebnfTerminal(op))
+		}
+	}
+	return strings.Join(alternatives, " | ")
+}
+
+// ebnfTerminal quotes the words in the operator individually because the lexer takes them as the separated tokens.
+func ebnfTerminal(op string) string {
+	words := strings.Fields(op)
+	for i, word := range words {
+		words[i] = `"` + word + `"`
+	}
+	return strings.Join(words, " , ")
+}
@@ -0,0 +1,145 @@
+package gqlparser_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestGrammarDescription(t *testing.T) {
+	t.Parallel()
+
+	g := gqlparser.GrammarDescription()
+
+	t.Run("Terminals", func(t *testing.T) {
+		t.Parallel()
+
+		for _, m := range regexp.MustCompile(`"([^"]+)"`).FindAllStringSubmatch(g.EBNF(), -1) {
+			var tokens []gqlparser.Token
+			for lexer := gqlparser.NewLexer(m[1]); lexer.Next(); {
+				tok, err := lexer.Read()
+				if err != nil {
+					t.Fatalf("terminal %q: %v", m[1], err)
+				}
+				tokens = append(tokens, tok)
+			}
+			if len(tokens) != 1 {
+				t.Errorf("terminal %q is lexed as %d tokens", m[1], len(tokens))
+				continue
+			}
+			switch tokens[0].(type) {
+			case *gqlparser.KeywordToken, *gqlparser.OperatorToken, *gqlparser.OrderToken, *gqlparser.BooleanToken, *gqlparser.WildcardToken:
+			default:
+				t.Errorf("terminal %q is lexed as %T", m[1], tokens[0])
+			}
+		}
+	})
+
+	t.Run("NonTerminals", func(t *testing.T) {
+		t.Parallel()
+
+		defined := map[string]bool{
+			"symbol": true, "quoted_name": true, "string": true, "integer": true, "double": true, "binding": true,
+		}
+		for _, rule := range g.Rules {
+			defined[rule.Name] = true
+		}
+		for _, rule := range g.Rules {
+			definition := regexp.MustCompile(`"[^"]+"`).ReplaceAllString(rule.Definition, "")
+			for _, name := range regexp.MustCompile(`[a-z_]+`).FindAllString(definition, -1) {
+				if !defined[name] {
+					t.Errorf("rule %s refers undefined %s", rule.Name, name)
+				}
+			}
+		}
+	})
+
+	t.Run("Keywords", func(t *testing.T) {
+		t.Parallel()
+
+		for _, keyword := range g.Keywords {
+			if !gqlparser.NeedsQuoting(strings.ToLower(keyword)) {
+				t.Errorf("keyword %s is not reserved", keyword)
+			}
+		}
+	})
+
+	t.Run("Precedence", func(t *testing.T) {
+		t.Parallel()
+
+		precedences := map[string]uint8{}
+		for _, op := range g.Operators {
+			precedences[string(op.Category)+" "+op.Operator] = op.Precedence
+		}
+		if !(precedences["compound OR"] < precedences["compound AND"] && precedences["compound AND"] < precedences["either ="]) {
+			t.Errorf("unexpected precedences: %v", precedences)
+		}
+		if !strings.Contains(g.EBNF(), "or_condition = and_condition , { \"OR\" , and_condition } ;\n") {
+			t.Errorf("unexpected EBNF:\n%s", g.EBNF())
+		}
+	})
+}
+
+// grammarExamples are the queries using each rule. They're parsed to test the rules against the parser.
+var grammarExamples = map[string][]string{
+	"query":               {"SELECT * FROM Kind"},
+	"terminator":          {"SELECT * FROM Kind;", "AGGREGATE COUNT(*) OVER (SELECT * FROM Kind);"},
+	"query_body":          {"SELECT DISTINCT a FROM Kind WHERE a = 1 ORDER BY a DESC, b LIMIT 1 OFFSET 2"},
+	"result_range":        {"SELECT * FROM Kind LIMIT 1", "SELECT * FROM Kind LIMIT 1 OFFSET @1", "SELECT * FROM Kind OFFSET 1"},
+	"offset_limit":        {"SELECT * FROM Kind OFFSET 1 LIMIT 2"},
+	"aggregation_query":   {"SELECT COUNT(*) FROM Kind WHERE a = 1", "AGGREGATE COUNT(*) OVER (SELECT * FROM Kind LIMIT 1)"},
+	"aggregations":        {"AGGREGATE COUNT(*), SUM(a) OVER (SELECT * FROM Kind)"},
+	"aggregation":         {"AGGREGATE COUNT(*) AS c, COUNT_UP_TO(10), SUM(a), AVG(`b c`) AS d OVER (SELECT * FROM Kind)"},
+	"distinct":            {"SELECT DISTINCT a FROM Kind", "SELECT DISTINCT ON (a, b.c) a, b.c FROM Kind"},
+	"projection":          {"SELECT * FROM Kind", "SELECT a, b FROM Kind"},
+	"projected_property":  {"SELECT a.b, `c d` FROM Kind"},
+	"alias":               {"SELECT a AS x FROM Kind"},
+	"kind":                {"SELECT * FROM `Kind-1`"},
+	"name":                {"SELECT a, `b c` FROM Kind"},
+	"property_path":       {"SELECT * FROM Kind ORDER BY a.b.`c d`, `e`.f"},
+	"order_by":            {"SELECT * FROM Kind ORDER BY a ASC, b DESC, c"},
+	"limit":               {"SELECT * FROM Kind LIMIT FIRST(10, @cursor)", "SELECT * FROM Kind LIMIT FIRST(@cursor, 10)", "SELECT * FROM Kind LIMIT @cursor + 10"},
+	"result_position":     {"SELECT * FROM Kind LIMIT 10 OFFSET @cursor + 1 + 2"},
+	"condition":           {"SELECT * FROM Kind WHERE a = 1"},
+	"or_condition":        {"SELECT * FROM Kind WHERE a = 1 OR b = 2"},
+	"and_condition":       {"SELECT * FROM Kind WHERE a = 1 AND b = 2 OR c = 3"},
+	"comparison":          {"SELECT * FROM Kind WHERE (a IS NULL) AND b IN (1, 2) AND c NOT IN ARRAY(3) AND 4 IN d"},
+	"either_comparator":   {"SELECT * FROM Kind WHERE a = 1 AND b != 2 AND c > 3 AND d >= 4 AND e < 5 AND f <= 6 AND 7 = g"},
+	"forward_comparator":  {"SELECT * FROM Kind WHERE a CONTAINS 1 AND __key__ HAS ANCESTOR KEY(Parent, 1) AND b IN ARRAY(1) AND c NOT IN ARRAY(2)"},
+	"backward_comparator": {"SELECT * FROM Kind WHERE 1 IN a AND KEY(Child, 1) HAS DESCENDANT __key__"},
+	"value":               {"SELECT * FROM Kind WHERE a = NULL AND b = TRUE AND c = 1 AND d = 1.5 AND e = 'x' AND f = @1 AND g = BLOB('YQ') AND h = DATETIME('2006-01-02T15:04:05Z') AND i IN ARRAY()"},
+	"values":              {"SELECT * FROM Kind WHERE a IN ARRAY(1, 'a')"},
+	"trailing_comma":      {"SELECT * FROM Kind WHERE a IN ARRAY(1, 2,)", "SELECT * FROM Kind WHERE a IN (1,)"},
+	"boolean":             {"SELECT * FROM Kind WHERE a = TRUE AND b = FALSE"},
+	"key":                 {"SELECT * FROM Kind WHERE __key__ = KEY(PROJECT('p'), NAMESPACE('n'), Parent, 'a', Kind, 1)"},
+	"key_path":            {"SELECT * FROM Kind WHERE __key__ = KEY(Kind, 'a') OR __key__ = KEY(Kind, 1) OR __key__ = KEY(Kind, @id)"},
+}
+
+func TestGrammarDescription_Parser(t *testing.T) {
+	t.Parallel()
+
+	for _, rule := range gqlparser.GrammarDescription().Rules {
+		rule := rule
+		t.Run(rule.Name, func(t *testing.T) {
+			t.Parallel()
+
+			examples := grammarExamples[rule.Name]
+			if len(examples) == 0 {
+				t.Fatalf("no examples of rule %s", rule.Name)
+			}
+			for _, source := range examples {
+				if _, _, err := gqlparser.ParseQueryOrAggregationQuery(gqlparser.NewLexer(source)); err != nil {
+					t.Errorf("ParseQueryOrAggregationQuery(%q) error = %v", source, err)
+				}
+				_, _, err := gqlparser.ParseQueryOrAggregationQuery(gqlparser.NewLexer(source), gqlparser.WithStrictMode())
+				if rule.Lenient && err == nil {
+					t.Errorf("ParseQueryOrAggregationQuery(%q) should fail in strict mode", source)
+				} else if !rule.Lenient && err != nil {
+					t.Errorf("ParseQueryOrAggregationQuery(%q) error = %v in strict mode", source, err)
+				}
+			}
+		})
+	}
+}
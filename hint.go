@@ -0,0 +1,121 @@
+package gqlparser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Hint is the candidate fix of the parse error for the interactive tools. e.g. the consoles and the editors
+type Hint struct {
+	// Token is the token to be fixed.
+	Token Token
+	// Message is the explanation of the fix.
+	Message string
+	// Replacement is the suggested replacement of the token.
+	Replacement string
+}
+
+// Hints returns the candidate fixes of the error. It returns nil if no fix is known.
+//   - The reserved words used as the names are suggested to be quoted with backticks. e.g. SELECT key FROM order
+//   - The misspelled keywords are suggested to be replaced with the closest keywords. e.g. SELETC * FROM Kind
+//     The keywords of the optional clauses are suggested at the end of the query too. e.g. SELECT * FROM Kind LIMT 10
+func (e *ParseError) Hints() []Hint {
+	var syntaxErr *SyntaxError
+	if !errors.As(e.Err, &syntaxErr) || syntaxErr.Token == nil {
+		return nil
+	}
+
+	var hints []Hint
+	if word, ok := reservedWordOf(syntaxErr.Token); ok {
+		quoted := QuoteIdentifier(word)
+		hints = append(hints, Hint{
+			Token:       syntaxErr.Token,
+			Message:     fmt.Sprintf("%q is a reserved word; quote it with backticks to use it as a name: %s", word, quoted),
+			Replacement: quoted,
+		})
+	}
+	if suggestion := syntaxErr.Suggestion(); suggestion != "" {
+		hints = append(hints, Hint{
+			Token:       syntaxErr.Token,
+			Message:     fmt.Sprintf("did you mean %s?", suggestion),
+			Replacement: suggestion,
+		})
+	}
+	return hints
+}
+
+// reservedWordOf returns the content of the token if it's the reserved word that can be the name when quoted.
+func reservedWordOf(token Token) (string, bool) {
+	switch t := token.(type) {
+	case *KeywordToken, *BooleanToken, *OrderToken:
+		return t.GetContent(), true
+	case *OperatorToken:
+		// the word operators like AND and IN, not the punctuations
+		content := t.GetContent()
+		return content, isWord(content)
+	default:
+		return "", false
+	}
+}
+
+func isWord(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i] | 0x20; c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Suggestion returns the expected keyword closest to the misspelled name. e.g. SELECT for SELETC
+// The distance is measured by Levenshtein distance counting the transpositions, and the keyword is suggested
+// only if the distance is at most a third of its length. It returns the empty string if no keyword is close.
+func (e *SyntaxError) Suggestion() string {
+	symbol, ok := e.Token.(*SymbolToken)
+	if !ok || len(e.Expected) == 0 {
+		return ""
+	}
+
+	name := strings.ToUpper(symbol.Content)
+	suggestion, best := "", 0
+	for _, keyword := range e.Expected {
+		d := editDistance(name, keyword)
+		if d == 0 || d > max(1, len(keyword)/3) {
+			continue
+		}
+		if suggestion == "" || d < best {
+			suggestion, best = keyword, d
+		}
+	}
+	return suggestion
+}
+
+// editDistance returns the optimal string alignment distance of the strings in bytes.
+func editDistance(a, b string) int {
+	// the rows of the distances for a[:i-2], a[:i-1] and a[:i]
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,93 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestParseErrorHints(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{name: "Projection", source: "SELECT key FROM Kind", want: []string{"`key`"}},
+		{name: "Kind", source: "SELECT * FROM order", want: []string{"`order`"}},
+		{name: "Operator", source: "SELECT * FROM Kind WHERE in = 1", want: []string{"`in`"}},
+		{name: "Boolean", source: "SELECT * FROM Kind ORDER BY true", want: []string{"`true`"}},
+		{name: "Order", source: "SELECT desc FROM Kind", want: []string{"`desc`"}},
+		{name: "Punctuation", source: "SELECT * FROM Kind WHERE = 1", want: nil},
+		{name: "Transposition", source: "SELETC * FROM Kind", want: []string{"SELECT"}},
+		{name: "Misspelled", source: "SELECT * FORM Kind", want: []string{"FROM"}},
+		{name: "Lowercase", source: "select * frm Kind", want: []string{"FROM"}},
+		{name: "TooFar", source: "SELECT * FOO Kind", want: nil},
+		{name: "EndOfQuery", source: "SELECT * FROM Kind WHERE", want: nil},
+		{name: "TrailingLimit", source: "SELECT * FROM Kind LIMT 10", want: []string{"LIMIT"}},
+		{name: "TrailingOrder", source: "SELECT * FROM Kind WHERE a = 1 ODRER BY a", want: []string{"ORDER"}},
+		{name: "TrailingDirection", source: "SELECT * FROM Kind ORDER BY a ACS", want: []string{"ASC"}},
+		{name: "TrailingTooFar", source: "SELECT * FROM Kind FOO", want: nil},
+		{name: "ConditionOperator", source: "SELECT * FROM Kind WHERE a = 1 ADN b = 2", want: []string{"AND"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source))
+			var parseErr *gqlparser.ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("error = %v, want %T", err, parseErr)
+			}
+
+			var got []string
+			for _, hint := range parseErr.Hints() {
+				got = append(got, hint.Replacement)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseErrorHints_Message(t *testing.T) {
+	t.Parallel()
+
+	_, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT key FROM Kind"))
+	var parseErr *gqlparser.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("error = %v, want %T", err, parseErr)
+	}
+
+	want := []gqlparser.Hint{
+		{
+			Token:       &gqlparser.KeywordToken{Name: "KEY", RawContent: "key", Position: 7},
+			Message:     "\"key\" is a reserved word; quote it with backticks to use it as a name: `key`",
+			Replacement: "`key`",
+		},
+	}
+	if diff := cmp.Diff(want, parseErr.Hints()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestSyntaxErrorSuggestion(t *testing.T) {
+	t.Parallel()
+
+	_, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELETC * FROM Kind"))
+	var syntaxErr *gqlparser.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("error = %v, want %T", err, syntaxErr)
+	}
+	if got := syntaxErr.Suggestion(); got != "SELECT" {
+		t.Errorf("Suggestion() = %q, want %q", got, "SELECT")
+	}
+	if want := `unexpected token: SELETC at 0 (expect to be any of ["SELECT"]) (did you mean SELECT?)`; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,80 @@
+package gqlparser
+
+import "time"
+
+// ParseHooks is the callbacks to observe the parse operations. e.g. exporting the metrics
+// Every callback is optional.
+type ParseHooks struct {
+	OnParseStart func(ParseStartInfo)
+	OnParseEnd   func(ParseEndInfo)
+}
+
+// ParseStartInfo is passed to ParseHooks.OnParseStart.
+type ParseStartInfo struct {
+	// Operation is the name of the parse function. e.g. "ParseQuery"
+	Operation string
+}
+
+// ParseEndInfo is passed to ParseHooks.OnParseEnd.
+type ParseEndInfo struct {
+	// Operation is the name of the parse function. e.g. "ParseQuery"
+	Operation string
+	Duration  time.Duration
+	// Tokens is the number of the tokens consumed from the token source except the re-reads.
+	Tokens int
+	Err    error
+}
+
+// WithHooks sets the hooks called on every parse operation.
+func WithHooks(hooks *ParseHooks) ParseOption {
+	return func(o *parseOptions) {
+		o.hooks = hooks
+	}
+}
+
+// countingTokenSource counts the tokens consumed from the source.
+type countingTokenSource struct {
+	TokenSource
+	reads   int
+	unreads int
+}
+
+func (ts *countingTokenSource) Read() (Token, error) {
+	token, err := ts.TokenSource.Read()
+	if err == nil {
+		ts.reads++
+	}
+	return token, err
+}
+
+func (ts *countingTokenSource) Unread(token Token) {
+	ts.unreads++
+	ts.TokenSource.Unread(token)
+}
+
+func (ts *countingTokenSource) tokens() int {
+	return ts.reads - ts.unreads
+}
+
+// startParse calls the hooks on starting the operation. The returned function must be called with the result.
+func (o *parseOptions) startParse(operation string, ts TokenSource) (TokenSource, func(error)) {
+	if o.hooks == nil {
+		return ts, func(error) {}
+	}
+
+	if o.hooks.OnParseStart != nil {
+		o.hooks.OnParseStart(ParseStartInfo{Operation: operation})
+	}
+	counter := &countingTokenSource{TokenSource: ts}
+	start := time.Now()
+	return counter, func(err error) {
+		if o.hooks.OnParseEnd != nil {
+			o.hooks.OnParseEnd(ParseEndInfo{
+				Operation: operation,
+				Duration:  time.Since(start),
+				Tokens:    counter.tokens(),
+				Err:       err,
+			})
+		}
+	}
+}
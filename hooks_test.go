@@ -0,0 +1,39 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestWithHooks(t *testing.T) {
+	t.Parallel()
+
+	var starts []gqlparser.ParseStartInfo
+	var ends []gqlparser.ParseEndInfo
+	hooks := gqlparser.WithHooks(&gqlparser.ParseHooks{
+		OnParseStart: func(info gqlparser.ParseStartInfo) { starts = append(starts, info) },
+		OnParseEnd:   func(info gqlparser.ParseEndInfo) { ends = append(ends, info) },
+	})
+
+	if _, err := gqlparser.ParseKey(gqlparser.NewLexer("KEY(Kind, 1)"), hooks); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM"), hooks); err == nil {
+		t.Fatal("ParseQuery() error = nil")
+	}
+
+	if len(starts) != 2 || starts[0].Operation != "ParseKey" || starts[1].Operation != "ParseQuery" {
+		t.Errorf("starts = %+v", starts)
+	}
+	if len(ends) != 2 {
+		t.Fatalf("ends = %+v", ends)
+	}
+	if ends[0].Operation != "ParseKey" || ends[0].Tokens != 7 || ends[0].Err != nil || ends[0].Duration <= 0 {
+		t.Errorf("ends[0] = %+v", ends[0])
+	}
+	if ends[1].Operation != "ParseQuery" || ends[1].Tokens != 5 || !errors.Is(ends[1].Err, gqlparser.ErrNoTokens) {
+		t.Errorf("ends[1] = %+v", ends[1])
+	}
+}
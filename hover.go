@@ -0,0 +1,228 @@
+package gqlparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Hover is the description of the token under the cursor for the editors.
+type Hover struct {
+	// Span is the range of the described token or literal in the source.
+	Span Span
+	// Description describes the token in the context. e.g. Binding @name (named parameter), Comparator >= on property age
+	Description string
+}
+
+// HoverInfo describes the token at the byte offset in the query or the aggregation query for the editors.
+// The source must be valid with the options. It returns nil if there is no token to describe at the offset.
+// e.g. the whitespaces, the commas and the parentheses
+func HoverInfo(source string, offset int, opts ...ParseOption) (*Hover, error) {
+	scanner, err := newSymbolScanner(source, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	index := -1
+	for i, token := range scanner.tokens {
+		if token.GetPosition() <= offset && offset < scanner.ends[i] {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, nil
+	}
+	token := scanner.tokens[index]
+	hover := func(description string) (*Hover, error) {
+		return &Hover{Span: Span{Start: token.GetPosition(), End: scanner.ends[index]}, Description: description}, nil
+	}
+
+	if b, ok := token.(*BindingToken); ok {
+		if b.Index != 0 {
+			return hover(fmt.Sprintf("Binding @%d (indexed parameter)", b.Index))
+		}
+		return hover(fmt.Sprintf("Binding @%s (named parameter)", b.Name))
+	}
+	if first, last, kind, ok := scanner.literalAt(index); ok {
+		span := Span{Start: scanner.tokens[first].GetPosition(), End: scanner.ends[last]}
+		return &Hover{Span: span, Description: fmt.Sprintf("Literal %s (%s)", source[span.Start:span.End], kind)}, nil
+	}
+
+	for _, clause := range scanner.clauses() {
+		if offset < clause.Span.Start || clause.Span.End <= offset {
+			continue
+		}
+		for _, child := range clause.Children {
+			if child.Span.Start <= token.GetPosition() && token.GetPosition() < child.Span.End {
+				description := scanner.describe(child, index, opts)
+				if description == "" {
+					return nil, nil
+				}
+				return hover(description)
+			}
+		}
+		switch t := token.(type) {
+		case *WildcardToken:
+			return hover("All properties")
+		case *OperatorToken:
+			if t.Type == "AND" || t.Type == "OR" {
+				return hover("Operator " + t.Type)
+			}
+		}
+		if name, _ := scanner.clauseHeader(index); name != "" || isClauseKeyword(token, clause.Name) {
+			return hover("Clause " + clause.Name)
+		}
+		return nil, nil
+	}
+	return nil, nil
+}
+
+// isClauseKeyword reports whether the token is the keyword following the first one of the clause. e.g. BY of ORDER BY
+func isClauseKeyword(token Token, clause string) bool {
+	keyword, ok := token.(*KeywordToken)
+	return ok && keyword.Name == "BY" && strings.HasSuffix(clause, " BY")
+}
+
+// literalAt returns the range of the tokens of the literal at the index.
+// The literals taking the arguments in the parentheses are returned as a whole. e.g. KEY(Kind, 1)
+func (s *symbolScanner) literalAt(index int) (first, last int, kind LiteralSpanKind, ok bool) {
+	for i := 0; i < len(s.tokens); i++ {
+		keyword, isKeyword := s.tokens[i].(*KeywordToken)
+		if !isKeyword {
+			continue
+		}
+		kind, isLiteral := literalFunctions[keyword.Name]
+		if !isLiteral {
+			continue
+		}
+		// skip to the closing parenthesis. the parentheses are balanced because the query is valid.
+		j, depth := i+1, 0
+		for ; j < len(s.tokens); j++ {
+			if op, ok := s.tokens[j].(*OperatorToken); ok && op.Type == "(" {
+				depth++
+			} else if ok && op.Type == ")" {
+				if depth--; depth == 0 {
+					break
+				}
+			}
+		}
+		if i <= index && index <= j {
+			return i, j, kind, true
+		}
+		i = j
+	}
+
+	switch t := s.tokens[index].(type) {
+	case *StringToken:
+		if t.Quote != '`' {
+			return index, index, StringLiteralSpan, true
+		}
+	case *NumericToken:
+		if t.Floating {
+			return index, index, DoubleLiteralSpan, true
+		}
+		return index, index, IntegerLiteralSpan, true
+	case *BooleanToken:
+		return index, index, BooleanLiteralSpan, true
+	case *KeywordToken:
+		if t.Name == "NULL" {
+			return index, index, NullLiteralSpan, true
+		}
+	}
+	return 0, 0, "", false
+}
+
+// describe describes the token at the index in the symbol. It returns the empty string if there is nothing to describe.
+func (s *symbolScanner) describe(symbol *DocumentSymbol, index int, opts []ParseOption) string {
+	token := s.tokens[index]
+	text := s.source[token.GetPosition():s.ends[index]]
+	switch symbol.Kind {
+	case KindDocumentSymbol:
+		return "Kind " + symbol.Name
+	case AggregationDocumentSymbol:
+		return "Aggregation " + symbol.Name
+	case PropertyDocumentSymbol:
+		if _, ok := token.(*WildcardToken); ok {
+			return "All properties"
+		}
+		if !isNameToken(token) {
+			return ""
+		}
+		if index > 0 {
+			if as, ok := s.tokens[index-1].(*KeywordToken); ok && as.Name == "AS" {
+				property := s.tokens[index-2]
+				return fmt.Sprintf("Alias %s of property %s", text, s.source[property.GetPosition():s.ends[index-2]])
+			}
+		}
+		return "Property " + text
+	case OrderDocumentSymbol:
+		order := "ascending"
+		if strings.HasSuffix(strings.ToUpper(symbol.Name), "DESC") {
+			order = "descending"
+		}
+		property := strings.TrimSpace(s.source[symbol.Span.Start:s.ends[s.firstTokenOf(symbol)]])
+		return fmt.Sprintf("Property %s in %s order", property, order)
+	case ConditionDocumentSymbol:
+		cond, err := ParseCondition(NewLexer(symbol.Name), opts...)
+		if err != nil {
+			return ""
+		}
+		property, comparator := describeCondition(cond)
+		if property == "" {
+			return ""
+		}
+		switch t := token.(type) {
+		case *OperatorToken:
+			return fmt.Sprintf("Comparator %s on property %s", comparator, property)
+		case *SymbolToken:
+			if op, ok := s.tokens[index-1].(*OperatorToken); ok && op.Type == "CONTAINS" && !strings.EqualFold(t.Content, property) {
+				// the quantifier of CONTAINS ANY and CONTAINS ALL
+				return fmt.Sprintf("Comparator %s on property %s", comparator, property)
+			}
+		}
+		if isNameToken(token) {
+			return "Property " + property
+		}
+	}
+	return ""
+}
+
+// firstTokenOf returns the index of the first token of the symbol.
+func (s *symbolScanner) firstTokenOf(symbol *DocumentSymbol) int {
+	for i, token := range s.tokens {
+		if token.GetPosition() == symbol.Span.Start {
+			return i
+		}
+	}
+	return 0
+}
+
+// isNameToken reports whether the token is the name of the property, the kind or the alias.
+func isNameToken(token Token) bool {
+	switch t := token.(type) {
+	case *SymbolToken:
+		return true
+	case *StringToken:
+		return t.Quote == '`'
+	default:
+		return false
+	}
+}
+
+// describeCondition returns the property and the comparator of the comparison or the IS NULL condition.
+func describeCondition(cond Condition) (property string, comparator string) {
+	switch c := cond.(type) {
+	case *ForwardComparatorCondition:
+		return c.Property, string(c.Comparator)
+	case *BackwardComparatorCondition:
+		return c.Property, string(c.Comparator)
+	case *EitherComparatorCondition:
+		return c.Property, string(c.Comparator)
+	case *QuantifiedComparatorCondition:
+		return c.Property, string(c.Comparator)
+	case *IsNullCondition:
+		return c.Property, "IS NULL"
+	default:
+		return "", ""
+	}
+}
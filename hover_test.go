@@ -0,0 +1,117 @@
+package gqlparser_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestHoverInfo(t *testing.T) {
+	t.Parallel()
+
+	const source = "SELECT DISTINCT ON (a) a AS x, `b c` FROM Kind WHERE age >= @age AND (@1 IN tags OR __key__ HAS ANCESTOR KEY(Parent, 'p')) AND d IS NULL ORDER BY a DESC LIMIT 10"
+	at := func(s string) int {
+		return strings.Index(source, s)
+	}
+	tests := []struct {
+		name   string
+		offset int
+		want   *gqlparser.Hover
+	}{
+		{
+			name:   "NamedBinding",
+			offset: at("@age") + 2,
+			want:   &gqlparser.Hover{Span: gqlparser.Span{Start: at("@age"), End: at("@age") + 4}, Description: "Binding @age (named parameter)"},
+		},
+		{
+			name:   "IndexedBinding",
+			offset: at("@1"),
+			want:   &gqlparser.Hover{Span: gqlparser.Span{Start: at("@1"), End: at("@1") + 2}, Description: "Binding @1 (indexed parameter)"},
+		},
+		{
+			name:   "Comparator",
+			offset: at(">="),
+			want:   &gqlparser.Hover{Span: gqlparser.Span{Start: at(">="), End: at(">=") + 2}, Description: "Comparator >= on property age"},
+		},
+		{
+			name:   "BackwardComparator",
+			offset: at("IN tags"),
+			want:   &gqlparser.Hover{Span: gqlparser.Span{Start: at("IN tags"), End: at("IN tags") + 2}, Description: "Comparator IN on property tags"},
+		},
+		{
+			name:   "IsNull",
+			offset: at("IS NULL"),
+			want:   &gqlparser.Hover{Span: gqlparser.Span{Start: at("IS NULL"), End: at("IS NULL") + 2}, Description: "Comparator IS NULL on property d"},
+		},
+		{
+			name:   "ConditionProperty",
+			offset: at("tags"),
+			want:   &gqlparser.Hover{Span: gqlparser.Span{Start: at("tags"), End: at("tags") + 4}, Description: "Property tags"},
+		},
+		{
+			name:   "KeyLiteral",
+			offset: at("Parent"),
+			want:   &gqlparser.Hover{Span: gqlparser.Span{Start: at("KEY("), End: at("'p')") + 4}, Description: "Literal KEY(Parent, 'p') (key)"},
+		},
+		{
+			name:   "IntegerLiteral",
+			offset: at("10"),
+			want:   &gqlparser.Hover{Span: gqlparser.Span{Start: at("10"), End: at("10") + 2}, Description: "Literal 10 (integer)"},
+		},
+		{
+			name:   "Operator",
+			offset: at("OR"),
+			want:   &gqlparser.Hover{Span: gqlparser.Span{Start: at("OR"), End: at("OR") + 2}, Description: "Operator OR"},
+		},
+		{
+			name:   "Clause",
+			offset: at("BY"),
+			want:   &gqlparser.Hover{Span: gqlparser.Span{Start: at("BY"), End: at("BY") + 2}, Description: "Clause ORDER BY"},
+		},
+		{
+			name:   "Alias",
+			offset: at("x,"),
+			want:   &gqlparser.Hover{Span: gqlparser.Span{Start: at("x,"), End: at("x,") + 1}, Description: "Alias x of property a"},
+		},
+		{
+			name:   "QuotedProperty",
+			offset: at("`b c`") + 1,
+			want:   &gqlparser.Hover{Span: gqlparser.Span{Start: at("`b c`"), End: at("`b c`") + 5}, Description: "Property `b c`"},
+		},
+		{
+			name:   "Kind",
+			offset: at("Kind"),
+			want:   &gqlparser.Hover{Span: gqlparser.Span{Start: at("Kind"), End: at("Kind") + 4}, Description: "Kind Kind"},
+		},
+		{
+			name:   "Order",
+			offset: at("DESC"),
+			want:   &gqlparser.Hover{Span: gqlparser.Span{Start: at("DESC"), End: at("DESC") + 4}, Description: "Property a in descending order"},
+		},
+		{
+			name:   "Whitespace",
+			offset: at(" FROM"),
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := gqlparser.HoverInfo(source, tt.offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := gqlparser.HoverInfo("SELECT * FROM", 0); err == nil {
+		t.Error("HoverInfo() should fail")
+	}
+}
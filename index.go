@@ -0,0 +1,351 @@
+package gqlparser
+
+import (
+	"strconv"
+	"strings"
+)
+
+const keyProperty = "__key__"
+
+type IndexDirection string
+
+const (
+	AscendingIndexDirection  IndexDirection = "asc"
+	DescendingIndexDirection IndexDirection = "desc"
+)
+
+// Index is a composite index definition of Cloud Datastore.
+type Index struct {
+	Kind       Kind
+	Ancestor   bool
+	Properties []IndexProperty
+}
+
+type IndexProperty struct {
+	Name      Property
+	Direction IndexDirection
+}
+
+func (idx *Index) equal(other *Index) bool {
+	if idx.Kind != other.Kind || idx.Ancestor != other.Ancestor || len(idx.Properties) != len(other.Properties) {
+		return false
+	}
+	for i := range idx.Properties {
+		if idx.Properties[i] != other.Properties[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// RequiredIndexes returns the composite indexes needed to execute the query.
+// A query that has OR conditions needs an index for each disjunction.
+// It returns nil if the query can be served by the built-in indexes only.
+// The query that has more disjunctions than MaxDisjunctions is reported as LimitViolationError without expanding them.
+func (q *Query) RequiredIndexes() ([]*Index, error) {
+	requirements, err := queryIndexRequirements(q, nil)
+	if err != nil {
+		return nil, err
+	}
+	return indexRequirementsToIndexes(requirements), nil
+}
+
+// RequiredIndexes returns the composite indexes needed to execute the aggregation query.
+// The properties of SUM and AVG aggregations are required to be indexed too.
+func (q *AggregationQuery) RequiredIndexes() ([]*Index, error) {
+	requirements, err := q.indexRequirements()
+	if err != nil {
+		return nil, err
+	}
+	return indexRequirementsToIndexes(requirements), nil
+}
+
+func (q *AggregationQuery) indexRequirements() ([]*indexRequirement, error) {
+	var props []Property
+	for _, aggregation := range q.Aggregations {
+		switch a := aggregation.(type) {
+		case *SumAggregation:
+			props = append(props, Property(a.Property))
+		case *AvgAggregation:
+			props = append(props, Property(a.Property))
+		}
+	}
+	return queryIndexRequirements(&q.Query, props)
+}
+
+// indexRequirement is a required index with the number of leading properties for the equality filters.
+// The order of the equality properties doesn't matter to serve the query.
+type indexRequirement struct {
+	index      *Index
+	equalities int
+}
+
+func indexRequirementsToIndexes(requirements []*indexRequirement) []*Index {
+	if len(requirements) == 0 {
+		return nil
+	}
+	indexes := make([]*Index, len(requirements))
+	for i, r := range requirements {
+		indexes[i] = r.index
+	}
+	return indexes
+}
+
+func queryIndexRequirements(q *Query, extraProps []Property) ([]*indexRequirement, error) {
+	disjunctions := [][]Condition{nil}
+	if q.Where != nil {
+		var err error
+		if disjunctions, err = disjunctiveNormalForm(q.Where.Normalize()); err != nil {
+			return nil, err
+		}
+	}
+
+	var requirements []*indexRequirement
+	for _, conjunction := range disjunctions {
+		r := requiredIndex(q, conjunction, extraProps)
+		if r == nil {
+			continue
+		}
+
+		duplicated := false
+		for _, other := range requirements {
+			if other.index.equal(r.index) {
+				duplicated = true
+				break
+			}
+		}
+		if !duplicated {
+			requirements = append(requirements, r)
+		}
+	}
+	return requirements, nil
+}
+
+func requiredIndex(q *Query, conjunction []Condition, extraProps []Property) *indexRequirement {
+	idx := &Index{Kind: q.Kind}
+	seen := map[Property]struct{}{}
+	add := func(name Property, direction IndexDirection) {
+		if _, ok := seen[name]; ok {
+			return
+		}
+		seen[name] = struct{}{}
+		idx.Properties = append(idx.Properties, IndexProperty{Name: name, Direction: direction})
+	}
+
+	var inequalities []Property
+	for _, cond := range conjunction {
+		switch c := cond.(type) {
+		case *EitherComparatorCondition:
+			if c.Property == keyProperty {
+				continue
+			}
+			if c.Comparator == EqualsEitherComparator {
+				add(Property(c.Property), AscendingIndexDirection)
+			} else {
+				inequalities = append(inequalities, Property(c.Property))
+			}
+		case *ForwardComparatorCondition:
+			if c.Comparator == HasAncestorForwardComparator {
+				idx.Ancestor = true
+				continue
+			}
+			if c.Property == keyProperty {
+				continue
+			}
+			if c.Comparator == NotInForwardComparator {
+				inequalities = append(inequalities, Property(c.Property))
+			} else {
+				add(Property(c.Property), AscendingIndexDirection)
+			}
+		case *QuantifiedComparatorCondition:
+			// CONTAINS ANY and CONTAINS ALL are the equalities of the elements
+			add(Property(c.Property), AscendingIndexDirection)
+		}
+	}
+	equalities := len(idx.Properties)
+
+	orderBy := q.OrderBy
+	if n := len(orderBy); n != 0 && orderBy[n-1].Property == keyProperty && !orderBy[n-1].Descending {
+		// every index is implicitly ordered by the key in ascending order
+		orderBy = orderBy[:n-1]
+	}
+	for _, o := range orderBy {
+		if o.Descending {
+			add(o.Property, DescendingIndexDirection)
+		} else {
+			add(o.Property, AscendingIndexDirection)
+		}
+	}
+	for _, p := range inequalities {
+		add(p, AscendingIndexDirection)
+	}
+	for _, p := range q.Properties {
+		if p != keyProperty {
+			add(p, AscendingIndexDirection)
+		}
+	}
+	for _, p := range extraProps {
+		add(p, AscendingIndexDirection)
+	}
+
+	if len(idx.Properties) == 0 {
+		return nil
+	}
+	if len(idx.Properties) == 1 && !idx.Ancestor {
+		// single property indexes are built-in
+		return nil
+	}
+	if len(idx.Properties) == equalities {
+		// queries using only ancestor and equality filters are served by merge join of the built-in indexes
+		return nil
+	}
+	return &indexRequirement{index: idx, equalities: equalities}
+}
+
+// servedBy reports whether the declared index can serve the requirement.
+func (r *indexRequirement) servedBy(declared *Index) bool {
+	if r.index.Kind != declared.Kind || r.index.Ancestor != declared.Ancestor || len(r.index.Properties) != len(declared.Properties) {
+		return false
+	}
+
+	equalities := make(map[IndexProperty]struct{}, r.equalities)
+	for _, p := range r.index.Properties[:r.equalities] {
+		equalities[p] = struct{}{}
+	}
+	for _, p := range declared.Properties[:r.equalities] {
+		if _, ok := equalities[p]; !ok {
+			return false
+		}
+	}
+	for i := r.equalities; i < len(r.index.Properties); i++ {
+		if r.index.Properties[i] != declared.Properties[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// IndexUsage is a pair of the required index and the declared index that serves it.
+type IndexUsage struct {
+	Required *Index
+	Declared *Index
+}
+
+// IndexCheckResult is the result of checking a query against the declared composite indexes.
+type IndexCheckResult struct {
+	Served  []IndexUsage
+	Missing []*Index
+}
+
+// Satisfiable reports whether every required index has been declared.
+func (r *IndexCheckResult) Satisfiable() bool {
+	return len(r.Missing) == 0
+}
+
+// CheckIndexes checks whether the declared composite indexes can serve the query.
+// The query that has more disjunctions than MaxDisjunctions is reported as RequiredIndexes.
+func (q *Query) CheckIndexes(declared []*Index) (*IndexCheckResult, error) {
+	requirements, err := queryIndexRequirements(q, nil)
+	if err != nil {
+		return nil, err
+	}
+	return checkIndexRequirements(requirements, declared), nil
+}
+
+// CheckIndexes checks whether the declared composite indexes can serve the aggregation query.
+func (q *AggregationQuery) CheckIndexes(declared []*Index) (*IndexCheckResult, error) {
+	requirements, err := q.indexRequirements()
+	if err != nil {
+		return nil, err
+	}
+	return checkIndexRequirements(requirements, declared), nil
+}
+
+func checkIndexRequirements(requirements []*indexRequirement, declared []*Index) *IndexCheckResult {
+	result := &IndexCheckResult{}
+	for _, r := range requirements {
+		served := false
+		for _, idx := range declared {
+			if r.servedBy(idx) {
+				result.Served = append(result.Served, IndexUsage{Required: r.index, Declared: idx})
+				served = true
+				break
+			}
+		}
+		if !served {
+			result.Missing = append(result.Missing, r.index)
+		}
+	}
+	return result
+}
+
+// disjunctiveNormalForm expands the normalized condition into OR-ed groups of AND-ed conditions.
+// The disjunctions are counted before expanding them not to build the exponential number of the groups,
+// and more disjunctions than MaxDisjunctions are reported as LimitViolationError.
+func disjunctiveNormalForm(cond Condition) ([][]Condition, error) {
+	if n := countOrBranches(cond); n > MaxDisjunctions {
+		return nil, &LimitViolationError{Limit: "MaxDisjunctions", Max: MaxDisjunctions, Actual: n}
+	}
+	return expandDisjunctions(cond), nil
+}
+
+func expandDisjunctions(cond Condition) [][]Condition {
+	switch c := cond.(type) {
+	case *OrCompoundCondition:
+		return append(expandDisjunctions(c.Left), expandDisjunctions(c.Right)...)
+	case *AndCompoundCondition:
+		left := expandDisjunctions(c.Left)
+		right := expandDisjunctions(c.Right)
+		result := make([][]Condition, 0, len(left)*len(right))
+		for _, l := range left {
+			for _, r := range right {
+				conjunction := make([]Condition, 0, len(l)+len(r))
+				conjunction = append(conjunction, l...)
+				conjunction = append(conjunction, r...)
+				result = append(result, conjunction)
+			}
+		}
+		return result
+	default:
+		return [][]Condition{{cond}}
+	}
+}
+
+// FormatIndexYAML formats the indexes as index.yaml of Cloud Datastore.
+func FormatIndexYAML(indexes []*Index) string {
+	var sb strings.Builder
+	sb.WriteString("indexes:\n")
+	for _, idx := range indexes {
+		sb.WriteString("- kind: ")
+		sb.WriteString(quoteYAMLString(string(idx.Kind)))
+		sb.WriteByte('\n')
+		if idx.Ancestor {
+			sb.WriteString("  ancestor: yes\n")
+		}
+		if len(idx.Properties) != 0 {
+			sb.WriteString("  properties:\n")
+		}
+		for _, p := range idx.Properties {
+			sb.WriteString("  - name: ")
+			sb.WriteString(quoteYAMLString(string(p.Name)))
+			sb.WriteByte('\n')
+			if p.Direction == DescendingIndexDirection {
+				sb.WriteString("    direction: desc\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+func quoteYAMLString(s string) string {
+	switch strings.ToLower(s) {
+	case "", "~", "null", "true", "false", "yes", "no", "on", "off", "y", "n":
+		return strconv.Quote(s)
+	}
+	for i := 0; i < len(s); i++ {
+		if !isSymbolByte(s[i]) && s[i] != '.' {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
@@ -0,0 +1,321 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestQueryRequiredIndexes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		source string
+		want   []*gqlparser.Index
+	}{
+		{
+			name:   "KindOnly",
+			source: "SELECT * FROM Kind",
+			want:   nil,
+		},
+		{
+			name:   "SingleProperty",
+			source: "SELECT * FROM Kind WHERE a > 1 ORDER BY a DESC",
+			want:   nil,
+		},
+		{
+			name:   "EqualitiesOnly",
+			source: "SELECT * FROM Kind WHERE a = 1 AND b = 2 AND __key__ HAS ANCESTOR KEY(Parent, 1)",
+			want:   nil,
+		},
+		{
+			name:   "EqualityAndInequality",
+			source: "SELECT * FROM Kind WHERE b > 1 AND a = 1",
+			want: []*gqlparser.Index{
+				{
+					Kind: "Kind",
+					Properties: []gqlparser.IndexProperty{
+						{Name: "a", Direction: gqlparser.AscendingIndexDirection},
+						{Name: "b", Direction: gqlparser.AscendingIndexDirection},
+					},
+				},
+			},
+		},
+		{
+			name:   "InequalityWithOrder",
+			source: "SELECT * FROM Kind WHERE a = 1 AND b > 1 ORDER BY b DESC, c, __key__",
+			want: []*gqlparser.Index{
+				{
+					Kind: "Kind",
+					Properties: []gqlparser.IndexProperty{
+						{Name: "a", Direction: gqlparser.AscendingIndexDirection},
+						{Name: "b", Direction: gqlparser.DescendingIndexDirection},
+						{Name: "c", Direction: gqlparser.AscendingIndexDirection},
+					},
+				},
+			},
+		},
+		{
+			name:   "AncestorWithOrder",
+			source: "SELECT * FROM Kind WHERE KEY(Parent, 1) HAS DESCENDANT __key__ ORDER BY a DESC",
+			want: []*gqlparser.Index{
+				{
+					Kind:     "Kind",
+					Ancestor: true,
+					Properties: []gqlparser.IndexProperty{
+						{Name: "a", Direction: gqlparser.DescendingIndexDirection},
+					},
+				},
+			},
+		},
+		{
+			name:   "Projection",
+			source: "SELECT a, b FROM Kind",
+			want: []*gqlparser.Index{
+				{
+					Kind: "Kind",
+					Properties: []gqlparser.IndexProperty{
+						{Name: "a", Direction: gqlparser.AscendingIndexDirection},
+						{Name: "b", Direction: gqlparser.AscendingIndexDirection},
+					},
+				},
+			},
+		},
+		{
+			name:   "Disjunctions",
+			source: "SELECT * FROM Kind WHERE (a = 1 OR b = 1) AND c > 1",
+			want: []*gqlparser.Index{
+				{
+					Kind: "Kind",
+					Properties: []gqlparser.IndexProperty{
+						{Name: "a", Direction: gqlparser.AscendingIndexDirection},
+						{Name: "c", Direction: gqlparser.AscendingIndexDirection},
+					},
+				},
+				{
+					Kind: "Kind",
+					Properties: []gqlparser.IndexProperty{
+						{Name: "b", Direction: gqlparser.AscendingIndexDirection},
+						{Name: "c", Direction: gqlparser.AscendingIndexDirection},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source))
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+
+			got, err := query.RequiredIndexes()
+			if err != nil {
+				t.Fatalf("RequiredIndexes() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("RequiredIndexes() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAggregationQueryRequiredIndexes(t *testing.T) {
+	t.Parallel()
+
+	_, query, err := gqlparser.ParseQueryOrAggregationQuery(gqlparser.NewLexer("SELECT SUM(b) FROM Kind WHERE a = 1"))
+	if err != nil {
+		t.Fatalf("ParseQueryOrAggregationQuery() error = %v", err)
+	}
+
+	want := []*gqlparser.Index{
+		{
+			Kind: "Kind",
+			Properties: []gqlparser.IndexProperty{
+				{Name: "a", Direction: gqlparser.AscendingIndexDirection},
+				{Name: "b", Direction: gqlparser.AscendingIndexDirection},
+			},
+		},
+	}
+	got, err := query.RequiredIndexes()
+	if err != nil {
+		t.Fatalf("RequiredIndexes() error = %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("RequiredIndexes() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRequiredIndexes_TooManyDisjunctions(t *testing.T) {
+	t.Parallel()
+
+	conditions := make([]string, 20)
+	for i := range conditions {
+		conditions[i] = fmt.Sprintf("(a%d = 1 OR b%d = 2)", i, i)
+	}
+	query, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM Kind WHERE " + strings.Join(conditions, " AND ")))
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+
+	if _, err := query.RequiredIndexes(); !errors.Is(err, gqlparser.ErrLimitExceeded) {
+		t.Errorf("RequiredIndexes() error = %v, want %v", err, gqlparser.ErrLimitExceeded)
+	}
+	if _, err := query.CheckIndexes(nil); !errors.Is(err, gqlparser.ErrLimitExceeded) {
+		t.Errorf("CheckIndexes() error = %v, want %v", err, gqlparser.ErrLimitExceeded)
+	}
+	if got := gqlparser.Explain(query).CompositeIndexes; got != nil {
+		t.Errorf("Explain().CompositeIndexes = %v, want nil", got)
+	}
+}
+
+func TestRequiredIndexes_Quantified(t *testing.T) {
+	t.Parallel()
+
+	query := &gqlparser.Query{
+		Kind: "Kind",
+		Where: &gqlparser.AndCompoundCondition{
+			Left:  &gqlparser.QuantifiedComparatorCondition{Comparator: gqlparser.ContainsAllQuantifiedComparator, Property: "tags", Value: []any{"a", "b"}},
+			Right: &gqlparser.EitherComparatorCondition{Comparator: gqlparser.GreaterThanEitherComparator, Property: "b", Value: int64(1)},
+		},
+	}
+	want := []*gqlparser.Index{
+		{
+			Kind: "Kind",
+			Properties: []gqlparser.IndexProperty{
+				{Name: "tags", Direction: gqlparser.AscendingIndexDirection},
+				{Name: "b", Direction: gqlparser.AscendingIndexDirection},
+			},
+		},
+	}
+
+	got, err := query.RequiredIndexes()
+	if err != nil {
+		t.Fatalf("RequiredIndexes() error = %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestFormatIndexYAML(t *testing.T) {
+	t.Parallel()
+
+	got := gqlparser.FormatIndexYAML([]*gqlparser.Index{
+		{
+			Kind:     "Kind",
+			Ancestor: true,
+			Properties: []gqlparser.IndexProperty{
+				{Name: "a", Direction: gqlparser.AscendingIndexDirection},
+				{Name: "b c", Direction: gqlparser.DescendingIndexDirection},
+			},
+		},
+	})
+	want := `indexes:
+- kind: Kind
+  ancestor: yes
+  properties:
+  - name: a
+  - name: "b c"
+    direction: desc
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("FormatIndexYAML() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestQueryCheckIndexes(t *testing.T) {
+	t.Parallel()
+
+	declared := []*gqlparser.Index{
+		{
+			Kind: "Kind",
+			Properties: []gqlparser.IndexProperty{
+				{Name: "b", Direction: gqlparser.AscendingIndexDirection},
+				{Name: "a", Direction: gqlparser.AscendingIndexDirection},
+				{Name: "c", Direction: gqlparser.DescendingIndexDirection},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		want    *gqlparser.IndexCheckResult
+		satisfy bool
+	}{
+		{
+			name:    "BuiltIn",
+			source:  "SELECT * FROM Kind WHERE a = 1",
+			want:    &gqlparser.IndexCheckResult{},
+			satisfy: true,
+		},
+		{
+			name:   "ServedWithReorderedEqualities",
+			source: "SELECT * FROM Kind WHERE a = 1 AND b = 2 ORDER BY c DESC",
+			want: &gqlparser.IndexCheckResult{
+				Served: []gqlparser.IndexUsage{
+					{
+						Required: &gqlparser.Index{
+							Kind: "Kind",
+							Properties: []gqlparser.IndexProperty{
+								{Name: "a", Direction: gqlparser.AscendingIndexDirection},
+								{Name: "b", Direction: gqlparser.AscendingIndexDirection},
+								{Name: "c", Direction: gqlparser.DescendingIndexDirection},
+							},
+						},
+						Declared: declared[0],
+					},
+				},
+			},
+			satisfy: true,
+		},
+		{
+			name:   "Missing",
+			source: "SELECT * FROM Kind WHERE a = 1 AND b = 2 ORDER BY c",
+			want: &gqlparser.IndexCheckResult{
+				Missing: []*gqlparser.Index{
+					{
+						Kind: "Kind",
+						Properties: []gqlparser.IndexProperty{
+							{Name: "a", Direction: gqlparser.AscendingIndexDirection},
+							{Name: "b", Direction: gqlparser.AscendingIndexDirection},
+							{Name: "c", Direction: gqlparser.AscendingIndexDirection},
+						},
+					},
+				},
+			},
+			satisfy: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source))
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+
+			got, err := query.CheckIndexes(declared)
+			if err != nil {
+				t.Fatalf("CheckIndexes() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("CheckIndexes() mismatch (-want +got):\n%s", diff)
+			}
+			if got.Satisfiable() != tt.satisfy {
+				t.Errorf("Satisfiable() = %v, want %v", got.Satisfiable(), tt.satisfy)
+			}
+		})
+	}
+}
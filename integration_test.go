@@ -1,6 +1,8 @@
 package gqlparser_test
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -134,6 +136,136 @@ var (
 			},
 			wantErr: false,
 		},
+		{
+			name:   "TrailingSemicolon",
+			source: "SELECT * FROM `Kind` LIMIT 10 OFFSET 10 ; ",
+			want: &gqlparser.Query{
+				Kind: "Kind",
+				Limit: &gqlparser.Limit{
+					Position: 10,
+				},
+				Offset: &gqlparser.Offset{
+					Position: 10,
+				},
+			},
+			wantErr: false,
+		},
+		{"DoubleSemicolon", "SELECT * FROM `Kind`;;", nil, true},
+		{
+			name:   "OffsetWithMultipleTerms",
+			source: "SELECT * FROM `Kind` OFFSET @cursor + 2 + 3 +4",
+			want: &gqlparser.Query{
+				Kind: "Kind",
+				Offset: &gqlparser.Offset{
+					Position: 9,
+					Cursor:   &gqlparser.NamedBinding{Name: "cursor"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "OffsetWithConstantTerms",
+			source: "SELECT * FROM `Kind` OFFSET 2 + 3",
+			want: &gqlparser.Query{
+				Kind: "Kind",
+				Offset: &gqlparser.Offset{
+					Position: 5,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "LimitWithCursorAndTerms",
+			source: "SELECT * FROM `Kind` LIMIT @1 + 10 + 5 OFFSET @2",
+			want: &gqlparser.Query{
+				Kind: "Kind",
+				Limit: &gqlparser.Limit{
+					Position: 15,
+					Cursor:   &gqlparser.IndexedBinding{Index: 1},
+				},
+				Offset: &gqlparser.Offset{
+					Cursor: &gqlparser.IndexedBinding{Index: 2},
+				},
+			},
+			wantErr: false,
+		},
+		{"OffsetWithFloatingTerm", "SELECT * FROM `Kind` OFFSET @1 + 1.5", nil, true},
+		{"OffsetWithDanglingPlus", "SELECT * FROM `Kind` OFFSET @1 +", nil, true},
+		{"OffsetWithOverflow", "SELECT * FROM `Kind` OFFSET 9223372036854775807 + 1", nil, true},
+		{
+			name:   "DistinctOnWithFullProjection",
+			source: "SELECT DISTINCT ON (a, b) * FROM `Kind`",
+			want: &gqlparser.Query{
+				DistinctOn: []gqlparser.Property{"a", "b"},
+				Kind:       "Kind",
+			},
+			wantErr: false,
+		},
+		{"FullProjectionWithProperties", "SELECT *, a FROM `Kind`", nil, true},
+		{"DistinctOnWithFullProjectionAndProperties", "SELECT DISTINCT ON (a) *, b FROM `Kind`", nil, true},
+		{
+			name:   "OrderByNestedPath",
+			source: "SELECT * FROM `Kind` ORDER BY a.b DESC, `c d`.e, f.`g.h`.`i`",
+			want: &gqlparser.Query{
+				Kind: "Kind",
+				OrderBy: []gqlparser.OrderBy{
+					{Property: "a.b", Descending: true},
+					{Property: "c d.e"},
+					{Property: "f.g.h.i"},
+				},
+			},
+			wantErr: false,
+		},
+		{"OrderByTrailingDot", "SELECT * FROM `Kind` ORDER BY a.", nil, true},
+		{"OrderByEmptySegment", "SELECT * FROM `Kind` ORDER BY a.``", nil, true},
+		{"OrderBySpaceInPath", "SELECT * FROM `Kind` ORDER BY a. b", nil, true},
+		{
+			name:   "KeysOnly",
+			source: "SELECT __key__ FROM `Kind`",
+			want: &gqlparser.Query{
+				Properties: []gqlparser.Property{"__key__"},
+				KeysOnly:   true,
+				Kind:       "Kind",
+			},
+			wantErr: false,
+		},
+		{"KeyWithProperties", "SELECT __key__, a FROM `Kind`", nil, true},
+		{
+			name:   "OrderByAscending",
+			source: "SELECT * FROM `Kind` ORDER BY a ASC, b desc",
+			want: &gqlparser.Query{
+				Kind: "Kind",
+				OrderBy: []gqlparser.OrderBy{
+					{Property: "a"},
+					{Property: "b", Descending: true},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "PropertyAliases",
+			source: "SELECT a AS x, b, `c.d` AS `y z` FROM `Kind`",
+			want: &gqlparser.Query{
+				Properties: []gqlparser.Property{"a", "b", "c.d"},
+				Aliases:    []string{"x", "", "y z"},
+				Kind:       "Kind",
+			},
+			wantErr: false,
+		},
+		{
+			name:   "LastPropertyWithoutAlias",
+			source: "SELECT b AS k, a FROM `Kind`",
+			want: &gqlparser.Query{
+				Properties: []gqlparser.Property{"b", "a"},
+				Aliases:    []string{"k", ""},
+				Kind:       "Kind",
+			},
+			wantErr: false,
+		},
+		{"AliasWithoutName", "SELECT a AS FROM `Kind`", nil, true},
+		{"AliasWithString", "SELECT a AS 'x' FROM `Kind`", nil, true},
+		{"AliasOfWildcard", "SELECT * AS x FROM `Kind`", nil, true},
+		{"AliasInDistinctOn", "SELECT DISTINCT ON (a AS x) a FROM `Kind`", nil, true},
 	}
 	aggregationQueryTests = []integrateTestCase{
 		{"Empty", "", nil, true},
@@ -398,6 +530,32 @@ var (
 			},
 			wantErr: false,
 		},
+		{
+			name:   "TrailingSemicolonWithSelectSyntax",
+			source: "SELECT COUNT(*) FROM `Kind`;\n",
+			want: &gqlparser.AggregationQuery{
+				Aggregations: []gqlparser.Aggregation{
+					&gqlparser.CountAggregation{},
+				},
+				Query: gqlparser.Query{
+					Kind: "Kind",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "TrailingSemicolonWithAggregateSyntax",
+			source: "AGGREGATE COUNT(*) OVER (SELECT * FROM `Kind`) ;",
+			want: &gqlparser.AggregationQuery{
+				Aggregations: []gqlparser.Aggregation{
+					&gqlparser.CountAggregation{},
+				},
+				Query: gqlparser.Query{
+					Kind: "Kind",
+				},
+			},
+			wantErr: false,
+		},
 	}
 )
 
@@ -563,6 +721,16 @@ func TestParseKey_FromString(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:   "SurroundingWhitespaces",
+			source: " KEY(Foo, 123) ;\n",
+			want: &gqlparser.Key{
+				Path: []*gqlparser.KeyPath{
+					{Kind: "Foo", ID: 123},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name:   "Ancestor",
 			source: `KEY(Parent, 1, Child, 9)`,
@@ -889,6 +1057,58 @@ func TestParseCondition_FromString(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:   "EmptyArray",
+			source: `a IN ARRAY()`,
+			want: &gqlparser.ForwardComparatorCondition{
+				Comparator: gqlparser.InForwardComparator,
+				Property:   "a",
+				Value:      []any{},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "ArrayTrailingComma",
+			source: `a IN ARRAY(1, 2, )`,
+			want: &gqlparser.ForwardComparatorCondition{
+				Comparator: gqlparser.InForwardComparator,
+				Property:   "a",
+				Value:      []any{int64(1), int64(2)},
+			},
+			wantErr: false,
+		},
+		{"ArrayLeadingComma", `a IN ARRAY(, 1)`, nil, true},
+		{"ArrayOnlyComma", `a IN ARRAY(,)`, nil, true},
+		{"ArrayDoubleComma", `a IN ARRAY(1,, 2)`, nil, true},
+		{
+			name:   "InParenthesizedList",
+			source: `a IN ('a', 'b', 'c')`,
+			want: &gqlparser.ForwardComparatorCondition{
+				Comparator: gqlparser.InForwardComparator,
+				Property:   "a",
+				Value:      []any{"a", "b", "c"},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "NotInParenthesizedList",
+			source: `a NOT IN (2,3) AND b = 1`,
+			want: &gqlparser.AndCompoundCondition{
+				Left: &gqlparser.ForwardComparatorCondition{
+					Comparator: gqlparser.NotInForwardComparator,
+					Property:   "a",
+					Value:      []any{int64(2), int64(3)},
+				},
+				Right: &gqlparser.EitherComparatorCondition{
+					Comparator: gqlparser.EqualsEitherComparator,
+					Property:   "b",
+					Value:      int64(1),
+				},
+			},
+			wantErr: false,
+		},
+		{"ParenthesizedListWithEquals", `a = (1, 2)`, nil, true},
+		{"ParenthesizedListInBackwardCondition", `(1, 2) IN a`, nil, true},
 		{
 			name:   "HasAncestor",
 			source: `__key__ HAS ANCESTOR KEY(Parent, 1000)`,
@@ -975,7 +1195,7 @@ func TestParseCondition_FromString(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			// got, err := gqlparser.ParseCondition(&debugTokenSource{source: gqlparser.NewLexer(tt.source), logger: t})
+			// got, err := gqlparser.ParseCondition(gqlparser.NewRecordingTokenSource(gqlparser.NewLexer(tt.source), func(e gqlparser.TokenSourceEvent) { t.Log(e) }))
 			got, err := gqlparser.ParseCondition(gqlparser.NewLexer(tt.source))
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ParseCondition() error = %v, wantErr %v", err, tt.wantErr)
@@ -991,3 +1211,64 @@ func TestParseCondition_FromString(t *testing.T) {
 		})
 	}
 }
+
+func TestParse_StrictMode(t *testing.T) {
+	t.Parallel()
+
+	if _, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM Kind "), gqlparser.WithStrictMode()); err != nil {
+		t.Errorf("ParseQuery() error = %v", err)
+	}
+	if _, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM Kind;"), gqlparser.WithStrictMode()); !errors.Is(err, gqlparser.ErrUnexpectedToken) {
+		t.Errorf("ParseQuery() error = %v, want %v", err, gqlparser.ErrUnexpectedToken)
+	}
+	if _, err := gqlparser.ParseCondition(gqlparser.NewLexer("a = 1;"), gqlparser.WithStrictMode()); !errors.Is(err, gqlparser.ErrUnexpectedToken) {
+		t.Errorf("ParseCondition() error = %v, want %v", err, gqlparser.ErrUnexpectedToken)
+	}
+	if _, err := gqlparser.ParseKey(gqlparser.NewLexer("KEY(A, 1);"), gqlparser.WithStrictMode()); !errors.Is(err, gqlparser.ErrUnexpectedToken) {
+		t.Errorf("ParseKey() error = %v, want %v", err, gqlparser.ErrUnexpectedToken)
+	}
+	if _, err := gqlparser.ParseCondition(gqlparser.NewLexer("a IN ARRAY()"), gqlparser.WithStrictMode()); err != nil {
+		t.Errorf("ParseCondition() error = %v", err)
+	}
+	if _, err := gqlparser.ParseCondition(gqlparser.NewLexer("a IN ARRAY(1, ARRAY(2,))"), gqlparser.WithStrictMode()); !errors.Is(err, gqlparser.ErrUnexpectedToken) || !strings.Contains(err.Error(), "trailing comma") {
+		t.Errorf("ParseCondition() error = %v, want the trailing comma error", err)
+	}
+	if _, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT a AS x FROM Kind"), gqlparser.WithStrictMode()); !errors.Is(err, gqlparser.ErrUnexpectedToken) || !strings.Contains(err.Error(), "alias") {
+		t.Errorf("ParseQuery() error = %v, want the alias error", err)
+	}
+	if _, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM Kind OFFSET 10 LIMIT 5"), gqlparser.WithStrictMode()); !errors.Is(err, gqlparser.ErrUnexpectedToken) || !strings.Contains(err.Error(), "LIMIT after OFFSET") {
+		t.Errorf("ParseQuery() error = %v, want the LIMIT after OFFSET error", err)
+	}
+}
+
+func TestParseCondition_DeepNesting(t *testing.T) {
+	t.Parallel()
+
+	for _, source := range []string{
+		strings.Repeat("(", 100000) + "a = 1" + strings.Repeat(")", 100000),
+		"a IN " + strings.Repeat("ARRAY(", 100000) + "1" + strings.Repeat(")", 100000),
+	} {
+		if _, err := gqlparser.ParseCondition(gqlparser.NewLexer(source)); !errors.Is(err, gqlparser.ErrTooDeepNesting) {
+			t.Errorf("ParseCondition() error = %v, want %v", err, gqlparser.ErrTooDeepNesting)
+		}
+	}
+
+	source := strings.Repeat("(", 10) + "a = 1" + strings.Repeat(")", 10)
+	if _, err := gqlparser.ParseCondition(gqlparser.NewLexer(source), gqlparser.WithMaxNestingDepth(10)); !errors.Is(err, gqlparser.ErrTooDeepNesting) {
+		t.Errorf("ParseCondition() error = %v, want %v", err, gqlparser.ErrTooDeepNesting)
+	}
+	if _, err := gqlparser.ParseCondition(gqlparser.NewLexer(source), gqlparser.WithMaxNestingDepth(0)); err != nil {
+		t.Errorf("ParseCondition() error = %v", err)
+	}
+}
+
+func FuzzParseCondition_FromString(f *testing.F) {
+	f.Add("a = 1 AND (b > 2 OR c IN ARRAY(1, ARRAY(2)))")
+	f.Add(strings.Repeat("(", 1000) + "a = 1" + strings.Repeat(")", 1000))
+	f.Add("a IN " + strings.Repeat("ARRAY(", 1000) + "1" + strings.Repeat(")", 1000))
+	f.Add(strings.Repeat("(", 1000))
+	f.Fuzz(func(t *testing.T, source string) {
+		_, _ = gqlparser.ParseCondition(gqlparser.NewLexer(source))
+		// should be no panics
+	})
+}
@@ -0,0 +1,101 @@
+package gqlparser
+
+import (
+	"strings"
+)
+
+// AcceptorType is the type of the node in the parser structure.
+type AcceptorType string
+
+const (
+	// SequenceAcceptorType accepts the children in order.
+	SequenceAcceptorType AcceptorType = "sequence"
+	// ConditionalAcceptorType accepts the second child if the first one is accepted, otherwise the third one.
+	ConditionalAcceptorType AcceptorType = "conditional"
+	// ClauseAcceptorType accepts the only child as the named clause.
+	ClauseAcceptorType AcceptorType = "clause"
+	// KeywordAcceptorType accepts any of the keywords in the name joined with "|".
+	KeywordAcceptorType AcceptorType = "keyword"
+	// OperatorAcceptorType accepts the operator in the name.
+	OperatorAcceptorType AcceptorType = "operator"
+	// TokenAcceptorType accepts the tokens with the custom logic.
+	TokenAcceptorType AcceptorType = "token"
+	// DeferredAcceptorType is built on accepting, so the children are unknown until then.
+	//
+	// Deprecated: the grammar is built once without the deferred acceptors, so it's not described anymore.
+	DeferredAcceptorType AcceptorType = "deferred"
+	// NopAcceptorType accepts nothing.
+	NopAcceptorType AcceptorType = "nop"
+)
+
+// AcceptorDescription describes the structure of the parser to render it by the tools.
+type AcceptorDescription struct {
+	Type AcceptorType
+	// Name is the clause name for the clauses and the conditionals, the keywords or the operator. It may be empty.
+	Name     string
+	Children []*AcceptorDescription
+}
+
+// String renders the structure as the indented tree.
+func (d *AcceptorDescription) String() string {
+	var sb strings.Builder
+	d.writeTo(&sb, 0)
+	return sb.String()
+}
+
+func (d *AcceptorDescription) writeTo(sb *strings.Builder, depth int) {
+	sb.WriteString(strings.Repeat("  ", depth))
+	sb.WriteString(string(d.Type))
+	if d.Name != "" {
+		sb.WriteString(" ")
+		sb.WriteString(d.Name)
+	}
+	sb.WriteString("\n")
+	for _, child := range d.Children {
+		child.writeTo(sb, depth+1)
+	}
+}
+
+// DescribeQueryParser returns the structure of the parser used by ParseQuery.
+func DescribeQueryParser(opts ...ParseOption) *AcceptorDescription {
+	return describeAcceptor(acceptQuery, newParseOptions(opts))
+}
+
+// DescribeAggregationQueryParser returns the structure of the parser used by ParseAggregationQuery.
+func DescribeAggregationQueryParser(opts ...ParseOption) *AcceptorDescription {
+	return describeAcceptor(acceptAggregationQuery, newParseOptions(opts))
+}
+
+// describeAcceptor describes the acceptor with the branches chosen by the options.
+func describeAcceptor(acceptor tokenAcceptor, opts *parseOptions) *AcceptorDescription {
+	switch a := acceptor.(type) {
+	case tokenAcceptors:
+		d := &AcceptorDescription{Type: SequenceAcceptorType}
+		for _, child := range a {
+			d.Children = append(d.Children, describeAcceptor(child, opts))
+		}
+		return d
+	case *conditionalTokenAcceptor:
+		return &AcceptorDescription{
+			Type:     ConditionalAcceptorType,
+			Name:     a.name,
+			Children: []*AcceptorDescription{describeAcceptor(a.ifAccept, opts), describeAcceptor(a.andThen, opts), describeAcceptor(a.orElse, opts)},
+		}
+	case *namedTokenAcceptor:
+		return &AcceptorDescription{
+			Type:     ClauseAcceptorType,
+			Name:     a.name,
+			Children: []*AcceptorDescription{describeAcceptor(a.acceptor, opts)},
+		}
+	case *keywordTokenAcceptor:
+		return &AcceptorDescription{Type: KeywordAcceptorType, Name: strings.Join(a.keywords, "|")}
+	case *operatorTokenAcceptor:
+		return &AcceptorDescription{Type: OperatorAcceptorType, Name: a.operator}
+	case *optionTokenAcceptor:
+		return describeAcceptor(a.choose(opts), opts)
+	case nopAcceptorTyp:
+		return &AcceptorDescription{Type: NopAcceptorType}
+	default:
+		return &AcceptorDescription{Type: TokenAcceptorType}
+	}
+}
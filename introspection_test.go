@@ -0,0 +1,84 @@
+package gqlparser_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestDescribeQueryParser(t *testing.T) {
+	t.Parallel()
+
+	clauses := map[string]gqlparser.AcceptorType{}
+	var walk func(*gqlparser.AcceptorDescription)
+	walk = func(d *gqlparser.AcceptorDescription) {
+		if d.Type == gqlparser.ClauseAcceptorType || d.Type == gqlparser.ConditionalAcceptorType && d.Name != "" {
+			clauses[d.Name] = d.Type
+		}
+		if d.Type == gqlparser.ConditionalAcceptorType && len(d.Children) != 3 {
+			t.Errorf("conditional %s has %d children", d.Name, len(d.Children))
+		}
+		for _, child := range d.Children {
+			walk(child)
+		}
+	}
+	walk(gqlparser.DescribeQueryParser())
+
+	want := map[string]gqlparser.AcceptorType{
+		"DISTINCT": gqlparser.ConditionalAcceptorType,
+		"SELECT":   gqlparser.ClauseAcceptorType,
+		"FROM":     gqlparser.ClauseAcceptorType,
+		"WHERE":    gqlparser.ConditionalAcceptorType,
+		"ORDER BY": gqlparser.ConditionalAcceptorType,
+		"LIMIT":    gqlparser.ConditionalAcceptorType,
+		"OFFSET":   gqlparser.ConditionalAcceptorType,
+	}
+	for name, typ := range want {
+		if clauses[name] != typ {
+			t.Errorf("clause %s = %q, want %q", name, clauses[name], typ)
+		}
+	}
+}
+
+func TestDescribeAggregationQueryParser(t *testing.T) {
+	t.Parallel()
+
+	got := gqlparser.DescribeAggregationQueryParser().String()
+	for _, want := range []string{"\n  conditional\n    keyword SELECT\n", "clause AGGREGATE\n", "keyword OVER\n", "operator (\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("%q is not found in:\n%s", want, got)
+		}
+	}
+}
+
+func TestDescribeQueryParser_Dialect(t *testing.T) {
+	t.Parallel()
+
+	dialect, err := gqlparser.NewDialect(gqlparser.WithGroupBy(), gqlparser.WithHaving())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		describe func(...gqlparser.ParseOption) *gqlparser.AcceptorDescription
+		clause   string
+	}{
+		{"GroupBy", gqlparser.DescribeQueryParser, "conditional GROUP BY\n"},
+		{"Having", gqlparser.DescribeAggregationQueryParser, "conditional HAVING\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.describe().String(); strings.Contains(got, tt.clause) {
+				t.Errorf("%q is found without the dialect in:\n%s", tt.clause, got)
+			}
+			if got := tt.describe(gqlparser.WithDialect(dialect)).String(); !strings.Contains(got, tt.clause) {
+				t.Errorf("%q is not found with the dialect in:\n%s", tt.clause, got)
+			}
+		})
+	}
+}
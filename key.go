@@ -0,0 +1,247 @@
+package gqlparser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrBindKeyPath = errors.New("invalid key path value")
+	ErrInvalidKey  = errors.New("invalid key")
+)
+
+// KeyPathError is the error of the element of the key path. It wraps ErrInvalidKey.
+type KeyPathError struct {
+	// Index is the index of the element in the path.
+	Index  int
+	Path   *KeyPath
+	Reason string
+}
+
+func (e *KeyPathError) Error() string {
+	return fmt.Sprintf("%s: path[%d] %s: %s", ErrInvalidKey, e.Index, e.Path.Kind, e.Reason)
+}
+
+func (e *KeyPathError) Unwrap() error {
+	return ErrInvalidKey
+}
+
+// Validate checks the key by the rules of Cloud Datastore.
+//   - The path must not be empty, and must be at most 100 elements.
+//   - The kinds must not be empty nor reserved. e.g. __kind__
+//   - Each element must have exactly one of the positive ID, the name or the binding.
+//   - The names must not be reserved and must be at most 1500 bytes.
+//
+// The errors of the elements are reported as KeyPathError joined by errors.Join.
+func (k *Key) Validate() error {
+	if len(k.Path) == 0 {
+		return fmt.Errorf("%w: empty path", ErrInvalidKey)
+	}
+	if len(k.Path) > MaxKeyPathElements {
+		return fmt.Errorf("%w: too long path", ErrInvalidKey)
+	}
+
+	var errs []error
+	for i, path := range k.Path {
+		if reason := validateKeyPath(path); reason != "" {
+			errs = append(errs, &KeyPathError{Index: i, Path: path, Reason: reason})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateKeyPath(path *KeyPath) string {
+	if path.Kind == "" {
+		return "empty kind"
+	}
+	if isReservedName(string(path.Kind)) {
+		return "reserved kind"
+	}
+
+	identifiers := 0
+	if path.ID != 0 {
+		identifiers++
+	}
+	if path.Name != "" {
+		identifiers++
+	}
+	if path.Binding != nil && path.ID == 0 && path.Name == "" {
+		// the binding resolved by Bind is kept with the ID or the name to re-bind it
+		identifiers++
+	}
+	switch {
+	case identifiers == 0:
+		return "no ID or name"
+	case identifiers > 1:
+		return "both ID and name"
+	case path.ID < 0:
+		return "non-positive ID"
+	case isReservedName(path.Name):
+		return "reserved name"
+	case len(path.Name) > MaxKeyNameLength:
+		return "too long name"
+	default:
+		return ""
+	}
+}
+
+// isReservedName reports whether the kind or the name is reserved by Cloud Datastore. e.g. __key__, __kind__
+func isReservedName(name string) bool {
+	return len(name) >= 4 && strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__")
+}
+
+// Bind resolves the placeholders of the IDs and the names in the path.
+// The bound value must be an integer for the ID or a string for the name.
+// The placeholders are kept in the path, so the key can be re-bound with the other values.
+// The missing values are reported at once as ParameterError.
+func (k *Key) Bind(br *BindingResolver) error {
+	var variables []BindingVariable
+	for _, path := range k.Path {
+		if path.Binding != nil {
+			variables = append(variables, path.Binding)
+		}
+	}
+	if err := br.checkVariables(variables); err != nil {
+		return err
+	}
+	return k.bind(br)
+}
+
+func (k *Key) bind(br *BindingResolver) error {
+	for _, path := range k.Path {
+		if path.Binding == nil {
+			continue
+		}
+
+		v, err := br.Resolve(path.Binding)
+		if err != nil {
+			return err
+		}
+		switch id := v.(type) {
+		case int64:
+			path.ID, path.Name = id, ""
+		case int:
+			path.ID, path.Name = int64(id), ""
+		case string:
+			path.ID, path.Name = 0, id
+		default:
+			return &BindError{Variable: path.Binding, Err: fmt.Errorf("%w: %s %T", ErrBindKeyPath, path.Kind, v)}
+		}
+	}
+	return nil
+}
+
+// bindValueKeys binds every key in the value including the elements of arrays.
+func bindValueKeys(value any, br *BindingResolver) error {
+	var err error
+	walkValueKeys(value, func(k *Key) {
+		if err == nil {
+			err = k.bind(br)
+		}
+	})
+	return err
+}
+
+// Parent returns the key of the parent entity. It returns nil if the key has no parent.
+func (k *Key) Parent() *Key {
+	if len(k.Path) < 2 {
+		return nil
+	}
+	return &Key{
+		ProjectID: k.ProjectID,
+		Namespace: k.Namespace,
+		Path:      append([]*KeyPath(nil), k.Path[:len(k.Path)-1]...),
+	}
+}
+
+// Equal reports whether the keys point the same entity in the same project and namespace.
+// The bindings are compared only for the paths not bound yet, so the bound keys equal the literals.
+// e.g. KEY(Kind, @a) and KEY(Kind, @b) differ before binding, but equal KEY(Kind, 1) after both are bound to 1
+func (k *Key) Equal(other *Key) bool {
+	if k == nil || other == nil {
+		return k == other
+	}
+	if compareKeys(k, other) != 0 {
+		return false
+	}
+	for i, path := range k.Path {
+		if path.ID == 0 && path.Name == "" && !equalBindingVariables(path.Binding, other.Path[i].Binding) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalBindingVariables(a, b BindingVariable) bool {
+	switch a := a.(type) {
+	case *NamedBinding:
+		b, ok := b.(*NamedBinding)
+		return ok && a.Name == b.Name
+	case *IndexedBinding:
+		b, ok := b.(*IndexedBinding)
+		return ok && a.Index == b.Index
+	default:
+		return a == b
+	}
+}
+
+// IsAncestorOf reports whether the key is a proper ancestor of the other key in the same project and namespace.
+func (k *Key) IsAncestorOf(other *Key) bool {
+	if k == nil || other == nil || len(k.Path) >= len(other.Path) {
+		return false
+	}
+	return compareKeys(k, &Key{ProjectID: other.ProjectID, Namespace: other.Namespace, Path: other.Path[:len(k.Path)]}) == 0
+}
+
+// ApplyDefaults fills the project ID and the namespace of the key if they are empty.
+func (k *Key) ApplyDefaults(projectID ProjectID, namespace string) {
+	if k.ProjectID == "" {
+		k.ProjectID = projectID
+	}
+	if k.Namespace == "" {
+		k.Namespace = namespace
+	}
+}
+
+// ApplyKeyDefaults fills the project ID and the namespace of every KEY literal lacking them in the query.
+// It should be called after the conditions are bound to fill the bound keys too.
+func (q *Query) ApplyKeyDefaults(projectID ProjectID, namespace string) {
+	if q.Where == nil {
+		return
+	}
+	walkConditionKeys(q.Where, func(k *Key) {
+		k.ApplyDefaults(projectID, namespace)
+	})
+}
+
+// Keys returns every key embedded in the conditions of the query including the elements of arrays.
+// The bound keys are returned too if the conditions are already bound.
+func (q *Query) Keys() []*Key {
+	if q.Where == nil {
+		return nil
+	}
+
+	var keys []*Key
+	walkConditionKeys(q.Where, func(k *Key) {
+		keys = append(keys, k)
+	})
+	return keys
+}
+
+func walkConditionKeys(cond Condition, f func(*Key)) {
+	walkConditionValues(cond, keysOf(f))
+}
+
+func walkValueKeys(value any, f func(*Key)) {
+	walkValues(value, keysOf(f))
+}
+
+// keysOf adapts the callback of the keys to walkValues.
+func keysOf(f func(*Key)) func(any) {
+	return func(v any) {
+		if k, ok := v.(*Key); ok {
+			f(k)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestQueryApplyKeyDefaults(t *testing.T) {
+	t.Parallel()
+
+	query, err := gqlparser.ParseQuery(gqlparser.NewLexer(
+		"SELECT * FROM Kind WHERE __key__ HAS ANCESTOR KEY(Parent, 1) AND __key__ IN ARRAY(KEY(PROJECT('other'), Kind, 'a'), KEY(NAMESPACE('ns'), Kind, 'b')) AND ref = @1",
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := query.Where.Bind(&gqlparser.BindingResolver{Indexed: []any{&gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "Ref", ID: 2}}}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	query.ApplyKeyDefaults("project", "default")
+
+	want := []*gqlparser.Key{
+		{ProjectID: "project", Namespace: "default", Path: []*gqlparser.KeyPath{{Kind: "Parent", ID: 1}}},
+		{ProjectID: "other", Namespace: "default", Path: []*gqlparser.KeyPath{{Kind: "Kind", Name: "a"}}},
+		{ProjectID: "project", Namespace: "ns", Path: []*gqlparser.KeyPath{{Kind: "Kind", Name: "b"}}},
+		{ProjectID: "project", Namespace: "default", Path: []*gqlparser.KeyPath{{Kind: "Ref", ID: 2}}},
+	}
+	and := query.Where.(*gqlparser.AndCompoundCondition)
+	inner := and.Left.(*gqlparser.AndCompoundCondition)
+	got := []*gqlparser.Key{
+		inner.Left.(*gqlparser.ForwardComparatorCondition).Value.(*gqlparser.Key),
+		inner.Right.(*gqlparser.ForwardComparatorCondition).Value.([]any)[0].(*gqlparser.Key),
+		inner.Right.(*gqlparser.ForwardComparatorCondition).Value.([]any)[1].(*gqlparser.Key),
+		and.Right.(*gqlparser.EitherComparatorCondition).Value.(*gqlparser.Key),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestQueryKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		source string
+		want   []*gqlparser.Key
+	}{
+		{
+			name:   "NoWhere",
+			source: "SELECT * FROM Kind",
+			want:   nil,
+		},
+		{
+			name:   "NoKeys",
+			source: "SELECT * FROM Kind WHERE a = 1 OR b IN ARRAY(1, 2)",
+			want:   nil,
+		},
+		{
+			name:   "Keys",
+			source: "SELECT * FROM Kind WHERE KEY(Parent, 'p') HAS DESCENDANT __key__ AND (ref = KEY(Ref, 1, Child, 2) OR __key__ IN ARRAY(KEY(Kind, 'a'), 1))",
+			want: []*gqlparser.Key{
+				{Path: []*gqlparser.KeyPath{{Kind: "Parent", Name: "p"}}},
+				{Path: []*gqlparser.KeyPath{{Kind: "Ref", ID: 1}, {Kind: "Child", ID: 2}}},
+				{Path: []*gqlparser.KeyPath{{Kind: "Kind", Name: "a"}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, query.Keys()); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestKeyBind(t *testing.T) {
+	t.Parallel()
+
+	cond, err := gqlparser.ParseCondition(gqlparser.NewLexer("__key__ IN ARRAY(KEY(Parent, @parent, Kind, @1), KEY(Kind, 'fixed'))"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantParsed := &gqlparser.ForwardComparatorCondition{
+		Comparator: gqlparser.InForwardComparator,
+		Property:   "__key__",
+		Value: []any{
+			&gqlparser.Key{Path: []*gqlparser.KeyPath{
+				{Kind: "Parent", Binding: &gqlparser.NamedBinding{Name: "parent"}},
+				{Kind: "Kind", Binding: &gqlparser.IndexedBinding{Index: 1}},
+			}},
+			&gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "Kind", Name: "fixed"}}},
+		},
+	}
+	if diff := cmp.Diff(wantParsed, cond); diff != "" {
+		t.Errorf("parsed (-want, +got)\n%s", diff)
+	}
+
+	if err := cond.Bind(&gqlparser.BindingResolver{Indexed: []any{int64(10)}, Named: map[string]any{"parent": "p"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	wantBound := &gqlparser.ForwardComparatorCondition{
+		Comparator: gqlparser.InForwardComparator,
+		Property:   "__key__",
+		Value: []any{
+			&gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "Parent", Name: "p"}, {Kind: "Kind", ID: 10}}},
+			&gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "Kind", Name: "fixed"}}},
+		},
+	}
+	if diff := cmp.Diff(wantBound, cond); diff != "" {
+		t.Errorf("bound (-want, +got)\n%s", diff)
+	}
+	wantValues := []gqlparser.BoundValue{
+		{Property: "__key__", Variable: &gqlparser.NamedBinding{Name: "parent"}, Value: "p"},
+		{Property: "__key__", Variable: &gqlparser.IndexedBinding{Index: 1}, Value: int64(10)},
+	}
+	if diff := cmp.Diff(wantValues, gqlparser.BoundValues(cond)); diff != "" {
+		t.Errorf("BoundValues() (-want, +got)\n%s", diff)
+	}
+
+	// re-bind with the other values
+	if err := cond.Bind(&gqlparser.BindingResolver{Indexed: []any{"k"}, Named: map[string]any{"parent": int64(1)}}); err != nil {
+		t.Fatal(err)
+	}
+	wantRebound := []*gqlparser.KeyPath{
+		{Kind: "Parent", ID: 1, Binding: &gqlparser.NamedBinding{Name: "parent"}},
+		{Kind: "Kind", Name: "k", Binding: &gqlparser.IndexedBinding{Index: 1}},
+	}
+	if diff := cmp.Diff(wantRebound, cond.(*gqlparser.ForwardComparatorCondition).Value.([]any)[0].(*gqlparser.Key).Path); diff != "" {
+		t.Errorf("re-bound (-want, +got)\n%s", diff)
+	}
+	if s, err := gqlparser.FormatCondition(cond); err != nil || s != "__key__ IN ARRAY(KEY(Parent, 1, Kind, 'k'), KEY(Kind, 'fixed'))" {
+		t.Errorf("FormatCondition() = %q, %v", s, err)
+	}
+}
+
+func TestKeyBind_Error(t *testing.T) {
+	t.Parallel()
+
+	key := &gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "Kind", Binding: &gqlparser.IndexedBinding{Index: 1}}}}
+	if err := key.Bind(&gqlparser.BindingResolver{Indexed: []any{1.5}}); !errors.Is(err, gqlparser.ErrBindKeyPath) {
+		t.Errorf("Bind() error = %v, want %v", err, gqlparser.ErrBindKeyPath)
+	}
+	if err := key.Bind(&gqlparser.BindingResolver{}); !errors.Is(err, gqlparser.ErrBindValue) {
+		t.Errorf("Bind() error = %v, want %v", err, gqlparser.ErrBindValue)
+	}
+}
+
+func TestKeyValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		key     *gqlparser.Key
+		want    []*gqlparser.KeyPathError
+		wantErr bool
+	}{
+		{
+			name: "Valid",
+			key: &gqlparser.Key{Path: []*gqlparser.KeyPath{
+				{Kind: "Parent", ID: 1},
+				{Kind: "Kind", Name: "a"},
+				{Kind: "Bound", Binding: &gqlparser.NamedBinding{Name: "id"}},
+			}},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name:    "EmptyPath",
+			key:     &gqlparser.Key{},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "TooLongPath",
+			key:     &gqlparser.Key{Path: make([]*gqlparser.KeyPath, gqlparser.MaxKeyPathElements+1)},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "InvalidElements",
+			key: &gqlparser.Key{Path: []*gqlparser.KeyPath{
+				{Kind: "", ID: 1},
+				{Kind: "__kind__", ID: 1},
+				{Kind: "Kind"},
+				{Kind: "Kind", ID: 1, Name: "a"},
+				{Kind: "Kind", ID: -1},
+				{Kind: "Kind", Name: "__name__"},
+				{Kind: "Kind", Name: strings.Repeat("a", gqlparser.MaxKeyNameLength+1)},
+				{Kind: "Kind", ID: 1},
+			}},
+			want: []*gqlparser.KeyPathError{
+				{Index: 0, Reason: "empty kind"},
+				{Index: 1, Reason: "reserved kind"},
+				{Index: 2, Reason: "no ID or name"},
+				{Index: 3, Reason: "both ID and name"},
+				{Index: 4, Reason: "non-positive ID"},
+				{Index: 5, Reason: "reserved name"},
+				{Index: 6, Reason: "too long name"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.key.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, gqlparser.ErrInvalidKey) {
+				t.Errorf("Validate() error = %v, want %v", err, gqlparser.ErrInvalidKey)
+			}
+
+			var got []*gqlparser.KeyPathError
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				for _, e := range joined.Unwrap() {
+					var pathErr *gqlparser.KeyPathError
+					if errors.As(e, &pathErr) {
+						got = append(got, pathErr)
+					}
+				}
+			}
+			for i := range tt.want {
+				if i < len(got) {
+					tt.want[i].Path = got[i].Path
+				}
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestKeyHierarchy(t *testing.T) {
+	t.Parallel()
+
+	grandparent := &gqlparser.Key{ProjectID: "p", Namespace: "ns", Path: []*gqlparser.KeyPath{{Kind: "A", ID: 1}}}
+	parent := &gqlparser.Key{ProjectID: "p", Namespace: "ns", Path: []*gqlparser.KeyPath{{Kind: "A", ID: 1}, {Kind: "B", Name: "b"}}}
+	child := &gqlparser.Key{ProjectID: "p", Namespace: "ns", Path: []*gqlparser.KeyPath{{Kind: "A", ID: 1}, {Kind: "B", Name: "b"}, {Kind: "C", ID: 3}}}
+	otherNamespace := &gqlparser.Key{ProjectID: "p", Namespace: "other", Path: []*gqlparser.KeyPath{{Kind: "A", ID: 1}, {Kind: "B", Name: "b"}}}
+	unboundA := &gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "A", Binding: &gqlparser.NamedBinding{Name: "a"}}}}
+	unboundB := &gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "A", Binding: &gqlparser.NamedBinding{Name: "b"}}}}
+	boundA := &gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "A", ID: 1, Binding: &gqlparser.NamedBinding{Name: "a"}}}}
+	literal := &gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "A", ID: 1}}}
+
+	if diff := cmp.Diff(parent, child.Parent()); diff != "" {
+		t.Errorf("Parent() (-want, +got)\n%s", diff)
+	}
+	if got := grandparent.Parent(); got != nil {
+		t.Errorf("Parent() = %v, want nil", got)
+	}
+
+	tests := []struct {
+		name         string
+		a, b         *gqlparser.Key
+		wantEqual    bool
+		wantAncestor bool
+	}{
+		{"Same", parent, child.Parent(), true, false},
+		{"Parent", parent, child, false, true},
+		{"Grandparent", grandparent, child, false, true},
+		{"Child", child, parent, false, false},
+		{"OtherNamespace", otherNamespace, child, false, false},
+		{"OtherNamespaceEqual", otherNamespace, parent, false, false},
+		{"Nil", nil, parent, false, false},
+		{"BothNil", nil, nil, true, false},
+		{"SameUnbound", unboundA, &gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "A", Binding: &gqlparser.NamedBinding{Name: "a"}}}}, true, false},
+		{"OtherUnbound", unboundA, unboundB, false, false},
+		{"BoundAndLiteral", boundA, literal, true, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.a.Equal(tt.b); got != tt.wantEqual {
+				t.Errorf("Equal() = %v, want %v", got, tt.wantEqual)
+			}
+			if got := tt.a.IsAncestorOf(tt.b); got != tt.wantAncestor {
+				t.Errorf("IsAncestorOf() = %v, want %v", got, tt.wantAncestor)
+			}
+		})
+	}
+}
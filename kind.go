@@ -0,0 +1,66 @@
+package gqlparser
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+var ErrInvalidKind = errors.New("invalid kind")
+
+// metadataKinds are the reserved kinds which can be queried for the metadata. e.g. SELECT * FROM __kind__
+var metadataKinds = map[Kind]struct{}{
+	"__kind__":      {},
+	"__namespace__": {},
+	"__property__":  {},
+}
+
+// KindError is the error of the kind. It wraps ErrInvalidKind.
+type KindError struct {
+	Kind   Kind
+	Reason string
+}
+
+func (e *KindError) Error() string {
+	return fmt.Sprintf("%s: %q: %s", ErrInvalidKind, string(e.Kind), e.Reason)
+}
+
+func (e *KindError) Unwrap() error {
+	return ErrInvalidKind
+}
+
+// Validate checks the kind by the rules of Cloud Datastore.
+// The kinds having the spaces or the dashes are permitted, and they're quoted with backticks in GQL. e.g. `Task List`
+//   - The kind must not be empty, and must be at most 1500 bytes.
+//   - The kind must be valid UTF-8 without the control characters.
+//   - The kind must not be reserved like __foo__ unless it's the metadata kind. e.g. __kind__
+//
+// The violation is reported as KindError.
+func (k Kind) Validate() error {
+	if reason := validateKind(k); reason != "" {
+		return &KindError{Kind: k, Reason: reason}
+	}
+	return nil
+}
+
+func validateKind(k Kind) string {
+	if k == "" {
+		return "empty kind"
+	}
+	if len(k) > MaxKindLength {
+		return "too long kind"
+	}
+	if !utf8.ValidString(string(k)) {
+		return "invalid UTF-8"
+	}
+	for _, r := range string(k) {
+		if unicode.IsControl(r) {
+			return "control character"
+		}
+	}
+	if _, ok := metadataKinds[k]; !ok && isReservedName(string(k)) {
+		return "reserved kind"
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestKindValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		kind       gqlparser.Kind
+		wantReason string
+	}{
+		{name: "Simple", kind: "Task"},
+		{name: "Space", kind: "Task List"},
+		{name: "Dash", kind: "task-list"},
+		{name: "Unicode", kind: "タスク"},
+		{name: "Metadata", kind: "__kind__"},
+		{name: "MaxLength", kind: gqlparser.Kind(strings.Repeat("a", 1500))},
+		{name: "Empty", kind: "", wantReason: "empty kind"},
+		{name: "TooLong", kind: gqlparser.Kind(strings.Repeat("a", 1501)), wantReason: "too long kind"},
+		{name: "InvalidUTF8", kind: "a\xff", wantReason: "invalid UTF-8"},
+		{name: "Control", kind: "a\nb", wantReason: "control character"},
+		{name: "Reserved", kind: "__Task__", wantReason: "reserved kind"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.kind.Validate()
+			if tt.wantReason == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+
+			var got *gqlparser.KindError
+			if !errors.As(err, &got) {
+				t.Fatalf("Validate() error = %v, want %T", err, got)
+			}
+			if got.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", got.Reason, tt.wantReason)
+			}
+			if !errors.Is(err, gqlparser.ErrInvalidKind) {
+				t.Errorf("Validate() error = %v, want %v", err, gqlparser.ErrInvalidKind)
+			}
+		})
+	}
+}
+
+func TestQuotedKind(t *testing.T) {
+	t.Parallel()
+
+	source := "SELECT * FROM `Task List` WHERE __key__ HAS ANCESTOR KEY(`task-list`, 'x', Task, 1)"
+	query, err := gqlparser.ParseQuery(gqlparser.NewLexer(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Kind != "Task List" {
+		t.Errorf("Kind = %q, want %q", query.Kind, "Task List")
+	}
+	key := query.Where.(*gqlparser.ForwardComparatorCondition).Value.(*gqlparser.Key)
+	if key.Path[0].Kind != "task-list" {
+		t.Errorf("Kind = %q, want %q", key.Path[0].Kind, "task-list")
+	}
+	if err := query.Validate(nil); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	// the kinds are quoted again by the serializer
+	got, err := gqlparser.FormatQuery(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != source {
+		t.Errorf("FormatQuery() = %q, want %q", got, source)
+	}
+
+	query.Kind = "__Task__"
+	if err := query.Validate(nil); !errors.Is(err, gqlparser.ErrInvalidKind) {
+		t.Errorf("Validate() error = %v, want %v", err, gqlparser.ErrInvalidKind)
+	}
+
+	// the double quotes are the strings
+	if _, err := gqlparser.ParseQuery(gqlparser.NewLexer(`SELECT * FROM Task WHERE __key__ HAS ANCESTOR KEY("Task List", 1)`)); err == nil {
+		t.Error("ParseQuery() should fail")
+	}
+}
@@ -1,21 +1,64 @@
 package gqlparser
 
 import (
-	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/karupanerura/runetrie"
 )
 
-var ErrEndOfToken = errors.New("end of token")
-
 type Lexer struct {
-	source   string
-	position int
-	buffer   []Token
+	source         string
+	position       int
+	buffer         []Token
+	dialect        *Dialect
+	semicolonToken bool
+	lenient        bool
+	maxLength      int
+	maxTokens      int
+	// tokens is the number of the tokens taken from the source.
+	tokens int
+}
+
+// WithLenientCharacters normalizes the non-ASCII characters often mixed in the queries pasted from the documents.
+// The byte order marks and the Unicode spaces like U+00A0 are taken as the whitespaces, and the typographic quotes
+// like ‘a’ and “a” are taken as the quoted strings. The raw contents of the tokens are kept as they are.
+// Without it, the Lexer reports them by LexError with the ASCII characters to be replaced with.
+func WithLenientCharacters() LexerOption {
+	return func(l *Lexer) {
+		l.lenient = true
+	}
+}
+
+// LexerOption configures the Lexer.
+type LexerOption func(*Lexer)
+
+// WithSemicolonToken emits SemicolonToken for the semicolons instead of OperatorToken
+// to detect the ends of the statements without scanning the source. e.g. to split the multiple statements in REPL
+func WithSemicolonToken() LexerOption {
+	return func(l *Lexer) {
+		l.semicolonToken = true
+	}
+}
+
+// WithMaxLength limits the length of the source in bytes to cap the untrusted input before lexing it.
+// The Lexer returns LimitViolationError of MaxLength on reading if the source is longer than max.
+func WithMaxLength(max int) LexerOption {
+	return func(l *Lexer) {
+		l.maxLength = max
+	}
+}
+
+// WithMaxTokens limits the number of the tokens including the whitespaces taken from the source.
+// The Lexer returns LimitViolationError of MaxTokens on reading the token after max tokens.
+// The unread tokens aren't counted again.
+func WithMaxTokens(max int) LexerOption {
+	return func(l *Lexer) {
+		l.maxTokens = max
+	}
 }
 
 var _ TokenSource = (*Lexer)(nil)
@@ -27,8 +70,8 @@ var (
 	booleanTrie  = runetrie.Must(runetrie.NewCaseInsensitiveTrie[string]())
 )
 
-func init() {
-	_ = keywordTrie.Add(
+var (
+	keywords = []string{
 		"SELECT",
 		"FROM",
 		"WHERE",
@@ -53,14 +96,25 @@ func init() {
 		"BLOB",
 		"DATETIME",
 		"NULL",
-	)
-	_ = operatorTrie.Add("AND", "OR", "IS", "CONTAINS", "HAS", "ANCESTOR", "IN", "NOT", "DESCENDANT")
-	_ = orderTrie.Add("DESC", "ASC")
-	_ = booleanTrie.Add("TRUE", "FALSE")
+	}
+	operatorKeywords = []string{"AND", "OR", "IS", "CONTAINS", "HAS", "ANCESTOR", "IN", "NOT", "DESCENDANT"}
+	orderKeywords    = []string{"DESC", "ASC"}
+	booleanKeywords  = []string{"TRUE", "FALSE"}
+)
+
+func init() {
+	_ = keywordTrie.Add(keywords...)
+	_ = operatorTrie.Add(operatorKeywords...)
+	_ = orderTrie.Add(orderKeywords...)
+	_ = booleanTrie.Add(booleanKeywords...)
 }
 
-func NewLexer(source string) *Lexer {
-	return &Lexer{source: source}
+func NewLexer(source string, opts ...LexerOption) *Lexer {
+	l := &Lexer{source: source}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
 func (l *Lexer) Next() bool {
@@ -76,20 +130,36 @@ func (l *Lexer) Read() (Token, error) {
 	if l.position == len(l.source) {
 		return nil, ErrEndOfToken
 	}
+	if l.maxLength > 0 && len(l.source) > l.maxLength {
+		return nil, &LimitViolationError{Limit: "MaxLength", Max: l.maxLength, Actual: len(l.source)}
+	}
+	if l.maxTokens > 0 && l.tokens == l.maxTokens {
+		// the rest of the source isn't lexed, so the actual number of the tokens is unknown
+		return nil, &LimitViolationError{Limit: "MaxTokens", Max: l.maxTokens, Actual: l.tokens + 1}
+	}
 
+	token, err := l.take()
+	if err != nil {
+		return nil, err
+	}
+	l.tokens++
+	return token, nil
+}
+
+func (l *Lexer) take() (Token, error) {
 	switch l.source[l.position] {
 	case ' ', '\t', '\r', '\n': // isWhitespace
-		// skip whitespace
-		pos := l.position
-		var ws strings.Builder
-		for {
-			ws.WriteByte(l.source[l.position])
-			l.position++
-			if l.position == len(l.source) || !isWhitespace(l.source[l.position]) {
-				break
+		return l.takeWhitespaceToken(), nil
+
+	case '/':
+		// the block comments are taken as the whitespaces. e.g. /* comment */
+		if strings.HasPrefix(l.source[l.position:], "/*") {
+			if commentWidth(l.source[l.position:]) == 0 {
+				return nil, &LexError{Content: "/*", Position: l.position, Reason: "unterminated comment"}
 			}
+			return l.takeWhitespaceToken(), nil
 		}
-		return &WhitespaceToken{Content: ws.String(), Position: pos}, nil
+		return l.takeSymbolToken()
 
 	case '@':
 		t, w, err := takeBindingToken(l.source[l.position:], l.position)
@@ -107,7 +177,17 @@ func (l *Lexer) Read() (Token, error) {
 		l.position += w
 		return t, nil
 
-	case '(', ',', ')', '=':
+	case ';':
+		if l.semicolonToken {
+			t := &SemicolonToken{Position: l.position}
+			l.position++
+			return t, nil
+		}
+		t := &OperatorToken{Type: ";", Position: l.position}
+		l.position++
+		return t, nil
+
+	case '(', ',', ')', '=', '.':
 		t := &OperatorToken{Type: l.source[l.position : l.position+1], Position: l.position}
 		l.position++
 		return t, nil
@@ -134,28 +214,202 @@ func (l *Lexer) Read() (Token, error) {
 		return t, nil
 
 	default:
-		if v, ok := keywordTrie.LongestMatchPrefixOf(l.source[l.position:]); ok {
-			t := &KeywordToken{Name: v, RawContent: l.source[l.position : l.position+len(v)], Position: l.position}
-			l.position += len(v)
+		// take the longest match over the tries to distinguish the words sharing the prefix. e.g. AS and ASC
+		var longest *runetrie.Trie[string]
+		var v string
+		for _, trie := range []*runetrie.Trie[string]{keywordTrie, operatorTrie, orderTrie, booleanTrie} {
+			if m, ok := trie.LongestMatchPrefixOf(l.source[l.position:]); ok && len(m) > len(v) {
+				longest, v = trie, m
+			}
+		}
+		width := len(v)
+		if l.dialect != nil && l.dialect.aliasTrie != nil {
+			if m, ok := l.dialect.aliasTrie.LongestMatchPrefixOf(l.source[l.position:]); ok && len(m) > width {
+				alias := l.dialect.keywordAliases[m]
+				longest, v, width = alias.trie, alias.canonical, len(m)
+			}
+		}
+		raw := l.source[l.position : l.position+width]
+		switch longest {
+		case keywordTrie:
+			t := &KeywordToken{Name: v, RawContent: raw, Position: l.position}
+			l.position += width
 			return t, nil
-		} else if v, ok := operatorTrie.LongestMatchPrefixOf(l.source[l.position:]); ok {
-			t := &OperatorToken{Type: v, RawContent: l.source[l.position : l.position+len(v)], Position: l.position}
-			l.position += len(v)
+		case operatorTrie:
+			t := &OperatorToken{Type: v, RawContent: raw, Position: l.position}
+			l.position += width
 			return t, nil
-		} else if v, ok := orderTrie.LongestMatchPrefixOf(l.source[l.position:]); ok {
-			t := &OrderToken{Descending: v == "DESC", RawContent: l.source[l.position : l.position+len(v)], Position: l.position}
-			l.position += len(v)
+		case orderTrie:
+			t := &OrderToken{Descending: v == "DESC", RawContent: raw, Position: l.position}
+			l.position += width
 			return t, nil
-		} else if v, ok := booleanTrie.LongestMatchPrefixOf(l.source[l.position:]); ok {
-			t := &BooleanToken{Value: v == "TRUE", RawContent: l.source[l.position : l.position+len(v)], Position: l.position}
-			l.position += len(v)
+		case booleanTrie:
+			t := &BooleanToken{Value: v == "TRUE", RawContent: raw, Position: l.position}
+			l.position += width
 			return t, nil
-		} else {
+		default:
+			if l.source[l.position] >= utf8.RuneSelf {
+				return l.takeConfusableToken()
+			}
 			return l.takeSymbolToken()
 		}
 	}
 }
 
+func (l *Lexer) takeWhitespaceToken() *WhitespaceToken {
+	pos := l.position
+	for l.position != len(l.source) {
+		w := l.whitespaceWidth(l.source[l.position:])
+		if w == 0 {
+			break
+		}
+		l.position += w
+	}
+	return &WhitespaceToken{Content: l.source[pos:l.position], Position: pos}
+}
+
+// whitespaceWidth returns the width of the whitespace or the comment at the beginning of s, or zero if it's neither of them.
+func (l *Lexer) whitespaceWidth(s string) int {
+	if isWhitespace(s[0]) {
+		return 1
+	}
+	if s[0] == '/' {
+		return commentWidth(s)
+	}
+	if !l.lenient || s[0] < utf8.RuneSelf {
+		return 0
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if c, ok := confusableRunes[r]; ok && c.whitespace {
+		return size
+	}
+	return 0
+}
+
+// commentWidth returns the width of the block comment at the beginning of s, or zero if it's not the terminated comment.
+func commentWidth(s string) int {
+	if !strings.HasPrefix(s, "/*") {
+		return 0
+	}
+	end := strings.Index(s[2:], "*/")
+	if end < 0 {
+		return 0
+	}
+	return end + 4
+}
+
+// confusableRune is the non-ASCII character to be replaced with the ASCII one.
+type confusableRune struct {
+	name string
+	// ascii is the replacement. It's empty if the character should be removed.
+	ascii      string
+	whitespace bool
+	// closing is the closing quote of the opening typographic quote.
+	closing rune
+}
+
+var confusableRunes = map[rune]confusableRune{
+	'\uFEFF': {name: "BYTE ORDER MARK", whitespace: true},
+	'\u00A0': {name: "NO-BREAK SPACE", ascii: " ", whitespace: true},
+	'\u2002': {name: "EN SPACE", ascii: " ", whitespace: true},
+	'\u2003': {name: "EM SPACE", ascii: " ", whitespace: true},
+	'\u2009': {name: "THIN SPACE", ascii: " ", whitespace: true},
+	'\u200B': {name: "ZERO WIDTH SPACE", whitespace: true},
+	'\u202F': {name: "NARROW NO-BREAK SPACE", ascii: " ", whitespace: true},
+	'\u3000': {name: "IDEOGRAPHIC SPACE", ascii: " ", whitespace: true},
+	'\u2018': {name: "LEFT SINGLE QUOTATION MARK", ascii: "'", closing: '\u2019'},
+	'\u2019': {name: "RIGHT SINGLE QUOTATION MARK", ascii: "'"},
+	'\u201C': {name: "LEFT DOUBLE QUOTATION MARK", ascii: "\"", closing: '\u201D'},
+	'\u201D': {name: "RIGHT DOUBLE QUOTATION MARK", ascii: "\""},
+}
+
+// takeConfusableToken takes the token beginning with the non-ASCII character.
+// The confusable characters are normalized in lenient mode, otherwise they're reported with the hints.
+func (l *Lexer) takeConfusableToken() (Token, error) {
+	s := l.source[l.position:]
+	r, size := utf8.DecodeRuneInString(s)
+	c, ok := confusableRunes[r]
+	if !ok {
+		return l.takeSymbolToken()
+	}
+	if l.lenient {
+		if c.whitespace {
+			return l.takeWhitespaceToken(), nil
+		}
+		if c.closing != 0 {
+			t, w, err := takeTypographicQuotedStringToken(s, l.position, c.ascii[0], c.closing)
+			if err != nil {
+				return nil, err
+			}
+			l.position += w
+			return t, nil
+		}
+	}
+
+	reason := fmt.Sprintf("%U %s", r, c.name)
+	if c.ascii == "" {
+		reason += "; remove it"
+	} else {
+		reason += "; replace it with " + strconv.Quote(c.ascii)
+	}
+	return nil, &LexError{Content: s[:size], Position: l.position, Reason: reason}
+}
+
+// takeTypographicQuotedStringToken takes the string quoted with the typographic quotes as the string quoted with the ASCII quote.
+func takeTypographicQuotedStringToken(s string, pos int, quote byte, closing rune) (*StringToken, int, error) {
+	_, begins := utf8.DecodeRuneInString(s)
+	closingQuote := string(closing)
+	needsUnescape := false
+	for i := begins; i != len(s); i++ {
+		if strings.HasPrefix(s[i:], closingQuote) {
+			content := s[begins:i]
+			if needsUnescape {
+				// the escaped closing quote is always preceded by the odd number of backslashes
+				content = unquote(strings.ReplaceAll(content, "\\"+closingQuote, closingQuote))
+			}
+			ends := i + len(closingQuote)
+			return &StringToken{Quote: quote, Content: content, RawContent: s[:ends], Position: pos}, ends, nil
+		}
+		if s[i] == '\\' {
+			i++
+			if i == len(s) {
+				return nil, 0, &LexError{Content: "\\", Position: pos + i - 1, Reason: "unterminated escape"}
+			}
+			needsUnescape = true
+		}
+	}
+	return nil, 0, &LexError{Content: s[:begins], Position: pos, Reason: "unterminated string"}
+}
+
+// redactMalformed skips the malformed part reported by the LexError to lex the rest of the source for WithRedactedSource.
+// It returns the redacted part and reports whether the rest can be lexed.
+// The unterminated strings and comments swallow the rest, so they're redacted as a whole.
+func (l *Lexer) redactMalformed(err *LexError) (string, bool) {
+	s := l.source[l.position:]
+	if s == "" {
+		return "", false
+	}
+	switch s[0] {
+	case '\'', '"':
+		return redactedStringLiteral, false
+	case '`':
+		return "`?`", false
+	case '/':
+		if strings.HasPrefix(s, "/*") {
+			return "/*?*/", false
+		}
+	}
+	if r, _ := utf8.DecodeRuneInString(s); confusableRunes[r].ascii == "'" || confusableRunes[r].ascii == "\"" {
+		// the typographic quotes may begin the string
+		return redactedStringLiteral, false
+	}
+	if err.Position != l.position || err.Content == "" || !strings.HasPrefix(s, err.Content) {
+		return "", false
+	}
+	l.position += len(err.Content)
+	return err.Content, true
+}
+
 func (l *Lexer) takeSymbolToken() (Token, error) {
 	t, w, err := takeSymbolToken(l.source[l.position:], l.position)
 	if err != nil {
@@ -198,13 +452,13 @@ func takeQuotedStringToken(s string, pos int) (*StringToken, int, error) {
 		if s[i] == '\\' {
 			i++
 			if i == len(s) {
-				return nil, 0, fmt.Errorf("unexpected token: \\")
+				return nil, 0, &LexError{Content: "\\", Position: pos + i - 1, Reason: "unterminated escape"}
 			}
 			needsUnescape = true
 		}
 	}
 	if ends == 0 {
-		return nil, 0, fmt.Errorf("unexpected token: %c", quote)
+		return nil, 0, &LexError{Content: string(quote), Position: pos, Reason: "unterminated string"}
 	}
 	content := s[begins:ends]
 	if needsUnescape {
@@ -227,14 +481,14 @@ func takeOperatorToken(s string, pos int) (*OperatorToken, int, error) {
 
 func takeBindingToken(s string, pos int) (*BindingToken, int, error) {
 	if len(s) == 1 {
-		return nil, 0, fmt.Errorf("unexpected token: %c", s[0])
+		return nil, 0, &LexError{Content: s[0:1], Position: pos}
 	}
 
 	width := 1
 	numeric := false
 	switch s[width] {
 	case '0':
-		return nil, 0, fmt.Errorf("unexpected token: %s (invalid binding site)", s[0:width])
+		return nil, 0, &LexError{Content: s[0:width], Position: pos, Reason: "invalid binding site"}
 	case '1', '2', '3', '4', '5', '6', '7', '8', '9':
 		numeric = true
 		for '0' <= s[width] && s[width] <= '9' {
@@ -255,7 +509,7 @@ func takeBindingToken(s string, pos int) (*BindingToken, int, error) {
 	if numeric {
 		n, err := strconv.ParseInt(s[1:width], 10, 64)
 		if err != nil {
-			return nil, 0, fmt.Errorf("unexpected token: %s (%w)", s[0:width], err)
+			return nil, 0, &LexError{Content: s[0:width], Position: pos, Cause: err}
 		}
 		return &BindingToken{Index: n, Position: pos}, width, nil
 	} else {
@@ -285,13 +539,13 @@ func takeNumericToken(s string, pos int) (Token, int, error) {
 	if float {
 		n, err := strconv.ParseFloat(s[:width], 64)
 		if err != nil {
-			return nil, 0, fmt.Errorf("unexpected token: %s (%w)", s[:width], err)
+			return nil, 0, &LexError{Content: s[:width], Position: pos, Cause: err}
 		}
 		return &NumericToken{Float64: n, Floating: true, RawContent: s[:width], Position: pos}, width, nil
 	} else {
 		n, err := strconv.ParseInt(s[:width], 10, 64)
 		if err != nil {
-			return nil, 0, fmt.Errorf("unexpected token: %s (%w)", s[:width], err)
+			return nil, 0, &LexError{Content: s[:width], Position: pos, Cause: err}
 		}
 		return &NumericToken{Int64: n, Floating: false, RawContent: s[:width], Position: pos}, width, nil
 	}
@@ -306,23 +560,21 @@ func takeSymbolToken(s string, pos int) (*SymbolToken, int, error) {
 		}
 	}
 	if width == 0 {
-		return nil, 0, fmt.Errorf("unexpected token: %c", s[width])
+		_, size := utf8.DecodeRuneInString(s)
+		return nil, 0, &LexError{Content: s[:size], Position: pos}
 	}
 	for s[width] == '.' {
-		width++
-		if width == len(s) {
-			return &SymbolToken{Content: s[:width], Position: pos}, width, nil
+		// the dot is left as an operator if it isn't followed by the next segment. e.g. a.`b c`
+		if width+1 == len(s) || !isSymbolByte(s[width+1]) {
+			break
 		}
-		base := width
-		for isSymbolFollowingByte(s[width]) {
+		width++
+		for isSymbolByte(s[width]) {
 			width++
 			if width == len(s) {
 				return &SymbolToken{Content: s[:width], Position: pos}, width, nil
 			}
 		}
-		if width == base {
-			return nil, 0, fmt.Errorf("unexpected token: %c", s[width])
-		}
 	}
 
 	return &SymbolToken{Content: s[:width], Position: pos}, width, nil
@@ -336,14 +588,6 @@ func isSymbolByte(b byte) bool {
 	return unicode.IsLetter(rune(b)) || unicode.IsDigit(rune(b)) || b == '_' || b == '$'
 }
 
-// isSymbolFollowingByte matches the regular expression `[0-9_$]`.
-func isSymbolFollowingByte(b byte) bool {
-	if b > unicode.MaxASCII {
-		return false
-	}
-	return unicode.IsDigit(rune(b)) || b == '_' || b == '$'
-}
-
 var unquoteReplacer = strings.NewReplacer(
 	"\\\\", "\\",
 	"\\0", "\u0000", // NULL
@@ -1,10 +1,12 @@
 package gqlparser_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/karupanerura/gqlparser"
+	"github.com/karupanerura/gqlparser/gqltest"
 )
 
 func TestLexer(t *testing.T) {
@@ -199,6 +201,16 @@ func TestLexer(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:   "NestedPropertyPath",
+			source: "a.b1.$c.`d e`",
+			want: []gqlparser.Token{
+				&gqlparser.SymbolToken{Content: "a.b1.$c", Position: 0},
+				&gqlparser.OperatorToken{Type: ".", Position: 7},
+				&gqlparser.StringToken{Quote: '`', Content: "d e", RawContent: "`d e`", Position: 8},
+			},
+			wantErr: false,
+		},
 		{
 			name:   "ComplexQuery",
 			source: "SELECT a, b, c FROM Kind",
@@ -237,6 +249,232 @@ func TestLexer(t *testing.T) {
 	}
 }
 
+func TestLexer_WithSemicolonToken(t *testing.T) {
+	t.Parallel()
+
+	got, err := gqlparser.ReadAllTokens(gqlparser.NewLexer("SELECT * FROM A; ';'", gqlparser.WithSemicolonToken()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []gqlparser.Token{
+		&gqlparser.KeywordToken{Name: "SELECT", RawContent: "SELECT", Position: 0},
+		&gqlparser.WhitespaceToken{Content: " ", Position: 6},
+		&gqlparser.WildcardToken{Position: 7},
+		&gqlparser.WhitespaceToken{Content: " ", Position: 8},
+		&gqlparser.KeywordToken{Name: "FROM", RawContent: "FROM", Position: 9},
+		&gqlparser.WhitespaceToken{Content: " ", Position: 13},
+		&gqlparser.SymbolToken{Content: "A", Position: 14},
+		&gqlparser.SemicolonToken{Position: 15},
+		&gqlparser.WhitespaceToken{Content: " ", Position: 16},
+		&gqlparser.StringToken{Quote: '\'', Content: ";", RawContent: "';'", Position: 17},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	// the parser accepts the semicolon token at the end of the query
+	if _, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM A;", gqlparser.WithSemicolonToken())); err != nil {
+		t.Errorf("ParseQuery() error = %v", err)
+	}
+	if _, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM A;", gqlparser.WithSemicolonToken()), gqlparser.WithStrictMode()); err == nil {
+		t.Error("ParseQuery() error = nil in strict mode")
+	}
+}
+
+func TestLexer_LongestMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		source string
+		want   []gqlparser.Token
+	}{
+		{
+			name:   "AS/ASC",
+			source: "AS asc",
+			want: []gqlparser.Token{
+				&gqlparser.KeywordToken{Name: "AS", RawContent: "AS", Position: 0},
+				&gqlparser.WhitespaceToken{Content: " ", Position: 2},
+				&gqlparser.OrderToken{Descending: false, RawContent: "asc", Position: 3},
+			},
+		},
+		{
+			name:   "DESC/DESCENDANT",
+			source: "Desc DESCENDANT",
+			want: []gqlparser.Token{
+				&gqlparser.OrderToken{Descending: true, RawContent: "Desc", Position: 0},
+				&gqlparser.WhitespaceToken{Content: " ", Position: 4},
+				&gqlparser.OperatorToken{Type: "DESCENDANT", RawContent: "DESCENDANT", Position: 5},
+			},
+		},
+		{
+			name:   "ORDER/OR",
+			source: "OR ORDER",
+			want: []gqlparser.Token{
+				&gqlparser.OperatorToken{Type: "OR", RawContent: "OR", Position: 0},
+				&gqlparser.WhitespaceToken{Content: " ", Position: 2},
+				&gqlparser.KeywordToken{Name: "ORDER", RawContent: "ORDER", Position: 3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := gqlparser.ReadAllTokens(gqlparser.NewLexer(tt.source))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLexer_Comments(t *testing.T) {
+	t.Parallel()
+
+	got, err := gqlparser.ReadAllTokens(gqlparser.NewLexer("SELECT/* a */ * /* b */\n/**/FROM A"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []gqlparser.Token{
+		&gqlparser.KeywordToken{Name: "SELECT", RawContent: "SELECT", Position: 0},
+		&gqlparser.WhitespaceToken{Content: "/* a */ ", Position: 6},
+		&gqlparser.WildcardToken{Position: 14},
+		&gqlparser.WhitespaceToken{Content: " /* b */\n/**/", Position: 15},
+		&gqlparser.KeywordToken{Name: "FROM", RawContent: "FROM", Position: 28},
+		&gqlparser.WhitespaceToken{Content: " ", Position: 32},
+		&gqlparser.SymbolToken{Content: "A", Position: 33},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	_, err = gqlparser.ReadAllTokens(gqlparser.NewLexer("SELECT * /* FROM A"))
+	var lexErr *gqlparser.LexError
+	if !errors.As(err, &lexErr) || lexErr.Position != 9 || lexErr.Reason != "unterminated comment" {
+		t.Errorf("ReadAllTokens() error = %v", err)
+	}
+}
+
+func TestLexer_WithLenientCharacters(t *testing.T) {
+	t.Parallel()
+
+	source := "\uFEFFSELECT\u00A0*\u3000 FROM A WHERE b = \u2018it\\\u2019s\u2019 AND c = \u201Cx\u201D"
+	got, err := gqlparser.ReadAllTokens(gqlparser.NewLexer(source, gqlparser.WithLenientCharacters()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []gqlparser.Token{
+		&gqlparser.WhitespaceToken{Content: "\uFEFF", Position: 0},
+		&gqlparser.KeywordToken{Name: "SELECT", RawContent: "SELECT", Position: 3},
+		&gqlparser.WhitespaceToken{Content: "\u00A0", Position: 9},
+		&gqlparser.WildcardToken{Position: 11},
+		&gqlparser.WhitespaceToken{Content: "\u3000 ", Position: 12},
+		&gqlparser.KeywordToken{Name: "FROM", RawContent: "FROM", Position: 16},
+		&gqlparser.WhitespaceToken{Content: " ", Position: 20},
+		&gqlparser.SymbolToken{Content: "A", Position: 21},
+		&gqlparser.WhitespaceToken{Content: " ", Position: 22},
+		&gqlparser.KeywordToken{Name: "WHERE", RawContent: "WHERE", Position: 23},
+		&gqlparser.WhitespaceToken{Content: " ", Position: 28},
+		&gqlparser.SymbolToken{Content: "b", Position: 29},
+		&gqlparser.WhitespaceToken{Content: " ", Position: 30},
+		&gqlparser.OperatorToken{Type: "=", Position: 31},
+		&gqlparser.WhitespaceToken{Content: " ", Position: 32},
+		&gqlparser.StringToken{Quote: '\'', Content: "it\u2019s", RawContent: "\u2018it\\\u2019s\u2019", Position: 33},
+		&gqlparser.WhitespaceToken{Content: " ", Position: 46},
+		&gqlparser.OperatorToken{Type: "AND", RawContent: "AND", Position: 47},
+		&gqlparser.WhitespaceToken{Content: " ", Position: 50},
+		&gqlparser.SymbolToken{Content: "c", Position: 51},
+		&gqlparser.WhitespaceToken{Content: " ", Position: 52},
+		&gqlparser.OperatorToken{Type: "=", Position: 53},
+		&gqlparser.WhitespaceToken{Content: " ", Position: 54},
+		&gqlparser.StringToken{Quote: '"', Content: "x", RawContent: "\u201Cx\u201D", Position: 55},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestLexer_ConfusableCharacters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		source  string
+		lenient bool
+		wantErr string
+	}{
+		{"ByteOrderMark", "\uFEFFSELECT", false, "unexpected token: \uFEFF at 0 (U+FEFF BYTE ORDER MARK; remove it)"},
+		{"NoBreakSpace", "SELECT\u00A0*", false, "unexpected token: \u00A0 at 6 (U+00A0 NO-BREAK SPACE; replace it with \" \")"},
+		{"TypographicQuote", "\u2018a\u2019", false, "unexpected token: \u2018 at 0 (U+2018 LEFT SINGLE QUOTATION MARK; replace it with \"'\")"},
+		{"ClosingQuote", "\u2019a", true, "unexpected token: \u2019 at 0 (U+2019 RIGHT SINGLE QUOTATION MARK; replace it with \"'\")"},
+		{"UnterminatedQuote", "\u201Ca'", true, "unexpected token: \u201C at 0 (unterminated string)"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var opts []gqlparser.LexerOption
+			if tt.lenient {
+				opts = append(opts, gqlparser.WithLenientCharacters())
+			}
+			_, err := gqlparser.ReadAllTokens(gqlparser.NewLexer(tt.source, opts...))
+			if err == nil {
+				t.Fatal("ReadAllTokens() error = nil")
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLexer_Limits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		source string
+		opts   []gqlparser.LexerOption
+		want   *gqlparser.LimitViolationError
+	}{
+		{"WithinMaxLength", "SELECT * FROM A", []gqlparser.LexerOption{gqlparser.WithMaxLength(15)}, nil},
+		{"ExceedMaxLength", "SELECT * FROM A", []gqlparser.LexerOption{gqlparser.WithMaxLength(14)}, &gqlparser.LimitViolationError{Limit: "MaxLength", Max: 14, Actual: 15}},
+		{"WithinMaxTokens", "SELECT * FROM A", []gqlparser.LexerOption{gqlparser.WithMaxTokens(7)}, nil},
+		{"ExceedMaxTokens", "SELECT * FROM A WHERE a = 1", []gqlparser.LexerOption{gqlparser.WithMaxTokens(7)}, &gqlparser.LimitViolationError{Limit: "MaxTokens", Max: 7, Actual: 8}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source, tt.opts...))
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("ParseQuery() error = %v", err)
+				}
+				return
+			}
+
+			var got *gqlparser.LimitViolationError
+			if !errors.As(err, &got) {
+				t.Fatalf("ParseQuery() error = %v, want %T", err, got)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+			if !errors.Is(err, gqlparser.ErrLimitExceeded) {
+				t.Errorf("ParseQuery() error = %v, want %v", err, gqlparser.ErrLimitExceeded)
+			}
+		})
+	}
+}
+
 func FuzzLexer(f *testing.F) {
 	f.Fuzz(func(t *testing.T, src string) {
 		lexer := gqlparser.NewLexer(src)
@@ -244,3 +482,15 @@ func FuzzLexer(f *testing.F) {
 		// should be no panics
 	})
 }
+
+func TestLexer_Conformance(t *testing.T) {
+	t.Parallel()
+
+	gqltest.RunTokenSourceConformance(t, func(t *testing.T, tokens []gqlparser.Token, err error) gqlparser.TokenSource {
+		if err != nil {
+			// the lexer fails only on the malformed source
+			return nil
+		}
+		return gqlparser.NewLexer(gqlparser.RenderTokens(tokens))
+	})
+}
@@ -0,0 +1,173 @@
+package gqlparser
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// The limits of the language by Cloud Datastore shared by the validators.
+// See https://cloud.google.com/datastore/docs/concepts/limits
+const (
+	// MaxKindLength is the limit of the kinds in bytes. It's checked by Kind.Validate.
+	MaxKindLength = 1500
+	// MaxKeyNameLength is the limit of the names of the key paths in bytes. It's checked by Key.Validate.
+	MaxKeyNameLength = 1500
+	// MaxKeyPathElements is the limit of the elements of the key paths including the ancestors. It's checked by Key.Validate.
+	MaxKeyPathElements = 100
+	// MaxPropertyNameLength is the limit of the segments of the property paths in bytes. It's checked by Property.Validate.
+	MaxPropertyNameLength = 1500
+	// MaxPropertyPathDepth is the limit of the nesting of the entity values. It's checked by Property.Validate.
+	MaxPropertyPathDepth = 20
+	// MaxIndexedPropertyLength is the limit of the indexed string values in bytes. It's checked by ValidateCondition.
+	// The longer values aren't indexed, so the queries can't find the entities by them.
+	MaxIndexedPropertyLength = 1500
+	// MaxDisjunctions is the limit of the disjunctions of the filters.
+	// It's checked by EnforceLimits, SplitOr and RequiredIndexes.
+	MaxDisjunctions = 30
+)
+
+// Limits is the maximums of the query complexity for EnforceLimits. The zero fields are unlimited.
+type Limits struct {
+	// MaxConditions is the maximum number of the comparisons and the IS NULL conditions in WHERE.
+	MaxConditions int
+	// MaxOrBranches is the maximum number of the disjunctions after expanding WHERE into the disjunctive normal form.
+	// e.g. (a = 1 OR a = 2) AND (b = 1 OR b = 2) has 4 branches.
+	// The disjunctions are limited by MaxDisjunctions even if it's zero or larger than that.
+	MaxOrBranches int
+	// MaxInSize is the maximum number of the values in each array. e.g. a IN ARRAY(1, 2, 3)
+	MaxInSize int
+	// MaxOrderBy is the maximum number of the properties in ORDER BY.
+	MaxOrderBy int
+	// MaxProjections is the maximum number of the projected properties.
+	MaxProjections int
+}
+
+// LimitViolationError is the error of the exceeded limit. It wraps ErrLimitExceeded.
+type LimitViolationError struct {
+	// Limit is the name of the field of Limits, the name of the limit of the lexer, or the name of the constant.
+	// e.g. MaxConditions, MaxLength, MaxDisjunctions
+	Limit  string
+	Max    int
+	Actual int
+}
+
+func (e *LimitViolationError) Error() string {
+	return fmt.Sprintf("%s: %s is %d (exceeds %d)", ErrLimitExceeded, e.Limit, e.Actual, e.Max)
+}
+
+func (e *LimitViolationError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// EnforceLimits checks the complexity of the query by the limits to reject the expensive queries before executing them.
+// The violations are reported as LimitViolationError joined by errors.Join.
+func EnforceLimits(q *Query, limits Limits) error {
+	var errs []error
+	check := func(limit string, max, actual int) {
+		if max > 0 && actual > max {
+			errs = append(errs, &LimitViolationError{Limit: limit, Max: max, Actual: actual})
+		}
+	}
+
+	if q.Where != nil {
+		var conditions, inSize int
+		walkConditions(q.Where, func(cond Condition) {
+			conditions++
+			if n := conditionArraySize(cond); n > inSize {
+				inSize = n
+			}
+		})
+		check("MaxConditions", limits.MaxConditions, conditions)
+		if branches := countOrBranches(q.Where); limits.MaxOrBranches > 0 && limits.MaxOrBranches < MaxDisjunctions {
+			check("MaxOrBranches", limits.MaxOrBranches, branches)
+		} else {
+			check("MaxDisjunctions", MaxDisjunctions, branches)
+		}
+		check("MaxInSize", limits.MaxInSize, inSize)
+	}
+	check("MaxOrderBy", limits.MaxOrderBy, len(q.OrderBy))
+	check("MaxProjections", limits.MaxProjections, len(q.Properties))
+	return errors.Join(errs...)
+}
+
+// walkConditions calls the callback with the leaf conditions of the compound conditions.
+func walkConditions(cond Condition, f func(Condition)) {
+	switch c := cond.(type) {
+	case *AndCompoundCondition:
+		walkConditions(c.Left, f)
+		walkConditions(c.Right, f)
+	case *OrCompoundCondition:
+		walkConditions(c.Left, f)
+		walkConditions(c.Right, f)
+	default:
+		f(cond)
+	}
+}
+
+// walkConditionValues calls the callback with the values of the leaf conditions as walkValues.
+func walkConditionValues(cond Condition, f func(any)) {
+	walkConditions(cond, func(leaf Condition) {
+		value, _ := comparatorValue(leaf)
+		walkValues(value, f)
+	})
+}
+
+// walkValues calls the callback with the value and the elements of the arrays in it.
+func walkValues(value any, f func(any)) {
+	f(value)
+	if values, ok := value.([]any); ok {
+		for _, v := range values {
+			walkValues(v, f)
+		}
+	}
+}
+
+// hasOrCondition reports whether the condition has OR in any depth.
+func hasOrCondition(cond Condition) bool {
+	switch c := cond.(type) {
+	case *AndCompoundCondition:
+		return hasOrCondition(c.Left) || hasOrCondition(c.Right)
+	case *OrCompoundCondition:
+		return true
+	default:
+		return false
+	}
+}
+
+func conditionArraySize(cond Condition) int {
+	value, _ := comparatorValue(cond)
+	if values, ok := value.([]any); ok {
+		return len(values)
+	}
+	return 0
+}
+
+// countOrBranches counts the disjunctions of disjunctiveNormalForm without expanding them.
+// It saturates at math.MaxInt not to overflow by the huge conditions.
+func countOrBranches(cond Condition) int {
+	switch c := cond.(type) {
+	case *OrCompoundCondition:
+		left, right := countOrBranches(c.Left), countOrBranches(c.Right)
+		if left > math.MaxInt-right {
+			return math.MaxInt
+		}
+		return left + right
+	case *AndCompoundCondition:
+		left, right := countOrBranches(c.Left), countOrBranches(c.Right)
+		if left > math.MaxInt/right {
+			return math.MaxInt
+		}
+		return left * right
+	case *QuantifiedComparatorCondition:
+		// CONTAINS ANY is expanded into OR by Normalize
+		if values, ok := c.Value.([]any); ok && c.Comparator == ContainsAnyQuantifiedComparator && len(values) != 0 {
+			return len(values)
+		}
+		return 1
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,92 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestEnforceLimits(t *testing.T) {
+	t.Parallel()
+
+	limits := gqlparser.Limits{MaxConditions: 4, MaxOrBranches: 3, MaxInSize: 2, MaxOrderBy: 1, MaxProjections: 2}
+	tests := []struct {
+		name   string
+		source string
+		limits gqlparser.Limits
+		want   []*gqlparser.LimitViolationError
+	}{
+		{
+			name:   "WithinLimits",
+			source: "SELECT a, b FROM Kind WHERE (a = 1 OR a = 2) AND b IN ARRAY(1, 2) ORDER BY a",
+			limits: limits,
+		},
+		{
+			name:   "Unlimited",
+			source: "SELECT a, b, c FROM Kind WHERE (a = 1 OR a = 2) AND (b = 1 OR b = 2) AND c IN ARRAY(1, 2, 3) ORDER BY a, b",
+		},
+		{
+			name:   "NoWhere",
+			source: "SELECT a, b, c FROM Kind ORDER BY a, b",
+			limits: limits,
+			want: []*gqlparser.LimitViolationError{
+				{Limit: "MaxOrderBy", Max: 1, Actual: 2},
+				{Limit: "MaxProjections", Max: 2, Actual: 3},
+			},
+		},
+		{
+			name:   "MaxDisjunctions",
+			source: "SELECT * FROM Kind WHERE (a = 1 OR a = 2) AND (b = 1 OR b = 2) AND (c = 1 OR c = 2) AND (d = 1 OR d = 2) AND (e = 1 OR e = 2)",
+			want: []*gqlparser.LimitViolationError{
+				{Limit: "MaxDisjunctions", Max: gqlparser.MaxDisjunctions, Actual: 32},
+			},
+		},
+		{
+			name:   "ExceedAll",
+			source: "SELECT a, b, c FROM Kind WHERE (a = 1 OR a = 2) AND (b = 1 OR b = 2) AND c IN ARRAY(1, 2, 3) ORDER BY a, b",
+			limits: limits,
+			want: []*gqlparser.LimitViolationError{
+				{Limit: "MaxConditions", Max: 4, Actual: 5},
+				{Limit: "MaxOrBranches", Max: 3, Actual: 4},
+				{Limit: "MaxInSize", Max: 2, Actual: 3},
+				{Limit: "MaxOrderBy", Max: 1, Actual: 2},
+				{Limit: "MaxProjections", Max: 2, Actual: 3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = gqlparser.EnforceLimits(query, tt.limits)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("EnforceLimits() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, gqlparser.ErrLimitExceeded) {
+				t.Fatalf("EnforceLimits() error = %v, want %v", err, gqlparser.ErrLimitExceeded)
+			}
+
+			var got []*gqlparser.LimitViolationError
+			for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+				var violation *gqlparser.LimitViolationError
+				if errors.As(e, &violation) {
+					got = append(got, violation)
+				}
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
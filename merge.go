@@ -0,0 +1,135 @@
+package gqlparser
+
+// Entity is an entity in the results of the subqueries merged by OrPlan.Merge.
+// Key is required, and Properties need to have the values of the properties in ORDER BY at least.
+type Entity struct {
+	Key        *Key
+	Properties map[Property]any
+}
+
+// EntityIterator is the results of a subquery in the order of the subquery.
+type EntityIterator interface {
+	// Next returns the next entity. It returns nil at the end of the results.
+	Next() (*Entity, error)
+}
+
+// EntityIteratorFunc is the function to be used as EntityIterator.
+type EntityIteratorFunc func() (*Entity, error)
+
+func (f EntityIteratorFunc) Next() (*Entity, error) {
+	return f()
+}
+
+// Merge merges the results of the subqueries of the plan into the results of the query as OrPlan describes.
+// The results are read lazily, and the first error of the results is returned as is.
+// The entities are compared by CompareValues, and the missing properties are regarded as NULL.
+func (p *OrPlan) Merge(results []EntityIterator) EntityIterator {
+	m := &mergedEntityIterator{
+		orderBy: p.OrderBy,
+		results: results,
+		heads:   make([]*Entity, len(results)),
+		done:    make([]bool, len(results)),
+		limit:   -1,
+	}
+	if p.Limit != nil {
+		m.limit = p.Limit.Position
+	}
+	if p.Offset != nil {
+		m.offset = p.Offset.Position
+	}
+	return m
+}
+
+type mergedEntityIterator struct {
+	orderBy []OrderBy
+	results []EntityIterator
+	// heads are the next entities of the results. They're nil if not read yet or at the end.
+	heads []*Entity
+	done  []bool
+	last  *Entity
+	// limit is the number of the entities to return, or negative if unlimited.
+	limit  int64
+	offset int64
+}
+
+func (m *mergedEntityIterator) Next() (*Entity, error) {
+	for m.limit != 0 {
+		entity, err := m.pop()
+		if err != nil || entity == nil {
+			return nil, err
+		}
+
+		// the duplicated entities are ordered in a row since they have the same values to be compared
+		if m.last != nil && compareKeys(m.last.Key, entity.Key) == 0 {
+			continue
+		}
+		m.last = entity
+
+		if m.offset > 0 {
+			m.offset--
+			continue
+		}
+		if m.limit > 0 {
+			m.limit--
+		}
+		return entity, nil
+	}
+	return nil, nil
+}
+
+// pop returns the least entity of the heads and advances the results of it.
+func (m *mergedEntityIterator) pop() (*Entity, error) {
+	least := -1
+	for i, result := range m.results {
+		if m.heads[i] == nil && !m.done[i] {
+			entity, err := result.Next()
+			if err != nil {
+				return nil, err
+			}
+			m.heads[i] = entity
+			m.done[i] = entity == nil
+		}
+		if m.heads[i] == nil {
+			continue
+		}
+
+		if least < 0 {
+			least = i
+		} else if c, err := compareEntities(m.orderBy, m.heads[i], m.heads[least]); err != nil {
+			return nil, err
+		} else if c < 0 {
+			least = i
+		}
+	}
+	if least < 0 {
+		return nil, nil
+	}
+
+	entity := m.heads[least]
+	m.heads[least] = nil
+	return entity, nil
+}
+
+// compareEntities compares the entities by ORDER BY. The keys are compared as __key__.
+func compareEntities(orderBy []OrderBy, a, b *Entity) (int, error) {
+	for _, o := range orderBy {
+		var av, bv any
+		if o.Property == keyProperty {
+			av, bv = a.Key, b.Key
+		} else {
+			av, bv = a.Properties[o.Property], b.Properties[o.Property]
+		}
+
+		c, err := CompareValues(av, bv)
+		if err != nil {
+			return 0, err
+		}
+		if o.Descending {
+			c = -c
+		}
+		if c != 0 {
+			return c, nil
+		}
+	}
+	return 0, nil
+}
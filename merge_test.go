@@ -0,0 +1,146 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func sliceEntityIterator(entities ...*gqlparser.Entity) gqlparser.EntityIterator {
+	return gqlparser.EntityIteratorFunc(func() (*gqlparser.Entity, error) {
+		if len(entities) == 0 {
+			return nil, nil
+		}
+		entity := entities[0]
+		entities = entities[1:]
+		return entity, nil
+	})
+}
+
+func newEntity(id int64, c int64) *gqlparser.Entity {
+	return &gqlparser.Entity{
+		Key:        &gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "Kind", ID: id}}},
+		Properties: map[gqlparser.Property]any{"c": c},
+	}
+}
+
+func TestOrPlanMerge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		source  string
+		results [][]*gqlparser.Entity
+		want    []int64
+	}{
+		{
+			name:   "Dedupe",
+			source: "SELECT * FROM Kind WHERE a = 1 OR b = 2 ORDER BY c",
+			results: [][]*gqlparser.Entity{
+				{newEntity(1, 10), newEntity(3, 20), newEntity(5, 20)},
+				{newEntity(2, 10), newEntity(3, 20), newEntity(4, 30)},
+			},
+			want: []int64{1, 2, 3, 5, 4},
+		},
+		{
+			name:   "LimitOffset",
+			source: "SELECT * FROM Kind WHERE a = 1 OR b = 2 OR c = 3 ORDER BY c DESC LIMIT 2 OFFSET 1",
+			results: [][]*gqlparser.Entity{
+				{newEntity(1, 30), newEntity(2, 20), newEntity(3, 10)},
+				{newEntity(1, 30), newEntity(4, 25)},
+				{},
+			},
+			want: []int64{4, 2},
+		},
+		{
+			name:   "OffsetOnly",
+			source: "SELECT * FROM Kind WHERE a = 1 OR b = 2 OFFSET 3",
+			results: [][]*gqlparser.Entity{
+				{newEntity(1, 0), newEntity(3, 0), newEntity(5, 0)},
+				{newEntity(2, 0), newEntity(4, 0)},
+			},
+			want: []int64{4, 5},
+		},
+		{
+			name:   "NoOr",
+			source: "SELECT * FROM Kind WHERE a = 1 ORDER BY c LIMIT 2 OFFSET 1",
+			results: [][]*gqlparser.Entity{
+				{newEntity(2, 10), newEntity(1, 10)},
+			},
+			want: []int64{2, 1},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source))
+			if err != nil {
+				t.Fatal(err)
+			}
+			plan, err := gqlparser.PlanOr(query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var results []gqlparser.EntityIterator
+			for _, r := range tt.results {
+				results = append(results, sliceEntityIterator(r...))
+			}
+
+			var got []int64
+			merged := plan.Merge(results)
+			for {
+				entity, err := merged.Next()
+				if err != nil {
+					t.Fatalf("Next() error = %v", err)
+				}
+				if entity == nil {
+					break
+				}
+				got = append(got, entity.Key.Path[0].ID)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOrPlanMerge_Error(t *testing.T) {
+	t.Parallel()
+
+	query, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM Kind WHERE a = 1 OR b = 2 ORDER BY c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := gqlparser.PlanOr(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errFetch := errors.New("fetch error")
+	merged := plan.Merge([]gqlparser.EntityIterator{
+		sliceEntityIterator(newEntity(1, 10)),
+		gqlparser.EntityIteratorFunc(func() (*gqlparser.Entity, error) {
+			return nil, errFetch
+		}),
+	})
+	if _, err := merged.Next(); !errors.Is(err, errFetch) {
+		t.Errorf("Next() error = %v, want %v", err, errFetch)
+	}
+
+	// the values that cannot be compared
+	merged = plan.Merge([]gqlparser.EntityIterator{
+		sliceEntityIterator(newEntity(1, 10)),
+		sliceEntityIterator(&gqlparser.Entity{
+			Key:        &gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "Kind", ID: 2}}},
+			Properties: map[gqlparser.Property]any{"c": struct{}{}},
+		}),
+	})
+	if _, err := merged.Next(); !errors.Is(err, gqlparser.ErrTypeMismatch) {
+		t.Errorf("Next() error = %v, want %v", err, gqlparser.ErrTypeMismatch)
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/karupanerura/gqlparser/mongodb
+
+go 1.22.0
+
+require (
+	github.com/google/go-cmp v0.6.0
+	github.com/karupanerura/gqlparser v0.0.0
+)
+
+require github.com/karupanerura/runetrie v0.0.0-20240410000052-ea8ffbef19db // indirect
+
+replace github.com/karupanerura/gqlparser => ../
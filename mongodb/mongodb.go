@@ -0,0 +1,114 @@
+// Package mongodb converts the GQL conditions into the filter documents of MongoDB.
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/karupanerura/gqlparser"
+)
+
+var ErrUnsupported = errors.New("unsupported")
+
+// Filter is a bson-like filter document.
+// It can be passed to the MongoDB drivers as it is because every value is a map, a slice or a primitive value.
+type Filter map[string]any
+
+var eitherComparatorOperators = map[gqlparser.EitherComparator]string{
+	gqlparser.EqualsEitherComparator:                  "$eq",
+	gqlparser.NotEqualsEitherComparator:               "$ne",
+	gqlparser.GreaterThanEitherComparator:             "$gt",
+	gqlparser.GreaterThanOrEqualsThanEitherComparator: "$gte",
+	gqlparser.LesserThanEitherComparator:              "$lt",
+	gqlparser.LesserThanOrEqualsEitherComparator:      "$lte",
+}
+
+// ConvertCondition converts the condition into the filter document.
+// The condition is expected to be bound already.
+// Ancestor queries and keys are not supported because MongoDB has no equivalent of them.
+func ConvertCondition(cond gqlparser.Condition) (Filter, error) {
+	switch c := cond.Normalize().(type) {
+	case *gqlparser.AndCompoundCondition:
+		return convertCompoundCondition("$and", c.Left, c.Right)
+	case *gqlparser.OrCompoundCondition:
+		return convertCompoundCondition("$or", c.Left, c.Right)
+	case *gqlparser.EitherComparatorCondition:
+		op, ok := eitherComparatorOperators[c.Comparator]
+		if !ok {
+			return nil, fmt.Errorf("%w: comparator %s", ErrUnsupported, c.Comparator)
+		}
+		return fieldFilter(c.Property, op, c.Value)
+	case *gqlparser.ForwardComparatorCondition:
+		switch c.Comparator {
+		case gqlparser.InForwardComparator:
+			return fieldFilter(c.Property, "$in", c.Value)
+		case gqlparser.NotInForwardComparator:
+			return fieldFilter(c.Property, "$nin", c.Value)
+		default:
+			return nil, fmt.Errorf("%w: comparator %s", ErrUnsupported, c.Comparator)
+		}
+	case *gqlparser.BackwardComparatorCondition:
+		return nil, fmt.Errorf("%w: comparator %s", ErrUnsupported, c.Comparator)
+	default:
+		return nil, fmt.Errorf("%w: condition %T", ErrUnsupported, cond)
+	}
+}
+
+func convertCompoundCondition(op string, left, right gqlparser.Condition) (Filter, error) {
+	var filters []any
+	for _, cond := range []gqlparser.Condition{left, right} {
+		filter, err := ConvertCondition(cond)
+		if err != nil {
+			return nil, err
+		}
+
+		// flatten the nested filters that have the same operator
+		if children, ok := filter[op]; ok && len(filter) == 1 {
+			filters = append(filters, children.([]any)...)
+		} else {
+			filters = append(filters, filter)
+		}
+	}
+	return Filter{op: filters}, nil
+}
+
+func fieldFilter(property, op string, value any) (Filter, error) {
+	if property == "__key__" {
+		return nil, fmt.Errorf("%w: property %s", ErrUnsupported, property)
+	}
+
+	v, err := convertValue(value)
+	if err != nil {
+		return nil, err
+	}
+	if (op == "$in" || op == "$nin") && v != nil {
+		if _, isArray := v.([]any); !isArray {
+			return nil, fmt.Errorf("%w: %s with %T", ErrUnsupported, op, value)
+		}
+	}
+	return Filter{property: Filter{op: v}}, nil
+}
+
+func convertValue(value any) (any, error) {
+	switch v := value.(type) {
+	case nil, bool, int64, float64, string, []byte, time.Time:
+		return v, nil
+	case []any:
+		values := make([]any, len(v))
+		for i, item := range v {
+			mv, err := convertValue(item)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = mv
+		}
+		return values, nil
+	case *gqlparser.Key:
+		return nil, fmt.Errorf("%w: key value", ErrUnsupported)
+	case gqlparser.BindingVariable:
+		return nil, fmt.Errorf("%w: unbound variable %v", ErrUnsupported, v)
+	default:
+		return nil, fmt.Errorf("%w: value %T", ErrUnsupported, value)
+	}
+}
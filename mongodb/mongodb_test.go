@@ -0,0 +1,90 @@
+package mongodb_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+	"github.com/karupanerura/gqlparser/mongodb"
+)
+
+func TestConvertCondition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		source  string
+		want    mongodb.Filter
+		wantErr bool
+	}{
+		{
+			name:   "Equals",
+			source: "a = 1",
+			want:   mongodb.Filter{"a": mongodb.Filter{"$eq": int64(1)}},
+		},
+		{
+			name:   "IsNull",
+			source: "a IS NULL",
+			want:   mongodb.Filter{"a": mongodb.Filter{"$eq": nil}},
+		},
+		{
+			name:   "Compound",
+			source: "a > 1 AND b <= 'x' AND (c IN ARRAY(1, 2) OR d NOT IN ARRAY(true) OR 3 IN e)",
+			want: mongodb.Filter{
+				"$and": []any{
+					mongodb.Filter{"a": mongodb.Filter{"$gt": int64(1)}},
+					mongodb.Filter{"b": mongodb.Filter{"$lte": "x"}},
+					mongodb.Filter{
+						"$or": []any{
+							mongodb.Filter{"c": mongodb.Filter{"$in": []any{int64(1), int64(2)}}},
+							mongodb.Filter{"d": mongodb.Filter{"$nin": []any{true}}},
+							mongodb.Filter{"e": mongodb.Filter{"$eq": int64(3)}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "Ancestor",
+			source:  "__key__ HAS ANCESTOR KEY(Parent, 1)",
+			wantErr: true,
+		},
+		{
+			name:    "Key",
+			source:  "parent = KEY(Parent, 1)",
+			wantErr: true,
+		},
+		{
+			name:    "Unbound",
+			source:  "a = @1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cond, err := gqlparser.ParseCondition(gqlparser.NewLexer(tt.source))
+			if err != nil {
+				t.Fatalf("ParseCondition() error = %v", err)
+			}
+
+			got, err := mongodb.ConvertCondition(cond)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ConvertCondition() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !errors.Is(err, mongodb.ErrUnsupported) {
+					t.Errorf("ConvertCondition() error = %v, want ErrUnsupported", err)
+				}
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ConvertCondition() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
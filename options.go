@@ -0,0 +1,98 @@
+package gqlparser
+
+// ParseOption configures the optional behaviors of the parser.
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	templatePlaceholders bool
+	strict               bool
+	maxNestingDepth      int
+	warningHandler       func(Warning)
+	hooks                *ParseHooks
+	tracer               Tracer
+	redactSource         bool
+	cursorLiterals       bool
+	projectionSpans      bool
+	dedupeProjections    bool
+	dialect              *Dialect
+	pool                 *Pool
+	lexerOptions         []LexerOption
+}
+
+func newParseOptions(opts []ParseOption) *parseOptions {
+	o := &parseOptions{maxNestingDepth: DefaultMaxNestingDepth}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithTemplatePlaceholders permits the binding sites as the kind and the property names.
+// e.g. SELECT @prop FROM @kind ORDER BY @order
+// The placeholders are kept as Query.KindBinding and Query.PropertyBindings and resolved by Query.BindTemplate.
+func WithTemplatePlaceholders() ParseOption {
+	return func(o *parseOptions) {
+		o.templatePlaceholders = true
+	}
+}
+
+// WithStrictMode rejects the trailing semicolon of the statement, the trailing commas of the arrays,
+// the aliases of the projected properties, the duplicated projections, LIMIT following OFFSET and the projections in the aggregated queries.
+func WithStrictMode() ParseOption {
+	return func(o *parseOptions) {
+		o.strict = true
+	}
+}
+
+// WithMaxNestingDepth limits the nesting depth of the parentheses, the operators and the arrays in the conditions.
+// The limit is disabled if the depth is zero or negative. The default is DefaultMaxNestingDepth.
+func WithMaxNestingDepth(depth int) ParseOption {
+	return func(o *parseOptions) {
+		o.maxNestingDepth = depth
+	}
+}
+
+// WithCursorLiterals permits the cursor literals in LIMIT and OFFSET. e.g. LIMIT FIRST(10, CURSOR('Cg0SB2tleS0xMDAY')) OFFSET CURSOR('...') + 5
+// The literals are kept as Cursor in Limit.Cursor and Offset.Cursor. CURSOR isn't reserved by this option.
+func WithCursorLiterals() ParseOption {
+	return func(o *parseOptions) {
+		o.cursorLiterals = true
+	}
+}
+
+// WithProjectionSpans records the spans of the projected properties in the source as Query.ProjectionSpans.
+// They are reported by Query.Projection for the tools. e.g. the editors
+func WithProjectionSpans() ParseOption {
+	return func(o *parseOptions) {
+		o.projectionSpans = true
+	}
+}
+
+// WithDedupeProjections removes the duplicated projected properties rejected by Datastore. e.g. SELECT a, a FROM Kind
+// The duplicates are warned as DuplicateProjectionWarning regardless of this option, and rejected in strict mode without it.
+func WithDedupeProjections() ParseOption {
+	return func(o *parseOptions) {
+		o.dedupeProjections = true
+	}
+}
+
+// WithDialect enables the syntax extensions of the dialect. e.g. WithGroupBy
+// The keyword aliases of the dialect are applied by the lexer created by NewDialectLexer, not by this option.
+func WithDialect(dialect *Dialect) ParseOption {
+	return func(o *parseOptions) {
+		o.dialect = dialect
+	}
+}
+
+// WithLexerOptions configures the Lexers created from the sources by ReparseRange and DocumentSymbols.
+// e.g. WithLexerOptions(WithLenientCharacters()) to reparse the source lexed with the option
+func WithLexerOptions(opts ...LexerOption) ParseOption {
+	return func(o *parseOptions) {
+		o.lexerOptions = append(o.lexerOptions, opts...)
+	}
+}
+
+// newLexer creates the Lexer of the source with the dialect and the lexer options.
+func (o *parseOptions) newLexer(source string) *Lexer {
+	return NewDialectLexer(source, o.dialect, o.lexerOptions...)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -12,39 +13,64 @@ var (
 	ErrUnexpectedToken = errors.New("unexpected token")
 )
 
-func ParseQueryOrAggregationQuery(ts TokenSource) (*Query, *AggregationQuery, error) {
+// parseState is the state of the parse shared by the static acceptors of the grammar.
+// The grammar is built once for all parses, so the acceptors keep the results and the operands of the clauses here
+// instead of capturing them. It's carried by the token reader, and the acceptors get it by stateOf.
+type parseState struct {
+	opts *parseOptions
+	// query is the query being parsed. It's the query of the aggregation for the aggregation queries.
+	query       *Query
+	aggregation *AggregationQuery
+	// condition is the result of ParseCondition.
+	condition Condition
+	// key is the key literal being parsed, and keyPath is the element of its path being parsed.
+	key     *Key
+	keyPath *KeyPath
+	// tokens are the tokens of the clauses of the query. They're reset for the aggregated query.
+	tokens queryTokens
+	// keyword is the last keyword of the extensions to report it in strict mode. e.g. AS, GROUP, HAVING
+	keyword Token
+
+	// alias, property and upTo are the operands of the aggregation being accepted.
+	alias    string
+	property string
+	upTo     int64
+
+	// position and cursor are the fields of the LIMIT or OFFSET being accepted.
+	position *int64
+	cursor   *BindingVariable
+	// wantNextCursor reports that the first argument of LIMIT FIRST(...) is the position.
+	wantNextCursor bool
+
+	reader  resettableTokenReader
+	history tokenHistory
+	// buffer backs the history not to grow it for the short queries.
+	buffer [16]Token
+}
+
+// newParseState returns the state of the parse and the root reader of the tokens carrying it.
+func newParseState(ts TokenSource, opts *parseOptions) (*parseState, tokenReader) {
+	st := &parseState{opts: opts}
+	st.history.tokens = st.buffer[:0]
+	st.reader = resettableTokenReader{source: ts, history: &st.history, state: st}
+	return st, &st.reader
+}
+
+func ParseQueryOrAggregationQuery(ts TokenSource, opts ...ParseOption) (_ *Query, _ *AggregationQuery, err error) {
 	var query AggregationQuery
-	acceptor := tokenAcceptors{
-		skipWhitespaceToken,
-		&conditionalTokenAcceptor{
-			ifAccept: advanceAcceptor(acceptKeyword("AGGREGATE")),
-			andThen:  acceptAggregationQuery(&query),
-			orElse: &conditionalTokenAcceptor{
-				ifAccept: acceptKeyword("SELECT"),
-				andThen: tokenAcceptors{
-					acceptWhitespaceToken,
-					&conditionalTokenAcceptor{
-						ifAccept: advanceAcceptor(acceptKeyword("COUNT", "COUNT_UP_TO", "SUM", "AVG")),
-						andThen:  acceptSelectAggregationQueryBody(&query),
-						orElse:   acceptSelectQueryBody(&query.Query),
-					},
-				},
-				orElse: tokenAcceptorFn(func(tr tokenReader) error {
-					token, err := tr.Read()
-					if err != nil {
-						return err
-					}
-					return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
-				}),
-			},
-		},
+	o := newParseOptions(opts)
+	ts, end := o.startParse("ParseQueryOrAggregationQuery", ts)
+	defer func() { end(err) }()
+	ts, redact := o.startRedaction(ts)
+	defer func() { err = redact(err) }()
+	tracker := &eofTrackingTokenSource{TokenSource: ts}
+	st, tr := newParseState(tracker, o)
+	st.query, st.aggregation = &query.Query, &query
+	if err := acceptQueryOrAggregationQuery.accept(tr); err != nil {
+		return nil, nil, &ParseError{Partial: partialQueryOrAggregationQuery(&query), Err: tracker.wrapError(err)}
 	}
-	if err := acceptor.accept(ts); err != nil {
-		return nil, nil, err
-	}
-	if ts.Next() {
-		tok, _ := ts.Read()
-		return nil, nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, tok.GetContent(), tok.GetPosition())
+	if err := acceptEndOfQuery(tr); err != nil {
+		return nil, nil, &ParseError{Partial: partialQueryOrAggregationQuery(&query), Err: tracker.wrapError(err)}
 	}
 
 	if len(query.Aggregations) == 0 {
@@ -53,879 +79,1375 @@ func ParseQueryOrAggregationQuery(ts TokenSource) (*Query, *AggregationQuery, er
 	return nil, &query, nil
 }
 
-func ParseAggregationQuery(ts TokenSource) (*AggregationQuery, error) {
-	var query AggregationQuery
-	acceptor := acceptAggregationQuery(&query)
-	if err := acceptor.accept(ts); err != nil {
-		return nil, err
+var acceptQueryOrAggregationQuery = tokenAcceptors{
+	skipWhitespaceToken,
+	&conditionalTokenAcceptor{
+		ifAccept: advanceAcceptor(acceptKeyword("AGGREGATE")),
+		andThen:  acceptAggregationQuery,
+		orElse: &conditionalTokenAcceptor{
+			ifAccept: acceptKeyword("SELECT"),
+			andThen: tokenAcceptors{
+				acceptQueryHints,
+				&conditionalTokenAcceptor{
+					ifAccept: advanceAcceptor(acceptKeyword("COUNT", "COUNT_UP_TO", "SUM", "AVG")),
+					andThen:  acceptSelectAggregationQueryBody,
+					orElse:   acceptSelectQueryBody,
+				},
+			},
+			orElse: rejectToken,
+		},
+	},
+}
+
+// rejectToken reports the next token as the unexpected one. It's the last alternative of the conditionals.
+var rejectToken tokenAcceptorFn = func(tr tokenReader) error {
+	token, err := tr.Read()
+	if err != nil {
+		return err
 	}
-	if ts.Next() {
-		tok, _ := ts.Read()
-		return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, tok.GetContent(), tok.GetPosition())
+	return &SyntaxError{Token: token}
+}
+
+func ParseAggregationQuery(ts TokenSource, opts ...ParseOption) (_ *AggregationQuery, err error) {
+	o := newParseOptions(opts)
+	query := o.pool.newAggregationQuery()
+	ts, end := o.startParse("ParseAggregationQuery", ts)
+	defer func() { end(err) }()
+	ts, redact := o.startRedaction(ts)
+	defer func() { err = redact(err) }()
+	tracker := &eofTrackingTokenSource{TokenSource: ts}
+	st, tr := newParseState(tracker, o)
+	st.query, st.aggregation = &query.Query, query
+	if err := acceptAggregationQuery.accept(tr); err != nil {
+		return nil, &ParseError{Partial: query, Err: tracker.wrapError(err)}
 	}
-	return &query, nil
+	if err := acceptEndOfQuery(tr); err != nil {
+		return nil, &ParseError{Partial: query, Err: tracker.wrapError(err)}
+	}
+	return query, nil
 }
 
-func acceptAggregationQuery(query *AggregationQuery) tokenAcceptor {
-	return tokenAcceptors{
-		skipWhitespaceToken,
-		&conditionalTokenAcceptor{
-			ifAccept: acceptKeyword("SELECT"),
+// acceptEndOfQuery accepts the trailing whitespaces and the optional semicolon, and then requires the end of tokens.
+func acceptEndOfQuery(tr tokenReader) error {
+	if err := skipWhitespaceToken.accept(tr); err != nil {
+		return err
+	}
+	if !stateOf(tr).opts.strict {
+		if err := acceptTrailingSemicolon.accept(tr); err != nil {
+			return err
+		}
+	}
+	if tr.Next() {
+		tok, err := tr.Read()
+		if err != nil {
+			return err
+		}
+		return &SyntaxError{Token: tok, Expected: trailingKeywords}
+	}
+	return nil
+}
+
+var acceptTrailingSemicolon = &conditionalTokenAcceptor{
+	ifAccept: acceptEitherToken(
+		func(st *parseState, token *OperatorToken) error {
+			if token.Type != ";" {
+				return &SyntaxError{Token: token, Reason: `expect to be ";"`}
+			}
+			st.opts.warn(TrailingSemicolonWarning, token)
+			return nil
+		},
+		func(st *parseState, token *SemicolonToken) error {
+			st.opts.warn(TrailingSemicolonWarning, token)
+			return nil
+		},
+	),
+	andThen: skipWhitespaceToken,
+	orElse:  nopAcceptor,
+}
+
+// trailingKeywords are the keywords of the optional clauses and the directions that may follow the query body.
+// They're expected at the trailing token to suggest the misspelled ones. e.g. LIMT 10, ODRER BY a, ORDER BY a ACS
+var trailingKeywords = []string{"WHERE", "ORDER", "LIMIT", "OFFSET", "ASC", "DESC"}
+
+var acceptAggregationQuery = tokenAcceptors{
+	skipWhitespaceToken,
+	&conditionalTokenAcceptor{
+		ifAccept: acceptKeyword("SELECT"),
+		andThen: tokenAcceptors{
+			acceptQueryHints,
+			acceptSelectAggregationQueryBody,
+		},
+		orElse: &conditionalTokenAcceptor{
+			ifAccept: acceptKeyword("AGGREGATE"),
 			andThen: tokenAcceptors{
+				acceptQueryHints,
+				&namedTokenAcceptor{name: "AGGREGATE", acceptor: acceptAggregations},
 				acceptWhitespaceToken,
-				acceptSelectAggregationQueryBody(query),
-			},
-			orElse: &conditionalTokenAcceptor{
-				ifAccept: acceptKeyword("AGGREGATE"),
-				andThen: tokenAcceptors{
-					acceptWhitespaceToken,
-					acceptAggregations(&query.Aggregations),
-					acceptWhitespaceToken,
-					acceptKeyword("OVER"),
-					skipWhitespaceToken,
-					acceptOperator("("),
-					acceptQuery(&query.Query),
-					acceptOperator(")"),
-					skipWhitespaceToken,
-				},
-				orElse: tokenAcceptorFn(func(tr tokenReader) error {
-					token, err := tr.Read()
-					if err != nil {
-						return err
-					}
-					return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
-				}),
+				acceptKeyword("OVER"),
+				skipWhitespaceToken,
+				acceptOperator("("),
+				acceptAggregatedQuery,
+				acceptOperator(")"),
+				acceptHaving,
+				skipWhitespaceToken,
+				rejectOuterClause,
 			},
+			orElse: rejectToken,
 		},
+	},
+}
+
+// acceptAggregatedQuery accepts the nested query of AGGREGATE ... OVER and checks the clauses under the aggregation like the server.
+// ORDER BY without LIMIT doesn't change the aggregated entities, so it's warned.
+// The projections except __key__ and DISTINCT are rejected by the server, so they're rejected in strict mode and warned otherwise.
+var acceptAggregatedQuery tokenAcceptorFn = func(tr tokenReader) error {
+	st := stateOf(tr)
+	st.tokens = queryTokens{}
+	if err := acceptQuery.accept(tr); err != nil {
+		return err
+	}
+
+	// the first projected property or DISTINCT
+	query, tokens := st.query, &st.tokens
+	var projection Token
+	if tokens.distinct != nil {
+		projection = tokens.distinct
+	} else if len(tokens.properties) != 0 {
+		projection = tokens.properties[0]
+	}
+	if projection != nil && (query.Distinct || len(query.DistinctOn) != 0 || (len(query.Properties) != 0 && !query.KeysOnly)) {
+		if st.opts.strict {
+			return &SyntaxError{Token: projection, Reason: "projection is not allowed in the aggregated query in strict mode"}
+		}
+		st.opts.warn(AggregatedProjectionWarning, projection)
+	}
+	if tokens.orderBy != nil && query.Limit == nil {
+		st.opts.warn(AggregatedOrderByWarning, tokens.orderBy)
+	}
+	return nil
+}
+
+// rejectOuterClause reports the clauses of the aggregated query following OVER (...) with the reason instead of the unexpected token.
+// e.g. AGGREGATE COUNT(*) OVER (SELECT * FROM Kind) LIMIT 10
+var rejectOuterClause tokenAcceptorFn = func(tr tokenReader) error {
+	token, err := peekToken(tr)
+	if errors.Is(err, ErrEndOfToken) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if keyword, ok := token.(*KeywordToken); ok {
+		switch keyword.Name {
+		case "WHERE", "ORDER", "LIMIT", "OFFSET":
+			return &SyntaxError{Token: keyword, Reason: fmt.Sprintf("%s must be inside OVER (...) of the aggregation query", clauseKeywords[keyword.Name])}
+		}
 	}
+	return nil
+}
+
+var acceptSelectAggregationQueryBody = tokenAcceptors{
+	&namedTokenAcceptor{name: "SELECT", acceptor: acceptAggregations},
+	acceptWhitespaceToken,
+	acceptKeyword("FROM"),
+	acceptWhitespaceToken,
+	&namedTokenAcceptor{name: "FROM", acceptor: acceptKind},
+	acceptWhere,
+	acceptGroupBy,
+	acceptHaving,
+	skipWhitespaceToken,
+	rejectDuplicateClause,
 }
 
-func acceptSelectAggregationQueryBody(query *AggregationQuery) tokenAcceptor {
-	return tokenAcceptors{
-		acceptAggregations(&query.Aggregations),
+// acceptWhere accepts the optional WHERE clause of the query.
+var acceptWhere = &conditionalTokenAcceptor{
+	name: "WHERE",
+	ifAccept: tokenAcceptors{
 		acceptWhitespaceToken,
-		acceptKeyword("FROM"),
+		acceptKeyword("WHERE"),
+	},
+	andThen: tokenAcceptors{
 		acceptWhitespaceToken,
-		acceptEitherToken(
-			func(tok *SymbolToken) error {
-				query.Kind = Kind(tok.Content)
-				return nil
-			},
-			func(tok *StringToken) error {
-				if tok.Quote != '`' {
-					return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, tok.Content, tok.Position)
-				}
-				query.Kind = Kind(tok.Content)
-				return nil
-			},
-		),
-		&conditionalTokenAcceptor{
-			ifAccept: tokenAcceptors{
-				acceptWhitespaceToken,
-				acceptKeyword("WHERE"),
-			},
-			andThen: tokenAcceptors{
-				acceptWhitespaceToken,
-				acceptCondition(&query.Where),
-			},
-			orElse: nopAcceptor,
+		acceptCondition(func(st *parseState) *Condition { return &st.query.Where }),
+	},
+	orElse: nopAcceptor,
+}
+
+// acceptHaving accepts the optional HAVING clause if the dialect permits it.
+var acceptHaving = &optionTokenAcceptor{
+	enabled: func(opts *parseOptions) bool { return opts.dialect != nil && opts.dialect.having },
+	ifEnabled: &conditionalTokenAcceptor{
+		name: "HAVING",
+		ifAccept: tokenAcceptors{
+			skipWhitespaceToken,
+			acceptSymbolKeyword("HAVING"),
 		},
-	}
+		andThen: tokenAcceptors{
+			rejectInStrictMode("HAVING is not allowed in strict mode"),
+			acceptWhitespaceToken,
+			acceptCondition(func(st *parseState) *Condition { return &st.aggregation.Having }),
+		},
+		orElse: nopAcceptor,
+	},
+	orElse: nopAcceptor,
 }
 
-func acceptAggregations(aggregations *[]Aggregation) tokenAcceptor {
-	var upTo int64
-	var alias string
-	var prop string
-	return &conditionalTokenAcceptor{
-		ifAccept: acceptKeyword("COUNT"),
+// acceptAggregations accepts the comma separated aggregations. e.g. COUNT(*) AS total, SUM(price)
+var acceptAggregations = acceptSeparated(
+	tokenAcceptors{
+		skipWhitespaceToken,
+		acceptOperator(","),
+		skipWhitespaceToken,
+	},
+	func(tr tokenReader, _ int) (bool, error) {
+		stateOf(tr).alias = ""
+		return false, acceptAggregation.accept(tr)
+	},
+)
+
+var acceptAggregation = &conditionalTokenAcceptor{
+	ifAccept: acceptKeyword("COUNT"),
+	andThen: tokenAcceptors{
+		skipWhitespaceToken,
+		acceptOperator("("),
+		skipWhitespaceToken,
+		acceptWildcardToken,
+		skipWhitespaceToken,
+		acceptOperator(")"),
+		acceptAggregationAlias,
+		appendAggregation(func(st *parseState) Aggregation {
+			return &CountAggregation{Alias: st.alias}
+		}),
+	},
+	orElse: &conditionalTokenAcceptor{
+		ifAccept: acceptKeyword("COUNT_UP_TO"),
 		andThen: tokenAcceptors{
 			skipWhitespaceToken,
 			acceptOperator("("),
 			skipWhitespaceToken,
-			acceptWildcardToken,
+			acceptSingleToken(func(st *parseState, token *NumericToken) error {
+				if token.Floating {
+					return &SyntaxError{Token: token}
+				}
+				st.upTo = token.Int64
+				return nil
+			}),
 			skipWhitespaceToken,
 			acceptOperator(")"),
-			&conditionalTokenAcceptor{
-				ifAccept: tokenAcceptors{
-					acceptWhitespaceToken,
-					acceptKeyword("AS"),
-				},
-				andThen: tokenAcceptors{
-					acceptWhitespaceToken,
-					acceptEitherToken(
-						func(token *SymbolToken) error {
-							alias = token.Content
-							return nil
-						},
-						func(token *StringToken) error {
-							if token.Quote != '`' {
-								return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
-							}
-							alias = token.Content
-							return nil
-						},
-					),
-				},
-				orElse: nopAcceptor,
-			},
-			&conditionalTokenAcceptor{
-				ifAccept: tokenAcceptors{
-					skipWhitespaceToken,
-					acceptOperator(","),
-				},
-				andThen: tokenAcceptors{
-					skipWhitespaceToken,
-					deferAcceptor(func() tokenAcceptor {
-						*aggregations = append(*aggregations, &CountAggregation{Alias: alias})
-						return acceptAggregations(aggregations)
-					}),
-				},
-				orElse: deferAcceptor(func() tokenAcceptor {
-					*aggregations = append(*aggregations, &CountAggregation{Alias: alias})
-					return nopAcceptor
-				}),
-			},
+			acceptAggregationAlias,
+			appendAggregation(func(st *parseState) Aggregation {
+				return &CountUpToAggregation{Alias: st.alias, Limit: st.upTo}
+			}),
 		},
 		orElse: &conditionalTokenAcceptor{
-			ifAccept: acceptKeyword("COUNT_UP_TO"),
+			ifAccept: acceptKeyword("SUM"),
 			andThen: tokenAcceptors{
 				skipWhitespaceToken,
 				acceptOperator("("),
 				skipWhitespaceToken,
-				acceptSingleToken(func(token *NumericToken) error {
-					if token.Floating {
-						return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
-					}
-					upTo = token.Int64
-					return nil
-				}),
+				acceptAggregatedProperty,
 				skipWhitespaceToken,
 				acceptOperator(")"),
-				&conditionalTokenAcceptor{
-					ifAccept: tokenAcceptors{
-						acceptWhitespaceToken,
-						acceptKeyword("AS"),
-					},
-					andThen: tokenAcceptors{
-						acceptWhitespaceToken,
-						acceptEitherToken(
-							func(token *SymbolToken) error {
-								alias = token.Content
-								return nil
-							},
-							func(token *StringToken) error {
-								if token.Quote != '`' {
-									return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
-								}
-								alias = token.Content
-								return nil
-							},
-						),
-					},
-					orElse: nopAcceptor,
-				},
-				&conditionalTokenAcceptor{
-					ifAccept: tokenAcceptors{
-						skipWhitespaceToken,
-						acceptOperator(","),
-					},
-					andThen: tokenAcceptors{
-						skipWhitespaceToken,
-						deferAcceptor(func() tokenAcceptor {
-							*aggregations = append(*aggregations, &CountUpToAggregation{Alias: alias, Limit: upTo})
-							return acceptAggregations(aggregations)
-						}),
-					},
-					orElse: deferAcceptor(func() tokenAcceptor {
-						*aggregations = append(*aggregations, &CountUpToAggregation{Alias: alias, Limit: upTo})
-						return nopAcceptor
-					}),
-				},
+				acceptAggregationAlias,
+				appendAggregation(func(st *parseState) Aggregation {
+					return &SumAggregation{Alias: st.alias, Property: st.property}
+				}),
 			},
 			orElse: &conditionalTokenAcceptor{
-				ifAccept: acceptKeyword("SUM"),
+				ifAccept: acceptKeyword("AVG"),
 				andThen: tokenAcceptors{
 					skipWhitespaceToken,
 					acceptOperator("("),
 					skipWhitespaceToken,
-					acceptEitherToken(
-						func(token *SymbolToken) error {
-							prop = token.Content
-							return nil
-						},
-						func(token *StringToken) error {
-							if token.Quote != '`' {
-								return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
-							}
-							prop = token.Content
-							return nil
-						},
-					),
+					acceptAggregatedProperty,
 					skipWhitespaceToken,
 					acceptOperator(")"),
-					&conditionalTokenAcceptor{
-						ifAccept: tokenAcceptors{
-							acceptWhitespaceToken,
-							acceptKeyword("AS"),
-						},
-						andThen: tokenAcceptors{
-							acceptWhitespaceToken,
-							acceptEitherToken(
-								func(token *SymbolToken) error {
-									alias = token.Content
-									return nil
-								},
-								func(token *StringToken) error {
-									if token.Quote != '`' {
-										return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
-									}
-									alias = token.Content
-									return nil
-								},
-							),
-						},
-						orElse: nopAcceptor,
-					},
-					&conditionalTokenAcceptor{
-						ifAccept: tokenAcceptors{
-							skipWhitespaceToken,
-							acceptOperator(","),
-						},
-						andThen: tokenAcceptors{
-							skipWhitespaceToken,
-							deferAcceptor(func() tokenAcceptor {
-								*aggregations = append(*aggregations, &SumAggregation{Alias: alias, Property: prop})
-								return acceptAggregations(aggregations)
-							}),
-						},
-						orElse: deferAcceptor(func() tokenAcceptor {
-							*aggregations = append(*aggregations, &SumAggregation{Alias: alias, Property: prop})
-							return nopAcceptor
-						}),
-					},
-				},
-				orElse: &conditionalTokenAcceptor{
-					ifAccept: acceptKeyword("AVG"),
-					andThen: tokenAcceptors{
-						skipWhitespaceToken,
-						acceptOperator("("),
-						skipWhitespaceToken,
-						acceptEitherToken(
-							func(token *SymbolToken) error {
-								prop = token.Content
-								return nil
-							},
-							func(token *StringToken) error {
-								if token.Quote != '`' {
-									return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
-								}
-								prop = token.Content
-								return nil
-							},
-						),
-						skipWhitespaceToken,
-						acceptOperator(")"),
-						&conditionalTokenAcceptor{
-							ifAccept: tokenAcceptors{
-								acceptWhitespaceToken,
-								acceptKeyword("AS"),
-							},
-							andThen: tokenAcceptors{
-								acceptWhitespaceToken,
-								acceptEitherToken(
-									func(token *SymbolToken) error {
-										alias = token.Content
-										return nil
-									},
-									func(token *StringToken) error {
-										if token.Quote != '`' {
-											return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
-										}
-										alias = token.Content
-										return nil
-									},
-								),
-							},
-							orElse: nopAcceptor,
-						},
-						&conditionalTokenAcceptor{
-							ifAccept: tokenAcceptors{
-								skipWhitespaceToken,
-								acceptOperator(","),
-							},
-							andThen: tokenAcceptors{
-								skipWhitespaceToken,
-								deferAcceptor(func() tokenAcceptor {
-									*aggregations = append(*aggregations, &AvgAggregation{Alias: alias, Property: prop})
-									return acceptAggregations(aggregations)
-								}),
-							},
-							orElse: deferAcceptor(func() tokenAcceptor {
-								*aggregations = append(*aggregations, &AvgAggregation{Alias: alias, Property: prop})
-								return nopAcceptor
-							}),
-						},
-					},
-					orElse: tokenAcceptorFn(func(tr tokenReader) error {
-						token, err := tr.Read()
-						if err != nil {
-							return err
-						}
-						return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
+					acceptAggregationAlias,
+					appendAggregation(func(st *parseState) Aggregation {
+						return &AvgAggregation{Alias: st.alias, Property: st.property}
 					}),
 				},
+				orElse: rejectToken,
 			},
 		},
-	}
-}
-
-func ParseQuery(ts TokenSource) (*Query, error) {
-	var query Query
-	acceptor := acceptQuery(&query)
-	if err := acceptor.accept(ts); err != nil {
-		return nil, err
-	}
-	if ts.Next() {
-		tok, _ := ts.Read()
-		return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, tok.GetContent(), tok.GetPosition())
-	}
-	return &query, nil
+	},
 }
 
-func acceptQuery(query *Query) tokenAcceptor {
-	return tokenAcceptors{
-		skipWhitespaceToken,
-		acceptKeyword("SELECT"),
-		acceptWhitespaceToken,
-		acceptSelectQueryBody(query),
-	}
-}
+// acceptAggregatedProperty accepts the property of SUM and AVG.
+var acceptAggregatedProperty = acceptEitherToken(
+	func(st *parseState, token *SymbolToken) error {
+		st.property = token.Content
+		return nil
+	},
+	func(st *parseState, token *StringToken) error {
+		if token.Quote != '`' {
+			return &SyntaxError{Token: token}
+		}
+		st.property = token.Content
+		return nil
+	},
+)
 
-func acceptSelectQueryBody(query *Query) tokenAcceptor {
-	return tokenAcceptors{
-		&conditionalTokenAcceptor{
-			ifAccept: acceptKeyword("DISTINCT"),
-			andThen:  acceptDistinctBody(query),
-			orElse:   nopAcceptor,
-		},
-		acceptProperties(&query.Properties, true),
+// acceptAggregationAlias accepts the optional alias of the aggregation. e.g. COUNT(*) AS total
+var acceptAggregationAlias = &conditionalTokenAcceptor{
+	ifAccept: tokenAcceptors{
 		acceptWhitespaceToken,
-		acceptKeyword("FROM"),
+		acceptKeyword("AS"),
+	},
+	andThen: tokenAcceptors{
 		acceptWhitespaceToken,
 		acceptEitherToken(
-			func(tok *SymbolToken) error {
-				query.Kind = Kind(tok.Content)
+			func(st *parseState, token *SymbolToken) error {
+				st.alias = token.Content
 				return nil
 			},
-			func(tok *StringToken) error {
-				if tok.Quote != '`' {
-					return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, tok.Content, tok.Position)
+			func(st *parseState, token *StringToken) error {
+				if token.Quote != '`' {
+					return &SyntaxError{Token: token}
 				}
-				query.Kind = Kind(tok.Content)
+				st.alias = token.Content
 				return nil
 			},
 		),
-		&conditionalTokenAcceptor{
-			ifAccept: tokenAcceptors{
-				acceptWhitespaceToken,
-				acceptKeyword("WHERE"),
-			},
-			andThen: tokenAcceptors{
-				acceptWhitespaceToken,
-				acceptCondition(&query.Where),
-			},
-			orElse: nopAcceptor,
-		},
-		&conditionalTokenAcceptor{
-			ifAccept: tokenAcceptors{
-				acceptWhitespaceToken,
-				acceptKeyword("ORDER"),
-				acceptWhitespaceToken,
-				acceptKeyword("BY"),
-			},
-			andThen: tokenAcceptors{
-				acceptWhitespaceToken,
-				acceptOrderByBody(&query.OrderBy),
-			},
-			orElse: nopAcceptor,
-		},
-		&conditionalTokenAcceptor{
-			ifAccept: tokenAcceptors{
-				acceptWhitespaceToken,
-				acceptKeyword("LIMIT"),
-			},
-			andThen: tokenAcceptors{
-				acceptWhitespaceToken,
-				deferAcceptor(func() tokenAcceptor {
-					query.Limit = new(Limit)
-					return acceptLimitBody(query.Limit)
-				}),
-			},
-			orElse: nopAcceptor,
-		},
-		&conditionalTokenAcceptor{
-			ifAccept: tokenAcceptors{
-				acceptWhitespaceToken,
-				acceptKeyword("OFFSET"),
-			},
-			andThen: tokenAcceptors{
-				acceptWhitespaceToken,
-				deferAcceptor(func() tokenAcceptor {
-					query.Offset = new(Offset)
-					return acceptOffsetBody(query.Offset)
-				}),
-			},
-			orElse: nopAcceptor,
-		},
-		skipWhitespaceToken,
-	}
+	},
+	orElse: nopAcceptor,
 }
 
-func acceptDistinctBody(query *Query) tokenAcceptor {
-	return tokenAcceptors{
-		acceptWhitespaceToken,
-		&conditionalTokenAcceptor{
-			ifAccept: acceptKeyword("ON"),
-			andThen: tokenAcceptors{
-				acceptWhitespaceToken,
-				acceptOperator("("),
-				skipWhitespaceToken,
-				acceptProperties(&query.DistinctOn, false),
-				skipWhitespaceToken,
-				acceptOperator(")"),
-				skipWhitespaceToken,
-			},
-			orElse: tokenAcceptors{
-				notAcceptor(acceptWildcardToken),
-				deferAcceptor(func() tokenAcceptor {
-					query.Distinct = true
-					return nopAcceptor
-				}),
-			},
-		},
-	}
+// appendAggregation appends the aggregation built from the operands in the state.
+func appendAggregation(build func(*parseState) Aggregation) tokenAcceptor {
+	return tokenAcceptorFn(func(tr tokenReader) error {
+		st := stateOf(tr)
+		st.aggregation.Aggregations = append(st.aggregation.Aggregations, build(st))
+		return nil
+	})
 }
 
-func acceptProperties(props *[]Property, wildcard bool) tokenAcceptor {
-	if wildcard {
-		return tokenAcceptors{
-			acceptTokenFromAny3(
-				func(*WildcardToken) error {
-					*props = nil
-					return nil
-				},
-				func(tok *SymbolToken) error {
-					*props = append(*props, Property(tok.Content))
-					return nil
-				},
-				func(tok *StringToken) error {
-					if tok.Quote != '`' {
-						return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, tok.Content, tok.Position)
-					}
-					*props = append(*props, Property(tok.Content))
-					return nil
-				},
-			),
-			&conditionalTokenAcceptor{
-				ifAccept: tokenAcceptors{
-					skipWhitespaceToken,
-					acceptOperator(","),
-					skipWhitespaceToken,
-				},
-				andThen: deferAcceptor(func() tokenAcceptor {
-					return acceptProperties(props, false)
-				}),
-				orElse: nopAcceptor,
-			},
-		}
-	} else {
-		return tokenAcceptors{
-			acceptEitherToken(
-				func(tok *SymbolToken) error {
-					*props = append(*props, Property(tok.Content))
-					return nil
-				},
-				func(tok *StringToken) error {
-					if tok.Quote != '`' {
-						return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, tok.Content, tok.Position)
-					}
-					*props = append(*props, Property(tok.Content))
-					return nil
-				},
-			),
-			&conditionalTokenAcceptor{
-				ifAccept: tokenAcceptors{
-					skipWhitespaceToken,
-					acceptOperator(","),
-					skipWhitespaceToken,
-				},
-				andThen: deferAcceptor(func() tokenAcceptor {
-					return acceptProperties(props, false)
-				}),
-				orElse: nopAcceptor,
-			},
-		}
+func ParseQuery(ts TokenSource, opts ...ParseOption) (_ *Query, err error) {
+	o := newParseOptions(opts)
+	query := o.pool.newQuery()
+	ts, end := o.startParse("ParseQuery", ts)
+	defer func() { end(err) }()
+	ts, redact := o.startRedaction(ts)
+	defer func() { err = redact(err) }()
+	tracker := &eofTrackingTokenSource{TokenSource: ts}
+	st, tr := newParseState(tracker, o)
+	st.query = query
+	if err := acceptQuery.accept(tr); err != nil {
+		return nil, &ParseError{Partial: query, Err: tracker.wrapError(err)}
 	}
+	if err := acceptEndOfQuery(tr); err != nil {
+		return nil, &ParseError{Partial: query, Err: tracker.wrapError(err)}
+	}
+	return query, nil
 }
 
-func ParseCondition(ts TokenSource) (Condition, error) {
-	var condition Condition
-	acceptor := acceptCondition(&condition)
-	if err := acceptor.accept(ts); err != nil {
-		return nil, err
-	}
-	if ts.Next() {
-		tok, _ := ts.Read()
-		return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, tok.GetContent(), tok.GetPosition())
-	}
-	return condition, nil
+// queryTokens records the tokens of the clauses accepted by acceptSelectQueryBody to report them after accepting the query.
+type queryTokens struct {
+	distinct *KeywordToken
+	// properties are the first tokens of the projected properties.
+	properties []Token
+	orderBy    *KeywordToken
 }
 
-func acceptCondition(cond *Condition) tokenAcceptor {
-	return tokenAcceptorFn(func(tr tokenReader) error {
-		ast, err := constructAST(tr, 0)
-		if err != nil {
-			return err
-		}
+var acceptQuery = tokenAcceptors{
+	skipWhitespaceToken,
+	acceptKeyword("SELECT"),
+	acceptQueryHints,
+	acceptSelectQueryBody,
+}
 
-		if c, err := ast.toCondition(); err != nil {
-			return err
-		} else {
-			*cond = c
-			return nil
+var acceptSelectQueryBody = tokenAcceptors{
+	&conditionalTokenAcceptor{
+		name: "DISTINCT",
+		ifAccept: acceptKeywordToken("DISTINCT", func(st *parseState, token *KeywordToken) {
+			st.tokens.distinct = token
+		}),
+		andThen: acceptDistinctBody,
+		orElse:  nopAcceptor,
+	},
+	&namedTokenAcceptor{
+		name: "SELECT",
+		acceptor: checkKeyProjection(checkDuplicateProjections(acceptProperties(
+			func(query *Query) *[]Property { return &query.Properties },
+			ProjectionPropertyBindingClause,
+			true,
+		))),
+	},
+	tokenAcceptorFn(func(tr tokenReader) error {
+		query := stateOf(tr).query
+		for query.Aliases != nil && len(query.Aliases) < len(query.Properties) {
+			query.Aliases = append(query.Aliases, "")
 		}
-	})
+		query.KeysOnly = len(query.Properties) == 1 && query.Properties[0] == keyProperty
+		return nil
+	}),
+	acceptWhitespaceToken,
+	acceptKeyword("FROM"),
+	acceptWhitespaceToken,
+	&namedTokenAcceptor{name: "FROM", acceptor: acceptKind},
+	acceptWhere,
+	acceptGroupBy,
+	&conditionalTokenAcceptor{
+		name: "ORDER BY",
+		ifAccept: tokenAcceptors{
+			acceptWhitespaceToken,
+			acceptKeywordToken("ORDER", func(st *parseState, token *KeywordToken) {
+				st.tokens.orderBy = token
+			}),
+			acceptWhitespaceToken,
+			acceptKeyword("BY"),
+		},
+		andThen: tokenAcceptors{
+			acceptWhitespaceToken,
+			acceptOrderByBody,
+		},
+		orElse: nopAcceptor,
+	},
+	&conditionalTokenAcceptor{
+		name: "LIMIT",
+		ifAccept: tokenAcceptors{
+			acceptWhitespaceToken,
+			acceptKeyword("LIMIT"),
+		},
+		andThen: acceptLimit,
+		orElse:  nopAcceptor,
+	},
+	&conditionalTokenAcceptor{
+		name: "OFFSET",
+		ifAccept: tokenAcceptors{
+			acceptWhitespaceToken,
+			acceptKeyword("OFFSET"),
+		},
+		andThen: tokenAcceptors{
+			acceptWhitespaceToken,
+			tokenAcceptorFn(func(tr tokenReader) error {
+				st := stateOf(tr)
+				st.query.Offset = new(Offset)
+				st.position, st.cursor = &st.query.Offset.Position, &st.query.Offset.Cursor
+				return nil
+			}),
+			acceptResultPosition,
+		},
+		orElse: nopAcceptor,
+	},
+	acceptLimitAfterOffset,
+	skipWhitespaceToken,
+	rejectDuplicateClause,
 }
 
-func ParseKey(ts TokenSource) (*Key, error) {
-	var key Key
-	acceptor := tokenAcceptors{
-		acceptKeyword("KEY"),
-		acceptKeyBody(&key),
+// rejectDuplicateClause reports the clause appearing again after the query body as DuplicateClauseError
+// instead of the unexpected token. The token is left unread.
+var rejectDuplicateClause tokenAcceptorFn = func(tr tokenReader) error {
+	token, err := peekToken(tr)
+	if errors.Is(err, ErrEndOfToken) {
+		return nil
+	} else if err != nil {
+		return err
 	}
-	if err := acceptor.accept(ts); err != nil {
-		return nil, err
+	keyword, ok := token.(*KeywordToken)
+	if !ok {
+		return nil
+	}
+
+	query := stateOf(tr).query
+	var duplicated bool
+	switch keyword.Name {
+	case "WHERE":
+		duplicated = query.Where != nil
+	case "ORDER":
+		duplicated = query.OrderBy != nil
+	case "LIMIT":
+		duplicated = query.Limit != nil
+	case "OFFSET":
+		duplicated = query.Offset != nil
 	}
-	if ts.Next() {
-		tok, _ := ts.Read()
-		return nil, fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, tok.GetContent(), tok.GetPosition())
+	if duplicated {
+		return &DuplicateClauseError{Clause: clauseKeywords[keyword.Name], Token: keyword}
 	}
-	return &key, nil
+	return nil
 }
 
-func acceptKeyBody(result *Key) tokenAcceptor {
-	return tokenAcceptors{
-		acceptOperator("("),
-		skipWhitespaceToken,
-		&conditionalTokenAcceptor{
-			ifAccept: acceptKeyword("PROJECT"),
-			andThen: tokenAcceptors{
-				acceptOperator("("),
-				skipWhitespaceToken,
-				acceptSingleToken(func(token *StringToken) error {
-					if token.Quote == '`' {
-						return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
-					}
-					result.ProjectID = ProjectID(token.Content)
-					return nil
-				}),
-				skipWhitespaceToken,
-				acceptOperator(")"),
-				skipWhitespaceToken,
-				acceptOperator(","),
-				skipWhitespaceToken,
-			},
-			orElse: nopAcceptor,
-		},
-		&conditionalTokenAcceptor{
-			ifAccept: acceptKeyword("NAMESPACE"),
-			andThen: tokenAcceptors{
-				acceptOperator("("),
-				skipWhitespaceToken,
-				acceptSingleToken(func(token *StringToken) error {
-					if token.Quote == '`' {
-						return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
-					}
-					result.Namespace = token.Content
-					return nil
-				}),
-				skipWhitespaceToken,
-				acceptOperator(")"),
-				skipWhitespaceToken,
-				acceptOperator(","),
-				skipWhitespaceToken,
-			},
-			orElse: nopAcceptor,
-		},
-		acceptKeyPath(&result.Path),
-		acceptOperator(")"),
+var acceptLimit = tokenAcceptors{
+	acceptWhitespaceToken,
+	tokenAcceptorFn(func(tr tokenReader) error {
+		st := stateOf(tr)
+		st.query.Limit = new(Limit)
+		st.position, st.cursor = &st.query.Limit.Position, &st.query.Limit.Cursor
+		st.wantNextCursor = false
+		return nil
+	}),
+	acceptLimitBody,
+}
+
+// acceptLimitAfterOffset accepts LIMIT following OFFSET. e.g. OFFSET 10 LIMIT 5
+// The official grammar requires LIMIT before OFFSET, so it's rejected in strict mode and warned otherwise.
+var acceptLimitAfterOffset tokenAcceptorFn = func(tr tokenReader) error {
+	if query := stateOf(tr).query; query.Offset == nil || query.Limit != nil {
+		return nil
 	}
+	return acceptLimitFollowingOffset.accept(tr)
 }
 
-func acceptKeyPath(keyPaths *[]*KeyPath) tokenAcceptor {
-	var keyPath KeyPath
-	return tokenAcceptors{
-		acceptSingleToken(func(token *SymbolToken) error {
-			keyPath.Kind = Kind(token.Content)
+var acceptLimitFollowingOffset = &conditionalTokenAcceptor{
+	name: "LIMIT",
+	ifAccept: tokenAcceptors{
+		acceptWhitespaceToken,
+		acceptSingleToken(func(st *parseState, token *KeywordToken) error {
+			if token.Name != "LIMIT" {
+				return &SyntaxError{Token: token, Reason: `expect to be "LIMIT"`}
+			}
+			st.keyword = token
 			return nil
 		}),
-		skipWhitespaceToken,
-		acceptOperator(","),
-		skipWhitespaceToken,
-		acceptEitherToken(
-			func(token *StringToken) error {
-				if token.Quote == '`' {
-					return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
-				}
-				keyPath.Name = token.Content
-				return nil
-			},
-			func(token *NumericToken) error {
-				if token.Floating {
-					return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
-				}
-				keyPath.ID = token.Int64
-				return nil
-			},
-		),
-		skipWhitespaceToken,
-		deferAcceptor(func() tokenAcceptor {
-			*keyPaths = append(*keyPaths, &keyPath)
-			return nopAcceptor
+	},
+	andThen: tokenAcceptors{
+		tokenAcceptorFn(func(tr tokenReader) error {
+			st := stateOf(tr)
+			if st.opts.strict {
+				return &SyntaxError{Token: st.keyword, Reason: "LIMIT after OFFSET is not allowed in strict mode"}
+			}
+			st.opts.warn(OffsetBeforeLimitWarning, st.keyword)
+			return nil
 		}),
-		&conditionalTokenAcceptor{
-			ifAccept: tokenAcceptors{
-				acceptOperator(","),
-				skipWhitespaceToken,
-			},
-			andThen: deferAcceptor(func() tokenAcceptor {
-				return acceptKeyPath(keyPaths)
+		acceptLimit,
+	},
+	orElse: nopAcceptor,
+}
+
+var acceptDistinctBody = tokenAcceptors{
+	acceptWhitespaceToken,
+	&conditionalTokenAcceptor{
+		ifAccept: acceptKeyword("ON"),
+		andThen: tokenAcceptors{
+			acceptWhitespaceToken,
+			acceptOperator("("),
+			skipWhitespaceToken,
+			acceptProperties(
+				func(query *Query) *[]Property { return &query.DistinctOn },
+				DistinctOnPropertyBindingClause,
+				false,
+			),
+			skipWhitespaceToken,
+			acceptOperator(")"),
+			skipWhitespaceToken,
+		},
+		orElse: tokenAcceptors{
+			notAcceptor(acceptWildcardToken),
+			tokenAcceptorFn(func(tr tokenReader) error {
+				stateOf(tr).query.Distinct = true
+				return nil
 			}),
-			orElse: nopAcceptor,
 		},
-	}
+	},
 }
 
-func acceptArrayBody(result *[]conditionValuer) tokenAcceptor {
-	var v conditionValuer
-	return tokenAcceptors{
-		acceptOperator("("),
-		skipWhitespaceToken,
-		acceptConditionValue(&v),
-		skipWhitespaceToken,
-		deferAcceptor(func() tokenAcceptor {
-			*result = append(*result, v)
-			return nopAcceptor
-		}),
-		&conditionalTokenAcceptor{
-			ifAccept: acceptOperator(","),
-			andThen:  acceptMoreArrayBody(result),
-			orElse:   nopAcceptor,
+// acceptGroupBy accepts the optional GROUP BY clause if the dialect permits it.
+var acceptGroupBy = &optionTokenAcceptor{
+	enabled: func(opts *parseOptions) bool { return opts.dialect != nil && opts.dialect.groupBy },
+	ifEnabled: &conditionalTokenAcceptor{
+		name: "GROUP BY",
+		ifAccept: tokenAcceptors{
+			acceptWhitespaceToken,
+			acceptSymbolKeyword("GROUP"),
+			acceptWhitespaceToken,
+			acceptKeyword("BY"),
 		},
-		acceptOperator(")"),
-	}
+		andThen: tokenAcceptors{
+			rejectInStrictMode("GROUP BY is not allowed in strict mode"),
+			acceptWhitespaceToken,
+			acceptProperties(func(query *Query) *[]Property { return &query.GroupBy }, "", false),
+		},
+		orElse: nopAcceptor,
+	},
+	orElse: nopAcceptor,
 }
 
-func acceptMoreArrayBody(result *[]conditionValuer) tokenAcceptor {
-	var v conditionValuer
-	return tokenAcceptors{
-		skipWhitespaceToken,
-		acceptConditionValue(&v),
-		skipWhitespaceToken,
-		deferAcceptor(func() tokenAcceptor {
-			*result = append(*result, v)
-			return nopAcceptor
-		}),
-		&conditionalTokenAcceptor{
-			ifAccept: acceptOperator(","),
-			andThen: deferAcceptor(func() tokenAcceptor {
-				return acceptMoreArrayBody(result)
-			}),
-			orElse: nopAcceptor,
-		},
-	}
+// rejectInStrictMode rejects the keyword of the extension recorded in the state in strict mode.
+func rejectInStrictMode(reason string) tokenAcceptor {
+	return tokenAcceptorFn(func(tr tokenReader) error {
+		if st := stateOf(tr); st.opts.strict {
+			return &SyntaxError{Token: st.keyword, Reason: reason}
+		}
+		return nil
+	})
 }
 
-func acceptBlobBody(result *[]byte) tokenAcceptor {
-	return tokenAcceptors{
-		acceptOperator("("),
-		skipWhitespaceToken,
-		acceptSingleToken(func(token *StringToken) error {
-			if token.Quote == '`' {
-				return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
+// checkDuplicateProjections reports the properties projected again with the same aliases after accepting the projection.
+// They're removed by WithDedupeProjections, rejected in strict mode, and warned otherwise.
+// The acceptor must record the tokens of the properties into the tokens of the state.
+func checkDuplicateProjections(acceptor tokenAcceptor) tokenAcceptor {
+	return tokenAcceptorFn(func(tr tokenReader) error {
+		if err := acceptor.accept(tr); err != nil {
+			return err
+		}
+
+		st := stateOf(tr)
+		query, opts := st.query, st.opts
+		aliasOf := func(i int) string {
+			if i < len(query.Aliases) {
+				return query.Aliases[i]
+			}
+			return ""
+		}
+		for i := 0; i < len(query.Properties); i++ {
+			// the placeholders are resolved later
+			if query.Properties[i] == "" {
+				continue
 			}
 
-			b, err := base64.RawURLEncoding.DecodeString(token.Content)
-			if err != nil {
-				return fmt.Errorf("%w: %s at %d (%w)", ErrUnexpectedToken, token.GetContent(), token.GetPosition(), err)
+			duplicated := false
+			for j := 0; j < i; j++ {
+				if query.Properties[j] == query.Properties[i] && aliasOf(j) == aliasOf(i) {
+					duplicated = true
+					break
+				}
+			}
+			if !duplicated {
+				continue
 			}
 
-			*result = b
-			return nil
-		}),
-		skipWhitespaceToken,
-		acceptOperator(")"),
-	}
+			token := st.tokens.properties[i]
+			if opts.strict && !opts.dedupeProjections {
+				return &SyntaxError{Token: token, Reason: "duplicate projection is not allowed in strict mode"}
+			}
+			opts.warn(DuplicateProjectionWarning, token)
+			if opts.dedupeProjections {
+				removeProjection(query, i)
+				st.tokens.properties = append(st.tokens.properties[:i], st.tokens.properties[i+1:]...)
+				i--
+			}
+		}
+		return nil
+	})
 }
 
-func acceptDateTimeBody(result *time.Time) tokenAcceptor {
-	return tokenAcceptors{
-		acceptOperator("("),
-		skipWhitespaceToken,
-		acceptSingleToken(func(token *StringToken) error {
-			if token.Quote == '`' {
-				return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
+// checkKeyProjection rejects __key__ projected with the other properties after accepting the projection like Query.Validate.
+// The acceptor must record the tokens of the properties into the tokens of the state.
+func checkKeyProjection(acceptor tokenAcceptor) tokenAcceptor {
+	return tokenAcceptorFn(func(tr tokenReader) error {
+		if err := acceptor.accept(tr); err != nil {
+			return err
+		}
+		st := stateOf(tr)
+		if len(st.query.Properties) < 2 {
+			return nil
+		}
+		for i, prop := range st.query.Properties {
+			if prop == keyProperty {
+				return &SyntaxError{Token: st.tokens.properties[i], Reason: fmt.Sprintf("%s cannot be projected with the other properties", keyProperty)}
 			}
+		}
+		return nil
+	})
+}
 
-			t, err := time.Parse(time.RFC3339Nano, token.Content)
-			if err != nil {
-				return fmt.Errorf("%w: %s at %d (%w)", ErrUnexpectedToken, token.GetContent(), token.GetPosition(), err)
-			}
+// removeProjection removes the projected property at the index with its alias, span and the following placeholders shifted.
+func removeProjection(query *Query, index int) {
+	query.Properties = append(query.Properties[:index], query.Properties[index+1:]...)
+	if index < len(query.Aliases) {
+		query.Aliases = append(query.Aliases[:index], query.Aliases[index+1:]...)
+	}
+	if index < len(query.ProjectionSpans) {
+		query.ProjectionSpans = append(query.ProjectionSpans[:index], query.ProjectionSpans[index+1:]...)
+	}
+	for _, b := range query.PropertyBindings {
+		if b.Clause == ProjectionPropertyBindingClause && b.Index > index {
+			b.Index--
+		}
+	}
+}
+
+// acceptSymbolKeyword accepts the symbol as the unreserved keyword case-insensitively, and records it as the keyword of the state. e.g. GROUP
+func acceptSymbolKeyword(keyword string) tokenAcceptor {
+	reason := fmt.Sprintf("expect to be %q", keyword)
+	return acceptSingleToken(func(st *parseState, token *SymbolToken) error {
+		if !strings.EqualFold(token.Content, keyword) {
+			return &SyntaxError{Token: token, Reason: reason}
+		}
+		st.keyword = token
+		return nil
+	})
+}
 
-			*result = t
+// acceptProperties accepts the comma separated properties into the list of the query given by props.
+// The placeholders are permitted in the clause with the template placeholders unless the clause is empty.
+// The projection accepts the wildcard and the aliases by AS, and records the spans and the tokens of the properties.
+func acceptProperties(props func(*Query) *[]Property, clause PropertyBindingClause, projection bool) tokenAcceptor {
+	accepted := func(tr tokenReader, st *parseState, token Token) error {
+		if !projection {
 			return nil
-		}),
+		}
+		if st.opts.projectionSpans {
+			st.query.ProjectionSpans = append(st.query.ProjectionSpans, Span{Start: token.GetPosition(), End: token.GetPosition() + len(token.GetContent())})
+		}
+		st.tokens.properties = append(st.tokens.properties, token)
+		return acceptPropertyAlias.accept(tr)
+	}
+	separator := tokenAcceptors{
+		skipWhitespaceToken,
+		acceptOperator(","),
 		skipWhitespaceToken,
-		acceptOperator(")"),
 	}
+	return acceptSeparated(separator, func(tr tokenReader, index int) (bool, error) {
+		token, err := tr.Read()
+		if errors.Is(err, ErrEndOfToken) {
+			return false, ErrNoTokens
+		} else if err != nil {
+			return false, err
+		}
+
+		st := stateOf(tr)
+		list := props(st.query)
+		switch tok := token.(type) {
+		case *WildcardToken:
+			if projection && index == 0 {
+				// the full projection cannot be followed by any other properties
+				*list = nil
+				return true, nil
+			}
+		case *SymbolToken:
+			*list = append(*list, Property(tok.Content))
+			return false, accepted(tr, st, tok)
+		case *StringToken:
+			if tok.Quote == '`' {
+				*list = append(*list, Property(tok.Content))
+				return false, accepted(tr, st, tok)
+			}
+		case *BindingToken:
+			if clause != "" && st.opts.templatePlaceholders {
+				*list = append(*list, "")
+				addPropertyBinding(st.query, clause, tok)
+				return false, accepted(tr, st, tok)
+			}
+		}
+		return false, &SyntaxError{Token: token}
+	})
 }
 
-func acceptOrderByBody(orderBy *[]OrderBy) tokenAcceptor {
-	var prop Property
-	return tokenAcceptors{
+// acceptPropertyAlias accepts the optional alias of the last projected property. e.g. a AS x
+// The aliases are the extension of GQL, so they are rejected in strict mode.
+// The span of the last property is extended to the end of the alias if the spans are recorded.
+var acceptPropertyAlias = &conditionalTokenAcceptor{
+	ifAccept: tokenAcceptors{
+		acceptWhitespaceToken,
+		acceptSingleToken(func(st *parseState, token *KeywordToken) error {
+			if token.Name != "AS" {
+				return &SyntaxError{Token: token, Reason: `expect to be "AS"`}
+			}
+			st.keyword = token
+			return nil
+		}),
+	},
+	andThen: tokenAcceptors{
+		rejectInStrictMode("alias is not allowed in strict mode"),
+		acceptWhitespaceToken,
 		acceptEitherToken(
-			func(tok *SymbolToken) error {
-				prop = Property(tok.Content)
+			func(st *parseState, token *SymbolToken) error {
+				setPropertyAlias(st, token, token.Content)
 				return nil
 			},
-			func(tok *StringToken) error {
-				if tok.Quote != '`' {
-					return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, tok.Content, tok.Position)
+			func(st *parseState, token *StringToken) error {
+				if token.Quote != '`' {
+					return &SyntaxError{Token: token}
 				}
-				prop = Property(tok.Content)
+				setPropertyAlias(st, token, token.Content)
 				return nil
 			},
 		),
-		&conditionalTokenAcceptor{
-			ifAccept: tokenAcceptors{
-				acceptWhitespaceToken,
-				acceptSingleToken(func(token *OrderToken) error {
-					*orderBy = append(*orderBy, OrderBy{Descending: token.Descending, Property: prop})
-					return nil
-				}),
-			},
-			andThen: nopAcceptor,
-			orElse: deferAcceptor(func() tokenAcceptor {
-				*orderBy = append(*orderBy, OrderBy{Descending: false, Property: prop})
-				return nopAcceptor
-			}),
-		},
-		&conditionalTokenAcceptor{
-			ifAccept: tokenAcceptors{
-				skipWhitespaceToken,
-				acceptOperator(","),
-				skipWhitespaceToken,
-			},
-			andThen: deferAcceptor(func() tokenAcceptor {
-				return acceptOrderByBody(orderBy)
-			}),
-			orElse: nopAcceptor,
-		},
+	},
+	orElse: nopAcceptor,
+}
+
+func setPropertyAlias(st *parseState, token Token, alias string) {
+	query := st.query
+	for len(query.Aliases) < len(query.Properties) {
+		query.Aliases = append(query.Aliases, "")
+	}
+	query.Aliases[len(query.Properties)-1] = alias
+	if st.opts.projectionSpans {
+		query.ProjectionSpans[len(query.ProjectionSpans)-1].End = token.GetPosition() + len(token.GetContent())
+	}
+}
+
+func ParseCondition(ts TokenSource, opts ...ParseOption) (_ Condition, err error) {
+	o := newParseOptions(opts)
+	ts, end := o.startParse("ParseCondition", ts)
+	defer func() { end(err) }()
+	ts, redact := o.startRedaction(ts)
+	defer func() { err = redact(err) }()
+	tracker := &eofTrackingTokenSource{TokenSource: ts}
+	st, tr := newParseState(tracker, o)
+	if err := acceptStandaloneCondition.accept(tr); err != nil {
+		return nil, &ParseError{Partial: partialCondition(st.condition), Err: tracker.wrapError(err)}
+	}
+	if err := acceptEndOfQuery(tr); err != nil {
+		return nil, &ParseError{Partial: partialCondition(st.condition), Err: tracker.wrapError(err)}
+	}
+	return st.condition, nil
+}
+
+var acceptStandaloneCondition = tokenAcceptors{
+	skipWhitespaceToken,
+	acceptCondition(func(st *parseState) *Condition { return &st.condition }),
+}
+
+// acceptCondition accepts the condition into the target in the state.
+func acceptCondition(target func(*parseState) *Condition) tokenAcceptor {
+	return tokenAcceptorFn(func(tr tokenReader) error {
+		opts := stateOf(tr).opts
+		ast, err := constructAST(tr, 0, &nestingLimiter{max: opts.maxNestingDepth}, opts.dialect)
+		if err != nil {
+			return err
+		}
+		if err := opts.checkConditionAST(ast); err != nil {
+			return err
+		}
+
+		c, err := ast.toCondition(opts.pool)
+		if err != nil {
+			return err
+		}
+		if opts.strict && opts.dialect != nil && opts.dialect.containsQuantifiers {
+			// the extension is rewritten into the standard syntax in strict mode
+			if c, err = ExpandQuantifiers(c); err != nil {
+				return err
+			}
+		}
+		*target(stateOf(tr)) = c
+		return nil
+	})
+}
+
+func ParseKey(ts TokenSource, opts ...ParseOption) (_ *Key, err error) {
+	var key Key
+	o := newParseOptions(opts)
+	ts, end := o.startParse("ParseKey", ts)
+	defer func() { end(err) }()
+	ts, redact := o.startRedaction(ts)
+	defer func() { err = redact(err) }()
+	tracker := &eofTrackingTokenSource{TokenSource: ts}
+	st, tr := newParseState(tracker, o)
+	st.key = &key
+	if err := acceptKey.accept(tr); err != nil {
+		return nil, &ParseError{Partial: &key, Err: tracker.wrapError(err)}
+	}
+	if err := acceptEndOfQuery(tr); err != nil {
+		return nil, &ParseError{Partial: &key, Err: tracker.wrapError(err)}
+	}
+	return &key, nil
+}
+
+var acceptKey = tokenAcceptors{
+	skipWhitespaceToken,
+	acceptKeyword("KEY"),
+	acceptKeyBody,
+}
+
+// parseKeyBody parses the body of the key literal following KEY.
+func parseKeyBody(tr tokenReader) (*Key, error) {
+	key := &Key{}
+	stateOf(tr).key = key
+	if err := acceptKeyBody.accept(tr); err != nil {
+		return nil, err
 	}
+	return key, nil
 }
 
-func acceptLimitBody(limit *Limit) tokenAcceptor {
-	var wantNextCursor bool
-	return &conditionalTokenAcceptor{
-		ifAccept: acceptKeyword("FIRST"),
+// acceptKeyBody accepts the body of the key literal into the key of the state.
+var acceptKeyBody = tokenAcceptors{
+	acceptOperator("("),
+	skipWhitespaceToken,
+	&conditionalTokenAcceptor{
+		ifAccept: acceptKeyword("PROJECT"),
 		andThen: tokenAcceptors{
-			skipWhitespaceToken,
 			acceptOperator("("),
 			skipWhitespaceToken,
-			acceptEitherToken(
-				func(token *NumericToken) error {
-					if token.Floating {
-						return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
-					}
-					limit.Position = token.Int64
-					wantNextCursor = true
-					return nil
-				},
-				func(token *BindingToken) error {
-					limit.Cursor = parseBindingToken(token)
-					return nil
-				},
-			),
+			acceptSingleToken(func(st *parseState, token *StringToken) error {
+				if token.Quote == '`' {
+					return &SyntaxError{Token: token}
+				}
+				st.key.ProjectID = ProjectID(token.Content)
+				return nil
+			}),
+			skipWhitespaceToken,
+			acceptOperator(")"),
 			skipWhitespaceToken,
 			acceptOperator(","),
 			skipWhitespaceToken,
-			acceptEitherToken(
-				func(token *NumericToken) error {
-					if token.Floating {
-						return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
-					}
-					if wantNextCursor {
-						return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
-					}
-					limit.Position = token.Int64
-					return nil
-				},
-				func(token *BindingToken) error {
-					if !wantNextCursor {
-						return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
-					}
-					limit.Cursor = parseBindingToken(token)
-					return nil
-				},
-			),
+		},
+		orElse: nopAcceptor,
+	},
+	&conditionalTokenAcceptor{
+		ifAccept: acceptKeyword("NAMESPACE"),
+		andThen: tokenAcceptors{
+			acceptOperator("("),
+			skipWhitespaceToken,
+			acceptSingleToken(func(st *parseState, token *StringToken) error {
+				if token.Quote == '`' {
+					return &SyntaxError{Token: token}
+				}
+				st.key.Namespace = token.Content
+				return nil
+			}),
 			skipWhitespaceToken,
 			acceptOperator(")"),
+			skipWhitespaceToken,
+			acceptOperator(","),
+			skipWhitespaceToken,
 		},
-		orElse: acceptSingleToken(func(token *NumericToken) error {
+		orElse: nopAcceptor,
+	},
+	acceptKeyPath,
+	acceptOperator(")"),
+}
+
+var acceptKeyPath = acceptSeparated(
+	tokenAcceptors{
+		acceptOperator(","),
+		skipWhitespaceToken,
+	},
+	func(tr tokenReader, _ int) (bool, error) {
+		st := stateOf(tr)
+		st.keyPath = &KeyPath{}
+		if err := acceptKeyPathElement.accept(tr); err != nil {
+			return false, err
+		}
+		st.key.Path = append(st.key.Path, st.keyPath)
+		return false, nil
+	},
+)
+
+var acceptKeyPathElement = tokenAcceptors{
+	acceptEitherToken(
+		func(st *parseState, token *SymbolToken) error {
+			st.keyPath.Kind = Kind(token.Content)
+			return nil
+		},
+		func(st *parseState, token *StringToken) error {
+			if token.Quote != '`' {
+				return &SyntaxError{Token: token}
+			}
+			st.keyPath.Kind = Kind(token.Content)
+			return nil
+		},
+	),
+	skipWhitespaceToken,
+	acceptOperator(","),
+	skipWhitespaceToken,
+	acceptTokenFromAny3(
+		func(st *parseState, token *StringToken) error {
+			if token.Quote == '`' {
+				return &SyntaxError{Token: token}
+			}
+			st.keyPath.Name = token.Content
+			return nil
+		},
+		func(st *parseState, token *NumericToken) error {
 			if token.Floating {
-				return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
+				return &SyntaxError{Token: token}
 			}
-			limit.Position = token.Int64
+			st.keyPath.ID = token.Int64
+			return nil
+		},
+		func(st *parseState, token *BindingToken) error {
+			st.keyPath.Binding = parseBindingToken(token)
+			return nil
+		},
+	),
+	skipWhitespaceToken,
+}
+
+// parseArrayBody parses the parenthesized elements of the array. e.g. (1, 2, 3)
+func parseArrayBody(tr tokenReader, array *conditionArray, limiter *nestingLimiter) error {
+	if err := acceptOperator("(").accept(tr); err != nil {
+		return err
+	}
+	if err := skipWhitespaceToken.accept(tr); err != nil {
+		return err
+	}
+	if token, err := peekToken(tr); err == nil {
+		if op, ok := token.(*OperatorToken); ok && op.Type == ")" {
+			_, err := tr.Read()
+			return err
+		}
+	}
+	return parseArrayElements(tr, array, limiter)
+}
+
+// parseArrayElements parses the elements and the closing parenthesis of the array.
+// The trailing comma is accepted and recorded to be rejected in strict mode.
+// It's iterative not to recurse for each element of the huge arrays.
+func parseArrayElements(tr tokenReader, array *conditionArray, limiter *nestingLimiter) error {
+	for {
+		v, err := parseConditionValue(tr, limiter)
+		if err != nil {
+			return err
+		}
+		if err := skipWhitespaceToken.accept(tr); err != nil {
+			return err
+		}
+		array.values = append(array.values, v)
+
+		token, err := tr.Read()
+		if errors.Is(err, ErrEndOfToken) {
+			return ErrNoTokens
+		} else if err != nil {
+			return err
+		}
+		comma, ok := token.(*OperatorToken)
+		if !ok || comma.Type != "," && comma.Type != ")" {
+			return &SyntaxError{Token: token, Reason: `expect to be ")"`}
+		} else if comma.Type == ")" {
+			return nil
+		}
+
+		if err := skipWhitespaceToken.accept(tr); err != nil {
+			return err
+		}
+		if next, err := peekToken(tr); err == nil {
+			if op, ok := next.(*OperatorToken); ok && op.Type == ")" {
+				array.trailingComma = comma
+				_, err := tr.Read()
+				return err
+			}
+		}
+	}
+}
+
+// parseStringLiteralBody parses the parenthesized string of the literals like BLOB and DATETIME.
+// The string is converted by parse before the closing parenthesis to report the malformed literal first.
+func parseStringLiteralBody[T any](tr tokenReader, parse func(string) (T, error)) (T, error) {
+	var result T
+	if err := acceptOperator("(").accept(tr); err != nil {
+		return result, err
+	}
+	if err := skipWhitespaceToken.accept(tr); err != nil {
+		return result, err
+	}
+
+	token, err := tr.Read()
+	if errors.Is(err, ErrEndOfToken) {
+		return result, ErrNoTokens
+	} else if err != nil {
+		return result, err
+	}
+	str, ok := token.(*StringToken)
+	if !ok {
+		return result, &SyntaxError{Token: token, Reason: fmt.Sprintf("expect to be %T", str)}
+	} else if str.Quote == '`' {
+		return result, &SyntaxError{Token: str}
+	}
+	if result, err = parse(str.Content); err != nil {
+		return result, &SyntaxError{Token: str, Cause: err}
+	}
+
+	if err := skipWhitespaceToken.accept(tr); err != nil {
+		return result, err
+	}
+	if err := acceptOperator(")").accept(tr); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func parseBlobBody(tr tokenReader) ([]byte, error) {
+	return parseStringLiteralBody(tr, base64.RawURLEncoding.DecodeString)
+}
+
+func parseDateTimeBody(tr tokenReader) (time.Time, error) {
+	return parseStringLiteralBody(tr, func(s string) (time.Time, error) {
+		return time.Parse(time.RFC3339Nano, s)
+	})
+}
+
+// acceptOrderByBody accepts the comma separated orders. e.g. a DESC, b
+var acceptOrderByBody = acceptSeparated(
+	tokenAcceptors{
+		skipWhitespaceToken,
+		acceptOperator(","),
+		skipWhitespaceToken,
+	},
+	func(tr tokenReader, _ int) (bool, error) {
+		return false, acceptOrder.accept(tr)
+	},
+)
+
+var acceptOrder = tokenAcceptors{
+	tokenAcceptorFn(func(tr tokenReader) error {
+		st := stateOf(tr)
+		token, err := peekToken(tr)
+		if errors.Is(err, ErrEndOfToken) {
+			return ErrNoTokens
+		} else if err != nil {
+			return err
+		}
+		if tok, ok := token.(*BindingToken); ok && st.opts.templatePlaceholders {
+			if _, err := tr.Read(); err != nil {
+				return err
+			}
+			st.property = ""
+			addPropertyBinding(st.query, OrderByPropertyBindingClause, tok)
+			return nil
+		}
+
+		prop, err := parsePropertyPath(tr)
+		st.property = string(prop)
+		return err
+	}),
+	&conditionalTokenAcceptor{
+		ifAccept: tokenAcceptors{
+			acceptWhitespaceToken,
+			acceptSingleToken(func(st *parseState, token *OrderToken) error {
+				st.query.OrderBy = append(st.query.OrderBy, OrderBy{Descending: token.Descending, Property: Property(st.property)})
+				return nil
+			}),
+		},
+		andThen: nopAcceptor,
+		orElse: tokenAcceptorFn(func(tr tokenReader) error {
+			st := stateOf(tr)
+			st.query.OrderBy = append(st.query.OrderBy, OrderBy{Descending: false, Property: Property(st.property)})
 			return nil
 		}),
+	},
+}
+
+// parsePropertyPath parses the nested property path joined with dots. e.g. a.b, `a b`.c
+// The segments can be quoted with backticks, but the empty segments are not permitted in the nested path.
+func parsePropertyPath(tr tokenReader) (Property, error) {
+	var path strings.Builder
+	for {
+		token, err := tr.Read()
+		if errors.Is(err, ErrEndOfToken) {
+			return "", ErrNoTokens
+		} else if err != nil {
+			return "", err
+		}
+
+		switch tok := token.(type) {
+		case *SymbolToken:
+			path.WriteString(tok.Content)
+		case *StringToken:
+			if tok.Quote != '`' || (tok.Content == "" && path.Len() != 0) {
+				return "", &SyntaxError{Token: tok}
+			}
+			path.WriteString(tok.Content)
+		default:
+			return "", &SyntaxError{Token: token}
+		}
+
+		rtr, offset := markTokenReader(tr)
+		token, err = rtr.Read()
+		if errors.Is(err, ErrEndOfToken) {
+			break
+		} else if err != nil {
+			return "", err
+		}
+		if op, ok := token.(*OperatorToken); !ok || op.Type != "." {
+			rtr.resetTo(offset)
+			break
+		}
+		if path.Len() == 0 {
+			return "", &SyntaxError{Token: token}
+		}
+		path.WriteByte('.')
 	}
+	return Property(path.String()), nil
 }
 
-func acceptOffsetBody(offset *Offset) tokenAcceptor {
-	return tokenAcceptors{
-		acceptEitherToken(
-			func(token *NumericToken) error {
+var acceptLimitBody = &conditionalTokenAcceptor{
+	ifAccept: acceptKeyword("FIRST"),
+	andThen: tokenAcceptors{
+		skipWhitespaceToken,
+		acceptOperator("("),
+		skipWhitespaceToken,
+		acceptCursorLiteral(func(st *parseState, cursor Cursor, _ *StringToken) error {
+			st.query.Limit.Cursor = cursor
+			return nil
+		}, acceptEitherToken(
+			func(st *parseState, token *NumericToken) error {
 				if token.Floating {
-					return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
+					return &SyntaxError{Token: token}
 				}
-				offset.Position = token.Int64
+				st.query.Limit.Position = token.Int64
+				st.wantNextCursor = true
 				return nil
 			},
-			func(token *BindingToken) error {
-				offset.Cursor = parseBindingToken(token)
+			func(st *parseState, token *BindingToken) error {
+				st.query.Limit.Cursor = parseBindingToken(token)
 				return nil
 			},
-		),
-		&conditionalTokenAcceptor{
-			ifAccept: tokenAcceptors{
-				skipWhitespaceToken,
-				acceptOperator("+"),
-				skipWhitespaceToken,
-			},
-			andThen: acceptSingleToken(func(token *NumericToken) error {
+		)),
+		skipWhitespaceToken,
+		acceptOperator(","),
+		skipWhitespaceToken,
+		acceptCursorLiteral(func(st *parseState, cursor Cursor, token *StringToken) error {
+			if !st.wantNextCursor {
+				return &SyntaxError{Token: token}
+			}
+			st.query.Limit.Cursor = cursor
+			return nil
+		}, acceptEitherToken(
+			func(st *parseState, token *NumericToken) error {
 				if token.Floating {
-					return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
+					return &SyntaxError{Token: token}
 				}
-				if offset.Cursor == nil {
-					return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
+				if st.wantNextCursor {
+					return &SyntaxError{Token: token}
+				}
+				st.query.Limit.Position = token.Int64
+				return nil
+			},
+			func(st *parseState, token *BindingToken) error {
+				if !st.wantNextCursor {
+					return &SyntaxError{Token: token}
+				}
+				st.query.Limit.Cursor = parseBindingToken(token)
+				return nil
+			},
+		)),
+		skipWhitespaceToken,
+		acceptOperator(")"),
+	},
+	orElse: acceptResultPosition,
+}
+
+// acceptResultPosition accepts the integer or the cursor followed by the integers to add into the position and the cursor of the state.
+// e.g. 10, @cursor, @cursor + 10 + 5
+var acceptResultPosition = tokenAcceptors{
+	acceptCursorLiteral(func(st *parseState, c Cursor, _ *StringToken) error {
+		*st.cursor = c
+		return nil
+	}, acceptEitherToken(
+		func(st *parseState, token *NumericToken) error {
+			if token.Floating {
+				return &SyntaxError{Token: token}
+			}
+			*st.position = token.Int64
+			return nil
+		},
+		func(st *parseState, token *BindingToken) error {
+			*st.cursor = parseBindingToken(token)
+			return nil
+		},
+	)),
+	acceptAdditionalPositions,
+}
+
+// acceptCursorLiteral accepts the cursor literal like CURSOR('base64') if it's permitted, otherwise accepts the alternative.
+// CURSOR isn't a keyword, so it's accepted as the symbol not to reserve it in the other places.
+func acceptCursorLiteral(onCursor func(*parseState, Cursor, *StringToken) error, orElse tokenAcceptor) tokenAcceptor {
+	return &optionTokenAcceptor{
+		enabled: func(opts *parseOptions) bool { return opts.cursorLiterals },
+		ifEnabled: &conditionalTokenAcceptor{
+			ifAccept: acceptSingleToken(func(_ *parseState, token *SymbolToken) error {
+				if !strings.EqualFold(token.Content, "CURSOR") {
+					return &SyntaxError{Token: token}
 				}
-				offset.Position = token.Int64
 				return nil
 			}),
-			orElse: nopAcceptor,
+			andThen: tokenAcceptors{
+				skipWhitespaceToken,
+				acceptOperator("("),
+				skipWhitespaceToken,
+				acceptSingleToken(func(st *parseState, token *StringToken) error {
+					if token.Quote == '`' {
+						return &SyntaxError{Token: token}
+					}
+					cursor, err := ParseCursor(token.Content)
+					if err != nil {
+						return &SyntaxError{Token: token, Cause: err}
+					}
+					return onCursor(st, cursor, token)
+				}),
+				skipWhitespaceToken,
+				acceptOperator(")"),
+			},
+			orElse: orElse,
 		},
+		orElse: orElse,
+	}
+}
+
+// acceptAdditionalPositions accepts the trailing terms like `+ 10 + 5` and sums them up into the position of the state.
+// The sign of the integer is taken as the operator too. e.g. @cursor +10
+var acceptAdditionalPositions tokenAcceptorFn = func(tr tokenReader) error {
+	st := stateOf(tr)
+	for {
+		rtr, offset := markTokenReader(tr)
+		if err := skipWhitespaceToken.accept(rtr); err != nil {
+			return err
+		}
+		token, err := rtr.Read()
+		if errors.Is(err, ErrEndOfToken) {
+			rtr.resetTo(offset)
+			return nil
+		} else if err != nil {
+			rtr.resetTo(offset)
+			return err
+		}
+
+		switch tok := token.(type) {
+		case *OperatorToken:
+			if tok.Type == "+" {
+				if err := skipWhitespaceToken.accept(tr); err != nil {
+					return err
+				}
+				if err := acceptAdditionalPosition.accept(tr); err != nil {
+					return err
+				}
+				continue
+			}
+		case *NumericToken:
+			// the malformed term is left unread like the other tokens
+			if strings.HasPrefix(tok.RawContent, "+") && addPosition(st, tok) == nil {
+				continue
+			}
+		}
+		rtr.resetTo(offset)
+		return nil
+	}
+}
+
+var acceptAdditionalPosition = acceptSingleToken(addPosition)
+
+func addPosition(st *parseState, token *NumericToken) error {
+	if token.Floating {
+		return &SyntaxError{Token: token}
+	}
+	sum := *st.position + token.Int64
+	if (token.Int64 > 0 && sum < *st.position) || (token.Int64 < 0 && sum > *st.position) {
+		return &SyntaxError{Token: token, Reason: "overflow"}
+	}
+	*st.position = sum
+	return nil
+}
+
+// acceptKind accepts the kind of the query. The placeholder is permitted with the template placeholders.
+var acceptKind tokenAcceptorFn = func(tr tokenReader) error {
+	token, err := tr.Read()
+	if errors.Is(err, ErrEndOfToken) {
+		return ErrNoTokens
+	} else if err != nil {
+		return err
+	}
+
+	st := stateOf(tr)
+	switch tok := token.(type) {
+	case *SymbolToken:
+		st.query.Kind = Kind(tok.Content)
+		return nil
+	case *StringToken:
+		if tok.Quote != '`' {
+			return &SyntaxError{Token: tok}
+		}
+		st.query.Kind = Kind(tok.Content)
+		return nil
+	case *BindingToken:
+		if st.opts.templatePlaceholders {
+			st.query.KindBinding = &KindBinding{Variable: parseBindingToken(tok)}
+			return nil
+		}
 	}
+	return &SyntaxError{Token: token}
 }
 
 func parseBindingToken(bind *BindingToken) BindingVariable {
@@ -4,34 +4,14 @@ import (
 	"encoding/binary"
 	rand "math/rand/v2"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/karupanerura/gqlparser"
+	"github.com/karupanerura/gqlparser/gqltest"
 )
 
-type sliceTokenSource struct {
-	s []gqlparser.Token
-}
-
-func (ts *sliceTokenSource) Read() (gqlparser.Token, error) {
-	if len(ts.s) == 0 {
-		return nil, gqlparser.ErrEndOfToken
-	}
-
-	tok := ts.s[0]
-	ts.s = ts.s[1:]
-	return tok, nil
-}
-
-func (ts *sliceTokenSource) Unread(tok gqlparser.Token) {
-	ts.s = append([]gqlparser.Token{tok}, ts.s...)
-}
-
-func (ts *sliceTokenSource) Next() bool {
-	return len(ts.s) != 0
-}
-
 func TestParseQuery(t *testing.T) {
 	// t.Parallel()
 
@@ -404,7 +384,7 @@ func TestParseQuery(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			// t.Parallel()
-			normalizeTokens(tt.tokens)
+			gqlparser.NormalizeTokens(tt.tokens)
 
 			var query string
 			for _, token := range tt.tokens {
@@ -412,7 +392,7 @@ func TestParseQuery(t *testing.T) {
 			}
 			t.Log(query)
 
-			got, err := gqlparser.ParseQuery(&sliceTokenSource{tt.tokens})
+			got, err := gqlparser.ParseQuery(gqltest.NewSliceTokenSource(tt.tokens...))
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ParseQuery() error = %+v, wantErr %+v", err, tt.wantErr)
 				return
@@ -424,59 +404,29 @@ func TestParseQuery(t *testing.T) {
 	}
 }
 
-func normalizeTokens(tokens []gqlparser.Token) {
-	pos := 0
-	for _, token := range tokens {
-		switch t := token.(type) {
-		case *gqlparser.StringToken:
-			t.Position = pos
-			t.RawContent = string([]byte{t.Quote}) + t.Content + string([]byte{t.Quote})
-			pos += len(t.GetContent())
-		case *gqlparser.OperatorToken:
-			t.Position = pos
-			t.RawContent = t.Type
-			pos += len(t.GetContent())
-		case *gqlparser.WildcardToken:
-			t.Position = pos
-			pos += len(t.GetContent())
-		case *gqlparser.BooleanToken:
-			t.Position = pos
-			if t.Value {
-				t.RawContent = "TRUE"
-			} else {
-				t.RawContent = "FALSE"
-			}
-			pos += len(t.GetContent())
-		case *gqlparser.OrderToken:
-			t.Position = pos
-			if t.Descending {
-				t.RawContent = "DESC"
-			} else {
-				t.RawContent = "ASC"
-			}
-			pos += len(t.GetContent())
-		case *gqlparser.SymbolToken:
-			t.Position = pos
-			pos += len(t.GetContent())
-		case *gqlparser.KeywordToken:
-			t.Position = pos
-			t.RawContent = t.Name
-			pos += len(t.GetContent())
-		case *gqlparser.NumericToken:
-			t.Position = pos
-			if t.Floating {
-				t.RawContent = strconv.FormatFloat(t.Float64, 'f', 10, 64)
-			} else {
-				t.RawContent = strconv.FormatInt(t.Int64, 10)
-			}
-			pos += len(t.GetContent())
-		case *gqlparser.WhitespaceToken:
-			t.Position = pos
-			pos += len(t.GetContent())
-		case *gqlparser.BindingToken:
-			t.Position = pos
-			pos += len(t.GetContent())
-		}
+func TestParseQuery_WideProjectionAndKey(t *testing.T) {
+	t.Parallel()
+
+	const n = 10000
+	props := make([]string, n)
+	paths := make([]string, n)
+	want := make([]gqlparser.Property, n)
+	for i := range props {
+		props[i] = "p" + strconv.Itoa(i)
+		paths[i] = "K, " + strconv.Itoa(i+1)
+		want[i] = gqlparser.Property(props[i])
+	}
+
+	query, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT " + strings.Join(props, ", ") + " FROM Kind WHERE __key__ = KEY(" + strings.Join(paths, ", ") + ")"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, query.Properties); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	key := query.Where.(*gqlparser.EitherComparatorCondition).Value.(*gqlparser.Key)
+	if len(key.Path) != n || key.Path[n-1].ID != n {
+		t.Errorf("len(Path) = %d, want %d", len(key.Path), n)
 	}
 }
 
@@ -554,10 +504,15 @@ func FuzzParseQueryOrAggregationQuery(f *testing.F) {
 		for len(tokens) < length {
 			tokens = append(tokens, parts[r.IntN(len(parts))])
 		}
-		normalizeTokens(tokens)
+		gqlparser.NormalizeTokens(tokens)
 
-		_, _, _ = gqlparser.ParseQueryOrAggregationQuery(&sliceTokenSource{tokens})
+		query, aggregationQuery, err := gqlparser.ParseQueryOrAggregationQuery(gqltest.NewSliceTokenSource(tokens...))
 		// should be no panics
+		if err == nil && query != nil {
+			assertRoundTrip(t, query)
+		} else if err == nil {
+			assertRoundTrip(t, aggregationQuery)
+		}
 	})
 }
 
@@ -628,10 +583,13 @@ func FuzzParseAggregationQuery(f *testing.F) {
 		for len(tokens) < length {
 			tokens = append(tokens, parts[r.IntN(len(parts))])
 		}
-		normalizeTokens(tokens)
+		gqlparser.NormalizeTokens(tokens)
 
-		_, _ = gqlparser.ParseAggregationQuery(&sliceTokenSource{tokens})
+		aggregationQuery, err := gqlparser.ParseAggregationQuery(gqltest.NewSliceTokenSource(tokens...))
 		// should be no panics
+		if err == nil {
+			assertRoundTrip(t, aggregationQuery)
+		}
 	})
 }
 
@@ -702,10 +660,13 @@ func FuzzParseQuery(f *testing.F) {
 		for len(tokens) < length {
 			tokens = append(tokens, parts[r.IntN(len(parts))])
 		}
-		normalizeTokens(tokens)
+		gqlparser.NormalizeTokens(tokens)
 
-		_, _ = gqlparser.ParseQuery(&sliceTokenSource{tokens})
+		query, err := gqlparser.ParseQuery(gqltest.NewSliceTokenSource(tokens...))
 		// should be no panics
+		if err == nil {
+			assertRoundTrip(t, query)
+		}
 	})
 }
 
@@ -762,9 +723,9 @@ func FuzzParseCondition(f *testing.F) {
 		for len(tokens) < length {
 			tokens = append(tokens, parts[r.IntN(len(parts))])
 		}
-		normalizeTokens(tokens)
+		gqlparser.NormalizeTokens(tokens)
 
-		_, _ = gqlparser.ParseCondition(&sliceTokenSource{tokens})
+		_, _ = gqlparser.ParseCondition(gqltest.NewSliceTokenSource(tokens...))
 		// should be no panics
 	})
 }
@@ -795,9 +756,38 @@ func FuzzParseKey(f *testing.F) {
 		for len(tokens) < length {
 			tokens = append(tokens, parts[r.IntN(len(parts))])
 		}
-		normalizeTokens(tokens)
+		gqlparser.NormalizeTokens(tokens)
+
+		_, _ = gqlparser.ParseKey(gqltest.NewSliceTokenSource(tokens...))
+		// should be no panics
+	})
+}
+
+func FuzzParse_MalformedTokens(f *testing.F) {
+	f.Add("SELECT * FROM Kind WHERE a = 1 AND b IN ARRAY(1, 2)", 0, uint8(0))
+	f.Add("a = 1 AND b IN ARRAY(1, 2)", 5, uint8(1))
+	f.Add("KEY(Kind, 1)", 3, uint8(2))
+	f.Fuzz(func(t *testing.T, source string, at int, kind uint8) {
+		tokens, err := gqlparser.ReadAllTokens(gqlparser.NewLexer(source))
+		if err != nil {
+			return
+		}
+
+		malformed := []gqlparser.Token{
+			nil,
+			(*gqlparser.BooleanToken)(nil),
+			(*gqlparser.SymbolToken)(nil),
+			(*gqlparser.OperatorToken)(nil),
+		}
+		if at < 0 {
+			at = -at
+		}
+		at %= len(tokens) + 1
+		tokens = append(tokens[:at], append([]gqlparser.Token{malformed[int(kind)%len(malformed)]}, tokens[at:]...)...)
 
-		_, _ = gqlparser.ParseKey(&sliceTokenSource{tokens})
+		_, _, _ = gqlparser.ParseQueryOrAggregationQuery(gqltest.NewSliceTokenSource(tokens...))
+		_, _ = gqlparser.ParseCondition(gqltest.NewSliceTokenSource(tokens...))
+		_, _ = gqlparser.ParseKey(gqltest.NewSliceTokenSource(tokens...))
 		// should be no panics
 	})
 }
@@ -0,0 +1,133 @@
+package gqlparser
+
+import "sync"
+
+// Pool reuses the AST nodes across the parses for the high-throughput parsing. It's safe for concurrent use.
+// The zero value is ready to use. It's opt-in: the parser allocates the nodes from the pool only with WithPool.
+//
+// The queries of ParseQuery, the aggregation queries of ParseAggregationQuery and the conditions are reused.
+// Release them after using the parsed queries and don't reference them any more, because they're cleared and reused
+// by the following parses.
+type Pool struct {
+	queries            nodePool[Query]
+	aggregationQueries nodePool[AggregationQuery]
+	forwards           nodePool[ForwardComparatorCondition]
+	backwards          nodePool[BackwardComparatorCondition]
+	eithers            nodePool[EitherComparatorCondition]
+	quantifieds        nodePool[QuantifiedComparatorCondition]
+	isNulls            nodePool[IsNullCondition]
+	ands               nodePool[AndCompoundCondition]
+	ors                nodePool[OrCompoundCondition]
+}
+
+// WithPool allocates the AST nodes from the pool. The nodes of the failed parses aren't reused.
+func WithPool(pool *Pool) ParseOption {
+	return func(o *parseOptions) {
+		o.pool = pool
+	}
+}
+
+// Release returns the nodes of the query parsed with the pool to the pool.
+func (p *Pool) Release(q *Query) {
+	if q == nil {
+		return
+	}
+	p.ReleaseCondition(q.Where)
+	p.queries.put(q)
+}
+
+// ReleaseAggregationQuery returns the nodes of the aggregation query parsed with the pool to the pool.
+func (p *Pool) ReleaseAggregationQuery(q *AggregationQuery) {
+	if q == nil {
+		return
+	}
+	p.ReleaseCondition(q.Where)
+	p.ReleaseCondition(q.Having)
+	p.aggregationQueries.put(q)
+}
+
+// ReleaseCondition returns the nodes of the condition parsed with the pool to the pool.
+func (p *Pool) ReleaseCondition(cond Condition) {
+	switch c := cond.(type) {
+	case *AndCompoundCondition:
+		p.ReleaseCondition(c.Left)
+		p.ReleaseCondition(c.Right)
+		p.ands.put(c)
+	case *OrCompoundCondition:
+		p.ReleaseCondition(c.Left)
+		p.ReleaseCondition(c.Right)
+		p.ors.put(c)
+	case *ForwardComparatorCondition:
+		p.forwards.put(c)
+	case *BackwardComparatorCondition:
+		p.backwards.put(c)
+	case *EitherComparatorCondition:
+		p.eithers.put(c)
+	case *QuantifiedComparatorCondition:
+		p.quantifieds.put(c)
+	case *IsNullCondition:
+		p.isNulls.put(c)
+	}
+}
+
+// newNode allocates the node from the pool if any.
+func newNode[T any](p *Pool, nodes func(*Pool) *nodePool[T]) *T {
+	if p == nil {
+		return new(T)
+	}
+	return nodes(p).get()
+}
+
+func (p *Pool) newQuery() *Query {
+	return newNode(p, func(p *Pool) *nodePool[Query] { return &p.queries })
+}
+
+func (p *Pool) newAggregationQuery() *AggregationQuery {
+	return newNode(p, func(p *Pool) *nodePool[AggregationQuery] { return &p.aggregationQueries })
+}
+
+func (p *Pool) newForwardComparatorCondition() *ForwardComparatorCondition {
+	return newNode(p, func(p *Pool) *nodePool[ForwardComparatorCondition] { return &p.forwards })
+}
+
+func (p *Pool) newBackwardComparatorCondition() *BackwardComparatorCondition {
+	return newNode(p, func(p *Pool) *nodePool[BackwardComparatorCondition] { return &p.backwards })
+}
+
+func (p *Pool) newEitherComparatorCondition() *EitherComparatorCondition {
+	return newNode(p, func(p *Pool) *nodePool[EitherComparatorCondition] { return &p.eithers })
+}
+
+func (p *Pool) newQuantifiedComparatorCondition() *QuantifiedComparatorCondition {
+	return newNode(p, func(p *Pool) *nodePool[QuantifiedComparatorCondition] { return &p.quantifieds })
+}
+
+func (p *Pool) newIsNullCondition() *IsNullCondition {
+	return newNode(p, func(p *Pool) *nodePool[IsNullCondition] { return &p.isNulls })
+}
+
+func (p *Pool) newAndCompoundCondition() *AndCompoundCondition {
+	return newNode(p, func(p *Pool) *nodePool[AndCompoundCondition] { return &p.ands })
+}
+
+func (p *Pool) newOrCompoundCondition() *OrCompoundCondition {
+	return newNode(p, func(p *Pool) *nodePool[OrCompoundCondition] { return &p.ors })
+}
+
+// nodePool is the typed sync.Pool of the nodes.
+type nodePool[T any] struct {
+	pool sync.Pool
+}
+
+func (p *nodePool[T]) get() *T {
+	if v, ok := p.pool.Get().(*T); ok {
+		return v
+	}
+	return new(T)
+}
+
+func (p *nodePool[T]) put(v *T) {
+	var zero T
+	*v = zero
+	p.pool.Put(v)
+}
@@ -0,0 +1,107 @@
+package gqlparser_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	sources := []string{
+		"SELECT a FROM Kind WHERE a = 1 AND (b IS NULL OR 2 IN c) AND d CONTAINS 'x' ORDER BY a DESC LIMIT 10",
+		"SELECT * FROM Other WHERE __key__ HAS ANCESTOR KEY(Parent, 'foo')",
+		"SELECT * FROM Empty",
+	}
+	wants := make([]*gqlparser.Query, len(sources))
+	for i, source := range sources {
+		want, err := gqlparser.ParseQuery(gqlparser.NewLexer(source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		wants[i] = want
+	}
+
+	var pool gqlparser.Pool
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				got, err := gqlparser.ParseQuery(gqlparser.NewLexer(sources[i%len(sources)]), gqlparser.WithPool(&pool))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if diff := cmp.Diff(wants[i%len(sources)], got); diff != "" {
+					t.Errorf("(-want, +got)\n%s", diff)
+				}
+				pool.Release(got)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestPool_Quantified(t *testing.T) {
+	t.Parallel()
+
+	dialect, err := gqlparser.NewDialect(gqlparser.WithContainsQuantifiers())
+	if err != nil {
+		t.Fatal(err)
+	}
+	const source = "SELECT * FROM Kind WHERE tags CONTAINS ANY ARRAY('a', 'b') AND tags CONTAINS ALL ARRAY('c')"
+	want, err := gqlparser.ParseQuery(gqlparser.NewLexer(source), gqlparser.WithDialect(dialect))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pool gqlparser.Pool
+	for i := 0; i < 3; i++ {
+		got, err := gqlparser.ParseQuery(gqlparser.NewLexer(source), gqlparser.WithDialect(dialect), gqlparser.WithPool(&pool))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+		quantified := got.Where.(*gqlparser.AndCompoundCondition).Right
+		pool.Release(got)
+
+		// the released nodes are cleared
+		if diff := cmp.Diff(&gqlparser.QuantifiedComparatorCondition{}, quantified); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+	}
+}
+
+func TestPool_ReleaseAggregationQuery(t *testing.T) {
+	t.Parallel()
+
+	const source = "AGGREGATE COUNT(*) OVER (SELECT * FROM Kind WHERE a = 1 OR b = 2)"
+	want, err := gqlparser.ParseAggregationQuery(gqlparser.NewLexer(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pool gqlparser.Pool
+	for i := 0; i < 3; i++ {
+		got, err := gqlparser.ParseAggregationQuery(gqlparser.NewLexer(source), gqlparser.WithPool(&pool))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+		pool.ReleaseAggregationQuery(got)
+
+		// the released nodes are cleared
+		if got.Where != nil || got.Aggregations != nil {
+			t.Errorf("released query = %+v, want zero", got)
+		}
+	}
+}
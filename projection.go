@@ -0,0 +1,32 @@
+package gqlparser
+
+// ProjectionItem is the projected property with the metadata for each column.
+type ProjectionItem struct {
+	Property Property
+	// Alias is the alias by AS. e.g. SELECT a AS x FROM Kind
+	Alias string
+	// KeyProjection is true if the property is the special property __key__.
+	KeyProjection bool
+	// Span is the span of the property including the alias. It's zero unless parsed with WithProjectionSpans.
+	Span Span
+}
+
+// Projection returns the projected properties with the aliases and the spans.
+// It returns nil for the full projection. e.g. SELECT * FROM Kind
+func (q *Query) Projection() []ProjectionItem {
+	if len(q.Properties) == 0 {
+		return nil
+	}
+
+	items := make([]ProjectionItem, len(q.Properties))
+	for i, p := range q.Properties {
+		items[i] = ProjectionItem{Property: p, KeyProjection: p == keyProperty}
+		if i < len(q.Aliases) {
+			items[i].Alias = q.Aliases[i]
+		}
+		if i < len(q.ProjectionSpans) {
+			items[i].Span = q.ProjectionSpans[i]
+		}
+	}
+	return items
+}
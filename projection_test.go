@@ -0,0 +1,65 @@
+package gqlparser_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestQuery_Projection(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		source string
+		opts   []gqlparser.ParseOption
+		want   []gqlparser.ProjectionItem
+	}{
+		{
+			name:   "FullProjection",
+			source: "SELECT * FROM Kind",
+			opts:   []gqlparser.ParseOption{gqlparser.WithProjectionSpans()},
+			want:   nil,
+		},
+		{
+			name:   "WithoutSpans",
+			source: "SELECT __key__ AS x FROM Kind",
+			want: []gqlparser.ProjectionItem{
+				{Property: "__key__", Alias: "x", KeyProjection: true},
+			},
+		},
+		{
+			name:   "WithSpans",
+			source: "SELECT DISTINCT d, `a.b` AS `x y`,c FROM Kind",
+			opts:   []gqlparser.ParseOption{gqlparser.WithProjectionSpans()},
+			want: []gqlparser.ProjectionItem{
+				{Property: "d", Span: gqlparser.Span{Start: 16, End: 17}},
+				{Property: "a.b", Alias: "x y", Span: gqlparser.Span{Start: 19, End: 33}},
+				{Property: "c", Span: gqlparser.Span{Start: 34, End: 35}},
+			},
+		},
+		{
+			name:   "Binding",
+			source: "SELECT @prop AS x FROM Kind",
+			opts:   []gqlparser.ParseOption{gqlparser.WithProjectionSpans(), gqlparser.WithTemplatePlaceholders()},
+			want: []gqlparser.ProjectionItem{
+				{Property: "", Alias: "x", Span: gqlparser.Span{Start: 7, End: 17}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source), tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, query.Projection()); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
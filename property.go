@@ -0,0 +1,75 @@
+package gqlparser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidProperty = errors.New("invalid property")
+
+// reservedProperties are the special properties which can be used in the queries.
+var reservedProperties = map[Property]struct{}{
+	keyProperty: {},
+}
+
+// PropertyError is the error of the property path. It wraps ErrInvalidProperty.
+type PropertyError struct {
+	Property Property
+	Reason   string
+}
+
+func (e *PropertyError) Error() string {
+	return fmt.Sprintf("%s: %q: %s", ErrInvalidProperty, string(e.Property), e.Reason)
+}
+
+func (e *PropertyError) Unwrap() error {
+	return ErrInvalidProperty
+}
+
+// Validate checks the property path by the rules of Cloud Datastore. The path is split by the dots into the segments.
+//   - The path must not be empty, and must not have the leading, the trailing or the consecutive dots.
+//   - The path must be at most 20 segments.
+//   - Each segment must be at most 1500 bytes.
+//   - The segments must not start with "__" unless the path is the special property. e.g. __key__
+//
+// The violation is reported as PropertyError.
+func (p Property) Validate() error {
+	if reason := validatePropertyPath(p); reason != "" {
+		return &PropertyError{Property: p, Reason: reason}
+	}
+	return nil
+}
+
+func validatePropertyPath(p Property) string {
+	if p == "" {
+		return "empty property"
+	}
+	if _, ok := reservedProperties[p]; ok {
+		return ""
+	}
+
+	path := string(p)
+	switch {
+	case strings.HasPrefix(path, "."):
+		return "leading dot"
+	case strings.HasSuffix(path, "."):
+		return "trailing dot"
+	}
+
+	segments := strings.Split(path, ".")
+	if len(segments) > MaxPropertyPathDepth {
+		return "too deep path"
+	}
+	for _, segment := range segments {
+		switch {
+		case segment == "":
+			return "empty segment"
+		case len(segment) > MaxPropertyNameLength:
+			return "too long segment"
+		case strings.HasPrefix(segment, "__"):
+			return "reserved segment"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestPropertyValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		property   gqlparser.Property
+		wantReason string
+	}{
+		{name: "Simple", property: "a"},
+		{name: "Nested", property: "a.b.c"},
+		{name: "Key", property: "__key__"},
+		{name: "UnderscoreInside", property: "a_.b__"},
+		{name: "MaxDepth", property: gqlparser.Property(strings.Repeat("a.", 19) + "a")},
+		{name: "MaxLength", property: gqlparser.Property(strings.Repeat("a", 1500))},
+		{name: "Empty", property: "", wantReason: "empty property"},
+		{name: "LeadingDot", property: ".a", wantReason: "leading dot"},
+		{name: "TrailingDot", property: "a.", wantReason: "trailing dot"},
+		{name: "EmptySegment", property: "a..b", wantReason: "empty segment"},
+		{name: "TooDeep", property: gqlparser.Property(strings.Repeat("a.", 20) + "a"), wantReason: "too deep path"},
+		{name: "TooLong", property: gqlparser.Property("a." + strings.Repeat("a", 1501)), wantReason: "too long segment"},
+		{name: "Reserved", property: "__name__", wantReason: "reserved segment"},
+		{name: "ReservedSegment", property: "a.__key__", wantReason: "reserved segment"},
+		{name: "DoubleUnderscore", property: "__a", wantReason: "reserved segment"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.property.Validate()
+			if tt.wantReason == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+
+			var got *gqlparser.PropertyError
+			if !errors.As(err, &got) {
+				t.Fatalf("Validate() error = %v, want %T", err, got)
+			}
+			if got.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", got.Reason, tt.wantReason)
+			}
+			if !errors.Is(err, gqlparser.ErrInvalidProperty) {
+				t.Errorf("Validate() error = %v, want %v", err, gqlparser.ErrInvalidProperty)
+			}
+		})
+	}
+}
+
+func TestQueryValidate_Property(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		source  string
+		wantErr bool
+	}{
+		{source: "SELECT a.b FROM Kind WHERE __key__ = KEY(Kind, 1) ORDER BY c.d", wantErr: false},
+		{source: "SELECT __a FROM Kind", wantErr: true},
+		{source: "SELECT * FROM Kind WHERE `a..b` = 1", wantErr: true},
+		{source: "SELECT * FROM Kind WHERE `a.` IS NULL", wantErr: true},
+		{source: "SELECT * FROM Kind ORDER BY `.a`", wantErr: true},
+		{source: "SELECT DISTINCT ON (__b__) a FROM Kind", wantErr: true},
+	}
+	for _, tt := range tests {
+		query, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.source, err)
+		}
+		if err := query.Validate(nil); errors.Is(err, gqlparser.ErrInvalidProperty) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.source, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateCondition_Property(t *testing.T) {
+	t.Parallel()
+
+	err := gqlparser.ValidateCondition(gqlparser.Eq("a..b", 1))
+	if !errors.Is(err, gqlparser.ErrInvalidCondition) || !errors.Is(err, gqlparser.ErrInvalidProperty) {
+		t.Errorf("ValidateCondition() error = %v, want %v and %v", err, gqlparser.ErrInvalidCondition, gqlparser.ErrInvalidProperty)
+	}
+}
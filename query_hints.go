@@ -0,0 +1,174 @@
+package gqlparser
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidQueryHints = errors.New("invalid query hints")
+
+// QueryHints are the directives for the execution layers written as the comment following SELECT or AGGREGATE.
+// e.g. SELECT /*+ timeout=5s, read_consistency=eventual, max_rows=100 */ * FROM Kind
+// The directives are the comma separated pairs of the name and the value. The names are case-insensitive.
+// The parser doesn't apply them to the query, and the other comments are ignored as the whitespaces.
+type QueryHints struct {
+	// Timeout is the timeout of the query by timeout. e.g. timeout=5s
+	Timeout time.Duration
+	// ReadConsistency is the read consistency of the query by read_consistency. e.g. read_consistency=eventual
+	ReadConsistency ReadConsistency
+	// MaxRows is the max number of the entities to be read by max_rows. e.g. max_rows=100
+	MaxRows int64
+	// Extra are the other directives by the lowercased names to be honored by the execution layers.
+	Extra map[string]string
+}
+
+// ReadConsistency is the read consistency of the query hints.
+type ReadConsistency string
+
+const (
+	EventualReadConsistency ReadConsistency = "eventual"
+	StrongReadConsistency   ReadConsistency = "strong"
+)
+
+// String returns the directives in the comment without the delimiters. e.g. timeout=5s, max_rows=100
+// The known directives are written first, and the others are ordered by the names.
+func (h *QueryHints) String() string {
+	var directives []string
+	if h.Timeout != 0 {
+		directives = append(directives, "timeout="+h.Timeout.String())
+	}
+	if h.ReadConsistency != "" {
+		directives = append(directives, "read_consistency="+string(h.ReadConsistency))
+	}
+	if h.MaxRows != 0 {
+		directives = append(directives, "max_rows="+strconv.FormatInt(h.MaxRows, 10))
+	}
+	names := make([]string, 0, len(h.Extra))
+	for name := range h.Extra {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		directives = append(directives, name+"="+h.Extra[name])
+	}
+	return strings.Join(directives, ", ")
+}
+
+// parseQueryHints parses the directives in the hint comment without the delimiters.
+func parseQueryHints(s string) (*QueryHints, error) {
+	hints := &QueryHints{}
+	seen := map[string]struct{}{}
+	for _, directive := range strings.Split(s, ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(directive, "=")
+		name, value = strings.ToLower(strings.TrimSpace(name)), strings.TrimSpace(value)
+		if !ok || !isPlainName(name) || value == "" {
+			return nil, fmt.Errorf("%w: malformed directive %q", ErrInvalidQueryHints, directive)
+		}
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("%w: duplicate directive %q", ErrInvalidQueryHints, name)
+		}
+		seen[name] = struct{}{}
+
+		switch name {
+		case "timeout":
+			timeout, err := time.ParseDuration(value)
+			if err != nil || timeout <= 0 {
+				return nil, fmt.Errorf("%w: timeout must be the positive duration but got %q", ErrInvalidQueryHints, value)
+			}
+			hints.Timeout = timeout
+		case "read_consistency":
+			switch consistency := ReadConsistency(strings.ToLower(value)); consistency {
+			case EventualReadConsistency, StrongReadConsistency:
+				hints.ReadConsistency = consistency
+			default:
+				return nil, fmt.Errorf("%w: read_consistency must be eventual or strong but got %q", ErrInvalidQueryHints, value)
+			}
+		case "max_rows":
+			maxRows, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || maxRows <= 0 {
+				return nil, fmt.Errorf("%w: max_rows must be the positive integer but got %q", ErrInvalidQueryHints, value)
+			}
+			hints.MaxRows = maxRows
+		default:
+			if hints.Extra == nil {
+				hints.Extra = map[string]string{}
+			}
+			hints.Extra[name] = value
+		}
+	}
+	return hints, nil
+}
+
+// validate reports the directives that cannot be written in the comment to be parsed again.
+func (h *QueryHints) validate() error {
+	if h.Timeout < 0 || h.MaxRows < 0 {
+		return fmt.Errorf("%w: negative timeout or max_rows", ErrInvalidQueryHints)
+	}
+	switch h.ReadConsistency {
+	case "", EventualReadConsistency, StrongReadConsistency:
+	default:
+		return fmt.Errorf("%w: unknown read_consistency %q", ErrInvalidQueryHints, h.ReadConsistency)
+	}
+	for name, value := range h.Extra {
+		if !isPlainName(name) || name != strings.ToLower(name) {
+			return fmt.Errorf("%w: invalid directive name %q", ErrInvalidQueryHints, name)
+		}
+		if value = strings.TrimSpace(value); value == "" || strings.Contains(value, ",") || strings.Contains(value, "*/") {
+			return fmt.Errorf("%w: invalid value %q of %s", ErrInvalidQueryHints, value, name)
+		}
+	}
+	return nil
+}
+
+// isPlainName reports whether the name consists of the ASCII letters, the digits and the underscores not beginning with the digit.
+func isPlainName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if c := name[i] | 0x20; !(c >= 'a' && c <= 'z' || name[i] == '_' || i != 0 && name[i] >= '0' && name[i] <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// acceptQueryHints accepts the whitespaces following SELECT or AGGREGATE, and parses the hint comment in them if any.
+// The hint comment begins with "/*+", and only one of them is accepted in the query.
+// The hints are set to the query of the state.
+var acceptQueryHints = acceptSingleToken(func(st *parseState, token *WhitespaceToken) error {
+	for rest := token.Content; ; {
+		begin := strings.Index(rest, "/*")
+		if begin < 0 {
+			return nil
+		}
+		end := strings.Index(rest[begin+2:], "*/")
+		if end < 0 {
+			// the lexer rejects it, but the other token sources may not
+			return &SyntaxError{Token: token, Reason: "unterminated comment"}
+		}
+		end += begin + 2
+		comment := rest[begin+2 : end]
+		rest = rest[end+2:]
+		if !strings.HasPrefix(comment, "+") {
+			continue
+		}
+
+		if st.query.Hints != nil {
+			return &SyntaxError{Token: token, Reason: "duplicate query hints"}
+		}
+		h, err := parseQueryHints(comment[1:])
+		if err != nil {
+			return &SyntaxError{Token: token, Reason: "invalid query hints", Cause: err}
+		}
+		st.query.Hints = h
+	}
+})
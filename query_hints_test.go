@@ -0,0 +1,167 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestQueryHints(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		source  string
+		want    *gqlparser.QueryHints
+		wantErr string
+	}{
+		{
+			name:   "Known",
+			source: "SELECT /*+ timeout=5s, READ_CONSISTENCY=Eventual, max_rows=100 */ * FROM Kind",
+			want:   &gqlparser.QueryHints{Timeout: 5 * time.Second, ReadConsistency: gqlparser.EventualReadConsistency, MaxRows: 100},
+		},
+		{
+			name:   "Extra",
+			source: "SELECT/*+ priority = low,, */a FROM Kind",
+			want:   &gqlparser.QueryHints{Extra: map[string]string{"priority": "low"}},
+		},
+		{
+			name:   "Empty",
+			source: "SELECT /*+*/ * FROM Kind",
+			want:   &gqlparser.QueryHints{},
+		},
+		{
+			name:   "Comments",
+			source: "SELECT /* not a hint */ /*+ timeout=1m */ * FROM Kind /*+ max_rows=1 */",
+			want:   &gqlparser.QueryHints{Timeout: time.Minute},
+		},
+		{
+			name:   "None",
+			source: "SELECT /* timeout=1m */ * FROM Kind",
+		},
+		{
+			name:    "Duplicate",
+			source:  "SELECT /*+ timeout=1s */ /*+ timeout=1s */ * FROM Kind",
+			wantErr: "unexpected token:  /*+ timeout=1s */ /*+ timeout=1s */  at 6 (duplicate query hints)",
+		},
+		{
+			name:    "DuplicateDirective",
+			source:  "SELECT /*+ a=1, A=2 */ * FROM Kind",
+			wantErr: `unexpected token:  /*+ a=1, A=2 */  at 6 (invalid query hints) (invalid query hints: duplicate directive "a")`,
+		},
+		{
+			name:    "Malformed",
+			source:  "SELECT /*+ timeout */ * FROM Kind",
+			wantErr: `unexpected token:  /*+ timeout */  at 6 (invalid query hints) (invalid query hints: malformed directive "timeout")`,
+		},
+		{
+			name:    "InvalidTimeout",
+			source:  "SELECT /*+ timeout=-1s */ * FROM Kind",
+			wantErr: `unexpected token:  /*+ timeout=-1s */  at 6 (invalid query hints) (invalid query hints: timeout must be the positive duration but got "-1s")`,
+		},
+		{
+			name:    "InvalidReadConsistency",
+			source:  "SELECT /*+ read_consistency=weak */ * FROM Kind",
+			wantErr: `unexpected token:  /*+ read_consistency=weak */  at 6 (invalid query hints) (invalid query hints: read_consistency must be eventual or strong but got "weak")`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ParseQuery() error = %v, want %q", err, tt.wantErr)
+				}
+				if !errors.Is(err, gqlparser.ErrInvalidQueryHints) && tt.name != "Duplicate" {
+					t.Errorf("ParseQuery() error = %v, want %v", err, gqlparser.ErrInvalidQueryHints)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got.Hints); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQueryHints_UnterminatedComment(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan gqlparser.Token, 3)
+	ch <- &gqlparser.KeywordToken{Name: "SELECT", RawContent: "SELECT", Position: 0}
+	ch <- &gqlparser.WhitespaceToken{Content: " /*+ timeout=1s ", Position: 6}
+	ch <- &gqlparser.WildcardToken{Position: 22}
+	close(ch)
+
+	_, err := gqlparser.ParseQuery(gqlparser.NewChanTokenSource(ch))
+	if !errors.Is(err, gqlparser.ErrUnexpectedToken) {
+		t.Errorf("ParseQuery() error = %v, want %v", err, gqlparser.ErrUnexpectedToken)
+	}
+}
+
+func TestQueryHints_AggregationQuery(t *testing.T) {
+	t.Parallel()
+
+	want := &gqlparser.QueryHints{Timeout: 10 * time.Second}
+	for _, source := range []string{
+		"SELECT /*+ timeout=10s */ COUNT(*) FROM Kind",
+		"AGGREGATE /*+ timeout=10s */ COUNT(*) OVER (SELECT * FROM Kind)",
+		"AGGREGATE COUNT(*) OVER (SELECT /*+ timeout=10s */ * FROM Kind)",
+	} {
+		got, err := gqlparser.ParseAggregationQuery(gqlparser.NewLexer(source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got.Hints); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+	}
+
+	if _, err := gqlparser.ParseAggregationQuery(gqlparser.NewLexer("AGGREGATE /*+ a=1 */ COUNT(*) OVER (SELECT /*+ b=1 */ * FROM Kind)")); err == nil {
+		t.Error("ParseAggregationQuery() should fail")
+	}
+}
+
+func TestQueryHints_Format(t *testing.T) {
+	t.Parallel()
+
+	source := "SELECT /*+ timeout=1m30s, read_consistency=strong, max_rows=10, a=1, b=x */ * FROM Kind"
+	query, err := gqlparser.ParseQuery(gqlparser.NewLexer(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := gqlparser.FormatQuery(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != source {
+		t.Errorf("FormatQuery() = %q, want %q", got, source)
+	}
+
+	// the hints are kept by the encodings
+	sexpr, err := gqlparser.EncodeSExpr(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := gqlparser.DecodeSExpr(sexpr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(query.Hints, decoded.(*gqlparser.Query).Hints); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	query.Hints = &gqlparser.QueryHints{Extra: map[string]string{"a": "*/"}}
+	if _, err := gqlparser.FormatQuery(query); !errors.Is(err, gqlparser.ErrInvalidQueryHints) {
+		t.Errorf("FormatQuery() error = %v, want %v", err, gqlparser.ErrInvalidQueryHints)
+	}
+}
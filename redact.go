@@ -0,0 +1,222 @@
+package gqlparser
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+)
+
+// redactedStringLiteral is the placeholder of the string literals in the redacted query.
+const redactedStringLiteral = "'?'"
+
+// WithRedactedSource attaches the query with the string literals replaced by placeholders to ParseError.
+// The rest of the tokens are read from the token source on failure to redact the whole query,
+// and the malformed parts are skipped if the token source is the Lexer.
+// The logged error message has the placeholders too, and the causes of the errors are dropped from it.
+// e.g. SELECT * FROM Kind WHERE email = 'alice@example.com' -> SELECT * FROM Kind WHERE email = '?'
+func WithRedactedSource() ParseOption {
+	return func(o *parseOptions) {
+		o.redactSource = true
+	}
+}
+
+// collectingTokenSource collects the tokens read from the source in the order of reading to rebuild the query.
+// The unread tokens are dropped to be collected again by the following Read.
+type collectingTokenSource struct {
+	TokenSource
+	tokens []Token
+}
+
+func (ts *collectingTokenSource) Read() (Token, error) {
+	token, err := ts.TokenSource.Read()
+	if err == nil && token != nil {
+		ts.tokens = append(ts.tokens, token)
+	}
+	return token, err
+}
+
+func (ts *collectingTokenSource) Unread(token Token) {
+	if n := len(ts.tokens); n != 0 && ts.tokens[n-1] == token {
+		ts.tokens = ts.tokens[:n-1]
+	}
+	ts.TokenSource.Unread(token)
+}
+
+// startRedaction collects the tokens to redact the source on failure if WithRedactedSource is given.
+// The returned function attaches the redacted source to the ParseError.
+func (o *parseOptions) startRedaction(ts TokenSource) (TokenSource, func(error) error) {
+	if !o.redactSource {
+		return ts, func(err error) error { return err }
+	}
+
+	collector := &collectingTokenSource{TokenSource: ts}
+	return collector, func(err error) error {
+		parseErr, ok := err.(*ParseError)
+		if !ok {
+			return err
+		}
+
+		// read the rest of the query. the errors are ignored because the parsing has failed already,
+		// but the malformed parts are skipped to redact the rest if the source is the Lexer.
+		var sb strings.Builder
+		lexer, _ := lexerOf(ts)
+		for collector.Next() {
+			_, err := collector.Read()
+			if err == nil {
+				continue
+			}
+			var lexErr *LexError
+			if lexer == nil || !errors.As(err, &lexErr) {
+				break
+			}
+			redacted, ok := lexer.redactMalformed(lexErr)
+			collector.tokens = append(collector.tokens, &SymbolToken{Content: redacted, Position: lexErr.Position})
+			if !ok {
+				break
+			}
+		}
+
+		for _, token := range collector.tokens {
+			sb.WriteString(redactedContent(token))
+		}
+		parseErr.RedactedSource = sb.String()
+		parseErr.redactedMessage = redactError(parseErr.Err)
+		return parseErr
+	}
+}
+
+// lexerOf returns the Lexer under the token sources wrapped by the parser.
+func lexerOf(ts TokenSource) (*Lexer, bool) {
+	for {
+		switch s := ts.(type) {
+		case *Lexer:
+			return s, true
+		case *countingTokenSource:
+			ts = s.TokenSource
+		default:
+			return nil, false
+		}
+	}
+}
+
+// redactedContent returns the content of the token with the string literal replaced by the placeholder.
+func redactedContent(token Token) string {
+	if s, ok := token.(*StringToken); ok && s.Quote != '`' {
+		return redactedStringLiteral
+	}
+	return token.GetContent()
+}
+
+// redactError formats the error of the parser with the string literals in the tokens replaced by the placeholders.
+// The causes are dropped because they may echo the literals without the quotes. e.g. parsing time "alice@example.com"
+// The other errors are reported by the innermost ones not to format the values wrapped by them.
+func redactError(err error) string {
+	switch e := err.(type) {
+	case *ClauseError:
+		return redactError(e.Err) + " in " + e.Clause + " clause"
+	case *SyntaxError:
+		return e.format(redactedContent, nil)
+	case *LexError:
+		return e.format(nil)
+	case *UnexpectedEOFError:
+		return e.format(redactedContent)
+	case *DuplicateClauseError, *LimitViolationError:
+		return e.Error()
+	}
+
+	for {
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		case interface{ Unwrap() []error }:
+			err = e.Unwrap()[0]
+		default:
+			return err.Error()
+		}
+	}
+}
+
+// LogValue implements slog.LogValuer. The error message and the query are redacted if WithRedactedSource is given.
+func (e *ParseError) LogValue() slog.Value {
+	if e.RedactedSource == "" {
+		return slog.StringValue(e.Error())
+	}
+	return slog.GroupValue(
+		slog.String("error", e.redactedMessage),
+		slog.String("query", e.RedactedSource),
+	)
+}
+
+// Redact returns the copy of the query with every literal value in the conditions replaced by the indexed bindings.
+// The structure of the query is preserved: the arrays are redacted element by element, and the NULLs and the
+// existing bindings are kept. The new bindings are numbered after the largest existing index.
+// e.g. SELECT * FROM Kind WHERE a = 'x' AND b IN ARRAY(1, @1) -> SELECT * FROM Kind WHERE a = @2 AND b IN ARRAY(@3, @1)
+func Redact(q *Query) *Query {
+	redacted := *q
+	redacted.Properties = append([]Property(nil), q.Properties...)
+	redacted.Aliases = append([]string(nil), q.Aliases...)
+	redacted.ProjectionSpans = append([]Span(nil), q.ProjectionSpans...)
+	redacted.DistinctOn = append([]Property(nil), q.DistinctOn...)
+	redacted.GroupBy = append([]Property(nil), q.GroupBy...)
+	redacted.OrderBy = append([]OrderBy(nil), q.OrderBy...)
+	redacted.PropertyBindings = append([]*PropertyBinding(nil), q.PropertyBindings...)
+	if q.Limit != nil {
+		limit := *q.Limit
+		redacted.Limit = &limit
+	}
+	if q.Offset != nil {
+		offset := *q.Offset
+		redacted.Offset = &offset
+	}
+	if q.Where == nil {
+		return &redacted
+	}
+
+	var index int64
+	walkConditionValues(q.Where, func(v any) {
+		if b, ok := v.(*IndexedBinding); ok && b.Index > index {
+			index = b.Index
+		}
+	})
+	redacted.Where = redactCondition(q.Where, func() BindingVariable {
+		index++
+		return &IndexedBinding{Index: index}
+	})
+	return &redacted
+}
+
+func redactCondition(cond Condition, next func() BindingVariable) Condition {
+	switch c := cond.(type) {
+	case *AndCompoundCondition:
+		return &AndCompoundCondition{Left: redactCondition(c.Left, next), Right: redactCondition(c.Right, next)}
+	case *OrCompoundCondition:
+		return &OrCompoundCondition{Left: redactCondition(c.Left, next), Right: redactCondition(c.Right, next)}
+	case *IsNullCondition:
+		return &IsNullCondition{Property: c.Property}
+	case *ForwardComparatorCondition:
+		return &ForwardComparatorCondition{Comparator: c.Comparator, Property: c.Property, Value: redactValue(c.Value, next)}
+	case *BackwardComparatorCondition:
+		return &BackwardComparatorCondition{Comparator: c.Comparator, Property: c.Property, Value: redactValue(c.Value, next)}
+	case *EitherComparatorCondition:
+		return &EitherComparatorCondition{Comparator: c.Comparator, Property: c.Property, Value: redactValue(c.Value, next)}
+	case *QuantifiedComparatorCondition:
+		return &QuantifiedComparatorCondition{Comparator: c.Comparator, Property: c.Property, Value: redactValue(c.Value, next)}
+	default:
+		return cond
+	}
+}
+
+func redactValue(value any, next func() BindingVariable) any {
+	switch v := value.(type) {
+	case nil, BindingVariable:
+		return v
+	case []any:
+		values := make([]any, len(v))
+		for i, item := range v {
+			values[i] = redactValue(item, next)
+		}
+		return values
+	default:
+		return next()
+	}
+}
@@ -0,0 +1,177 @@
+package gqlparser_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestWithRedactedSource(t *testing.T) {
+	t.Parallel()
+
+	const source = "SELECT * FROM `Kind` WHERE email = 'alice@example.com' AND name = 'alice' 'bob' AND b = 1"
+	_, err := gqlparser.ParseQuery(gqlparser.NewLexer(source), gqlparser.WithRedactedSource())
+
+	var parseErr *gqlparser.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("error = %T, want %T", err, parseErr)
+	}
+	const want = "SELECT * FROM `Kind` WHERE email = '?' AND name = '?' '?' AND b = 1"
+	if parseErr.RedactedSource != want {
+		t.Errorf("RedactedSource = %q, want %q", parseErr.RedactedSource, want)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("parse failure", "err", parseErr)
+	if logged := buf.String(); strings.Contains(logged, "alice") || strings.Contains(logged, "bob") || !strings.Contains(logged, `"query":"SELECT`) {
+		t.Errorf("logged = %s", logged)
+	}
+
+	_, err = gqlparser.ParseQuery(gqlparser.NewLexer(source))
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("error = %T, want %T", err, parseErr)
+	}
+	if parseErr.RedactedSource != "" {
+		t.Errorf("RedactedSource = %q, want empty", parseErr.RedactedSource)
+	}
+}
+
+func TestWithRedactedSource_WithoutPositions(t *testing.T) {
+	t.Parallel()
+
+	// the tokens of the custom token sources may not have the positions
+	tokens := []gqlparser.Token{
+		&gqlparser.KeywordToken{Name: "SELECT", RawContent: "SELECT"},
+		&gqlparser.WhitespaceToken{Content: " "},
+		&gqlparser.WildcardToken{},
+		&gqlparser.WhitespaceToken{Content: " "},
+		&gqlparser.KeywordToken{Name: "FROM", RawContent: "FROM"},
+		&gqlparser.WhitespaceToken{Content: " "},
+		&gqlparser.SymbolToken{Content: "Kind"},
+		&gqlparser.WhitespaceToken{Content: " "},
+		&gqlparser.KeywordToken{Name: "WHERE", RawContent: "WHERE"},
+		&gqlparser.WhitespaceToken{Content: " "},
+		&gqlparser.SymbolToken{Content: "a"},
+		&gqlparser.WhitespaceToken{Content: " "},
+		&gqlparser.OperatorToken{Type: "=", RawContent: "="},
+		&gqlparser.WhitespaceToken{Content: " "},
+		&gqlparser.StringToken{Quote: '\'', Content: "alice", RawContent: "'alice'"},
+		&gqlparser.WhitespaceToken{Content: " "},
+		// the empty contents must not be replaced in the message
+		&gqlparser.StringToken{Quote: '\''},
+	}
+	ch := make(chan gqlparser.Token, len(tokens))
+	for _, token := range tokens {
+		ch <- token
+	}
+	close(ch)
+
+	_, err := gqlparser.ParseQuery(gqlparser.NewChanTokenSource(ch), gqlparser.WithRedactedSource())
+	var parseErr *gqlparser.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("error = %T, want %T", err, parseErr)
+	}
+	const want = "SELECT * FROM Kind WHERE a = '?' '?'"
+	if parseErr.RedactedSource != want {
+		t.Errorf("RedactedSource = %q, want %q", parseErr.RedactedSource, want)
+	}
+	const wantMessage = "unexpected token: '?' at 0 in WHERE clause"
+	if got := parseErr.LogValue().Group()[0].Value.String(); got != wantMessage {
+		t.Errorf("error = %q, want %q", got, wantMessage)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+
+	query, err := gqlparser.ParseQuery(gqlparser.NewLexer(
+		"SELECT a FROM Kind WHERE a = 'x' AND b IN ARRAY(1, @1) AND c = NULL AND d IS NULL AND __key__ HAS ANCESTOR KEY(Parent, 'p') ORDER BY a LIMIT 10",
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	original, err := gqlparser.ParseQuery(gqlparser.NewLexer(
+		"SELECT a FROM Kind WHERE a = 'x' AND b IN ARRAY(1, @1) AND c = NULL AND d IS NULL AND __key__ HAS ANCESTOR KEY(Parent, 'p') ORDER BY a LIMIT 10",
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := gqlparser.ParseQuery(gqlparser.NewLexer(
+		"SELECT a FROM Kind WHERE a = @2 AND b IN ARRAY(@3, @1) AND c = NULL AND d IS NULL AND __key__ HAS ANCESTOR @4 ORDER BY a LIMIT 10",
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(want, gqlparser.Redact(query)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(original, query); diff != "" {
+		t.Errorf("original is modified: (-want, +got)\n%s", diff)
+	}
+}
+
+func TestWithRedactedSource_Causes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		source      string
+		wantSource  string
+		wantMessage string
+	}{
+		{
+			name:        "DateTime",
+			source:      "SELECT * FROM Kind WHERE a = DATETIME('alice@example.com') AND b = 'bob'",
+			wantSource:  "SELECT * FROM Kind WHERE a = DATETIME('?') AND b = '?'",
+			wantMessage: "unexpected token: '?' at 38 in WHERE clause",
+		},
+		{
+			name:        "Blob",
+			source:      "SELECT * FROM Kind WHERE a = BLOB('alice@example.com')",
+			wantSource:  "SELECT * FROM Kind WHERE a = BLOB('?')",
+			wantMessage: "unexpected token: '?' at 34 in WHERE clause",
+		},
+		{
+			name:        "Key",
+			source:      "SELECT * FROM Kind WHERE __key__ = KEY(Kind, 'alice', 'bob')",
+			wantSource:  "SELECT * FROM Kind WHERE __key__ = KEY(Kind, '?', '?')",
+			wantMessage: "unexpected token: '?' at 54 in WHERE clause",
+		},
+		{
+			name:        "LexError",
+			source:      "SELECT * FROM Kind WHERE a = 'alice' AND b = 1.2.3 AND c = 'bob'",
+			wantSource:  "SELECT * FROM Kind WHERE a = '?' AND b = 1.2.3 AND c = '?'",
+			wantMessage: "unexpected token: 1.2.3 at 45 in WHERE clause",
+		},
+		{
+			name:        "UnterminatedString",
+			source:      "SELECT * FROM Kind WHERE a = 1 # AND c = 'bob",
+			wantSource:  "SELECT * FROM Kind WHERE a = 1 # AND c = '?'",
+			wantMessage: "unexpected token: # at 31 in WHERE clause",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source), gqlparser.WithRedactedSource())
+			var parseErr *gqlparser.ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("error = %T, want %T", err, parseErr)
+			}
+			if parseErr.RedactedSource != tt.wantSource {
+				t.Errorf("RedactedSource = %q, want %q", parseErr.RedactedSource, tt.wantSource)
+			}
+			if got := parseErr.LogValue().Group()[0].Value.String(); got != tt.wantMessage {
+				t.Errorf("error = %q, want %q (%v)", got, tt.wantMessage, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package gqlparser
+
+// ReferencedProperties returns the property paths used in the projection, DISTINCT ON, WHERE and ORDER BY clauses
+// in the order of appearance without duplicates. The special property __key__ is included if it's used.
+// The unbound property placeholders of the templates are not included.
+func (q *Query) ReferencedProperties() []Property {
+	var props []Property
+	seen := map[Property]struct{}{}
+	add := func(prop Property) {
+		if prop == "" {
+			return
+		}
+		if _, ok := seen[prop]; ok {
+			return
+		}
+		seen[prop] = struct{}{}
+		props = append(props, prop)
+	}
+
+	for _, prop := range q.Properties {
+		add(prop)
+	}
+	for _, prop := range q.DistinctOn {
+		add(prop)
+	}
+	if q.Where != nil {
+		walkConditionProperties(q.Where, func(prop string) {
+			add(Property(prop))
+		})
+	}
+	for _, orderBy := range q.OrderBy {
+		add(orderBy.Property)
+	}
+	return props
+}
+
+func walkConditionProperties(cond Condition, f func(string)) {
+	walkConditions(cond, func(leaf Condition) {
+		if property, _ := describeCondition(leaf); property != "" {
+			f(property)
+		}
+	})
+}
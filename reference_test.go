@@ -0,0 +1,48 @@
+package gqlparser_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestQueryReferencedProperties(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		source string
+		want   []gqlparser.Property
+	}{
+		{
+			name:   "FullProjection",
+			source: "SELECT * FROM Kind",
+			want:   nil,
+		},
+		{
+			name:   "AllClauses",
+			source: "SELECT DISTINCT ON (a) a, b.c FROM Kind WHERE d = 1 AND (1 IN e OR f IS NULL) AND tags CONTAINS 'x' AND b.c > 0 ORDER BY g DESC, a",
+			want:   []gqlparser.Property{"a", "b.c", "d", "e", "f", "tags", "g"},
+		},
+		{
+			name:   "Key",
+			source: "SELECT __key__ FROM Kind WHERE __key__ HAS ANCESTOR KEY(Parent, 1)",
+			want:   []gqlparser.Property{"__key__"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, query.ReferencedProperties()); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package gqlparser
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrInvalidQueryName = errors.New("invalid query name")
+	ErrDuplicateQuery   = errors.New("duplicate query")
+	ErrUnknownQuery     = errors.New("unknown query")
+)
+
+// queryRefPrefix is the prefix of the reference to the named query. e.g. @@activeUsers
+const queryRefPrefix = "@@"
+
+// Registry is the set of the named canned queries resolved by ParseRef. It's safe for concurrent use.
+// The zero value is ready to use.
+type Registry struct {
+	mu      sync.RWMutex
+	queries map[string]*registeredQuery
+}
+
+type registeredQuery struct {
+	source string
+	opts   []ParseOption
+}
+
+// Register registers the query or the aggregation query by the name. The name consists of the ASCII letters, the digits
+// and the underscores not beginning with the digit. The query is parsed with the options to report the errors early,
+// and it's parsed with them again by ParseRef.
+func (r *Registry) Register(name, source string, opts ...ParseOption) error {
+	if !isPlainName(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidQueryName, name)
+	}
+	if _, _, err := ParseQueryOrAggregationQuery(NewLexer(source), opts...); err != nil {
+		return fmt.Errorf("query %s: %w", name, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.queries[name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateQuery, name)
+	}
+	if r.queries == nil {
+		r.queries = map[string]*registeredQuery{}
+	}
+	r.queries[name] = &registeredQuery{source: source, opts: slices.Clone(opts)}
+	return nil
+}
+
+// Names returns the names of the registered queries in the order of the names.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.queries))
+	for name := range r.queries {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ParseRef parses the named query referenced as @@name, and binds the params to it.
+// Either the query or the aggregation query is returned as ParseQueryOrAggregationQuery. They're parsed for each call,
+// so the callers can modify them. The binding variables in the conditions and the template placeholders are resolved
+// by the params, and the missing values are reported at once as ParameterError. The nil params binds no values.
+// The cursors of LIMIT and OFFSET are bound to a Cursor or an integer added to the position as BuildQuery.
+func (r *Registry) ParseRef(ref string, params *BindingResolver) (*Query, *AggregationQuery, error) {
+	name, ok := strings.CutPrefix(ref, queryRefPrefix)
+	if !ok || !isPlainName(name) {
+		return nil, nil, fmt.Errorf("%w: %q", ErrInvalidQueryName, ref)
+	}
+
+	r.mu.RLock()
+	registered, ok := r.queries[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownQuery, name)
+	}
+
+	query, aggregationQuery, err := ParseQueryOrAggregationQuery(NewLexer(registered.source), registered.opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query %s: %w", name, err)
+	}
+	if params == nil {
+		params = &BindingResolver{}
+	}
+	if aggregationQuery != nil {
+		if err := bindQuery(&aggregationQuery.Query, aggregationQuery.Having, params); err != nil {
+			return nil, nil, fmt.Errorf("query %s: %w", name, err)
+		}
+		return nil, aggregationQuery, nil
+	}
+	if err := bindQuery(query, nil, params); err != nil {
+		return nil, nil, fmt.Errorf("query %s: %w", name, err)
+	}
+	return query, nil, nil
+}
+
+// bindQuery checks the variables in the query before binding them as bindCondition.
+func bindQuery(q *Query, having Condition, br *BindingResolver) error {
+	variables := conditionVariables(nil, q.Where)
+	variables = conditionVariables(variables, having)
+	if q.KindBinding != nil {
+		variables = append(variables, q.KindBinding.Variable)
+	}
+	for _, b := range q.PropertyBindings {
+		variables = append(variables, b.Variable)
+	}
+	positions := resultPositions(q)
+	for _, p := range positions {
+		if p.isBound() {
+			variables = append(variables, *p.cursor)
+		}
+	}
+	if err := br.checkVariables(variables); err != nil {
+		return err
+	}
+
+	for _, cond := range []Condition{q.Where, having} {
+		if cond == nil {
+			continue
+		}
+		if err := cond.bind(br); err != nil {
+			return err
+		}
+	}
+	for _, p := range positions {
+		if err := p.bind(br); err != nil {
+			return err
+		}
+	}
+	return q.BindTemplate(br)
+}
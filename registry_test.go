@@ -0,0 +1,172 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func newTestRegistry(t *testing.T) *gqlparser.Registry {
+	t.Helper()
+
+	var registry gqlparser.Registry
+	for name, source := range map[string]string{
+		"activeUsers": "SELECT * FROM User WHERE active = @active AND age >= @1",
+		"countByKind": "SELECT * FROM @kind",
+		"pagedUsers":  "SELECT * FROM User LIMIT @limit OFFSET @offset + 5",
+		"userCounts":  "AGGREGATE COUNT(*) AS c OVER (SELECT * FROM User WHERE team = @team)",
+	} {
+		var opts []gqlparser.ParseOption
+		if name == "countByKind" {
+			opts = append(opts, gqlparser.WithTemplatePlaceholders())
+		}
+		if err := registry.Register(name, source, opts...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &registry
+}
+
+func TestRegistry_ParseRef(t *testing.T) {
+	t.Parallel()
+
+	registry := newTestRegistry(t)
+	if diff := cmp.Diff([]string{"activeUsers", "countByKind", "pagedUsers", "userCounts"}, registry.Names()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	tests := []struct {
+		name    string
+		ref     string
+		params  *gqlparser.BindingResolver
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "Query",
+			ref:    "@@activeUsers",
+			params: &gqlparser.BindingResolver{Indexed: []any{int64(20)}, Named: map[string]any{"active": true}},
+			want:   "SELECT * FROM User WHERE active = TRUE AND age >= 20",
+		},
+		{
+			name:   "Template",
+			ref:    "@@countByKind",
+			params: &gqlparser.BindingResolver{Named: map[string]any{"kind": "Task"}, RejectUnused: true},
+			want:   "SELECT * FROM Task",
+		},
+		{
+			name:   "AggregationQuery",
+			ref:    "@@userCounts",
+			params: &gqlparser.BindingResolver{Named: map[string]any{"team": "a"}},
+			want:   "SELECT COUNT(*) AS c FROM User WHERE team = 'a'",
+		},
+		{
+			name:   "Positions",
+			ref:    "@@pagedUsers",
+			params: &gqlparser.BindingResolver{Named: map[string]any{"limit": gqlparser.Cursor("Cg0SB2tleS0xMDAY"), "offset": 10}},
+			want:   "SELECT * FROM User LIMIT CURSOR('Cg0SB2tleS0xMDAY') OFFSET 15",
+		},
+		{
+			name:    "MissingPosition",
+			ref:     "@@pagedUsers",
+			params:  &gqlparser.BindingResolver{Named: map[string]any{"limit": 10}},
+			wantErr: gqlparser.ErrBindValue,
+		},
+		{
+			name:    "InvalidPosition",
+			ref:     "@@pagedUsers",
+			params:  &gqlparser.BindingResolver{Named: map[string]any{"limit": "10", "offset": 0}},
+			wantErr: gqlparser.ErrBindTemplate,
+		},
+		{
+			name:    "Missing",
+			ref:     "@@activeUsers",
+			wantErr: gqlparser.ErrBindValue,
+		},
+		{
+			name:    "Unused",
+			ref:     "@@userCounts",
+			params:  &gqlparser.BindingResolver{Named: map[string]any{"team": "a", "x": 1}, RejectUnused: true},
+			wantErr: gqlparser.ErrUnusedBindValue,
+		},
+		{
+			name:    "Unknown",
+			ref:     "@@unknown",
+			wantErr: gqlparser.ErrUnknownQuery,
+		},
+		{
+			name:    "NoPrefix",
+			ref:     "activeUsers",
+			wantErr: gqlparser.ErrInvalidQueryName,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, aggregationQuery, err := registry.ParseRef(tt.ref, tt.params)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ParseRef() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got string
+			if query != nil {
+				got, err = gqlparser.FormatQuery(query)
+			} else {
+				got, err = gqlparser.FormatAggregationQuery(aggregationQuery)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRef() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	t.Parallel()
+
+	registry := newTestRegistry(t)
+	if err := registry.Register("activeUsers", "SELECT * FROM User"); !errors.Is(err, gqlparser.ErrDuplicateQuery) {
+		t.Errorf("Register() error = %v, want %v", err, gqlparser.ErrDuplicateQuery)
+	}
+	if err := registry.Register("1st", "SELECT * FROM User"); !errors.Is(err, gqlparser.ErrInvalidQueryName) {
+		t.Errorf("Register() error = %v, want %v", err, gqlparser.ErrInvalidQueryName)
+	}
+	if err := registry.Register("broken", "SELECT * FROM"); !errors.Is(err, gqlparser.ErrNoTokens) {
+		t.Errorf("Register() error = %v, want %v", err, gqlparser.ErrNoTokens)
+	}
+	if err := registry.Register("template", "SELECT * FROM @kind"); err == nil {
+		t.Error("Register() should fail without the template placeholders")
+	}
+}
+
+func TestRegistry_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	var registry gqlparser.Registry
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = registry.Register("q", "SELECT * FROM Kind WHERE a = @a")
+			if _, _, err := registry.ParseRef("@@q", &gqlparser.BindingResolver{Named: map[string]any{"a": 1}}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,97 @@
+package gqlparser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// RenderTokens reconstructs the source text from the tokens by concatenating them in order.
+// The raw contents of the tokens are used as they are if present, otherwise they are synthesized from the values:
+//   - The strings are quoted with the Quote (or ' if it's zero) by QuoteString.
+//   - The keywords, the operators, the booleans and the orders are rendered in the upper case.
+//   - The doubles are rendered with the decimal point to be lexed as the doubles again.
+//
+// The positions are ignored and no whitespaces are inserted, so the tokens must contain the WhitespaceTokens
+// between the words to be lexed into the same tokens again.
+func RenderTokens(tokens []Token) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		sb.WriteString(renderToken(token))
+	}
+	return sb.String()
+}
+
+func renderToken(token Token) string {
+	if content := token.GetContent(); content != "" {
+		return content
+	}
+
+	switch t := token.(type) {
+	case *StringToken:
+		quote := t.Quote
+		if quote == 0 {
+			quote = '\''
+		}
+		return QuoteString(t.Content, quote)
+	case *BooleanToken:
+		if t.Value {
+			return "TRUE"
+		}
+		return "FALSE"
+	case *OrderToken:
+		if t.Descending {
+			return "DESC"
+		}
+		return "ASC"
+	case *KeywordToken:
+		return t.Name
+	case *NumericToken:
+		if !t.Floating {
+			return strconv.FormatInt(t.Int64, 10)
+		}
+		return formatDouble(t.Float64)
+	default:
+		return ""
+	}
+}
+
+// NormalizeTokens fills the raw contents and the positions of the tokens in place as if they are lexed from
+// the source rendered by RenderTokens. It's useful to construct the tokens programmatically.
+// The existing raw contents are kept, and the positions are computed from the beginning of the tokens.
+func NormalizeTokens(tokens []Token) {
+	pos := 0
+	for _, token := range tokens {
+		content := renderToken(token)
+		switch t := token.(type) {
+		case *StringToken:
+			t.RawContent = content
+			t.Position = pos
+		case *OperatorToken:
+			// the lexer fills the raw content only for the keyword operators like AND
+			t.Position = pos
+		case *WildcardToken:
+			t.Position = pos
+		case *SemicolonToken:
+			t.Position = pos
+		case *BooleanToken:
+			t.RawContent = content
+			t.Position = pos
+		case *OrderToken:
+			t.RawContent = content
+			t.Position = pos
+		case *SymbolToken:
+			t.Position = pos
+		case *KeywordToken:
+			t.RawContent = content
+			t.Position = pos
+		case *NumericToken:
+			t.RawContent = content
+			t.Position = pos
+		case *BindingToken:
+			t.Position = pos
+		case *WhitespaceToken:
+			t.Position = pos
+		}
+		pos += len(content)
+	}
+}
@@ -0,0 +1,172 @@
+package gqlparser_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+	"github.com/karupanerura/gqlparser/gqltest"
+)
+
+func TestRenderTokens(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		tokens []gqlparser.Token
+		want   string
+	}{
+		{
+			name: "Synthesized",
+			tokens: []gqlparser.Token{
+				&gqlparser.KeywordToken{Name: "SELECT"},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.WildcardToken{},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.KeywordToken{Name: "FROM"},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.StringToken{Quote: '`', Content: "K`ind"},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.KeywordToken{Name: "WHERE"},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.SymbolToken{Content: "a"},
+				&gqlparser.OperatorToken{Type: "="},
+				&gqlparser.StringToken{Content: `it's \`},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.KeywordToken{Name: "AND"},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.SymbolToken{Content: "b"},
+				&gqlparser.OperatorToken{Type: "<"},
+				&gqlparser.NumericToken{Float64: 2, Floating: true},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.KeywordToken{Name: "AND"},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.SymbolToken{Content: "c"},
+				&gqlparser.OperatorToken{Type: "="},
+				&gqlparser.BooleanToken{Value: true},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.KeywordToken{Name: "ORDER"},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.KeywordToken{Name: "BY"},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.SymbolToken{Content: "a"},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.OrderToken{Descending: true},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.KeywordToken{Name: "LIMIT"},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.NumericToken{Int64: -1},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.KeywordToken{Name: "OFFSET"},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.BindingToken{Name: "offset"},
+			},
+			want: "SELECT * FROM `K\\`ind` WHERE a='it\\'s \\\\' AND b<2.0 AND c=TRUE ORDER BY a DESC LIMIT -1 OFFSET @offset",
+		},
+		{
+			name: "RawContent",
+			tokens: []gqlparser.Token{
+				&gqlparser.KeywordToken{Name: "SELECT", RawContent: "select"},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.SymbolToken{Content: "a"},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.KeywordToken{Name: "FROM", RawContent: "from"},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.StringToken{Quote: '"', Content: "Kind", RawContent: `"Kind"`},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.KeywordToken{Name: "LIMIT", RawContent: "limit"},
+				&gqlparser.WhitespaceToken{Content: " "},
+				&gqlparser.NumericToken{Int64: 10, RawContent: "0010"},
+			},
+			want: `select a from "Kind" limit 0010`,
+		},
+		{
+			name:   "Empty",
+			tokens: nil,
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.want, gqlparser.RenderTokens(tt.tokens)); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderTokensRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	sources := []string{
+		"SELECT * FROM Kind",
+		"select DISTINCT ON (a, b.c) a, b.c FROM `Kind` WHERE a = 'x' AND b.c IN ARRAY(1, 2.50, @v) ORDER BY a ASC LIMIT @1 OFFSET 10",
+		"SELECT __key__ FROM Kind WHERE __key__ HAS ANCESTOR KEY(Parent, \"p\\\"q\") AND d = true AND e != NULL",
+		"AGGREGATE COUNT(*) AS total OVER (SELECT * FROM Kind)",
+	}
+	for _, source := range sources {
+		source := source
+		t.Run(source, func(t *testing.T) {
+			t.Parallel()
+
+			tokens, err := gqltest.ReadAll(gqlparser.NewLexer(source))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(source, gqlparser.RenderTokens(tokens)); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNormalizeTokens(t *testing.T) {
+	t.Parallel()
+
+	tokens := []gqlparser.Token{
+		&gqlparser.KeywordToken{Name: "SELECT"},
+		&gqlparser.WhitespaceToken{Content: " "},
+		&gqlparser.WildcardToken{},
+		&gqlparser.WhitespaceToken{Content: " "},
+		&gqlparser.KeywordToken{Name: "FROM", RawContent: "from"},
+		&gqlparser.WhitespaceToken{Content: " "},
+		&gqlparser.SymbolToken{Content: "Kind"},
+		&gqlparser.WhitespaceToken{Content: " "},
+		&gqlparser.KeywordToken{Name: "WHERE"},
+		&gqlparser.WhitespaceToken{Content: " "},
+		&gqlparser.SymbolToken{Content: "a"},
+		&gqlparser.OperatorToken{Type: "="},
+		&gqlparser.StringToken{Quote: '\'', Content: "x"},
+	}
+	gqlparser.NormalizeTokens(tokens)
+
+	want := []gqlparser.Token{
+		&gqlparser.KeywordToken{Name: "SELECT", RawContent: "SELECT", Position: 0},
+		&gqlparser.WhitespaceToken{Content: " ", Position: 6},
+		&gqlparser.WildcardToken{Position: 7},
+		&gqlparser.WhitespaceToken{Content: " ", Position: 8},
+		&gqlparser.KeywordToken{Name: "FROM", RawContent: "from", Position: 9},
+		&gqlparser.WhitespaceToken{Content: " ", Position: 13},
+		&gqlparser.SymbolToken{Content: "Kind", Position: 14},
+		&gqlparser.WhitespaceToken{Content: " ", Position: 18},
+		&gqlparser.KeywordToken{Name: "WHERE", RawContent: "WHERE", Position: 19},
+		&gqlparser.WhitespaceToken{Content: " ", Position: 24},
+		&gqlparser.SymbolToken{Content: "a", Position: 25},
+		&gqlparser.OperatorToken{Type: "=", Position: 26},
+		&gqlparser.StringToken{Quote: '\'', Content: "x", RawContent: "'x'", Position: 27},
+	}
+	if diff := cmp.Diff(want, tokens); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	lexed, err := gqltest.ReadAll(gqlparser.NewLexer(gqlparser.RenderTokens(tokens)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(tokens, lexed); diff != "" {
+		t.Errorf("lexed tokens (-want, +got)\n%s", diff)
+	}
+}
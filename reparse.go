@@ -0,0 +1,134 @@
+package gqlparser
+
+import (
+	"errors"
+	"strings"
+)
+
+// ReparseRange parses the query edited from the old source for the editor integrations.
+// The edited span is the range in the old source replaced with the new text, so the new source must be
+// oldSrc[:edited.Start] + newText + oldSrc[edited.End:].
+//
+// If the edit is local to the condition of WHERE, only the new condition is lexed and parsed again,
+// and it's patched into the copy of the old query sharing the other clauses with it.
+// Otherwise the new source is parsed as a whole by ParseQuery. The old query must be parsed from the old source
+// with the same options, and it's never modified. The sources are lexed with the dialect and WithLexerOptions.
+func ReparseRange(oldAST *Query, oldSrc, newSrc string, edited Span, opts ...ParseOption) (*Query, error) {
+	o := newParseOptions(opts)
+	if oldAST != nil && oldAST.Where != nil {
+		if query, ok := reparseWhere(oldAST, oldSrc, newSrc, edited, o, opts); ok {
+			return query, nil
+		}
+	}
+	return ParseQuery(o.newLexer(newSrc), opts...)
+}
+
+// reparseWhere patches the condition of WHERE if the edit is local to it. It reports false to parse the query as a whole.
+func reparseWhere(oldAST *Query, oldSrc, newSrc string, edited Span, o *parseOptions, opts []ParseOption) (*Query, bool) {
+	delta := len(newSrc) - len(oldSrc)
+	if edited.Start < 0 || edited.Start > edited.End || edited.End > len(oldSrc) || edited.End+delta < edited.Start {
+		return nil, false
+	}
+	if oldSrc[:edited.Start] != newSrc[:edited.Start] || oldSrc[edited.End:] != newSrc[edited.End+delta:] {
+		return nil, false
+	}
+
+	span, ok := findWhereSpan(oldSrc, o)
+	if !ok || edited.Start < span.Start || edited.End > span.End {
+		return nil, false
+	}
+	body := newSrc[span.Start : span.End+delta]
+	if strings.TrimSpace(body) == "" || strings.Contains(body, ";") {
+		// the semicolon may end the query in the middle of the clauses
+		return nil, false
+	}
+
+	// lex only the new condition keeping the positions in the new source
+	lexer := o.newLexer(newSrc[:span.End+delta])
+	lexer.position = span.Start
+	cond, err := ParseCondition(lexer, opts...)
+	if err != nil {
+		return nil, false
+	}
+	query := *oldAST
+	query.Where = cond
+	return &query, true
+}
+
+// findWhereSpan finds the span of the condition of WHERE in the source from the first token to the last one.
+// It's lexed with the same options as the query to find the same tokens.
+func findWhereSpan(source string, o *parseOptions) (Span, bool) {
+	lexer := o.newLexer(source)
+	span := Span{Start: -1, End: -1}
+	depth := 0
+	inWhere, lastToken := false, false
+	for lexer.Next() {
+		token, err := lexer.Read()
+		if errors.Is(err, ErrEndOfToken) {
+			break
+		} else if err != nil {
+			return Span{}, false
+		}
+
+		if _, ok := token.(*WhitespaceToken); ok {
+			if lastToken {
+				span.End = token.GetPosition()
+				lastToken = false
+			}
+			continue
+		}
+		if symbol, ok := token.(*SymbolToken); ok && inWhere && depth == 0 && o.dialect.beginsClause(symbol) {
+			// GROUP BY and HAVING can't be told from the properties without parsing
+			return Span{}, false
+		}
+		if depth == 0 && isClauseToken(token) {
+			if inWhere {
+				if lastToken {
+					span.End = token.GetPosition()
+				}
+				return span, span.Start >= 0
+			}
+			if keyword, ok := token.(*KeywordToken); ok && keyword.Name == "WHERE" {
+				inWhere = true
+			}
+			continue
+		}
+		if op, ok := token.(*OperatorToken); ok {
+			switch op.Type {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			}
+		}
+		if inWhere {
+			if span.Start < 0 {
+				span.Start = token.GetPosition()
+			}
+			lastToken = true
+		}
+	}
+	if !inWhere {
+		return Span{}, false
+	}
+	if lastToken {
+		span.End = len(source)
+	}
+	return span, span.Start >= 0
+}
+
+// isClauseToken reports whether the token begins the clause of the query or ends the query.
+func isClauseToken(token Token) bool {
+	switch t := token.(type) {
+	case *KeywordToken:
+		switch t.Name {
+		case "SELECT", "FROM", "WHERE", "ORDER", "LIMIT", "OFFSET":
+			return true
+		}
+	case *SemicolonToken:
+		return true
+	case *OperatorToken:
+		return t.Type == ";"
+	}
+	return false
+}
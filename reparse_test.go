@@ -0,0 +1,165 @@
+package gqlparser_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestReparseRange(t *testing.T) {
+	t.Parallel()
+
+	const oldSrc = "SELECT a, b FROM Kind WHERE a = 1 AND (b > 2 OR c IN ARRAY(1, 2)) ORDER BY a LIMIT 10"
+	tests := []struct {
+		name        string
+		edited      gqlparser.Span
+		newText     string
+		incremental bool
+		wantErr     bool
+	}{
+		{
+			name:        "ReplaceValue",
+			edited:      gqlparser.Span{Start: 32, End: 33},
+			newText:     "100",
+			incremental: true,
+		},
+		{
+			name:        "AppendCondition",
+			edited:      gqlparser.Span{Start: 65, End: 65},
+			newText:     " AND d = @d",
+			incremental: true,
+		},
+		{
+			name:        "ReplaceCondition",
+			edited:      gqlparser.Span{Start: 28, End: 65},
+			newText:     "__key__ HAS ANCESTOR KEY(Parent, 'p')",
+			incremental: true,
+		},
+		{
+			name:    "EditOrderBy",
+			edited:  gqlparser.Span{Start: 75, End: 76},
+			newText: "b DESC",
+		},
+		{
+			name:    "EditWhereKeyword",
+			edited:  gqlparser.Span{Start: 22, End: 27},
+			newText: "WHERE x = 1 AND",
+		},
+		{
+			name:    "MoveClause",
+			edited:  gqlparser.Span{Start: 65, End: 65},
+			newText: " LIMIT 5",
+			wantErr: true,
+		},
+		{
+			name:    "Broken",
+			edited:  gqlparser.Span{Start: 34, End: 37},
+			newText: "OR OR",
+			wantErr: true,
+		},
+		{
+			name:    "Semicolon",
+			edited:  gqlparser.Span{Start: 33, End: 33},
+			newText: ";",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			oldAST, err := gqlparser.ParseQuery(gqlparser.NewLexer(oldSrc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			newSrc := oldSrc[:tt.edited.Start] + tt.newText + oldSrc[tt.edited.End:]
+			got, err := gqlparser.ReparseRange(oldAST, oldSrc, newSrc, tt.edited)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ReparseRange() should fail: %s", newSrc)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want, err := gqlparser.ParseQuery(gqlparser.NewLexer(newSrc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+			if incremental := &got.Properties[0] == &oldAST.Properties[0]; incremental != tt.incremental {
+				t.Errorf("incremental = %v, want %v", incremental, tt.incremental)
+			}
+		})
+	}
+}
+
+func TestReparseRange_Errors(t *testing.T) {
+	t.Parallel()
+
+	const oldSrc = "SELECT * FROM Kind WHERE a = 1"
+	oldAST, err := gqlparser.ParseQuery(gqlparser.NewLexer(oldSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the inconsistent span falls back to the full parse
+	newSrc := "SELECT * FROM Kind WHERE a = 2"
+	got, err := gqlparser.ReparseRange(oldAST, oldSrc, newSrc, gqlparser.Span{Start: 0, End: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := gqlparser.ParseQuery(gqlparser.NewLexer(newSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	// the errors are reported with the positions in the new source
+	_, err = gqlparser.ReparseRange(oldAST, oldSrc, "SELECT * FROM Kind WHERE a = = 1", gqlparser.Span{Start: 29, End: 29})
+	if err == nil || err.Error() != "unexpected token: = at 29 in WHERE clause" {
+		t.Errorf("ReparseRange() error = %v", err)
+	}
+	if oldAST.Where.(*gqlparser.EitherComparatorCondition).Value != int64(1) {
+		t.Error("ReparseRange() modified the old query")
+	}
+}
+
+func TestReparseRange_LexerOptions(t *testing.T) {
+	t.Parallel()
+
+	// the typographic quotes are lexed only with WithLenientCharacters
+	const oldSrc = "SELECT * FROM Kind WHERE a = ‘x’ ORDER BY a"
+	opts := []gqlparser.ParseOption{gqlparser.WithLexerOptions(gqlparser.WithLenientCharacters())}
+	oldAST, err := gqlparser.ParseQuery(gqlparser.NewLexer(oldSrc, gqlparser.WithLenientCharacters()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := strings.Index(oldSrc, "x")
+	newSrc := oldSrc[:start] + "y" + oldSrc[start+1:]
+	got, err := gqlparser.ReparseRange(oldAST, oldSrc, newSrc, gqlparser.Span{Start: start, End: start + 1}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := gqlparser.ParseQuery(gqlparser.NewLexer(newSrc, gqlparser.WithLenientCharacters()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	if _, err := gqlparser.ReparseRange(oldAST, oldSrc, newSrc, gqlparser.Span{Start: start, End: start + 1}); err == nil {
+		t.Error("ReparseRange() error = nil without the lexer options")
+	}
+}
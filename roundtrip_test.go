@@ -0,0 +1,123 @@
+package gqlparser_test
+
+import (
+	"encoding"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/karupanerura/gqlparser"
+	"github.com/karupanerura/gqlparser/benchmarks"
+)
+
+// roundTripSeeds are the seeds of FuzzRoundTrip covering the grammar in addition to the benchmark corpora.
+var roundTripSeeds = []string{
+	"SELECT __key__ FROM Kind",
+	"SELECT DISTINCT a FROM `Kind Name` WHERE `a b` != 'x' AND c IS NULL",
+	"SELECT * FROM Kind WHERE a IN ARRAY(1, 2) OR b NOT IN @list OR c CONTAINS 'x' OR 'y' IN d",
+	"SELECT * FROM Kind WHERE KEY(Kind, 1) HAS DESCENDANT __key__ AND a <= 1.5 ORDER BY a ASC",
+	"SELECT * FROM Kind LIMIT FIRST(10, @cursor) OFFSET @offset + 10",
+	"SELECT * FROM Kind WHERE a = -1 AND b = -0.5 AND c = FALSE AND d = NULL",
+	"AGGREGATE COUNT_UP_TO(10), SUM(a), AVG(b) OVER (SELECT * FROM Kind)",
+}
+
+// FuzzRoundTrip parses the query, serializes the syntax by every serializer, and re-reads it to assert the equality.
+func FuzzRoundTrip(f *testing.F) {
+	for _, c := range benchmarks.Queries {
+		f.Add(c.Source)
+	}
+	for _, c := range benchmarks.AggregationQueries {
+		f.Add(c.Source)
+	}
+	for _, s := range roundTripSeeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, source string) {
+		tokens, err := gqlparser.ReadAllTokens(gqlparser.NewLexer(source))
+		if err != nil {
+			return
+		}
+		query, aggregationQuery, err := gqlparser.ParseQueryOrAggregationQuery(gqlparser.NewLexer(source))
+		if err != nil {
+			return
+		}
+
+		// the rendered tokens are parsed into the equal syntax
+		rendered := gqlparser.RenderTokens(tokens)
+		gotQuery, gotAggregationQuery, err := gqlparser.ParseQueryOrAggregationQuery(gqlparser.NewLexer(rendered))
+		if err != nil {
+			t.Fatalf("ParseQueryOrAggregationQuery(%q) error = %v", rendered, err)
+		}
+		if diff := cmp.Diff(query, gotQuery, roundTripOptions); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+		if diff := cmp.Diff(aggregationQuery, gotAggregationQuery, roundTripOptions); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+
+		if query != nil {
+			assertRoundTrip(t, query)
+		} else {
+			assertRoundTrip(t, aggregationQuery)
+		}
+	})
+}
+
+var roundTripOptions = cmp.Options{cmpopts.EquateEmpty(), cmpopts.EquateNaNs()}
+
+// assertRoundTrip asserts the syntax is serialized by every serializer and re-read into the equal syntax.
+// The syntax that cannot be serialized is skipped.
+func assertRoundTrip[T interface {
+	gqlparser.Syntax
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}](t *testing.T, s T) {
+	t.Helper()
+
+	if encoded, err := gqlparser.EncodeSExpr(s); err == nil {
+		decoded, err := gqlparser.DecodeSExpr(encoded)
+		if err != nil {
+			t.Fatalf("DecodeSExpr(%q) error = %v", encoded, err)
+		}
+		if diff := cmp.Diff(gqlparser.Syntax(s), decoded, roundTripOptions); diff != "" {
+			t.Errorf("DecodeSExpr(%q): (-want, +got)\n%s", encoded, diff)
+		}
+	}
+
+	if data, err := s.MarshalBinary(); err == nil {
+		got := reflect.New(reflect.TypeOf(s).Elem()).Interface().(T)
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary() error = %v", err)
+		}
+		if diff := cmp.Diff(s, got, roundTripOptions); diff != "" {
+			t.Errorf("UnmarshalBinary(): (-want, +got)\n%s", diff)
+		}
+	}
+
+	switch s := any(s).(type) {
+	case *gqlparser.Query:
+		if source, err := gqlparser.FormatQuery(s); err == nil {
+			got, err := gqlparser.ParseQuery(gqlparser.NewLexer(source))
+			if err != nil {
+				t.Fatalf("ParseQuery(%q) error = %v", source, err)
+			}
+			if diff := cmp.Diff(s, got, roundTripOptions); diff != "" {
+				t.Errorf("ParseQuery(%q): (-want, +got)\n%s", source, diff)
+			}
+		}
+	case *gqlparser.AggregationQuery:
+		for _, form := range []gqlparser.AggregationForm{gqlparser.SelectAggregationForm, gqlparser.AggregateOverAggregationForm} {
+			if source, err := gqlparser.FormatAggregationQuery(s, gqlparser.WithAggregationForm(form)); err == nil {
+				got, err := gqlparser.ParseAggregationQuery(gqlparser.NewLexer(source))
+				if err != nil {
+					t.Fatalf("ParseAggregationQuery(%q) error = %v", source, err)
+				}
+				if diff := cmp.Diff(s, got, roundTripOptions); diff != "" {
+					t.Errorf("ParseAggregationQuery(%q): (-want, +got)\n%s", source, diff)
+				}
+			}
+		}
+	}
+}
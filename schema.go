@@ -0,0 +1,188 @@
+package gqlparser
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrTypeMismatch      = errors.New("type mismatch")
+	ErrInvalidProjection = errors.New("invalid projection")
+)
+
+// ValueType is the type of the property values.
+type ValueType string
+
+const (
+	// UnknownValueType is the type that cannot be determined. e.g. unknown properties or unbound variables
+	UnknownValueType   ValueType = ""
+	NullValueType      ValueType = "NULL"
+	BooleanValueType   ValueType = "BOOLEAN"
+	IntegerValueType   ValueType = "INTEGER"
+	DoubleValueType    ValueType = "DOUBLE"
+	StringValueType    ValueType = "STRING"
+	BlobValueType      ValueType = "BLOB"
+	TimestampValueType ValueType = "TIMESTAMP"
+	KeyValueType       ValueType = "KEY"
+	// ArrayValueType is the type of the array properties. The types of the elements are not checked.
+	ArrayValueType ValueType = "ARRAY"
+)
+
+// ValueTypeOf returns the type of the value in the conditions.
+func ValueTypeOf(value any) ValueType {
+	switch value.(type) {
+	case nil:
+		return NullValueType
+	case bool:
+		return BooleanValueType
+	case int64:
+		return IntegerValueType
+	case float64:
+		return DoubleValueType
+	case string:
+		return StringValueType
+	case []byte:
+		return BlobValueType
+	case time.Time:
+		return TimestampValueType
+	case *Key:
+		return KeyValueType
+	case []any:
+		return ArrayValueType
+	default:
+		return UnknownValueType
+	}
+}
+
+// Schema provides the types of the properties of the kinds.
+type Schema interface {
+	// PropertyType returns the type of the property. It returns UnknownValueType if the property isn't known.
+	PropertyType(kind Kind, path Property) ValueType
+}
+
+// Validate validates the kind, the projection and the conditions of the query like ValidateCondition.
+// The kind is validated by Kind.Validate unless it's the placeholder, and the referenced property paths are validated by Property.Validate.
+// The special property __key__ cannot be projected with the other properties, and it's reported as ErrInvalidProjection
+// for the queries built programmatically since the parser rejects it.
+// If the schema is given, the conditions are type-checked with it too,
+// and the range comparisons with the keys are allowed only on __key__ and the properties typed as KeyValueType.
+// Otherwise, they're allowed only on __key__ as ValidateCondition.
+// The type mismatches are reported as ErrTypeMismatch.
+func (q *Query) Validate(schema Schema) error {
+	if q.KindBinding == nil {
+		if err := q.Kind.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := validateProjection(q.Properties); err != nil {
+		return err
+	}
+	for _, prop := range q.ReferencedProperties() {
+		if err := prop.Validate(); err != nil {
+			return err
+		}
+	}
+	if q.Where == nil {
+		return nil
+	}
+	if err := validateCondition(q.Where, schema != nil); err != nil {
+		return err
+	}
+	if schema == nil {
+		return nil
+	}
+	return typeCheckCondition(q.Kind, q.Where, schema)
+}
+
+func validateProjection(props []Property) error {
+	if len(props) < 2 {
+		return nil
+	}
+	for _, prop := range props {
+		if prop == keyProperty {
+			return fmt.Errorf("%w: %s cannot be projected with the other properties", ErrInvalidProjection, keyProperty)
+		}
+	}
+	return nil
+}
+
+func typeCheckCondition(kind Kind, cond Condition, schema Schema) error {
+	propertyType := func(property string) ValueType {
+		if property == keyProperty {
+			return KeyValueType
+		}
+		return schema.PropertyType(kind, Property(property))
+	}
+
+	switch c := cond.(type) {
+	case *AndCompoundCondition:
+		if err := typeCheckCondition(kind, c.Left, schema); err != nil {
+			return err
+		}
+		return typeCheckCondition(kind, c.Right, schema)
+	case *OrCompoundCondition:
+		if err := typeCheckCondition(kind, c.Left, schema); err != nil {
+			return err
+		}
+		return typeCheckCondition(kind, c.Right, schema)
+	case *EitherComparatorCondition:
+		typ := propertyType(c.Property)
+		if _, isKey := c.Value.(*Key); isKey && c.Comparator.isRange() && typ != KeyValueType {
+			return fmt.Errorf("%w: %v with the key requires %s or the key property but %s is not", ErrTypeMismatch, c.Comparator, keyProperty, c.Property)
+		}
+		return typeCheckValue(c.Comparator, c.Property, typ, c.Value)
+	case *ForwardComparatorCondition:
+		typ := propertyType(c.Property)
+		switch c.Comparator {
+		case ContainsForwardComparator:
+			if typ != UnknownValueType && typ != ArrayValueType {
+				return fmt.Errorf("%w: %v requires an array property but %s is %s", ErrTypeMismatch, c.Comparator, c.Property, typ)
+			}
+			return nil
+		case InForwardComparator, NotInForwardComparator:
+			values, ok := c.Value.([]any)
+			if !ok {
+				return nil
+			}
+			for _, v := range values {
+				if err := typeCheckValue(c.Comparator, c.Property, typ, v); err != nil {
+					return err
+				}
+			}
+			return nil
+		default:
+			return typeCheckValue(c.Comparator, c.Property, typ, c.Value)
+		}
+	case *BackwardComparatorCondition:
+		typ := propertyType(c.Property)
+		if c.Comparator == InBackwardComparator {
+			if typ != UnknownValueType && typ != ArrayValueType {
+				return fmt.Errorf("%w: %v requires an array property but %s is %s", ErrTypeMismatch, c.Comparator, c.Property, typ)
+			}
+			return nil
+		}
+		return typeCheckValue(c.Comparator, c.Property, typ, c.Value)
+	case *QuantifiedComparatorCondition:
+		if typ := propertyType(c.Property); typ != UnknownValueType && typ != ArrayValueType {
+			return fmt.Errorf("%w: %v requires an array property but %s is %s", ErrTypeMismatch, c.Comparator, c.Property, typ)
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+func typeCheckValue(comparator Comparator, property string, typ ValueType, value any) error {
+	if typ == UnknownValueType || ValueTypeOf(value) == UnknownValueType {
+		return nil
+	}
+	if _, err := CoerceValue(value, typ); err != nil {
+		return fmt.Errorf("%w (%s by %v)", err, property, comparator)
+	}
+	return nil
+}
+
+func isNumericValueType(typ ValueType) bool {
+	return typ == IntegerValueType || typ == DoubleValueType
+}
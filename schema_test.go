@@ -0,0 +1,115 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/karupanerura/gqlparser"
+)
+
+type mapSchema map[gqlparser.Kind]map[gqlparser.Property]gqlparser.ValueType
+
+func (s mapSchema) PropertyType(kind gqlparser.Kind, path gqlparser.Property) gqlparser.ValueType {
+	return s[kind][path]
+}
+
+func TestQueryValidate(t *testing.T) {
+	t.Parallel()
+
+	schema := mapSchema{
+		"Kind": {
+			"name":  gqlparser.StringValueType,
+			"age":   gqlparser.IntegerValueType,
+			"score": gqlparser.DoubleValueType,
+			"tags":  gqlparser.ArrayValueType,
+			"at":    gqlparser.TimestampValueType,
+			"owner": gqlparser.KeyValueType,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		source   string
+		mismatch bool
+		// untyped reports whether it's invalid without the schema
+		untyped bool
+	}{
+		{name: "NoWhere", source: "SELECT * FROM Kind"},
+		{name: "Match", source: "SELECT * FROM Kind WHERE name = 'a' AND age > 1 AND at < DATETIME('2013-09-29T09:30:20Z')"},
+		{name: "Numeric", source: "SELECT * FROM Kind WHERE age > 1.5 AND score <= 2"},
+		{name: "Null", source: "SELECT * FROM Kind WHERE name = NULL AND age IS NULL"},
+		{name: "Unknown", source: "SELECT * FROM Kind WHERE unknown = 1 AND name = @name"},
+		{name: "Array", source: "SELECT * FROM Kind WHERE tags CONTAINS 'a' AND 'b' IN tags AND tags = 1"},
+		{name: "In", source: "SELECT * FROM Kind WHERE name IN ARRAY('a', 'b') AND age NOT IN ARRAY(1, 2.5)"},
+		{name: "Key", source: "SELECT * FROM Kind WHERE __key__ HAS ANCESTOR KEY(Parent, 1) AND __key__ = KEY(Kind, 1)"},
+		{name: "StringWithInteger", source: "SELECT * FROM Kind WHERE name = 1", mismatch: true},
+		{name: "IntegerWithString", source: "SELECT * FROM Kind WHERE 'a' < age", mismatch: true},
+		{name: "ContainsOnNonArray", source: "SELECT * FROM Kind WHERE name CONTAINS 'a'", mismatch: true},
+		{name: "InOnNonArray", source: "SELECT * FROM Kind WHERE 'a' IN name", mismatch: true},
+		{name: "InElement", source: "SELECT * FROM Kind WHERE name IN ARRAY('a', 1)", mismatch: true},
+		{name: "KeyWithString", source: "SELECT * FROM Kind WHERE __key__ = 'a'", mismatch: true},
+		{name: "KeyRange", source: "SELECT * FROM Kind WHERE __key__ > KEY(Kind, 1) AND owner <= KEY(User, 'a') AND KEY(User, 'b') > owner", untyped: true},
+		{name: "KeyRangeOnUnknown", source: "SELECT * FROM Kind WHERE unknown < KEY(Kind, 1)", mismatch: true, untyped: true},
+		{name: "KeyRangeOnString", source: "SELECT * FROM Kind WHERE KEY(Kind, 1) >= name", mismatch: true, untyped: true},
+		{name: "OtherKind", source: "SELECT * FROM Other WHERE name = 1"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = query.Validate(schema)
+			if errors.Is(err, gqlparser.ErrTypeMismatch) != tt.mismatch {
+				t.Errorf("Validate() error = %v, mismatch %v", err, tt.mismatch)
+			}
+			if err := query.Validate(nil); errors.Is(err, gqlparser.ErrInvalidCondition) != tt.untyped {
+				t.Errorf("Validate(nil) error = %v, untyped %v", err, tt.untyped)
+			}
+		})
+	}
+}
+
+func TestQueryValidate_Projection(t *testing.T) {
+	t.Parallel()
+
+	for source, wantErr := range map[string]bool{
+		"SELECT __key__ FROM Kind": false,
+		"SELECT a, b FROM Kind":    false,
+	} {
+		query, err := gqlparser.ParseQuery(gqlparser.NewLexer(source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := query.Validate(nil); errors.Is(err, gqlparser.ErrInvalidProjection) != wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", source, err, wantErr)
+		}
+	}
+
+	// the parser rejects them, so they're built programmatically
+	for _, props := range [][]gqlparser.Property{{"__key__", "a"}, {"a", "__key__"}} {
+		query := &gqlparser.Query{Properties: props, Kind: "Kind"}
+		if err := query.Validate(nil); !errors.Is(err, gqlparser.ErrInvalidProjection) {
+			t.Errorf("%v: Validate() error = %v, want %v", props, err, gqlparser.ErrInvalidProjection)
+		}
+	}
+}
+
+func TestParseQuery_KeyProjection(t *testing.T) {
+	t.Parallel()
+
+	for source, wantErr := range map[string]string{
+		"SELECT __key__, a FROM Kind":        "unexpected token: __key__ at 7 (__key__ cannot be projected with the other properties) in SELECT clause",
+		"SELECT a, `__key__` AS k FROM Kind": "unexpected token: `__key__` at 10 (__key__ cannot be projected with the other properties) in SELECT clause",
+	} {
+		_, err := gqlparser.ParseQuery(gqlparser.NewLexer(source))
+		var parseErr *gqlparser.ParseError
+		if !errors.As(err, &parseErr) || err.Error() != wantErr {
+			t.Errorf("%s: ParseQuery() error = %v, want %q", source, err, wantErr)
+		}
+	}
+}
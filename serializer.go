@@ -0,0 +1,415 @@
+package gqlparser
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// AggregationForm is the surface syntax of the aggregation queries written by FormatAggregationQuery.
+type AggregationForm int
+
+const (
+	// SelectAggregationForm writes the aggregations in the projection. e.g. SELECT COUNT(*) FROM Kind WHERE a = 1
+	// The query that has the clauses not written in this form is written in AggregateOverAggregationForm instead.
+	SelectAggregationForm AggregationForm = iota
+	// AggregateOverAggregationForm writes the aggregations over the nested query.
+	// e.g. AGGREGATE COUNT(*) OVER (SELECT * FROM Kind WHERE a = 1)
+	AggregateOverAggregationForm
+)
+
+// FormatOption configures the optional behaviors of the serializer.
+type FormatOption func(*formatOptions)
+
+type formatOptions struct {
+	aggregationForm AggregationForm
+	sortInValues    bool
+}
+
+func newFormatOptions(opts []FormatOption) *formatOptions {
+	o := &formatOptions{aggregationForm: SelectAggregationForm}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithAggregationForm selects the surface syntax of the aggregation queries. It's SelectAggregationForm by default.
+func WithAggregationForm(form AggregationForm) FormatOption {
+	return func(o *formatOptions) {
+		o.aggregationForm = form
+	}
+}
+
+// WithSortedInValues sorts the values of IN, NOT IN, CONTAINS ANY and CONTAINS ALL by CompareValues, since they're the sets whose order doesn't matter.
+// It makes the output stable for the golden tests regardless of the order of the values.
+// The array having the values not compared like the bindings is written as is.
+func WithSortedInValues() FormatOption {
+	return func(o *formatOptions) {
+		o.sortInValues = true
+	}
+}
+
+// FormatQuery writes the query as GQL to be parsed as the same query again.
+// The template placeholders are written as the bindings, and the cursor literals are written as CURSOR('...').
+// The hints are written as the hint comment following SELECT. e.g. SELECT /*+ timeout=5s */ * FROM Kind
+// It returns ErrTypeMismatch if any value cannot be written as GQL literal like FormatValue.
+func FormatQuery(q *Query, opts ...FormatOption) (string, error) {
+	o := newFormatOptions(opts)
+
+	var sb strings.Builder
+	if err := o.formatQuery(&sb, q); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// FormatAggregationQuery writes the aggregation query as GQL to be parsed as the same query again.
+// The form of the syntax is selected by WithAggregationForm.
+func FormatAggregationQuery(q *AggregationQuery, opts ...FormatOption) (string, error) {
+	o := newFormatOptions(opts)
+
+	var sb strings.Builder
+	if o.aggregationForm == SelectAggregationForm && selectAggregationFormAvailable(q) {
+		sb.WriteString("SELECT ")
+		if err := formatQueryHints(&sb, q.Hints); err != nil {
+			return "", err
+		}
+		formatAggregations(&sb, q.Aggregations)
+		sb.WriteString(" FROM ")
+		formatKind(&sb, &q.Query)
+		if q.Where != nil {
+			sb.WriteString(" WHERE ")
+			if err := o.formatCondition(&sb, q.Where); err != nil {
+				return "", err
+			}
+		}
+		formatGroupBy(&sb, q.GroupBy)
+	} else {
+		sb.WriteString("AGGREGATE ")
+		formatAggregations(&sb, q.Aggregations)
+		sb.WriteString(" OVER (")
+		if err := o.formatQuery(&sb, &q.Query); err != nil {
+			return "", err
+		}
+		sb.WriteString(")")
+	}
+	if q.Having != nil {
+		sb.WriteString(" HAVING ")
+		if err := o.formatCondition(&sb, q.Having); err != nil {
+			return "", err
+		}
+	}
+	return sb.String(), nil
+}
+
+// FormatCondition writes the condition as GQL to be parsed as the same condition again.
+// The compound conditions are parenthesized only if needed.
+func FormatCondition(cond Condition, opts ...FormatOption) (string, error) {
+	o := newFormatOptions(opts)
+
+	var sb strings.Builder
+	if err := o.formatCondition(&sb, cond); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// selectAggregationFormAvailable reports whether the aggregation query can be written in SelectAggregationForm.
+// The form has no projection, DISTINCT, ORDER BY, LIMIT and OFFSET of the aggregated query.
+func selectAggregationFormAvailable(q *AggregationQuery) bool {
+	return len(q.Properties) == 0 && len(q.PropertyBindings) == 0 && !q.Distinct && len(q.DistinctOn) == 0 &&
+		len(q.OrderBy) == 0 && q.Limit == nil && q.Offset == nil
+}
+
+// formatQueryHints writes the hint comment followed by the space if any.
+func formatQueryHints(sb *strings.Builder, hints *QueryHints) error {
+	if hints == nil {
+		return nil
+	}
+	if err := hints.validate(); err != nil {
+		return err
+	}
+	sb.WriteString("/*+")
+	if directives := hints.String(); directives != "" {
+		sb.WriteString(" ")
+		sb.WriteString(directives)
+	}
+	sb.WriteString(" */ ")
+	return nil
+}
+
+func (o *formatOptions) formatQuery(sb *strings.Builder, q *Query) error {
+	bindings := make(map[PropertyBindingClause]map[int]BindingVariable, len(q.PropertyBindings))
+	for _, b := range q.PropertyBindings {
+		if bindings[b.Clause] == nil {
+			bindings[b.Clause] = map[int]BindingVariable{}
+		}
+		bindings[b.Clause][b.Index] = b.Variable
+	}
+
+	sb.WriteString("SELECT ")
+	if err := formatQueryHints(sb, q.Hints); err != nil {
+		return err
+	}
+	if len(q.DistinctOn) != 0 {
+		sb.WriteString("DISTINCT ON (")
+		if err := formatProperties(sb, q.DistinctOn, nil, bindings[DistinctOnPropertyBindingClause]); err != nil {
+			return err
+		}
+		sb.WriteString(") ")
+	} else if q.Distinct {
+		sb.WriteString("DISTINCT ")
+	}
+	if len(q.Properties) == 0 {
+		if q.Distinct || len(q.DistinctOn) != 0 {
+			return fmt.Errorf("%w: DISTINCT cannot be written with the full projection", ErrInvalidProjection)
+		}
+		sb.WriteString("*")
+	} else if err := formatProperties(sb, q.Properties, q.Aliases, bindings[ProjectionPropertyBindingClause]); err != nil {
+		return err
+	}
+
+	sb.WriteString(" FROM ")
+	formatKind(sb, q)
+	if q.Where != nil {
+		sb.WriteString(" WHERE ")
+		if err := o.formatCondition(sb, q.Where); err != nil {
+			return err
+		}
+	}
+	formatGroupBy(sb, q.GroupBy)
+	if len(q.OrderBy) != 0 {
+		sb.WriteString(" ORDER BY ")
+		orderByBindings := bindings[OrderByPropertyBindingClause]
+		for i, orderBy := range q.OrderBy {
+			if i != 0 {
+				sb.WriteString(", ")
+			}
+			if b, ok := orderByBindings[i]; ok {
+				if err := formatValue(sb, b); err != nil {
+					return err
+				}
+				if orderBy.Descending {
+					sb.WriteString(" DESC")
+				}
+				continue
+			}
+			sb.WriteString(orderBy.String())
+		}
+	}
+	if q.Limit != nil {
+		sb.WriteString(" LIMIT ")
+		if q.Limit.Cursor != nil && q.Limit.Position != 0 {
+			sb.WriteString("FIRST(")
+			sb.WriteString(strconv.FormatInt(q.Limit.Position, 10))
+			sb.WriteString(", ")
+			if err := formatResultPosition(sb, 0, q.Limit.Cursor); err != nil {
+				return err
+			}
+			sb.WriteString(")")
+		} else if err := formatResultPosition(sb, q.Limit.Position, q.Limit.Cursor); err != nil {
+			return err
+		}
+	}
+	if q.Offset != nil {
+		sb.WriteString(" OFFSET ")
+		if err := formatResultPosition(sb, q.Offset.Position, q.Offset.Cursor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatKind(sb *strings.Builder, q *Query) {
+	if q.KindBinding != nil {
+		// the binding variables are always written as GQL
+		_ = formatValue(sb, q.KindBinding.Variable)
+		return
+	}
+	sb.WriteString(formatIdentifier(string(q.Kind)))
+}
+
+// formatProperties writes the comma separated properties with the aliases and the placeholders at the indexes.
+func formatProperties(sb *strings.Builder, props []Property, aliases []string, bindings map[int]BindingVariable) error {
+	for i, p := range props {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		if b, ok := bindings[i]; ok {
+			if err := formatValue(sb, b); err != nil {
+				return err
+			}
+		} else {
+			sb.WriteString(formatIdentifier(string(p)))
+		}
+		if i < len(aliases) && aliases[i] != "" {
+			sb.WriteString(" AS ")
+			sb.WriteString(formatIdentifier(aliases[i]))
+		}
+	}
+	return nil
+}
+
+func formatGroupBy(sb *strings.Builder, groupBy []Property) {
+	if len(groupBy) == 0 {
+		return
+	}
+	sb.WriteString(" GROUP BY ")
+	for i, p := range groupBy {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(formatIdentifier(string(p)))
+	}
+}
+
+// formatResultPosition writes the position and the cursor of LIMIT and OFFSET. e.g. 10, @cursor + 10, CURSOR('...')
+func formatResultPosition(sb *strings.Builder, position int64, cursor BindingVariable) error {
+	if cursor == nil {
+		sb.WriteString(strconv.FormatInt(position, 10))
+		return nil
+	}
+
+	if c, ok := cursor.(Cursor); ok {
+		sb.WriteString("CURSOR(")
+		sb.WriteString(QuoteString(string(c), '\''))
+		sb.WriteString(")")
+	} else if err := formatValue(sb, cursor); err != nil {
+		return err
+	}
+	if position != 0 {
+		sb.WriteString(" + ")
+		sb.WriteString(strconv.FormatInt(position, 10))
+	}
+	return nil
+}
+
+func formatAggregations(sb *strings.Builder, aggregations []Aggregation) {
+	for i, aggregation := range aggregations {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+
+		var alias string
+		switch a := aggregation.(type) {
+		case *CountAggregation:
+			sb.WriteString("COUNT(*)")
+			alias = a.Alias
+		case *CountUpToAggregation:
+			sb.WriteString("COUNT_UP_TO(")
+			sb.WriteString(strconv.FormatInt(a.Limit, 10))
+			sb.WriteString(")")
+			alias = a.Alias
+		case *SumAggregation:
+			sb.WriteString("SUM(")
+			sb.WriteString(formatIdentifier(a.Property))
+			sb.WriteString(")")
+			alias = a.Alias
+		case *AvgAggregation:
+			sb.WriteString("AVG(")
+			sb.WriteString(formatIdentifier(a.Property))
+			sb.WriteString(")")
+			alias = a.Alias
+		}
+		if alias != "" {
+			sb.WriteString(" AS ")
+			sb.WriteString(formatIdentifier(alias))
+		}
+	}
+}
+
+// formatCondition writes the condition. AND binds tighter than OR, and the both are left-associative,
+// so OR in AND and the right operand of the same operator are parenthesized.
+func (o *formatOptions) formatCondition(sb *strings.Builder, cond Condition) error {
+	switch c := cond.(type) {
+	case *AndCompoundCondition:
+		if err := o.formatOperand(sb, c.Left, isOrCondition(c.Left)); err != nil {
+			return err
+		}
+		sb.WriteString(" AND ")
+		return o.formatOperand(sb, c.Right, isCompoundCondition(c.Right))
+	case *OrCompoundCondition:
+		if err := o.formatCondition(sb, c.Left); err != nil {
+			return err
+		}
+		sb.WriteString(" OR ")
+		return o.formatOperand(sb, c.Right, isOrCondition(c.Right))
+	case *IsNullCondition:
+		sb.WriteString(formatIdentifier(c.Property))
+		sb.WriteString(" IS NULL")
+	case *EitherComparatorCondition:
+		sb.WriteString(formatIdentifier(c.Property))
+		sb.WriteString(" ")
+		sb.WriteString(string(c.Comparator))
+		sb.WriteString(" ")
+		return formatValue(sb, c.Value)
+	case *ForwardComparatorCondition:
+		sb.WriteString(formatIdentifier(c.Property))
+		sb.WriteString(" ")
+		sb.WriteString(string(c.Comparator))
+		sb.WriteString(" ")
+		if values, ok := c.Value.([]any); ok && o.sortInValues && (c.Comparator == InForwardComparator || c.Comparator == NotInForwardComparator) {
+			return formatValue(sb, sortedValues(values))
+		}
+		return formatValue(sb, c.Value)
+	case *BackwardComparatorCondition:
+		if err := formatValue(sb, c.Value); err != nil {
+			return err
+		}
+		sb.WriteString(" ")
+		sb.WriteString(string(c.Comparator))
+		sb.WriteString(" ")
+		sb.WriteString(formatIdentifier(c.Property))
+	case *QuantifiedComparatorCondition:
+		sb.WriteString(formatIdentifier(c.Property))
+		sb.WriteString(" ")
+		sb.WriteString(string(c.Comparator))
+		sb.WriteString(" ")
+		if values, ok := c.Value.([]any); ok && o.sortInValues {
+			return formatValue(sb, sortedValues(values))
+		}
+		return formatValue(sb, c.Value)
+	default:
+		return fmt.Errorf("%w: unsupported condition %T", ErrInvalidCondition, cond)
+	}
+	return nil
+}
+
+func (o *formatOptions) formatOperand(sb *strings.Builder, cond Condition, parenthesize bool) error {
+	if !parenthesize {
+		return o.formatCondition(sb, cond)
+	}
+	sb.WriteString("(")
+	if err := o.formatCondition(sb, cond); err != nil {
+		return err
+	}
+	sb.WriteString(")")
+	return nil
+}
+
+func isOrCondition(cond Condition) bool {
+	_, ok := cond.(*OrCompoundCondition)
+	return ok
+}
+
+func isCompoundCondition(cond Condition) bool {
+	_, ok := cond.(CompoundCondition)
+	return ok
+}
+
+// sortedValues returns the copy of the values sorted by CompareValues, or the values as is if any of them cannot be compared.
+func sortedValues(values []any) []any {
+	for _, v := range values {
+		if ValueTypeOf(v) == UnknownValueType {
+			return values
+		}
+	}
+	sorted := slices.Clone(values)
+	slices.SortStableFunc(sorted, func(a, b any) int {
+		c, _ := CompareValues(a, b)
+		return c
+	})
+	return sorted
+}
@@ -0,0 +1,304 @@
+package gqlparser_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestFormatQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		source string
+		opts   []gqlparser.ParseOption
+		want   string
+	}{
+		{
+			name:   "Full",
+			source: "select * from Kind",
+			want:   "SELECT * FROM Kind",
+		},
+		{
+			name:   "Projection",
+			source: "SELECT a AS x, `b c`, d FROM `Kind Name`",
+			want:   "SELECT a AS x, `b c`, d FROM `Kind Name`",
+		},
+		{
+			name:   "DistinctOn",
+			source: "SELECT DISTINCT ON (a, b) a, b FROM Kind ORDER BY a DESC, `b c`.d",
+			want:   "SELECT DISTINCT ON (a, b) a, b FROM Kind ORDER BY a DESC, `b c`.d",
+		},
+		{
+			name:   "Conditions",
+			source: "SELECT * FROM Kind WHERE (a = 1 OR b > 1.0) AND (c IN ARRAY(1, 'x') AND 'y' IN d) AND __key__ HAS ANCESTOR KEY(Parent, 'p') AND e IS NULL",
+			want:   "SELECT * FROM Kind WHERE (a = 1 OR b > 1.0) AND (c IN ARRAY(1, 'x') AND 'y' IN d) AND __key__ HAS ANCESTOR KEY(Parent, 'p') AND e IS NULL",
+		},
+		{
+			name:   "LimitOffset",
+			source: "SELECT * FROM Kind LIMIT FIRST(10, @cursor) OFFSET @offset + 5",
+			want:   "SELECT * FROM Kind LIMIT FIRST(10, @cursor) OFFSET @offset + 5",
+		},
+		{
+			name:   "CursorLiterals",
+			source: "SELECT * FROM Kind LIMIT CURSOR('Y3Vyc29y') OFFSET 10",
+			opts:   []gqlparser.ParseOption{gqlparser.WithCursorLiterals()},
+			want:   "SELECT * FROM Kind LIMIT CURSOR('Y3Vyc29y') OFFSET 10",
+		},
+		{
+			name:   "TemplatePlaceholders",
+			source: "SELECT a, @prop FROM @kind WHERE b = @1 ORDER BY @order DESC, c",
+			opts:   []gqlparser.ParseOption{gqlparser.WithTemplatePlaceholders()},
+			want:   "SELECT a, @prop FROM @kind WHERE b = @1 ORDER BY @order DESC, c",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source), tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := gqlparser.FormatQuery(query)
+			if err != nil {
+				t.Fatalf("FormatQuery() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatQuery() = %q, want %q", got, tt.want)
+			}
+
+			reparsed, err := gqlparser.ParseQuery(gqlparser.NewLexer(got), tt.opts...)
+			if err != nil {
+				t.Fatalf("ParseQuery(%q) error = %v", got, err)
+			}
+			if diff := cmp.Diff(query, reparsed); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFormatQuery_Error(t *testing.T) {
+	t.Parallel()
+
+	query := &gqlparser.Query{Kind: "Kind", Where: gqlparser.Eq("a", math.NaN())}
+	if _, err := gqlparser.FormatQuery(query); !errors.Is(err, gqlparser.ErrTypeMismatch) {
+		t.Errorf("FormatQuery() error = %v, want %v", err, gqlparser.ErrTypeMismatch)
+	}
+
+	query = &gqlparser.Query{Kind: "Kind", Distinct: true}
+	if _, err := gqlparser.FormatQuery(query); !errors.Is(err, gqlparser.ErrInvalidProjection) {
+		t.Errorf("FormatQuery() error = %v, want %v", err, gqlparser.ErrInvalidProjection)
+	}
+}
+
+func TestFormatAggregationQuery(t *testing.T) {
+	t.Parallel()
+
+	dialect, err := gqlparser.NewDialect(gqlparser.WithGroupBy(), gqlparser.WithHaving())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		source     string
+		wantSelect string
+		wantOver   string
+	}{
+		{
+			name:       "Count",
+			source:     "SELECT COUNT(*) AS total FROM Kind WHERE a = 1",
+			wantSelect: "SELECT COUNT(*) AS total FROM Kind WHERE a = 1",
+			wantOver:   "AGGREGATE COUNT(*) AS total OVER (SELECT * FROM Kind WHERE a = 1)",
+		},
+		{
+			name:       "Over",
+			source:     "AGGREGATE COUNT_UP_TO(10), SUM(a), AVG(`b c`) AS mean OVER (SELECT * FROM Kind)",
+			wantSelect: "SELECT COUNT_UP_TO(10), SUM(a), AVG(`b c`) AS mean FROM Kind",
+			wantOver:   "AGGREGATE COUNT_UP_TO(10), SUM(a), AVG(`b c`) AS mean OVER (SELECT * FROM Kind)",
+		},
+		{
+			name:       "GroupByHaving",
+			source:     "SELECT COUNT(*) AS c FROM Kind GROUP BY a HAVING c > 1",
+			wantSelect: "SELECT COUNT(*) AS c FROM Kind GROUP BY a HAVING c > 1",
+			wantOver:   "AGGREGATE COUNT(*) AS c OVER (SELECT * FROM Kind GROUP BY a) HAVING c > 1",
+		},
+		{
+			// the nested query having the clauses not written in the SELECT form
+			name:       "Fallback",
+			source:     "AGGREGATE COUNT(*) OVER (SELECT a FROM Kind ORDER BY a LIMIT 10)",
+			wantSelect: "AGGREGATE COUNT(*) OVER (SELECT a FROM Kind ORDER BY a LIMIT 10)",
+			wantOver:   "AGGREGATE COUNT(*) OVER (SELECT a FROM Kind ORDER BY a LIMIT 10)",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := gqlparser.ParseAggregationQuery(gqlparser.NewLexer(tt.source), gqlparser.WithDialect(dialect))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, c := range []struct {
+				form gqlparser.AggregationForm
+				want string
+			}{
+				{gqlparser.SelectAggregationForm, tt.wantSelect},
+				{gqlparser.AggregateOverAggregationForm, tt.wantOver},
+			} {
+				got, err := gqlparser.FormatAggregationQuery(query, gqlparser.WithAggregationForm(c.form))
+				if err != nil {
+					t.Fatalf("FormatAggregationQuery() error = %v", err)
+				}
+				if got != c.want {
+					t.Errorf("FormatAggregationQuery() = %q, want %q", got, c.want)
+				}
+
+				reparsed, err := gqlparser.ParseAggregationQuery(gqlparser.NewLexer(got), gqlparser.WithDialect(dialect))
+				if err != nil {
+					t.Fatalf("ParseAggregationQuery(%q) error = %v", got, err)
+				}
+				if diff := cmp.Diff(query, reparsed); diff != "" {
+					t.Errorf("(-want, +got)\n%s", diff)
+				}
+			}
+
+			// the SELECT form is the default
+			if got, err := gqlparser.FormatAggregationQuery(query); err != nil || got != tt.wantSelect {
+				t.Errorf("FormatAggregationQuery() = %q, %v, want %q", got, err, tt.wantSelect)
+			}
+		})
+	}
+}
+
+func TestFormatCondition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cond gqlparser.Condition
+		want string
+	}{
+		{
+			name: "LeftAssociative",
+			cond: gqlparser.And(gqlparser.Eq("a", int64(1)), gqlparser.Eq("b", int64(2)), gqlparser.Eq("c", int64(3))),
+			want: "a = 1 AND b = 2 AND c = 3",
+		},
+		{
+			name: "RightNested",
+			cond: gqlparser.Or(gqlparser.Eq("a", int64(1)), gqlparser.Or(gqlparser.Eq("b", int64(2)), gqlparser.Eq("c", int64(3)))),
+			want: "a = 1 OR (b = 2 OR c = 3)",
+		},
+		{
+			name: "OrInAnd",
+			cond: gqlparser.And(gqlparser.Or(gqlparser.Eq("a", int64(1)), gqlparser.Eq("b", int64(2))), gqlparser.Eq("c", int64(3))),
+			want: "(a = 1 OR b = 2) AND c = 3",
+		},
+		{
+			name: "AndInOr",
+			cond: gqlparser.Or(gqlparser.And(gqlparser.Eq("a", int64(1)), gqlparser.Eq("b", int64(2))), gqlparser.And(gqlparser.Eq("c", int64(3)), gqlparser.IsNull("d"))),
+			want: "a = 1 AND b = 2 OR c = 3 AND d IS NULL",
+		},
+		{
+			name: "Comparators",
+			cond: gqlparser.And(gqlparser.NotIn("a", int64(1), "x"), gqlparser.Contains("first name", "y"), gqlparser.HasAncestor(&gqlparser.NamedBinding{Name: "parent"})),
+			want: "a NOT IN ARRAY(1, 'x') AND `first name` CONTAINS 'y' AND __key__ HAS ANCESTOR @parent",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := gqlparser.FormatCondition(tt.cond)
+			if err != nil {
+				t.Fatalf("FormatCondition() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatCondition() = %q, want %q", got, tt.want)
+			}
+
+			reparsed, err := gqlparser.ParseCondition(gqlparser.NewLexer(got))
+			if err != nil {
+				t.Fatalf("ParseCondition(%q) error = %v", got, err)
+			}
+			if diff := cmp.Diff(tt.cond, reparsed); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFormatCondition_SortedInValues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"a IN ARRAY(3, 'b', 1, NULL, 'a', 2.5)", "a IN ARRAY(NULL, 1, 2.5, 3, 'a', 'b')"},
+		{"a NOT IN ARRAY(KEY(Kind, 'b'), KEY(Kind, 2), KEY(Kind, 1))", "a NOT IN ARRAY(KEY(Kind, 1), KEY(Kind, 2), KEY(Kind, 'b'))"},
+		// the bindings are not compared
+		{"a IN ARRAY(3, @x, 1)", "a IN ARRAY(3, @x, 1)"},
+		// the arrays of the other comparators are not sets
+		{"a = ARRAY(3, 1) AND 3 IN b", "a = ARRAY(3, 1) AND 3 IN b"},
+	}
+	for _, tt := range tests {
+		cond, err := gqlparser.ParseCondition(gqlparser.NewLexer(tt.source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := gqlparser.FormatCondition(cond, gqlparser.WithSortedInValues())
+		if err != nil {
+			t.Fatalf("FormatCondition() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("FormatCondition(%q) = %q, want %q", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestFormat_Deterministic(t *testing.T) {
+	t.Parallel()
+
+	query, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT DISTINCT ON (a) a, b AS x FROM Kind WHERE c IN ARRAY(2, 1) AND (d = @d OR e > DATETIME('2013-09-29T09:30:20Z')) ORDER BY a DESC LIMIT 10 OFFSET 5"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	render := func() []string {
+		formatted, err := gqlparser.FormatQuery(query, gqlparser.WithSortedInValues())
+		if err != nil {
+			t.Fatal(err)
+		}
+		sexpr, err := gqlparser.EncodeSExpr(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc, err := query.MarshalYAML()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// encoding/json sorts the keys of the maps
+		j, err := json.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return []string{formatted, sexpr, gqlparser.Describe(query), string(j)}
+	}
+
+	want := render()
+	for i := 0; i < 10; i++ {
+		if diff := cmp.Diff(want, render()); diff != "" {
+			t.Fatalf("(-want, +got)\n%s", diff)
+		}
+	}
+}
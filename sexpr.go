@@ -0,0 +1,982 @@
+package gqlparser
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidSExpr = errors.New("invalid s-expression")
+
+// EncodeSExpr renders the syntax as the compact s-expression in a line to store the expected syntax textually.
+// It's decoded by DecodeSExpr into the equal syntax. e.g.
+//
+//	(query (kind "Kind") (where (either "=" "a" 1)) (limit 10 (binding 1)))
+//
+// The values are rendered as follows:
+//   - NULL, booleans, integers and strings are the atoms. e.g. null, true, 1, "a"
+//   - The doubles always have the decimal point or the exponent. e.g. 1.0, 1e+100, NaN, +Inf
+//   - The others are the lists. e.g. (array 1 2), (blob "YQ=="), (datetime "2013-09-29T09:30:20Z"), (binding "name")
+//
+// It returns ErrInvalidSExpr for the values that cannot be rendered.
+func EncodeSExpr(s Syntax) (string, error) {
+	var sb strings.Builder
+	if err := encodeSExprSyntax(&sb, s); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func encodeSExprSyntax(sb *strings.Builder, s Syntax) error {
+	switch v := s.(type) {
+	case *Query:
+		sb.WriteString("(query")
+		if err := encodeSExprQueryClauses(sb, v); err != nil {
+			return err
+		}
+		sb.WriteString(")")
+	case *AggregationQuery:
+		sb.WriteString("(aggregate")
+		for _, a := range v.Aggregations {
+			sb.WriteString(" ")
+			s, ok := a.(Syntax)
+			if !ok {
+				return fmt.Errorf("%w: unsupported aggregation %T", ErrInvalidSExpr, a)
+			}
+			if err := encodeSExprSyntax(sb, s); err != nil {
+				return err
+			}
+		}
+		if v.Having != nil {
+			sb.WriteString(" (having ")
+			s, ok := v.Having.(Syntax)
+			if !ok {
+				return fmt.Errorf("%w: unsupported condition %T", ErrInvalidSExpr, v.Having)
+			}
+			if err := encodeSExprSyntax(sb, s); err != nil {
+				return err
+			}
+			sb.WriteString(")")
+		}
+		sb.WriteString(" (over (query")
+		if err := encodeSExprQueryClauses(sb, &v.Query); err != nil {
+			return err
+		}
+		sb.WriteString(")))")
+	case *CountAggregation:
+		sb.WriteString("(count")
+		encodeSExprAlias(sb, v.Alias)
+		sb.WriteString(")")
+	case *CountUpToAggregation:
+		fmt.Fprintf(sb, "(count-up-to %d", v.Limit)
+		encodeSExprAlias(sb, v.Alias)
+		sb.WriteString(")")
+	case *SumAggregation:
+		fmt.Fprintf(sb, "(sum %s", strconv.Quote(v.Property))
+		encodeSExprAlias(sb, v.Alias)
+		sb.WriteString(")")
+	case *AvgAggregation:
+		fmt.Fprintf(sb, "(avg %s", strconv.Quote(v.Property))
+		encodeSExprAlias(sb, v.Alias)
+		sb.WriteString(")")
+	case *AndCompoundCondition:
+		return encodeSExprCompound(sb, "and", v.Left, v.Right)
+	case *OrCompoundCondition:
+		return encodeSExprCompound(sb, "or", v.Left, v.Right)
+	case *IsNullCondition:
+		fmt.Fprintf(sb, "(is-null %s)", strconv.Quote(v.Property))
+	case *EitherComparatorCondition:
+		return encodeSExprComparator(sb, "either", string(v.Comparator), v.Property, v.Value)
+	case *ForwardComparatorCondition:
+		return encodeSExprComparator(sb, "forward", string(v.Comparator), v.Property, v.Value)
+	case *BackwardComparatorCondition:
+		return encodeSExprComparator(sb, "backward", string(v.Comparator), v.Property, v.Value)
+	case *QuantifiedComparatorCondition:
+		return encodeSExprComparator(sb, "quantified", string(v.Comparator), v.Property, v.Value)
+	case *Key:
+		return encodeSExprValue(sb, v)
+	case *OrderBy:
+		encodeSExprOrderBy(sb, *v)
+	case *Limit:
+		return encodeSExprResultPosition(sb, "limit", v.Position, v.Cursor)
+	case *Offset:
+		return encodeSExprResultPosition(sb, "offset", v.Position, v.Cursor)
+	default:
+		return fmt.Errorf("%w: unsupported syntax %T", ErrInvalidSExpr, s)
+	}
+	return nil
+}
+
+func encodeSExprQueryClauses(sb *strings.Builder, q *Query) error {
+	if len(q.Properties) != 0 {
+		sb.WriteString(" (properties")
+		for _, p := range q.Properties {
+			sb.WriteString(" ")
+			sb.WriteString(strconv.Quote(string(p)))
+		}
+		sb.WriteString(")")
+	}
+	if len(q.Aliases) != 0 {
+		sb.WriteString(" (aliases")
+		for _, alias := range q.Aliases {
+			sb.WriteString(" ")
+			sb.WriteString(strconv.Quote(alias))
+		}
+		sb.WriteString(")")
+	}
+	if q.KeysOnly {
+		sb.WriteString(" (keys-only)")
+	}
+	if q.Distinct {
+		sb.WriteString(" (distinct)")
+	}
+	if len(q.DistinctOn) != 0 {
+		sb.WriteString(" (distinct-on")
+		for _, p := range q.DistinctOn {
+			sb.WriteString(" ")
+			sb.WriteString(strconv.Quote(string(p)))
+		}
+		sb.WriteString(")")
+	}
+	if q.Kind != "" {
+		fmt.Fprintf(sb, " (kind %s)", strconv.Quote(string(q.Kind)))
+	}
+	if q.KindBinding != nil {
+		sb.WriteString(" (kind-binding ")
+		if err := encodeSExprValue(sb, q.KindBinding.Variable); err != nil {
+			return err
+		}
+		sb.WriteString(")")
+	}
+	for _, b := range q.PropertyBindings {
+		fmt.Fprintf(sb, " (property-binding %s %d ", strconv.Quote(string(b.Clause)), b.Index)
+		if err := encodeSExprValue(sb, b.Variable); err != nil {
+			return err
+		}
+		sb.WriteString(")")
+	}
+	if q.Where != nil {
+		sb.WriteString(" (where ")
+		s, ok := q.Where.(Syntax)
+		if !ok {
+			return fmt.Errorf("%w: unsupported condition %T", ErrInvalidSExpr, q.Where)
+		}
+		if err := encodeSExprSyntax(sb, s); err != nil {
+			return err
+		}
+		sb.WriteString(")")
+	}
+	if len(q.GroupBy) != 0 {
+		sb.WriteString(" (group-by")
+		for _, p := range q.GroupBy {
+			sb.WriteString(" ")
+			sb.WriteString(strconv.Quote(string(p)))
+		}
+		sb.WriteString(")")
+	}
+	if len(q.OrderBy) != 0 {
+		sb.WriteString(" (order-by")
+		for _, o := range q.OrderBy {
+			sb.WriteString(" ")
+			encodeSExprOrderBy(sb, o)
+		}
+		sb.WriteString(")")
+	}
+	if q.Limit != nil {
+		sb.WriteString(" ")
+		if err := encodeSExprResultPosition(sb, "limit", q.Limit.Position, q.Limit.Cursor); err != nil {
+			return err
+		}
+	}
+	if q.Offset != nil {
+		sb.WriteString(" ")
+		if err := encodeSExprResultPosition(sb, "offset", q.Offset.Position, q.Offset.Cursor); err != nil {
+			return err
+		}
+	}
+	if q.Hints != nil {
+		if err := q.Hints.validate(); err != nil {
+			return err
+		}
+		fmt.Fprintf(sb, " (hints %s)", strconv.Quote(q.Hints.String()))
+	}
+	return nil
+}
+
+func encodeSExprAlias(sb *strings.Builder, alias string) {
+	if alias != "" {
+		fmt.Fprintf(sb, " (as %s)", strconv.Quote(alias))
+	}
+}
+
+func encodeSExprCompound(sb *strings.Builder, op string, left, right Condition) error {
+	sb.WriteString("(")
+	sb.WriteString(op)
+	for _, c := range []Condition{left, right} {
+		sb.WriteString(" ")
+		s, ok := c.(Syntax)
+		if !ok {
+			return fmt.Errorf("%w: unsupported condition %T", ErrInvalidSExpr, c)
+		}
+		if err := encodeSExprSyntax(sb, s); err != nil {
+			return err
+		}
+	}
+	sb.WriteString(")")
+	return nil
+}
+
+func encodeSExprComparator(sb *strings.Builder, typ, comparator, property string, value any) error {
+	fmt.Fprintf(sb, "(%s %s %s ", typ, strconv.Quote(comparator), strconv.Quote(property))
+	if err := encodeSExprValue(sb, value); err != nil {
+		return err
+	}
+	sb.WriteString(")")
+	return nil
+}
+
+func encodeSExprOrderBy(sb *strings.Builder, o OrderBy) {
+	if o.Descending {
+		fmt.Fprintf(sb, "(order %s desc)", strconv.Quote(string(o.Property)))
+	} else {
+		fmt.Fprintf(sb, "(order %s asc)", strconv.Quote(string(o.Property)))
+	}
+}
+
+func encodeSExprResultPosition(sb *strings.Builder, name string, position int64, cursor BindingVariable) error {
+	fmt.Fprintf(sb, "(%s %d", name, position)
+	if cursor != nil {
+		sb.WriteString(" ")
+		if err := encodeSExprValue(sb, cursor); err != nil {
+			return err
+		}
+	}
+	sb.WriteString(")")
+	return nil
+}
+
+func encodeSExprValue(sb *strings.Builder, value any) error {
+	switch v := value.(type) {
+	case nil:
+		sb.WriteString("null")
+	case bool:
+		sb.WriteString(strconv.FormatBool(v))
+	case int64:
+		sb.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		s := strconv.FormatFloat(v, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".eIN") {
+			s += ".0"
+		}
+		sb.WriteString(s)
+	case string:
+		sb.WriteString(strconv.Quote(v))
+	case []byte:
+		fmt.Fprintf(sb, "(blob %s)", strconv.Quote(base64.StdEncoding.EncodeToString(v)))
+	case time.Time:
+		fmt.Fprintf(sb, "(datetime %s)", strconv.Quote(v.Format(time.RFC3339Nano)))
+	case []any:
+		sb.WriteString("(array")
+		for _, item := range v {
+			sb.WriteString(" ")
+			if err := encodeSExprValue(sb, item); err != nil {
+				return err
+			}
+		}
+		sb.WriteString(")")
+	case *Key:
+		sb.WriteString("(key")
+		if v.ProjectID != "" {
+			fmt.Fprintf(sb, " (project %s)", strconv.Quote(string(v.ProjectID)))
+		}
+		if v.Namespace != "" {
+			fmt.Fprintf(sb, " (namespace %s)", strconv.Quote(v.Namespace))
+		}
+		for _, path := range v.Path {
+			fmt.Fprintf(sb, " (path %s ", strconv.Quote(string(path.Kind)))
+			switch {
+			case path.Name != "":
+				sb.WriteString(strconv.Quote(path.Name))
+			case path.Binding != nil && path.ID == 0:
+				if err := encodeSExprValue(sb, path.Binding); err != nil {
+					return err
+				}
+			default:
+				sb.WriteString(strconv.FormatInt(path.ID, 10))
+			}
+			sb.WriteString(")")
+		}
+		sb.WriteString(")")
+	case *NamedBinding:
+		fmt.Fprintf(sb, "(binding %s)", strconv.Quote(v.Name))
+	case *IndexedBinding:
+		fmt.Fprintf(sb, "(binding %d)", v.Index)
+	case Cursor:
+		fmt.Fprintf(sb, "(cursor %s)", strconv.Quote(string(v)))
+	default:
+		return fmt.Errorf("%w: unsupported value %T", ErrInvalidSExpr, value)
+	}
+	return nil
+}
+
+// sexprNode is the atom or the list of the s-expression.
+type sexprNode struct {
+	// atom is the content of the atom. It's unquoted if the atom is quoted.
+	atom   string
+	quoted bool
+	list   []*sexprNode
+	isList bool
+	pos    int
+}
+
+func (n *sexprNode) String() string {
+	if n.isList {
+		return fmt.Sprintf("list at %d", n.pos)
+	}
+	if n.quoted {
+		return fmt.Sprintf("%q at %d", n.atom, n.pos)
+	}
+	return fmt.Sprintf("%s at %d", n.atom, n.pos)
+}
+
+// head returns the symbol at the head of the list.
+func (n *sexprNode) head() string {
+	if !n.isList || len(n.list) == 0 || n.list[0].isList || n.list[0].quoted {
+		return ""
+	}
+	return n.list[0].atom
+}
+
+// DecodeSExpr reads the syntax from the s-expression rendered by EncodeSExpr.
+// It returns ErrInvalidSExpr if the s-expression is malformed.
+func DecodeSExpr(s string) (Syntax, error) {
+	r := &sexprReader{source: s}
+	node, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+	r.skipSpaces()
+	if r.pos != len(r.source) {
+		return nil, fmt.Errorf("%w: unexpected %q at %d", ErrInvalidSExpr, r.source[r.pos:], r.pos)
+	}
+	return decodeSExprSyntax(node)
+}
+
+type sexprReader struct {
+	source string
+	pos    int
+}
+
+func (r *sexprReader) skipSpaces() {
+	for r.pos < len(r.source) && strings.IndexByte(" \t\r\n", r.source[r.pos]) >= 0 {
+		r.pos++
+	}
+}
+
+func (r *sexprReader) read() (*sexprNode, error) {
+	r.skipSpaces()
+	if r.pos == len(r.source) {
+		return nil, fmt.Errorf("%w: unexpected end at %d", ErrInvalidSExpr, r.pos)
+	}
+
+	start := r.pos
+	switch r.source[r.pos] {
+	case '(':
+		r.pos++
+		node := &sexprNode{isList: true, pos: start}
+		for {
+			r.skipSpaces()
+			if r.pos == len(r.source) {
+				return nil, fmt.Errorf("%w: unclosed list at %d", ErrInvalidSExpr, start)
+			}
+			if r.source[r.pos] == ')' {
+				r.pos++
+				return node, nil
+			}
+			child, err := r.read()
+			if err != nil {
+				return nil, err
+			}
+			node.list = append(node.list, child)
+		}
+	case ')':
+		return nil, fmt.Errorf("%w: unexpected ) at %d", ErrInvalidSExpr, start)
+	case '"':
+		for r.pos++; r.pos < len(r.source) && r.source[r.pos] != '"'; r.pos++ {
+			if r.source[r.pos] == '\\' {
+				r.pos++
+			}
+		}
+		if r.pos >= len(r.source) {
+			return nil, fmt.Errorf("%w: unclosed string at %d", ErrInvalidSExpr, start)
+		}
+		r.pos++
+		atom, err := strconv.Unquote(r.source[start:r.pos])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s at %d (%w)", ErrInvalidSExpr, r.source[start:r.pos], start, err)
+		}
+		return &sexprNode{atom: atom, quoted: true, pos: start}, nil
+	default:
+		for r.pos < len(r.source) && strings.IndexByte(" \t\r\n()\"", r.source[r.pos]) < 0 {
+			r.pos++
+		}
+		return &sexprNode{atom: r.source[start:r.pos], pos: start}, nil
+	}
+}
+
+func unexpectedSExprNode(n *sexprNode) error {
+	return fmt.Errorf("%w: unexpected %s", ErrInvalidSExpr, n)
+}
+
+// sexprArgs returns the arguments of the list after checking the head and the number of the arguments.
+func sexprArgs(n *sexprNode, head string, min, max int) ([]*sexprNode, error) {
+	if n.head() != head {
+		return nil, unexpectedSExprNode(n)
+	}
+	args := n.list[1:]
+	if len(args) < min || max >= 0 && len(args) > max {
+		return nil, fmt.Errorf("%w: %s has %d arguments at %d", ErrInvalidSExpr, head, len(args), n.pos)
+	}
+	return args, nil
+}
+
+func decodeSExprString(n *sexprNode) (string, error) {
+	if n.isList || !n.quoted {
+		return "", unexpectedSExprNode(n)
+	}
+	return n.atom, nil
+}
+
+func decodeSExprInt(n *sexprNode) (int64, error) {
+	if n.isList || n.quoted {
+		return 0, unexpectedSExprNode(n)
+	}
+	i, err := strconv.ParseInt(n.atom, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s (%w)", ErrInvalidSExpr, n, err)
+	}
+	return i, nil
+}
+
+func decodeSExprSyntax(n *sexprNode) (Syntax, error) {
+	switch n.head() {
+	case "query":
+		var q Query
+		if err := decodeSExprQueryClauses(&q, n.list[1:]); err != nil {
+			return nil, err
+		}
+		return &q, nil
+	case "aggregate":
+		args := n.list[1:]
+		if len(args) == 0 || args[len(args)-1].head() != "over" {
+			return nil, fmt.Errorf("%w: aggregate requires over at %d", ErrInvalidSExpr, n.pos)
+		}
+		var q AggregationQuery
+		for _, arg := range args[:len(args)-1] {
+			if arg.head() == "having" {
+				having, err := sexprArgs(arg, "having", 1, 1)
+				if err != nil {
+					return nil, err
+				}
+				if q.Having, err = decodeSExprCondition(having[0]); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			s, err := decodeSExprSyntax(arg)
+			if err != nil {
+				return nil, err
+			}
+			a, ok := s.(Aggregation)
+			if !ok {
+				return nil, unexpectedSExprNode(arg)
+			}
+			q.Aggregations = append(q.Aggregations, a)
+		}
+		over, err := sexprArgs(args[len(args)-1], "over", 1, 1)
+		if err != nil {
+			return nil, err
+		}
+		if over[0].head() != "query" {
+			return nil, unexpectedSExprNode(over[0])
+		}
+		if err := decodeSExprQueryClauses(&q.Query, over[0].list[1:]); err != nil {
+			return nil, err
+		}
+		return &q, nil
+	case "count":
+		args, err := sexprArgs(n, "count", 0, 1)
+		if err != nil {
+			return nil, err
+		}
+		var a CountAggregation
+		a.Alias, err = decodeSExprAlias(args)
+		return &a, err
+	case "count-up-to":
+		args, err := sexprArgs(n, "count-up-to", 1, 2)
+		if err != nil {
+			return nil, err
+		}
+		var a CountUpToAggregation
+		if a.Limit, err = decodeSExprInt(args[0]); err != nil {
+			return nil, err
+		}
+		a.Alias, err = decodeSExprAlias(args[1:])
+		return &a, err
+	case "sum":
+		args, err := sexprArgs(n, "sum", 1, 2)
+		if err != nil {
+			return nil, err
+		}
+		var a SumAggregation
+		if a.Property, err = decodeSExprString(args[0]); err != nil {
+			return nil, err
+		}
+		a.Alias, err = decodeSExprAlias(args[1:])
+		return &a, err
+	case "avg":
+		args, err := sexprArgs(n, "avg", 1, 2)
+		if err != nil {
+			return nil, err
+		}
+		var a AvgAggregation
+		if a.Property, err = decodeSExprString(args[0]); err != nil {
+			return nil, err
+		}
+		a.Alias, err = decodeSExprAlias(args[1:])
+		return &a, err
+	case "and", "or":
+		args, err := sexprArgs(n, n.head(), 2, 2)
+		if err != nil {
+			return nil, err
+		}
+		left, err := decodeSExprCondition(args[0])
+		if err != nil {
+			return nil, err
+		}
+		right, err := decodeSExprCondition(args[1])
+		if err != nil {
+			return nil, err
+		}
+		if n.head() == "and" {
+			return &AndCompoundCondition{Left: left, Right: right}, nil
+		}
+		return &OrCompoundCondition{Left: left, Right: right}, nil
+	case "is-null":
+		args, err := sexprArgs(n, "is-null", 1, 1)
+		if err != nil {
+			return nil, err
+		}
+		property, err := decodeSExprString(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return &IsNullCondition{Property: property}, nil
+	case "either", "forward", "backward", "quantified":
+		args, err := sexprArgs(n, n.head(), 3, 3)
+		if err != nil {
+			return nil, err
+		}
+		comparator, err := decodeSExprString(args[0])
+		if err != nil {
+			return nil, err
+		}
+		property, err := decodeSExprString(args[1])
+		if err != nil {
+			return nil, err
+		}
+		value, err := decodeSExprValue(args[2])
+		if err != nil {
+			return nil, err
+		}
+		switch n.head() {
+		case "either":
+			if !EitherComparator(comparator).Valid() {
+				return nil, unexpectedSExprNode(args[0])
+			}
+			return &EitherComparatorCondition{Comparator: EitherComparator(comparator), Property: property, Value: value}, nil
+		case "forward":
+			if !ForwardComparator(comparator).Valid() {
+				return nil, unexpectedSExprNode(args[0])
+			}
+			return &ForwardComparatorCondition{Comparator: ForwardComparator(comparator), Property: property, Value: value}, nil
+		case "quantified":
+			if !QuantifiedComparator(comparator).Valid() {
+				return nil, unexpectedSExprNode(args[0])
+			}
+			return &QuantifiedComparatorCondition{Comparator: QuantifiedComparator(comparator), Property: property, Value: value}, nil
+		default:
+			if !BackwardComparator(comparator).Valid() {
+				return nil, unexpectedSExprNode(args[0])
+			}
+			return &BackwardComparatorCondition{Comparator: BackwardComparator(comparator), Property: property, Value: value}, nil
+		}
+	case "key":
+		v, err := decodeSExprValue(n)
+		if err != nil {
+			return nil, err
+		}
+		return v.(*Key), nil
+	case "order":
+		o, err := decodeSExprOrderBy(n)
+		if err != nil {
+			return nil, err
+		}
+		return &o, nil
+	case "limit":
+		var limit Limit
+		if err := decodeSExprResultPosition(n, &limit.Position, &limit.Cursor); err != nil {
+			return nil, err
+		}
+		return &limit, nil
+	case "offset":
+		var offset Offset
+		if err := decodeSExprResultPosition(n, &offset.Position, &offset.Cursor); err != nil {
+			return nil, err
+		}
+		return &offset, nil
+	default:
+		return nil, unexpectedSExprNode(n)
+	}
+}
+
+func decodeSExprQueryClauses(q *Query, clauses []*sexprNode) error {
+	for _, clause := range clauses {
+		switch clause.head() {
+		case "properties", "distinct-on", "group-by":
+			args, err := sexprArgs(clause, clause.head(), 1, -1)
+			if err != nil {
+				return err
+			}
+			props := make([]Property, len(args))
+			for i, arg := range args {
+				p, err := decodeSExprString(arg)
+				if err != nil {
+					return err
+				}
+				props[i] = Property(p)
+			}
+			switch clause.head() {
+			case "properties":
+				q.Properties = props
+			case "distinct-on":
+				q.DistinctOn = props
+			default:
+				q.GroupBy = props
+			}
+		case "aliases":
+			args, err := sexprArgs(clause, "aliases", 1, -1)
+			if err != nil {
+				return err
+			}
+			q.Aliases = make([]string, len(args))
+			for i, arg := range args {
+				if q.Aliases[i], err = decodeSExprString(arg); err != nil {
+					return err
+				}
+			}
+		case "keys-only":
+			if _, err := sexprArgs(clause, "keys-only", 0, 0); err != nil {
+				return err
+			}
+			q.KeysOnly = true
+		case "distinct":
+			if _, err := sexprArgs(clause, "distinct", 0, 0); err != nil {
+				return err
+			}
+			q.Distinct = true
+		case "kind":
+			args, err := sexprArgs(clause, "kind", 1, 1)
+			if err != nil {
+				return err
+			}
+			kind, err := decodeSExprString(args[0])
+			if err != nil {
+				return err
+			}
+			q.Kind = Kind(kind)
+		case "hints":
+			args, err := sexprArgs(clause, "hints", 1, 1)
+			if err != nil {
+				return err
+			}
+			directives, err := decodeSExprString(args[0])
+			if err != nil {
+				return err
+			}
+			if q.Hints, err = parseQueryHints(directives); err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidSExpr, err)
+			}
+		case "kind-binding":
+			args, err := sexprArgs(clause, "kind-binding", 1, 1)
+			if err != nil {
+				return err
+			}
+			b, err := decodeSExprBinding(args[0])
+			if err != nil {
+				return err
+			}
+			q.KindBinding = &KindBinding{Variable: b}
+		case "property-binding":
+			args, err := sexprArgs(clause, "property-binding", 3, 3)
+			if err != nil {
+				return err
+			}
+			c, err := decodeSExprString(args[0])
+			if err != nil {
+				return err
+			}
+			index, err := decodeSExprInt(args[1])
+			if err != nil {
+				return err
+			}
+			b, err := decodeSExprBinding(args[2])
+			if err != nil {
+				return err
+			}
+			q.PropertyBindings = append(q.PropertyBindings, &PropertyBinding{Clause: PropertyBindingClause(c), Index: int(index), Variable: b})
+		case "where":
+			args, err := sexprArgs(clause, "where", 1, 1)
+			if err != nil {
+				return err
+			}
+			if q.Where, err = decodeSExprCondition(args[0]); err != nil {
+				return err
+			}
+		case "order-by":
+			args, err := sexprArgs(clause, "order-by", 1, -1)
+			if err != nil {
+				return err
+			}
+			for _, arg := range args {
+				o, err := decodeSExprOrderBy(arg)
+				if err != nil {
+					return err
+				}
+				q.OrderBy = append(q.OrderBy, o)
+			}
+		case "limit":
+			q.Limit = new(Limit)
+			if err := decodeSExprResultPosition(clause, &q.Limit.Position, &q.Limit.Cursor); err != nil {
+				return err
+			}
+		case "offset":
+			q.Offset = new(Offset)
+			if err := decodeSExprResultPosition(clause, &q.Offset.Position, &q.Offset.Cursor); err != nil {
+				return err
+			}
+		default:
+			return unexpectedSExprNode(clause)
+		}
+	}
+	return nil
+}
+
+func decodeSExprAlias(args []*sexprNode) (string, error) {
+	if len(args) == 0 {
+		return "", nil
+	}
+	alias, err := sexprArgs(args[0], "as", 1, 1)
+	if err != nil {
+		return "", err
+	}
+	return decodeSExprString(alias[0])
+}
+
+func decodeSExprCondition(n *sexprNode) (Condition, error) {
+	s, err := decodeSExprSyntax(n)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := s.(Condition)
+	if !ok {
+		return nil, unexpectedSExprNode(n)
+	}
+	return c, nil
+}
+
+func decodeSExprOrderBy(n *sexprNode) (OrderBy, error) {
+	args, err := sexprArgs(n, "order", 2, 2)
+	if err != nil {
+		return OrderBy{}, err
+	}
+	property, err := decodeSExprString(args[0])
+	if err != nil {
+		return OrderBy{}, err
+	}
+	switch {
+	case !args[1].isList && !args[1].quoted && args[1].atom == "asc":
+		return OrderBy{Property: Property(property)}, nil
+	case !args[1].isList && !args[1].quoted && args[1].atom == "desc":
+		return OrderBy{Property: Property(property), Descending: true}, nil
+	default:
+		return OrderBy{}, unexpectedSExprNode(args[1])
+	}
+}
+
+func decodeSExprResultPosition(n *sexprNode, position *int64, cursor *BindingVariable) error {
+	args, err := sexprArgs(n, n.head(), 1, 2)
+	if err != nil {
+		return err
+	}
+	if *position, err = decodeSExprInt(args[0]); err != nil {
+		return err
+	}
+	if len(args) == 2 {
+		if *cursor, err = decodeSExprBinding(args[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeSExprBinding(n *sexprNode) (BindingVariable, error) {
+	v, err := decodeSExprValue(n)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := v.(BindingVariable)
+	if !ok {
+		return nil, unexpectedSExprNode(n)
+	}
+	return b, nil
+}
+
+func decodeSExprValue(n *sexprNode) (any, error) {
+	if n.quoted {
+		return n.atom, nil
+	}
+	if !n.isList {
+		switch n.atom {
+		case "null":
+			return nil, nil
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		if !strings.ContainsAny(n.atom, ".eIN") {
+			return decodeSExprInt(n)
+		}
+		f, err := strconv.ParseFloat(n.atom, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s (%w)", ErrInvalidSExpr, n, err)
+		}
+		return f, nil
+	}
+
+	switch n.head() {
+	case "array":
+		values := make([]any, len(n.list)-1)
+		for i, item := range n.list[1:] {
+			v, err := decodeSExprValue(item)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		return values, nil
+	case "blob":
+		args, err := sexprArgs(n, "blob", 1, 1)
+		if err != nil {
+			return nil, err
+		}
+		s, err := decodeSExprString(args[0])
+		if err != nil {
+			return nil, err
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s (%w)", ErrInvalidSExpr, args[0], err)
+		}
+		return b, nil
+	case "datetime":
+		args, err := sexprArgs(n, "datetime", 1, 1)
+		if err != nil {
+			return nil, err
+		}
+		s, err := decodeSExprString(args[0])
+		if err != nil {
+			return nil, err
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s (%w)", ErrInvalidSExpr, args[0], err)
+		}
+		return t, nil
+	case "binding":
+		args, err := sexprArgs(n, "binding", 1, 1)
+		if err != nil {
+			return nil, err
+		}
+		if args[0].quoted {
+			return &NamedBinding{Name: args[0].atom}, nil
+		}
+		index, err := decodeSExprInt(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return &IndexedBinding{Index: index}, nil
+	case "cursor":
+		args, err := sexprArgs(n, "cursor", 1, 1)
+		if err != nil {
+			return nil, err
+		}
+		s, err := decodeSExprString(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return Cursor(s), nil
+	case "key":
+		var key Key
+		for _, arg := range n.list[1:] {
+			switch arg.head() {
+			case "project", "namespace":
+				args, err := sexprArgs(arg, arg.head(), 1, 1)
+				if err != nil {
+					return nil, err
+				}
+				s, err := decodeSExprString(args[0])
+				if err != nil {
+					return nil, err
+				}
+				if arg.head() == "project" {
+					key.ProjectID = ProjectID(s)
+				} else {
+					key.Namespace = s
+				}
+			case "path":
+				args, err := sexprArgs(arg, "path", 2, 2)
+				if err != nil {
+					return nil, err
+				}
+				kind, err := decodeSExprString(args[0])
+				if err != nil {
+					return nil, err
+				}
+				path := &KeyPath{Kind: Kind(kind)}
+				switch {
+				case args[1].isList:
+					if path.Binding, err = decodeSExprBinding(args[1]); err != nil {
+						return nil, err
+					}
+				case args[1].quoted:
+					path.Name = args[1].atom
+				default:
+					if path.ID, err = decodeSExprInt(args[1]); err != nil {
+						return nil, err
+					}
+				}
+				key.Path = append(key.Path, path)
+			default:
+				return nil, unexpectedSExprNode(arg)
+			}
+		}
+		return &key, nil
+	default:
+		return nil, unexpectedSExprNode(n)
+	}
+}
@@ -0,0 +1,119 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestEncodeSExpr(t *testing.T) {
+	t.Parallel()
+
+	query, err := gqlparser.ParseQuery(gqlparser.NewLexer(
+		"SELECT DISTINCT ON (a) a, b FROM Kind WHERE a = 1 AND b IN ARRAY('x', 1.0, NULL, BLOB('YQ'), DATETIME('2013-09-29T09:30:20.00002-08:00'))" +
+			" OR __key__ HAS ANCESTOR KEY(NAMESPACE('ns'), Parent, 'p', Child, @id) ORDER BY a DESC, b LIMIT FIRST(10, @1) OFFSET 5",
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := gqlparser.EncodeSExpr(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `(query (properties "a" "b") (distinct-on "a") (kind "Kind")` +
+		` (where (or (and (either "=" "a" 1) (forward "IN" "b" (array "x" 1.0 null (blob "YQ==") (datetime "2013-09-29T09:30:20.00002-08:00"))))` +
+		` (forward "HAS ANCESTOR" "__key__" (key (namespace "ns") (path "Parent" "p") (path "Child" (binding "id"))))))` +
+		` (order-by (order "a" desc) (order "b" asc)) (limit 10 (binding 1)) (offset 5))`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestSExprRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := append(append([]integrateTestCase{}, queryTests...), aggregationQueryTests...)
+	tests = append(tests,
+		integrateTestCase{name: "Condition", want: &gqlparser.IsNullCondition{Property: "a.b"}},
+		integrateTestCase{name: "Doubles", want: &gqlparser.ForwardComparatorCondition{
+			Comparator: gqlparser.InForwardComparator,
+			Property:   "a",
+			Value:      []any{math.Inf(1), math.Inf(-1), 1e100, -0.5, []any{}},
+		}},
+		integrateTestCase{name: "Cursor", want: &gqlparser.Limit{Position: 1, Cursor: gqlparser.Cursor("AQID")}},
+		integrateTestCase{name: "Template", want: &gqlparser.Query{
+			Properties:       []gqlparser.Property{""},
+			KindBinding:      &gqlparser.KindBinding{Variable: &gqlparser.NamedBinding{Name: "kind"}},
+			PropertyBindings: []*gqlparser.PropertyBinding{{Clause: gqlparser.ProjectionPropertyBindingClause, Variable: &gqlparser.IndexedBinding{Index: 1}}},
+		}},
+		integrateTestCase{name: "Aggregations", want: &gqlparser.AggregationQuery{
+			Aggregations: []gqlparser.Aggregation{
+				&gqlparser.CountUpToAggregation{Limit: 10},
+				&gqlparser.SumAggregation{Property: "a", Alias: "s"},
+				&gqlparser.AvgAggregation{Property: "b"},
+			},
+			Query: gqlparser.Query{Kind: "Kind"},
+		}},
+		integrateTestCase{name: "GroupByAndHaving", want: &gqlparser.AggregationQuery{
+			Aggregations: []gqlparser.Aggregation{&gqlparser.CountAggregation{Alias: "c"}},
+			Query:        gqlparser.Query{Kind: "Kind", GroupBy: []gqlparser.Property{"a", "b"}},
+			Having:       &gqlparser.EitherComparatorCondition{Comparator: gqlparser.GreaterThanEitherComparator, Property: "c", Value: int64(1)},
+		}},
+	)
+	for _, tt := range tests {
+		tt := tt
+		if tt.wantErr {
+			continue
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := gqlparser.EncodeSExpr(tt.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := gqlparser.DecodeSExpr(encoded)
+			if err != nil {
+				t.Fatalf("DecodeSExpr(%s) error = %v", encoded, err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("%s (-want, +got)\n%s", encoded, diff)
+			}
+		})
+	}
+}
+
+func TestDecodeSExpr_Error(t *testing.T) {
+	t.Parallel()
+
+	for _, source := range []string{
+		``,
+		`(query`,
+		`(query))`,
+		`(unknown)`,
+		`(query (kind Kind))`,
+		`(query (where (either "~" "a" 1)))`,
+		`(query (where (either "=" "a")))`,
+		`(query (where (either "=" "a" 1.2.3)))`,
+		`(query (where (either "=" "a" "unclosed)))`,
+		`(query (order-by (order "a" up)))`,
+		`(aggregate (count) (query))`,
+		`(aggregate (kind "Kind") (over (query)))`,
+		`(limit 1 2)`,
+		`(key (path "Kind" 1.5))`,
+	} {
+		source := source
+		t.Run(source, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := gqlparser.DecodeSExpr(source); !errors.Is(err, gqlparser.ErrInvalidSExpr) {
+				t.Errorf("DecodeSExpr() error = %v, want %v", err, gqlparser.ErrInvalidSExpr)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package gqlparser
+
+import "errors"
+
+// Span is the range in the source. Start and End are the byte offsets, and End is exclusive.
+type Span struct {
+	Start int
+	End   int
+}
+
+// LiteralSpanKind is the kind of the literal or the binding found in the source.
+type LiteralSpanKind string
+
+const (
+	StringLiteralSpan   LiteralSpanKind = "string"
+	IntegerLiteralSpan  LiteralSpanKind = "integer"
+	DoubleLiteralSpan   LiteralSpanKind = "double"
+	BooleanLiteralSpan  LiteralSpanKind = "boolean"
+	NullLiteralSpan     LiteralSpanKind = "null"
+	KeyLiteralSpan      LiteralSpanKind = "key"
+	BlobLiteralSpan     LiteralSpanKind = "blob"
+	DateTimeLiteralSpan LiteralSpanKind = "datetime"
+	BindingSpan         LiteralSpanKind = "binding"
+)
+
+// LiteralSpan is the span of the literal or the binding in the source.
+type LiteralSpan struct {
+	Kind LiteralSpanKind
+	// Start and End are the byte offsets in the source. End is exclusive.
+	Start int
+	End   int
+	Text  string
+}
+
+// literalFunctions are the keywords of the literals taking the arguments in the parentheses.
+var literalFunctions = map[string]LiteralSpanKind{
+	"KEY":      KeyLiteralSpan,
+	"BLOB":     BlobLiteralSpan,
+	"DATETIME": DateTimeLiteralSpan,
+}
+
+// LiteralSpans returns the spans of every literal and binding in the query in the order of appearance.
+// The source must be a valid query or aggregation query. The elements of ARRAY are returned individually,
+// and the KEY, BLOB and DATETIME literals are returned as a whole.
+func LiteralSpans(source string) ([]LiteralSpan, error) {
+	if _, _, err := ParseQueryOrAggregationQuery(NewLexer(source)); err != nil {
+		return nil, err
+	}
+
+	var tokens []Token
+	for lexer := NewLexer(source); lexer.Next(); {
+		token, err := lexer.Read()
+		if errors.Is(err, ErrEndOfToken) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+
+	var spans []LiteralSpan
+	span := func(kind LiteralSpanKind, start, end int) {
+		spans = append(spans, LiteralSpan{Kind: kind, Start: start, End: end, Text: source[start:end]})
+	}
+	for i := 0; i < len(tokens); i++ {
+		start := tokens[i].GetPosition()
+		end := start + len(tokens[i].GetContent())
+		switch t := tokens[i].(type) {
+		case *StringToken:
+			if t.Quote != '`' {
+				span(StringLiteralSpan, start, end)
+			}
+		case *NumericToken:
+			if t.Floating {
+				span(DoubleLiteralSpan, start, end)
+			} else {
+				span(IntegerLiteralSpan, start, end)
+			}
+		case *BooleanToken:
+			span(BooleanLiteralSpan, start, end)
+		case *BindingToken:
+			span(BindingSpan, start, end)
+		case *KeywordToken:
+			if t.Name == "NULL" {
+				span(NullLiteralSpan, start, end)
+			} else if kind, ok := literalFunctions[t.Name]; ok {
+				// skip to the closing parenthesis. the parentheses are balanced because the query is valid.
+				depth := 0
+				for i++; i < len(tokens); i++ {
+					if op, ok := tokens[i].(*OperatorToken); ok && op.Type == "(" {
+						depth++
+					} else if ok && op.Type == ")" {
+						depth--
+						if depth == 0 {
+							break
+						}
+					}
+				}
+				span(kind, start, tokens[i].GetPosition()+1)
+			}
+		}
+	}
+	return spans, nil
+}
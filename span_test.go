@@ -0,0 +1,49 @@
+package gqlparser_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestLiteralSpans(t *testing.T) {
+	t.Parallel()
+
+	const source = "SELECT * FROM `Kind` WHERE a = 'x' AND b IN ARRAY(1, 2.5, @v) AND c = NULL AND d = true" +
+		" AND __key__ HAS ANCESTOR KEY(Parent, 'p') AND e > DATETIME('2013-09-29T09:30:20Z') LIMIT @1"
+	got, err := gqlparser.LiteralSpans(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []gqlparser.LiteralSpan{
+		{Kind: gqlparser.StringLiteralSpan, Text: "'x'"},
+		{Kind: gqlparser.IntegerLiteralSpan, Text: "1"},
+		{Kind: gqlparser.DoubleLiteralSpan, Text: "2.5"},
+		{Kind: gqlparser.BindingSpan, Text: "@v"},
+		{Kind: gqlparser.NullLiteralSpan, Text: "NULL"},
+		{Kind: gqlparser.BooleanLiteralSpan, Text: "true"},
+		{Kind: gqlparser.KeyLiteralSpan, Text: "KEY(Parent, 'p')"},
+		{Kind: gqlparser.DateTimeLiteralSpan, Text: "DATETIME('2013-09-29T09:30:20Z')"},
+		{Kind: gqlparser.BindingSpan, Text: "@1"},
+	}
+	for i := range want {
+		if i < len(got) {
+			want[i].Start = got[i].Start
+			want[i].End = got[i].End
+		}
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	for _, span := range got {
+		if source[span.Start:span.End] != span.Text {
+			t.Errorf("span %+v doesn't match the source %q", span, source[span.Start:span.End])
+		}
+	}
+
+	if _, err := gqlparser.LiteralSpans("SELECT * FROM"); err == nil {
+		t.Error("LiteralSpans() error = nil for the invalid query")
+	}
+}
@@ -0,0 +1,88 @@
+package gqlparser
+
+import (
+	"math"
+)
+
+// SplitOr splits the query into the subqueries without OR by expanding WHERE into the disjunctive normal form.
+// e.g. SELECT * FROM Kind WHERE (a = 1 OR b = 2) AND c = 3 is split into
+// SELECT * FROM Kind WHERE a = 1 AND c = 3 and SELECT * FROM Kind WHERE b = 2 AND c = 3.
+// The subqueries share the clauses except WHERE with the query, including LIMIT and OFFSET, so use PlanOr to execute them.
+// The query without OR is returned as the only subquery. The number of the subqueries can be limited by Limits.MaxOrBranches,
+// and the query that would be split into more subqueries than MaxDisjunctions is reported as LimitViolationError.
+func SplitOr(q *Query) ([]*Query, error) {
+	if q.Where == nil {
+		return []*Query{q}, nil
+	}
+	disjunctions, err := disjunctiveNormalForm(q.Where)
+	if err != nil {
+		return nil, err
+	}
+	if len(disjunctions) == 1 {
+		return []*Query{q}, nil
+	}
+
+	subqueries := make([]*Query, len(disjunctions))
+	for i, conjunction := range disjunctions {
+		subquery := *q
+		subquery.Where = And(conjunction...)
+		subqueries[i] = &subquery
+	}
+	return subqueries, nil
+}
+
+// OrPlan is the plan to execute the query that has OR on the client side by the subqueries without OR.
+//
+// The results of the subqueries are merged in the order of OrderBy, the duplicated entities matching multiple subqueries are
+// removed by the keys, and then Offset and Limit are applied to the merged results. Merge implements it.
+// Each subquery fetches LIMIT + OFFSET entities at most, which are enough to fill the merged results since every entity
+// in the merged results is ranked in its subquery no lower than in the merged results.
+type OrPlan struct {
+	// Subqueries are the branches of OR ordered by OrderBy. They have no OFFSET.
+	Subqueries []*Query
+	// OrderBy is the order of the subqueries to merge the results. It ends with __key__ to order the entities totally.
+	OrderBy []OrderBy
+	// Limit is the number of the merged results. It's nil if unlimited.
+	Limit *Limit
+	// Offset is the number of the merged results to skip. It's nil if no results are skipped.
+	Offset *Offset
+}
+
+// PlanOr splits the query by SplitOr and pushes LIMIT and OFFSET into the subqueries as OrPlan describes.
+// The query without OR is planned as the only subquery keeping LIMIT and OFFSET, so the results need not be merged.
+// It returns UnsupportedFeatureError for the cursors and DISTINCT that cannot be applied to the merged results.
+func PlanOr(q *Query) (*OrPlan, error) {
+	subqueries, err := SplitOr(q)
+	if err != nil {
+		return nil, err
+	}
+	if len(subqueries) == 1 {
+		return &OrPlan{Subqueries: subqueries, OrderBy: q.OrderBy}, nil
+	}
+
+	if (q.Limit != nil && q.Limit.Cursor != nil) || (q.Offset != nil && q.Offset.Cursor != nil) {
+		return nil, &UnsupportedFeatureError{Feature: "cursor with OR"}
+	}
+	if q.Distinct || len(q.DistinctOn) != 0 {
+		return nil, &UnsupportedFeatureError{Feature: "DISTINCT with OR"}
+	}
+
+	orderBy := q.OrderBy
+	if len(orderBy) == 0 || orderBy[len(orderBy)-1].Property != keyProperty {
+		orderBy = append(orderBy[:len(orderBy):len(orderBy)], OrderBy{Property: keyProperty})
+	}
+
+	for _, subquery := range subqueries {
+		subquery.OrderBy = orderBy
+		subquery.Offset = nil
+		if q.Limit != nil {
+			position := q.Limit.Position
+			if q.Offset != nil && q.Offset.Position > 0 {
+				// saturate not to overflow since it's unlimited in effect
+				position = min(position, math.MaxInt64-q.Offset.Position) + q.Offset.Position
+			}
+			subquery.Limit = &Limit{Position: position}
+		}
+	}
+	return &OrPlan{Subqueries: subqueries, OrderBy: orderBy, Limit: q.Limit, Offset: q.Offset}, nil
+}
@@ -0,0 +1,197 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestSplitOr(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{
+			name:   "NoWhere",
+			source: "SELECT * FROM Kind",
+			want:   []string{"SELECT * FROM Kind"},
+		},
+		{
+			name:   "NoOr",
+			source: "SELECT * FROM Kind WHERE a = 1 AND b = 2 LIMIT 10",
+			want:   []string{"SELECT * FROM Kind WHERE a = 1 AND b = 2 LIMIT 10"},
+		},
+		{
+			name:   "Distribute",
+			source: "SELECT a FROM Kind WHERE (a = 1 OR b = 2) AND (c = 3 OR d IS NULL) ORDER BY a LIMIT 10",
+			want: []string{
+				"SELECT a FROM Kind WHERE a = 1 AND c = 3 ORDER BY a LIMIT 10",
+				"SELECT a FROM Kind WHERE a = 1 AND d IS NULL ORDER BY a LIMIT 10",
+				"SELECT a FROM Kind WHERE b = 2 AND c = 3 ORDER BY a LIMIT 10",
+				"SELECT a FROM Kind WHERE b = 2 AND d IS NULL ORDER BY a LIMIT 10",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source))
+			if err != nil {
+				t.Fatal(err)
+			}
+			subqueries, err := gqlparser.SplitOr(query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, subquery := range subqueries {
+				s, err := gqlparser.FormatQuery(subquery)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, s)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSplitOr_TooManyDisjunctions(t *testing.T) {
+	t.Parallel()
+
+	conditions := make([]string, 5)
+	for i := range conditions {
+		conditions[i] = fmt.Sprintf("(a%d = 1 OR b%d = 2)", i, i)
+	}
+	query, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM Kind WHERE " + strings.Join(conditions, " AND ")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gqlparser.SplitOr(query); !errors.Is(err, gqlparser.ErrLimitExceeded) {
+		t.Errorf("SplitOr() error = %v, want %v", err, gqlparser.ErrLimitExceeded)
+	}
+	if _, err := gqlparser.PlanOr(query); !errors.Is(err, gqlparser.ErrLimitExceeded) {
+		t.Errorf("PlanOr() error = %v, want %v", err, gqlparser.ErrLimitExceeded)
+	}
+}
+
+func TestPlanOr(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		source         string
+		wantSubqueries []string
+		wantOrderBy    []gqlparser.OrderBy
+		wantLimit      *gqlparser.Limit
+		wantOffset     *gqlparser.Offset
+	}{
+		{
+			name:           "NoOr",
+			source:         "SELECT * FROM Kind WHERE a = 1 ORDER BY b LIMIT 10 OFFSET 5",
+			wantSubqueries: []string{"SELECT * FROM Kind WHERE a = 1 ORDER BY b LIMIT 10 OFFSET 5"},
+			wantOrderBy:    []gqlparser.OrderBy{{Property: "b"}},
+		},
+		{
+			name:   "LimitOffset",
+			source: "SELECT * FROM Kind WHERE a = 1 OR a = 2 ORDER BY b DESC LIMIT 10 OFFSET 5",
+			wantSubqueries: []string{
+				"SELECT * FROM Kind WHERE a = 1 ORDER BY b DESC, __key__ LIMIT 15",
+				"SELECT * FROM Kind WHERE a = 2 ORDER BY b DESC, __key__ LIMIT 15",
+			},
+			wantOrderBy: []gqlparser.OrderBy{{Property: "b", Descending: true}, {Property: "__key__"}},
+			wantLimit:   &gqlparser.Limit{Position: 10},
+			wantOffset:  &gqlparser.Offset{Position: 5},
+		},
+		{
+			name:   "OffsetOnly",
+			source: "SELECT * FROM Kind WHERE a = 1 OR a = 2 ORDER BY __key__ DESC OFFSET 5",
+			wantSubqueries: []string{
+				"SELECT * FROM Kind WHERE a = 1 ORDER BY __key__ DESC",
+				"SELECT * FROM Kind WHERE a = 2 ORDER BY __key__ DESC",
+			},
+			wantOrderBy: []gqlparser.OrderBy{{Property: "__key__", Descending: true}},
+			wantOffset:  &gqlparser.Offset{Position: 5},
+		},
+		{
+			name:   "Saturated",
+			source: "SELECT * FROM Kind WHERE a = 1 OR a = 2 LIMIT 9223372036854775807 OFFSET 1",
+			wantSubqueries: []string{
+				"SELECT * FROM Kind WHERE a = 1 ORDER BY __key__ LIMIT 9223372036854775807",
+				"SELECT * FROM Kind WHERE a = 2 ORDER BY __key__ LIMIT 9223372036854775807",
+			},
+			wantOrderBy: []gqlparser.OrderBy{{Property: "__key__"}},
+			wantLimit:   &gqlparser.Limit{Position: 9223372036854775807},
+			wantOffset:  &gqlparser.Offset{Position: 1},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source))
+			if err != nil {
+				t.Fatal(err)
+			}
+			plan, err := gqlparser.PlanOr(query)
+			if err != nil {
+				t.Fatalf("PlanOr() error = %v", err)
+			}
+			var got []string
+			for _, subquery := range plan.Subqueries {
+				s, err := gqlparser.FormatQuery(subquery)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, s)
+			}
+			if diff := cmp.Diff(tt.wantSubqueries, got); diff != "" {
+				t.Errorf("Subqueries: (-want, +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantOrderBy, plan.OrderBy); diff != "" {
+				t.Errorf("OrderBy: (-want, +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantLimit, plan.Limit); diff != "" {
+				t.Errorf("Limit: (-want, +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantOffset, plan.Offset); diff != "" {
+				t.Errorf("Offset: (-want, +got)\n%s", diff)
+			}
+
+			// the query is left as is
+			if s, err := gqlparser.FormatQuery(query); err != nil || s != tt.source {
+				t.Errorf("FormatQuery() = %q, %v, want %q", s, err, tt.source)
+			}
+		})
+	}
+}
+
+func TestPlanOr_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	for _, source := range []string{
+		"SELECT * FROM Kind WHERE a = 1 OR a = 2 LIMIT @cursor",
+		"SELECT * FROM Kind WHERE a = 1 OR a = 2 OFFSET @cursor + 1",
+		"SELECT DISTINCT a FROM Kind WHERE a = 1 OR a = 2",
+	} {
+		query, err := gqlparser.ParseQuery(gqlparser.NewLexer(source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := gqlparser.PlanOr(query); !errors.Is(err, gqlparser.ErrUnsupportedFeature) {
+			t.Errorf("PlanOr(%q) error = %v, want %v", source, err, gqlparser.ErrUnsupportedFeature)
+		}
+	}
+}
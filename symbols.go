@@ -0,0 +1,261 @@
+package gqlparser
+
+import (
+	"errors"
+	"strings"
+)
+
+// DocumentSymbolKind is the kind of the symbol in the query for the language servers.
+type DocumentSymbolKind string
+
+const (
+	// ClauseDocumentSymbol is the clause including the keywords. e.g. WHERE a = 1
+	ClauseDocumentSymbol DocumentSymbolKind = "clause"
+	// PropertyDocumentSymbol is the property in SELECT, DISTINCT ON or GROUP BY including the alias. e.g. a AS x
+	PropertyDocumentSymbol DocumentSymbolKind = "property"
+	// AggregationDocumentSymbol is the aggregation including the alias. e.g. COUNT(*) AS c
+	AggregationDocumentSymbol DocumentSymbolKind = "aggregation"
+	// KindDocumentSymbol is the kind in FROM.
+	KindDocumentSymbol DocumentSymbolKind = "kind"
+	// ConditionDocumentSymbol is the comparison or the IS NULL condition in WHERE or HAVING. e.g. a IN ARRAY(1, 2)
+	ConditionDocumentSymbol DocumentSymbolKind = "condition"
+	// OrderDocumentSymbol is the entry of ORDER BY. e.g. a DESC
+	OrderDocumentSymbol DocumentSymbolKind = "order"
+)
+
+// DocumentSymbol is the symbol in the query with the range in the source.
+type DocumentSymbol struct {
+	Kind DocumentSymbolKind
+	// Name is the clause name for the clauses, and the text in the source for the others. e.g. ORDER BY, a DESC
+	Name string
+	Span Span
+	// Children are the symbols in the clause in the order of appearance.
+	Children []*DocumentSymbol
+}
+
+// DocumentSymbols returns the clauses of the query or the aggregation query and the symbols in them
+// in the order of appearance, to back the language servers. The source must be valid with the options.
+// The clauses of the query in AGGREGATE ... OVER (...) are returned following the AGGREGATE clause.
+func DocumentSymbols(source string, opts ...ParseOption) ([]*DocumentSymbol, error) {
+	scanner, err := newSymbolScanner(source, opts)
+	if err != nil {
+		return nil, err
+	}
+	return scanner.clauses(), nil
+}
+
+func newSymbolScanner(source string, opts []ParseOption) (*symbolScanner, error) {
+	o := newParseOptions(opts)
+	if _, _, err := ParseQueryOrAggregationQuery(o.newLexer(source), opts...); err != nil {
+		return nil, err
+	}
+
+	scanner := &symbolScanner{source: source, dialect: o.dialect}
+	for lexer := o.newLexer(source); lexer.Next(); {
+		token, err := lexer.Read()
+		if errors.Is(err, ErrEndOfToken) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		// the token ends at the next one because the contents of some tokens differ from the source
+		if n := len(scanner.ends); n != 0 && scanner.ends[n-1] < 0 {
+			scanner.ends[n-1] = token.GetPosition()
+		}
+		if _, ok := token.(*WhitespaceToken); !ok {
+			scanner.tokens = append(scanner.tokens, token)
+			scanner.ends = append(scanner.ends, -1)
+		}
+	}
+	if n := len(scanner.ends); n != 0 && scanner.ends[n-1] < 0 {
+		scanner.ends[n-1] = len(source)
+	}
+	return scanner, nil
+}
+
+// symbolScanner finds the symbols in the tokens without the whitespaces of the valid query.
+type symbolScanner struct {
+	source string
+	tokens []Token
+	// ends are the ends of the tokens in the source.
+	ends    []int
+	dialect *Dialect
+}
+
+func (s *symbolScanner) symbol(kind DocumentSymbolKind, first, last int) *DocumentSymbol {
+	span := Span{Start: s.tokens[first].GetPosition(), End: s.ends[last]}
+	return &DocumentSymbol{Kind: kind, Name: s.source[span.Start:span.End], Span: span}
+}
+
+// clauseHeader returns the name of the clause and the number of the tokens of the keywords if the clause begins at i.
+func (s *symbolScanner) clauseHeader(i int) (string, int) {
+	switch t := s.tokens[i].(type) {
+	case *KeywordToken:
+		switch t.Name {
+		case "SELECT", "AGGREGATE", "FROM", "WHERE", "LIMIT", "OFFSET":
+			return t.Name, 1
+		case "ORDER":
+			return "ORDER BY", 2
+		}
+	case *SymbolToken:
+		if s.dialect == nil {
+			return "", 0
+		}
+		if s.dialect.groupBy && strings.EqualFold(t.Content, "GROUP") && i+1 < len(s.tokens) {
+			if by, ok := s.tokens[i+1].(*KeywordToken); ok && by.Name == "BY" {
+				return "GROUP BY", 2
+			}
+		}
+		if s.dialect.having && strings.EqualFold(t.Content, "HAVING") {
+			return "HAVING", 1
+		}
+	}
+	return "", 0
+}
+
+// isBoundary reports whether the token at i ends the clause.
+func (s *symbolScanner) isBoundary(i int) bool {
+	if name, _ := s.clauseHeader(i); name != "" {
+		return true
+	}
+	switch t := s.tokens[i].(type) {
+	case *KeywordToken:
+		return t.Name == "OVER"
+	case *SemicolonToken:
+		return true
+	case *OperatorToken:
+		return t.Type == ";"
+	}
+	return false
+}
+
+func (s *symbolScanner) clauses() []*DocumentSymbol {
+	var clauses []*DocumentSymbol
+	for i := 0; i < len(s.tokens); i++ {
+		name, width := s.clauseHeader(i)
+		if name == "" {
+			continue
+		}
+
+		// the clause ends before the next clause or the closing parenthesis of OVER (...)
+		first, last := i+width, i+width-1
+		for depth := 0; last+1 < len(s.tokens) && !s.isBoundary(last+1); last++ {
+			if op, ok := s.tokens[last+1].(*OperatorToken); ok && op.Type == "(" {
+				depth++
+			} else if ok && op.Type == ")" {
+				if depth == 0 {
+					break
+				}
+				depth--
+			}
+		}
+
+		clause := s.symbol(ClauseDocumentSymbol, i, last)
+		clause.Name = name
+		switch name {
+		case "SELECT", "AGGREGATE":
+			clause.Children = s.projections(first, last)
+		case "FROM":
+			clause.Children = []*DocumentSymbol{s.symbol(KindDocumentSymbol, first, last)}
+		case "WHERE", "HAVING":
+			clause.Children = s.conditions(first, last)
+		case "GROUP BY":
+			clause.Children = s.items(PropertyDocumentSymbol, first, last)
+		case "ORDER BY":
+			clause.Children = s.items(OrderDocumentSymbol, first, last)
+		}
+		clauses = append(clauses, clause)
+		i = last
+	}
+	return clauses
+}
+
+// items splits the tokens from first to last by the commas not in the parentheses.
+func (s *symbolScanner) items(kind DocumentSymbolKind, first, last int) []*DocumentSymbol {
+	var items []*DocumentSymbol
+	start, depth := first, 0
+	for i := first; i <= last; i++ {
+		op, ok := s.tokens[i].(*OperatorToken)
+		switch {
+		case ok && op.Type == "(":
+			depth++
+		case ok && op.Type == ")":
+			depth--
+		case ok && op.Type == "," && depth == 0:
+			items = append(items, s.symbol(kind, start, i-1))
+			start = i + 1
+		}
+	}
+	if start <= last {
+		items = append(items, s.symbol(kind, start, last))
+	}
+	return items
+}
+
+// projections returns the properties of DISTINCT ON and the projection, or the aggregations.
+func (s *symbolScanner) projections(first, last int) []*DocumentSymbol {
+	var symbols []*DocumentSymbol
+	if keyword, ok := s.tokens[first].(*KeywordToken); ok && keyword.Name == "DISTINCT" {
+		first++
+		if keyword, ok := s.tokens[first].(*KeywordToken); ok && keyword.Name == "ON" {
+			// DISTINCT ON (a, b)
+			closing := first + 1
+			for ; closing < last; closing++ {
+				if op, ok := s.tokens[closing].(*OperatorToken); ok && op.Type == ")" {
+					break
+				}
+			}
+			symbols = append(symbols, s.items(PropertyDocumentSymbol, first+2, closing-1)...)
+			first = closing + 1
+		}
+	}
+	for _, item := range s.items(PropertyDocumentSymbol, first, last) {
+		if _, ok := aggregationKeywords[strings.ToUpper(strings.SplitN(item.Name, "(", 2)[0])]; ok {
+			item.Kind = AggregationDocumentSymbol
+		}
+		symbols = append(symbols, item)
+	}
+	return symbols
+}
+
+// aggregationKeywords are the keywords beginning the aggregations.
+var aggregationKeywords = map[string]struct{}{
+	"COUNT":       {},
+	"COUNT_UP_TO": {},
+	"SUM":         {},
+	"AVG":         {},
+}
+
+// conditions returns the comparisons and the IS NULL conditions joined by AND and OR.
+// The parentheses at the beginnings of the conditions group them, and the others are the values. e.g. ARRAY(1, 2)
+func (s *symbolScanner) conditions(first, last int) []*DocumentSymbol {
+	var conditions []*DocumentSymbol
+	start := -1
+	for i := first; i <= last; i++ {
+		op, ok := s.tokens[i].(*OperatorToken)
+		switch {
+		case ok && op.Type == "(" && start >= 0:
+			// skip the values in the parentheses
+			for depth := 0; i <= last; i++ {
+				if op, ok := s.tokens[i].(*OperatorToken); ok && op.Type == "(" {
+					depth++
+				} else if ok && op.Type == ")" {
+					if depth--; depth == 0 {
+						break
+					}
+				}
+			}
+		case ok && (op.Type == "(" || op.Type == ")" || op.Type == "AND" || op.Type == "OR"):
+			if start >= 0 {
+				conditions = append(conditions, s.symbol(ConditionDocumentSymbol, start, i-1))
+				start = -1
+			}
+		case start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		conditions = append(conditions, s.symbol(ConditionDocumentSymbol, start, last))
+	}
+	return conditions
+}
@@ -0,0 +1,97 @@
+package gqlparser_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestDocumentSymbols(t *testing.T) {
+	t.Parallel()
+
+	dialect, err := gqlparser.NewDialect(gqlparser.WithGroupBy(), gqlparser.WithHaving())
+	if err != nil {
+		t.Fatal(err)
+	}
+	clause := func(name string, start, end int, children ...*gqlparser.DocumentSymbol) *gqlparser.DocumentSymbol {
+		return &gqlparser.DocumentSymbol{Kind: gqlparser.ClauseDocumentSymbol, Name: name, Span: gqlparser.Span{Start: start, End: end}, Children: children}
+	}
+	symbol := func(kind gqlparser.DocumentSymbolKind, name string, start int) *gqlparser.DocumentSymbol {
+		return &gqlparser.DocumentSymbol{Kind: kind, Name: name, Span: gqlparser.Span{Start: start, End: start + len(name)}}
+	}
+
+	tests := []struct {
+		name   string
+		source string
+		want   []*gqlparser.DocumentSymbol
+	}{
+		{
+			name:   "Query",
+			source: "SELECT DISTINCT ON (a) a AS x, b FROM Kind WHERE a = 1 AND (b > @b OR c IN ARRAY(1, 2)) ORDER BY a DESC, b LIMIT 10 OFFSET 5",
+			want: []*gqlparser.DocumentSymbol{
+				clause("SELECT", 0, 32,
+					symbol(gqlparser.PropertyDocumentSymbol, "a", 20),
+					symbol(gqlparser.PropertyDocumentSymbol, "a AS x", 23),
+					symbol(gqlparser.PropertyDocumentSymbol, "b", 31),
+				),
+				clause("FROM", 33, 42, symbol(gqlparser.KindDocumentSymbol, "Kind", 38)),
+				clause("WHERE", 43, 87,
+					symbol(gqlparser.ConditionDocumentSymbol, "a = 1", 49),
+					symbol(gqlparser.ConditionDocumentSymbol, "b > @b", 60),
+					symbol(gqlparser.ConditionDocumentSymbol, "c IN ARRAY(1, 2)", 70),
+				),
+				clause("ORDER BY", 88, 106,
+					symbol(gqlparser.OrderDocumentSymbol, "a DESC", 97),
+					symbol(gqlparser.OrderDocumentSymbol, "b", 105),
+				),
+				clause("LIMIT", 107, 115),
+				clause("OFFSET", 116, 124),
+			},
+		},
+		{
+			name:   "AggregationQuery",
+			source: "AGGREGATE COUNT(*) AS c, SUM(a) OVER (SELECT * FROM Kind WHERE @x IN tags /* comment */)",
+			want: []*gqlparser.DocumentSymbol{
+				clause("AGGREGATE", 0, 31,
+					symbol(gqlparser.AggregationDocumentSymbol, "COUNT(*) AS c", 10),
+					symbol(gqlparser.AggregationDocumentSymbol, "SUM(a)", 25),
+				),
+				clause("SELECT", 38, 46, symbol(gqlparser.PropertyDocumentSymbol, "*", 45)),
+				clause("FROM", 47, 56, symbol(gqlparser.KindDocumentSymbol, "Kind", 52)),
+				clause("WHERE", 57, 73, symbol(gqlparser.ConditionDocumentSymbol, "@x IN tags", 63)),
+			},
+		},
+		{
+			name:   "GroupBy",
+			source: "SELECT COUNT(*) AS c FROM Kind GROUP BY a, b HAVING c > 1;",
+			want: []*gqlparser.DocumentSymbol{
+				clause("SELECT", 0, 20, symbol(gqlparser.AggregationDocumentSymbol, "COUNT(*) AS c", 7)),
+				clause("FROM", 21, 30, symbol(gqlparser.KindDocumentSymbol, "Kind", 26)),
+				clause("GROUP BY", 31, 44,
+					symbol(gqlparser.PropertyDocumentSymbol, "a", 40),
+					symbol(gqlparser.PropertyDocumentSymbol, "b", 43),
+				),
+				clause("HAVING", 45, 57, symbol(gqlparser.ConditionDocumentSymbol, "c > 1", 52)),
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := gqlparser.DocumentSymbols(tt.source, gqlparser.WithDialect(dialect))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := gqlparser.DocumentSymbols("SELECT * FROM"); err == nil {
+		t.Error("DocumentSymbols() should fail")
+	}
+}
@@ -1,6 +1,8 @@
 package gqlparser
 
 import (
+	"fmt"
+
 	"github.com/karupanerura/runetrie"
 )
 
@@ -28,17 +30,36 @@ type KeyPath struct {
 	Kind Kind
 	ID   int64
 	Name string
+	// Binding is the placeholder of the ID or the name. e.g. KEY(Kind, @id)
+	// It's resolved into ID or Name by Key.Bind, and kept to re-bind the key.
+	Binding BindingVariable
 }
 
 type Query struct {
 	Properties []Property
+	// Aliases are the aliases of the projected properties by AS. e.g. SELECT a AS x FROM Kind
+	// It's nil if no property has the alias, otherwise it has the same length as Properties with the empty aliases.
+	Aliases []string
+	// ProjectionSpans are the spans of the projected properties including the aliases.
+	// They are recorded only if parsed with WithProjectionSpans, and have the same length as Properties.
+	ProjectionSpans []Span
+	// KeysOnly is true if the projection is only the special property __key__. e.g. SELECT __key__ FROM Kind
+	KeysOnly   bool
 	Distinct   bool
 	DistinctOn []Property
 	Kind       Kind
 	Where      Condition
-	OrderBy    []OrderBy
-	Limit      *Limit
-	Offset     *Offset
+	// GroupBy is the properties of GROUP BY permitted by the dialect. e.g. SELECT a FROM Kind GROUP BY a
+	GroupBy []Property
+	OrderBy []OrderBy
+	Limit   *Limit
+	Offset  *Offset
+
+	KindBinding      *KindBinding
+	PropertyBindings []*PropertyBinding
+
+	// Hints are the directives of the hint comment following SELECT or AGGREGATE. It's nil if there is no hint comment.
+	Hints *QueryHints
 }
 
 func (*Query) isSyntax() {}
@@ -67,6 +88,8 @@ func (*Offset) isSyntax() {}
 type AggregationQuery struct {
 	Aggregations []Aggregation
 	Query
+	// Having is the condition of HAVING permitted by the dialect. e.g. SELECT COUNT(*) AS c FROM Kind GROUP BY a HAVING c > 1
+	Having Condition
 }
 
 func (*AggregationQuery) isSyntax() {}
@@ -120,11 +143,16 @@ func (*AndCompoundCondition) isCompoundCondition() {}
 func (*AndCompoundCondition) isCondition()         {}
 func (*AndCompoundCondition) isSyntax()            {}
 
+// Bind resolves the binding variables in the condition. The missing values are reported at once as ParameterError.
 func (c *AndCompoundCondition) Bind(br *BindingResolver) error {
-	if err := c.Left.Bind(br); err != nil {
+	return bindCondition(c, br)
+}
+
+func (c *AndCompoundCondition) bind(br *BindingResolver) error {
+	if err := c.Left.bind(br); err != nil {
 		return err
 	}
-	if err := c.Right.Bind(br); err != nil {
+	if err := c.Right.bind(br); err != nil {
 		return err
 	}
 	return nil
@@ -137,6 +165,12 @@ func (c *AndCompoundCondition) Normalize() Condition {
 	}
 }
 
+// Flatten returns the operands of the nested AND compounds from left to right. e.g. [a, b, c] for a AND (b AND c)
+// The other compounds are returned as the operands without flattening.
+func (c *AndCompoundCondition) Flatten() []Condition {
+	return appendAndOperands(nil, c)
+}
+
 type OrCompoundCondition struct {
 	Left  Condition
 	Right Condition
@@ -146,11 +180,16 @@ func (*OrCompoundCondition) isCompoundCondition() {}
 func (*OrCompoundCondition) isCondition()         {}
 func (*OrCompoundCondition) isSyntax()            {}
 
+// Bind resolves the binding variables in the condition. The missing values are reported at once as ParameterError.
 func (c *OrCompoundCondition) Bind(br *BindingResolver) error {
-	if err := c.Left.Bind(br); err != nil {
+	return bindCondition(c, br)
+}
+
+func (c *OrCompoundCondition) bind(br *BindingResolver) error {
+	if err := c.Left.bind(br); err != nil {
 		return err
 	}
-	if err := c.Right.Bind(br); err != nil {
+	if err := c.Right.bind(br); err != nil {
 		return err
 	}
 	return nil
@@ -163,9 +202,15 @@ func (c *OrCompoundCondition) Normalize() Condition {
 	}
 }
 
+// Flatten returns the operands of the nested OR compounds from left to right like AndCompoundCondition.Flatten.
+func (c *OrCompoundCondition) Flatten() []Condition {
+	return appendOrOperands(nil, c)
+}
+
 type Condition interface {
 	isCondition()
 	Bind(*BindingResolver) error
+	bind(*BindingResolver) error
 	Normalize() Condition
 }
 
@@ -176,6 +221,7 @@ type IsNullCondition struct {
 func (*IsNullCondition) isCondition()                   {}
 func (*IsNullCondition) isSyntax()                      {}
 func (*IsNullCondition) Bind(br *BindingResolver) error { return nil }
+func (*IsNullCondition) bind(br *BindingResolver) error { return nil }
 
 func (c *IsNullCondition) Normalize() Condition {
 	return &EitherComparatorCondition{
@@ -189,19 +235,25 @@ type ForwardComparatorCondition struct {
 	Comparator ForwardComparator
 	Property   string
 	Value      any
+	// Binding is the binding variable that Value has been bound from by Bind. It's nil if Value isn't bound.
+	// Bind resolves it again, so the condition can be re-bound with the other values.
+	Binding BindingVariable
 }
 
 func (*ForwardComparatorCondition) isCondition() {}
 func (*ForwardComparatorCondition) isSyntax()    {}
 
+// Bind resolves the binding variables in the condition. The missing values are reported at once as ParameterError.
 func (c *ForwardComparatorCondition) Bind(br *BindingResolver) error {
-	if bv, ok := c.Value.(BindingVariable); ok {
-		if v, err := br.Resolve(bv); err != nil {
-			return err
-		} else {
-			c.Value = v
-		}
+	return bindCondition(c, br)
+}
+
+func (c *ForwardComparatorCondition) bind(br *BindingResolver) error {
+	value, binding, err := bindComparatorValue(c.Value, c.Binding, br)
+	if err != nil {
+		return err
 	}
+	c.Value, c.Binding = value, binding
 	return nil
 }
 
@@ -212,19 +264,25 @@ func (c *ForwardComparatorCondition) Normalize() Condition {
 			Comparator: EqualsEitherComparator,
 			Property:   c.Property,
 			Value:      c.Value,
+			Binding:    c.Binding,
 		}
 	default:
 		return c
 	}
 }
 
+// ForwardComparator is the comparator that takes the property on the left side. e.g. prop IN ARRAY(1, 2)
 type ForwardComparator string
 
 const (
-	ContainsForwardComparator    ForwardComparator = "CONTAINS"
+	// ContainsForwardComparator matches if the array property contains the value.
+	ContainsForwardComparator ForwardComparator = "CONTAINS"
+	// HasAncestorForwardComparator matches if the key has the value as the ancestor.
 	HasAncestorForwardComparator ForwardComparator = "HAS ANCESTOR"
-	InForwardComparator          ForwardComparator = "IN"
-	NotInForwardComparator       ForwardComparator = "NOT IN"
+	// InForwardComparator matches if the property equals to any of the array values.
+	InForwardComparator ForwardComparator = "IN"
+	// NotInForwardComparator matches if the property equals to none of the array values.
+	NotInForwardComparator ForwardComparator = "NOT IN"
 )
 
 var forwardComparatorTrie = runetrie.NewTrie(
@@ -242,19 +300,25 @@ type BackwardComparatorCondition struct {
 	Comparator BackwardComparator
 	Property   string
 	Value      any
+	// Binding is the binding variable that Value has been bound from by Bind. It's nil if Value isn't bound.
+	// Bind resolves it again, so the condition can be re-bound with the other values.
+	Binding BindingVariable
 }
 
 func (*BackwardComparatorCondition) isCondition() {}
 func (*BackwardComparatorCondition) isSyntax()    {}
 
+// Bind resolves the binding variables in the condition. The missing values are reported at once as ParameterError.
 func (c *BackwardComparatorCondition) Bind(br *BindingResolver) error {
-	if bv, ok := c.Value.(BindingVariable); ok {
-		if v, err := br.Resolve(bv); err != nil {
-			return err
-		} else {
-			c.Value = v
-		}
+	return bindCondition(c, br)
+}
+
+func (c *BackwardComparatorCondition) bind(br *BindingResolver) error {
+	value, binding, err := bindComparatorValue(c.Value, c.Binding, br)
+	if err != nil {
+		return err
 	}
+	c.Value, c.Binding = value, binding
 	return nil
 }
 
@@ -265,22 +329,27 @@ func (c *BackwardComparatorCondition) Normalize() Condition {
 			Comparator: EqualsEitherComparator,
 			Property:   c.Property,
 			Value:      c.Value,
+			Binding:    c.Binding,
 		}
 	case HasDescendantBackwardComparator:
 		return &ForwardComparatorCondition{
 			Comparator: HasAncestorForwardComparator,
 			Property:   c.Property,
 			Value:      c.Value,
+			Binding:    c.Binding,
 		}
 	default:
 		return c
 	}
 }
 
+// BackwardComparator is the comparator that takes the property on the right side. e.g. 1 IN prop
 type BackwardComparator string
 
 const (
-	InBackwardComparator            BackwardComparator = "IN"
+	// InBackwardComparator matches if the array property contains the value.
+	InBackwardComparator BackwardComparator = "IN"
+	// HasDescendantBackwardComparator matches if the value is the ancestor of the key.
 	HasDescendantBackwardComparator BackwardComparator = "HAS DESCENDANT"
 )
 
@@ -293,23 +362,104 @@ func (c BackwardComparator) Valid() bool {
 	return backwardComparatorTrie.MatchAny(c)
 }
 
+// QuantifiedComparatorCondition is the comparison of the array property with all the values of the array.
+// e.g. tags CONTAINS ANY ARRAY('a', 'b')
+// It's the extension of GQL permitted by WithContainsQuantifiers, and Expand rewrites it into CONTAINS.
+type QuantifiedComparatorCondition struct {
+	Comparator QuantifiedComparator
+	Property   string
+	// Value is the array of the values or the binding variable of the array.
+	Value any
+	// Binding is the binding variable that Value has been bound from by Bind. It's nil if Value isn't bound.
+	// Bind resolves it again, so the condition can be re-bound with the other values.
+	Binding BindingVariable
+}
+
+func (*QuantifiedComparatorCondition) isCondition() {}
+func (*QuantifiedComparatorCondition) isSyntax()    {}
+
+// Bind resolves the binding variables in the condition. The missing values are reported at once as ParameterError.
+func (c *QuantifiedComparatorCondition) Bind(br *BindingResolver) error {
+	return bindCondition(c, br)
+}
+
+func (c *QuantifiedComparatorCondition) bind(br *BindingResolver) error {
+	value, binding, err := bindComparatorValue(c.Value, c.Binding, br)
+	if err != nil {
+		return err
+	}
+	c.Value, c.Binding = value, binding
+	return nil
+}
+
+// Normalize normalizes the expanded condition. The condition is returned as is if it cannot be expanded.
+func (c *QuantifiedComparatorCondition) Normalize() Condition {
+	expanded, err := c.Expand()
+	if err != nil {
+		return c
+	}
+	return expanded.Normalize()
+}
+
+// Expand rewrites the condition into CONTAINS of each value combined with OR for CONTAINS ANY and AND for CONTAINS ALL.
+// e.g. tags CONTAINS ANY ARRAY('a', 'b') is rewritten to tags CONTAINS 'a' OR tags CONTAINS 'b'
+// It returns ErrInvalidCondition if the value isn't the array, e.g. the unbound binding variable, or the array is empty.
+func (c *QuantifiedComparatorCondition) Expand() (Condition, error) {
+	values, ok := c.Value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: %v requires an array to be expanded but got %T", ErrInvalidCondition, c.Comparator, c.Value)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%w: %v requires at least one value to be expanded", ErrInvalidCondition, c.Comparator)
+	}
+
+	conditions := make([]Condition, len(values))
+	for i, v := range values {
+		conditions[i] = &ForwardComparatorCondition{Comparator: ContainsForwardComparator, Property: c.Property, Value: v}
+	}
+	if c.Comparator == ContainsAllQuantifiedComparator {
+		return And(conditions...), nil
+	}
+	return Or(conditions...), nil
+}
+
+// QuantifiedComparator is the comparator that compares the property with all the values of the array. e.g. CONTAINS ANY
+type QuantifiedComparator string
+
+const (
+	// ContainsAnyQuantifiedComparator matches if the array property contains any of the array values.
+	ContainsAnyQuantifiedComparator QuantifiedComparator = "CONTAINS ANY"
+	// ContainsAllQuantifiedComparator matches if the array property contains all of the array values.
+	ContainsAllQuantifiedComparator QuantifiedComparator = "CONTAINS ALL"
+)
+
+func (c QuantifiedComparator) Valid() bool {
+	return c == ContainsAnyQuantifiedComparator || c == ContainsAllQuantifiedComparator
+}
+
 type EitherComparatorCondition struct {
 	Comparator EitherComparator
 	Property   string
 	Value      any
+	// Binding is the binding variable that Value has been bound from by Bind. It's nil if Value isn't bound.
+	// Bind resolves it again, so the condition can be re-bound with the other values.
+	Binding BindingVariable
 }
 
 func (*EitherComparatorCondition) isCondition() {}
 func (*EitherComparatorCondition) isSyntax()    {}
 
+// Bind resolves the binding variables in the condition. The missing values are reported at once as ParameterError.
 func (c *EitherComparatorCondition) Bind(br *BindingResolver) error {
-	if bv, ok := c.Value.(BindingVariable); ok {
-		if v, err := br.Resolve(bv); err != nil {
-			return err
-		} else {
-			c.Value = v
-		}
+	return bindCondition(c, br)
+}
+
+func (c *EitherComparatorCondition) bind(br *BindingResolver) error {
+	value, binding, err := bindComparatorValue(c.Value, c.Binding, br)
+	if err != nil {
+		return err
 	}
+	c.Value, c.Binding = value, binding
 	return nil
 }
 
@@ -317,15 +467,23 @@ func (c *EitherComparatorCondition) Normalize() Condition {
 	return c
 }
 
+// EitherComparator is the comparator that takes the property on either side.
+// The conditions are canonicalized to have the property on the left side.
 type EitherComparator string
 
 const (
-	EqualsEitherComparator                  EitherComparator = "="
-	NotEqualsEitherComparator               EitherComparator = "!="
-	GreaterThanEitherComparator             EitherComparator = ">"
+	// EqualsEitherComparator matches if the property equals to the value.
+	EqualsEitherComparator EitherComparator = "="
+	// NotEqualsEitherComparator matches if the property doesn't equal to the value.
+	NotEqualsEitherComparator EitherComparator = "!="
+	// GreaterThanEitherComparator matches if the property is greater than the value.
+	GreaterThanEitherComparator EitherComparator = ">"
+	// GreaterThanOrEqualsThanEitherComparator matches if the property is greater than or equals to the value.
 	GreaterThanOrEqualsThanEitherComparator EitherComparator = ">="
-	LesserThanEitherComparator              EitherComparator = "<"
-	LesserThanOrEqualsEitherComparator      EitherComparator = "<="
+	// LesserThanEitherComparator matches if the property is lesser than the value.
+	LesserThanEitherComparator EitherComparator = "<"
+	// LesserThanOrEqualsEitherComparator matches if the property is lesser than or equals to the value.
+	LesserThanOrEqualsEitherComparator EitherComparator = "<="
 )
 
 var eitherComparatorTrie = runetrie.NewTrie(
@@ -340,3 +498,13 @@ var eitherComparatorTrie = runetrie.NewTrie(
 func (c EitherComparator) Valid() bool {
 	return eitherComparatorTrie.MatchAny(c)
 }
+
+// isRange reports whether the comparator compares the order of the values. e.g. <, >=
+func (c EitherComparator) isRange() bool {
+	switch c {
+	case GreaterThanEitherComparator, GreaterThanOrEqualsThanEitherComparator, LesserThanEitherComparator, LesserThanOrEqualsEitherComparator:
+		return true
+	default:
+		return false
+	}
+}
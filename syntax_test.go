@@ -1,6 +1,7 @@
 package gqlparser_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -35,11 +36,13 @@ func TestConditionBind(t *testing.T) {
 					Comparator: gqlparser.GreaterThanEitherComparator,
 					Property:   "a",
 					Value:      int64(10),
+					Binding:    &gqlparser.IndexedBinding{Index: 1},
 				},
 				Right: &gqlparser.ForwardComparatorCondition{
 					Comparator: gqlparser.ContainsForwardComparator,
 					Property:   "a",
 					Value:      int64(20),
+					Binding:    &gqlparser.IndexedBinding{Index: 2},
 				},
 			},
 			wantErr: false,
@@ -80,6 +83,7 @@ func TestConditionBind(t *testing.T) {
 							{Kind: "Parent", Name: "foo"},
 						},
 					},
+					Binding: &gqlparser.NamedBinding{Name: "ancestor"},
 				},
 				Right: &gqlparser.OrCompoundCondition{
 					Left: &gqlparser.IsNullCondition{Property: "a"},
@@ -87,11 +91,56 @@ func TestConditionBind(t *testing.T) {
 						Comparator: gqlparser.InBackwardComparator,
 						Property:   "a",
 						Value:      []any{int64(10), int64(20)},
+						Binding:    &gqlparser.NamedBinding{Name: "list"},
 					},
 				},
 			},
 			wantErr: false,
 		},
+		{
+			name:     "ExpandArrayElements",
+			resolver: &gqlparser.BindingResolver{Indexed: []any{int64(10)}, Named: map[string]any{"x": "foo"}},
+			condition: &gqlparser.AndCompoundCondition{
+				Left: &gqlparser.ForwardComparatorCondition{
+					Comparator: gqlparser.InForwardComparator,
+					Property:   "a",
+					Value:      []any{&gqlparser.IndexedBinding{Index: 1}, int64(20)},
+				},
+				Right: &gqlparser.ForwardComparatorCondition{
+					Comparator: gqlparser.NotInForwardComparator,
+					Property:   "b",
+					Value:      []any{"bar", &gqlparser.NamedBinding{Name: "x"}},
+				},
+			},
+			want: &gqlparser.AndCompoundCondition{
+				Left: &gqlparser.ForwardComparatorCondition{
+					Comparator: gqlparser.InForwardComparator,
+					Property:   "a",
+					Value:      []any{int64(10), int64(20)},
+				},
+				Right: &gqlparser.ForwardComparatorCondition{
+					Comparator: gqlparser.NotInForwardComparator,
+					Property:   "b",
+					Value:      []any{"bar", "foo"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "MissingArrayElement",
+			resolver: &gqlparser.BindingResolver{Named: map[string]any{}},
+			condition: &gqlparser.ForwardComparatorCondition{
+				Comparator: gqlparser.InForwardComparator,
+				Property:   "a",
+				Value:      []any{&gqlparser.NamedBinding{Name: "x"}},
+			},
+			want: &gqlparser.ForwardComparatorCondition{
+				Comparator: gqlparser.InForwardComparator,
+				Property:   "a",
+				Value:      []any{&gqlparser.NamedBinding{Name: "x"}},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -107,3 +156,146 @@ func TestConditionBind(t *testing.T) {
 		})
 	}
 }
+
+func TestConditionBind_ParameterError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		resolver *gqlparser.BindingResolver
+		want     *gqlparser.ParameterError
+		wantErr  string
+	}{
+		{
+			name:     "Satisfied",
+			resolver: &gqlparser.BindingResolver{Indexed: []any{int64(1), int64(2)}, Named: map[string]any{"a": 1, "id": "x", "unused": 1}},
+		},
+		{
+			name:     "Missing",
+			resolver: &gqlparser.BindingResolver{Indexed: []any{int64(1)}},
+			want: &gqlparser.ParameterError{
+				Missing: []gqlparser.BindingVariable{
+					&gqlparser.NamedBinding{Name: "a"},
+					&gqlparser.IndexedBinding{Index: 2},
+					&gqlparser.NamedBinding{Name: "id"},
+				},
+			},
+			wantErr: "no bind value: @a, @2, @id",
+		},
+		{
+			name:     "MissingAndUnused",
+			resolver: &gqlparser.BindingResolver{Indexed: []any{int64(1), int64(2), int64(3)}, Named: map[string]any{"a": 1, "z": 1, "y": 1}, RejectUnused: true},
+			want: &gqlparser.ParameterError{
+				Missing: []gqlparser.BindingVariable{&gqlparser.NamedBinding{Name: "id"}},
+				Unused: []gqlparser.BindingVariable{
+					&gqlparser.IndexedBinding{Index: 3},
+					&gqlparser.NamedBinding{Name: "y"},
+					&gqlparser.NamedBinding{Name: "z"},
+				},
+			},
+			wantErr: "no bind value: @id; unused bind value: @3, @y, @z",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cond, err := gqlparser.ParseCondition(gqlparser.NewLexer("a = @a AND (b = @1 OR c = @2 OR d = @a) AND __key__ HAS ANCESTOR KEY(Kind, @id)"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = cond.Bind(tt.resolver)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Bind() error = %v", err)
+				}
+				return
+			}
+
+			var got *gqlparser.ParameterError
+			if !errors.As(err, &got) {
+				t.Fatalf("Bind() error = %v, want %T", err, got)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.wantErr)
+			}
+			if !errors.Is(err, gqlparser.ErrBindValue) {
+				t.Errorf("Bind() error = %v, want %v", err, gqlparser.ErrBindValue)
+			}
+
+			// the condition is left unbound on the error
+			if diff := cmp.Diff(&gqlparser.NamedBinding{Name: "a"}, cond.(*gqlparser.AndCompoundCondition).Left.(*gqlparser.AndCompoundCondition).Left.(*gqlparser.EitherComparatorCondition).Value); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBoundValues(t *testing.T) {
+	t.Parallel()
+
+	cond, err := gqlparser.ParseCondition(gqlparser.NewLexer("age >= @age AND (name = @1 OR flag = true)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := gqlparser.BoundValues(cond); got != nil {
+		t.Errorf("BoundValues() = %v, want nil before Bind", got)
+	}
+
+	if err := cond.Bind(&gqlparser.BindingResolver{Indexed: []any{"foo"}, Named: map[string]any{"age": int64(18)}}); err != nil {
+		t.Fatal(err)
+	}
+	want := []gqlparser.BoundValue{
+		{Property: "age", Variable: &gqlparser.NamedBinding{Name: "age"}, Value: int64(18)},
+		{Property: "name", Variable: &gqlparser.IndexedBinding{Index: 1}, Value: "foo"},
+	}
+	if diff := cmp.Diff(want, gqlparser.BoundValues(cond)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	// re-bind with the other values
+	if err := cond.Bind(&gqlparser.BindingResolver{Indexed: []any{"bar"}, Named: map[string]any{"age": int64(20)}}); err != nil {
+		t.Fatal(err)
+	}
+	want = []gqlparser.BoundValue{
+		{Property: "age", Variable: &gqlparser.NamedBinding{Name: "age"}, Value: int64(20)},
+		{Property: "name", Variable: &gqlparser.IndexedBinding{Index: 1}, Value: "bar"},
+	}
+	if diff := cmp.Diff(want, gqlparser.BoundValues(cond)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestCompoundConditionFlatten(t *testing.T) {
+	t.Parallel()
+
+	cond, err := gqlparser.ParseCondition(gqlparser.NewLexer("a = 1 AND (b = 2 AND c = 3) AND (d = 4 OR e = 5 OR (f = 6 AND g = 7))"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	and := cond.(*gqlparser.AndCompoundCondition)
+	got := and.Flatten()
+	want := []gqlparser.Condition{
+		gqlparser.Eq("a", int64(1)),
+		gqlparser.Eq("b", int64(2)),
+		gqlparser.Eq("c", int64(3)),
+		gqlparser.Or(gqlparser.Eq("d", int64(4)), gqlparser.Eq("e", int64(5)), gqlparser.And(gqlparser.Eq("f", int64(6)), gqlparser.Eq("g", int64(7)))),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("AndCompoundCondition.Flatten(): (-want, +got)\n%s", diff)
+	}
+
+	got = got[3].(*gqlparser.OrCompoundCondition).Flatten()
+	want = []gqlparser.Condition{
+		gqlparser.Eq("d", int64(4)),
+		gqlparser.Eq("e", int64(5)),
+		gqlparser.And(gqlparser.Eq("f", int64(6)), gqlparser.Eq("g", int64(7))),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("OrCompoundCondition.Flatten(): (-want, +got)\n%s", diff)
+	}
+}
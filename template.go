@@ -0,0 +1,210 @@
+package gqlparser
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrBindTemplate = errors.New("invalid template value")
+
+// KindBinding is a placeholder of the kind. e.g. SELECT * FROM @kind
+type KindBinding struct {
+	Variable BindingVariable
+}
+
+// PropertyBindingClause is the clause that has the property placeholder.
+type PropertyBindingClause string
+
+const (
+	ProjectionPropertyBindingClause PropertyBindingClause = "SELECT"
+	DistinctOnPropertyBindingClause PropertyBindingClause = "DISTINCT ON"
+	OrderByPropertyBindingClause    PropertyBindingClause = "ORDER BY"
+)
+
+// PropertyBinding is a placeholder of the property name.
+// Index is the position of the property in the clause.
+type PropertyBinding struct {
+	Clause   PropertyBindingClause
+	Index    int
+	Variable BindingVariable
+}
+
+// ResolveKind resolves the kind placeholder. The bound value must be a string or a Kind.
+func (r *BindingResolver) ResolveKind(b *KindBinding) (Kind, error) {
+	v, err := r.Resolve(b.Variable)
+	if err != nil {
+		return "", err
+	}
+	switch kind := v.(type) {
+	case Kind:
+		return kind, nil
+	case string:
+		return Kind(kind), nil
+	default:
+		return "", &BindError{Variable: b.Variable, Err: fmt.Errorf("%w: kind %T", ErrBindTemplate, v)}
+	}
+}
+
+// ResolveProperty resolves the property placeholder. The bound value must be a string or a Property.
+func (r *BindingResolver) ResolveProperty(b *PropertyBinding) (Property, error) {
+	v, err := r.Resolve(b.Variable)
+	if err != nil {
+		return "", err
+	}
+	switch prop := v.(type) {
+	case Property:
+		return prop, nil
+	case string:
+		return Property(prop), nil
+	default:
+		return "", &BindError{Variable: b.Variable, Err: fmt.Errorf("%w: property %T", ErrBindTemplate, v)}
+	}
+}
+
+// BindTemplate replaces the kind and property placeholders with the resolved names.
+func (q *Query) BindTemplate(br *BindingResolver) error {
+	if q.KindBinding != nil {
+		kind, err := br.ResolveKind(q.KindBinding)
+		if err != nil {
+			return err
+		}
+		q.Kind = kind
+		q.KindBinding = nil
+	}
+
+	for _, b := range q.PropertyBindings {
+		prop, err := br.ResolveProperty(b)
+		if err != nil {
+			return err
+		}
+
+		switch b.Clause {
+		case ProjectionPropertyBindingClause:
+			q.Properties[b.Index] = prop
+		case DistinctOnPropertyBindingClause:
+			q.DistinctOn[b.Index] = prop
+		case OrderByPropertyBindingClause:
+			q.OrderBy[b.Index].Property = prop
+		default:
+			return &BindError{Variable: b.Variable, Err: fmt.Errorf("%w: clause %s", ErrBindTemplate, b.Clause)}
+		}
+	}
+	q.PropertyBindings = nil
+	q.KeysOnly = len(q.Properties) == 1 && q.Properties[0] == keyProperty
+	return nil
+}
+
+// addPropertyBinding records the property placeholder in the clause of the query.
+// The placeholder of ORDER BY is recorded before appending the order, so its index is the current length.
+func addPropertyBinding(query *Query, clause PropertyBindingClause, tok *BindingToken) {
+	var index int
+	switch clause {
+	case ProjectionPropertyBindingClause:
+		index = len(query.Properties) - 1
+	case DistinctOnPropertyBindingClause:
+		index = len(query.DistinctOn) - 1
+	case OrderByPropertyBindingClause:
+		index = len(query.OrderBy)
+	}
+	query.PropertyBindings = append(query.PropertyBindings, &PropertyBinding{
+		Clause:   clause,
+		Index:    index,
+		Variable: parseBindingToken(tok),
+	})
+}
+
+// BuildQuery parses the query template and binds the named params to it at once.
+// The params are substituted only as the literal values: the templates can't have the kind and property placeholders,
+// and the cursors of LIMIT and OFFSET must be bound to a Cursor or an integer added to the position, not to a raw string.
+// The missing and unused params are reported at once as ParameterError.
+// e.g. BuildQuery("SELECT * FROM User WHERE name = @name LIMIT @limit", map[string]any{"name": "x", "limit": 10})
+func BuildQuery(template string, params map[string]any) (*Query, error) {
+	query, err := ParseQuery(NewLexer(template))
+	if err != nil {
+		return nil, err
+	}
+
+	positions := resultPositions(query)
+	var variables []BindingVariable
+	for _, p := range positions {
+		if p.isBound() {
+			variables = append(variables, *p.cursor)
+		}
+	}
+	br := &BindingResolver{Named: params, RejectUnused: true}
+	if err := br.checkVariables(conditionVariables(variables, query.Where)); err != nil {
+		return nil, err
+	}
+	if query.Where != nil {
+		if err := query.Where.bind(br); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range positions {
+		if err := p.bind(br); err != nil {
+			return nil, err
+		}
+	}
+	return query, nil
+}
+
+// resultPosition is the position of LIMIT or OFFSET.
+type resultPosition struct {
+	position *int64
+	cursor   *BindingVariable
+}
+
+// resultPositions returns the positions of LIMIT and OFFSET of the query.
+func resultPositions(q *Query) []*resultPosition {
+	var positions []*resultPosition
+	if q.Limit != nil {
+		positions = append(positions, &resultPosition{position: &q.Limit.Position, cursor: &q.Limit.Cursor})
+	}
+	if q.Offset != nil {
+		positions = append(positions, &resultPosition{position: &q.Offset.Position, cursor: &q.Offset.Cursor})
+	}
+	return positions
+}
+
+// isBound reports whether the cursor is the binding variable, not the cursor literal.
+func (p *resultPosition) isBound() bool {
+	switch (*p.cursor).(type) {
+	case *NamedBinding, *IndexedBinding:
+		return true
+	default:
+		return false
+	}
+}
+
+// bind resolves the binding variable of the cursor into the cursor or the integer added to the position.
+func (p *resultPosition) bind(br *BindingResolver) error {
+	if !p.isBound() {
+		return nil
+	}
+	v, err := br.Resolve(*p.cursor)
+	if err != nil {
+		return err
+	}
+	var n int64
+	switch v := v.(type) {
+	case Cursor:
+		*p.cursor = v
+		return nil
+	case int64:
+		n = v
+	case int:
+		n = int64(v)
+	default:
+		return &BindError{Variable: *p.cursor, Err: fmt.Errorf("%w: cursor %T", ErrBindTemplate, v)}
+	}
+	if n < 0 {
+		return &BindError{Variable: *p.cursor, Err: fmt.Errorf("%w: negative position %d", ErrBindTemplate, n)}
+	}
+	if n > math.MaxInt64-*p.position {
+		return &BindError{Variable: *p.cursor, Err: fmt.Errorf("%w: position %d + %d overflows", ErrBindTemplate, *p.position, n)}
+	}
+	*p.position += n
+	*p.cursor = nil
+	return nil
+}
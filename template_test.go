@@ -0,0 +1,213 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestQueryBindTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		source   string
+		resolver *gqlparser.BindingResolver
+		want     *gqlparser.Query
+		wantErr  error
+	}{
+		{
+			name:     "Kind",
+			source:   "SELECT * FROM @kind WHERE a = 1",
+			resolver: &gqlparser.BindingResolver{Named: map[string]any{"kind": "Tenant1Kind"}},
+			want: &gqlparser.Query{
+				Kind: "Tenant1Kind",
+				Where: &gqlparser.EitherComparatorCondition{
+					Comparator: gqlparser.EqualsEitherComparator,
+					Property:   "a",
+					Value:      int64(1),
+				},
+			},
+		},
+		{
+			name:     "Properties",
+			source:   "SELECT DISTINCT ON (@1) @1, b, @2 FROM Kind ORDER BY a, @3 DESC",
+			resolver: &gqlparser.BindingResolver{Indexed: []any{"x", gqlparser.Property("y"), "z"}},
+			want: &gqlparser.Query{
+				Kind:       "Kind",
+				DistinctOn: []gqlparser.Property{"x"},
+				Properties: []gqlparser.Property{"x", "b", "y"},
+				OrderBy: []gqlparser.OrderBy{
+					{Property: "a"},
+					{Property: "z", Descending: true},
+				},
+			},
+		},
+		{
+			name:     "MissingValue",
+			source:   "SELECT * FROM @kind",
+			resolver: &gqlparser.BindingResolver{},
+			wantErr:  gqlparser.ErrBindValue,
+		},
+		{
+			name:     "InvalidValue",
+			source:   "SELECT @p FROM Kind",
+			resolver: &gqlparser.BindingResolver{Named: map[string]any{"p": 1}},
+			wantErr:  gqlparser.ErrBindTemplate,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source), gqlparser.WithTemplatePlaceholders())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = query.BindTemplate(tt.resolver)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("BindTemplate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, query); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseQueryTemplatePlaceholdersDisabled(t *testing.T) {
+	t.Parallel()
+
+	for _, source := range []string{
+		"SELECT * FROM @kind",
+		"SELECT @p FROM Kind",
+		"SELECT * FROM Kind ORDER BY @p",
+	} {
+		if _, err := gqlparser.ParseQuery(gqlparser.NewLexer(source)); !errors.Is(err, gqlparser.ErrUnexpectedToken) {
+			t.Errorf("ParseQuery(%q) error = %v, want %v", source, err, gqlparser.ErrUnexpectedToken)
+		}
+	}
+}
+
+func TestParseAggregationQueryTemplatePlaceholders(t *testing.T) {
+	t.Parallel()
+
+	query, err := gqlparser.ParseAggregationQuery(gqlparser.NewLexer("AGGREGATE COUNT(*) OVER (SELECT * FROM @kind)"), gqlparser.WithTemplatePlaceholders())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := query.BindTemplate(&gqlparser.BindingResolver{Named: map[string]any{"kind": gqlparser.Kind("Kind")}}); err != nil {
+		t.Fatal(err)
+	}
+	if query.Kind != "Kind" {
+		t.Errorf("Kind = %q, want %q", query.Kind, "Kind")
+	}
+}
+
+func TestBuildQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		template string
+		params   map[string]any
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "Values",
+			template: "SELECT * FROM User WHERE name = @name AND age > @age LIMIT @limit OFFSET @offset + 5",
+			params:   map[string]any{"name": `x" OR a = "y`, "age": int64(20), "limit": 10, "offset": int64(1)},
+			want:     `SELECT * FROM User WHERE name = 'x" OR a = "y' AND age > 20 LIMIT 10 OFFSET 6`,
+		},
+		{
+			name:     "Cursor",
+			template: "SELECT * FROM User LIMIT @cursor",
+			params:   map[string]any{"cursor": gqlparser.Cursor("Cg0SB2tleS0xMDAY")},
+			want:     "SELECT * FROM User LIMIT CURSOR('Cg0SB2tleS0xMDAY')",
+		},
+		{
+			name:     "RawStringCursor",
+			template: "SELECT * FROM User LIMIT @cursor",
+			params:   map[string]any{"cursor": "10; DROP"},
+			wantErr:  gqlparser.ErrBindTemplate,
+		},
+		{
+			name:     "NegativePosition",
+			template: "SELECT * FROM User OFFSET @offset",
+			params:   map[string]any{"offset": -1},
+			wantErr:  gqlparser.ErrBindTemplate,
+		},
+		{
+			name:     "OverflowPosition",
+			template: "SELECT * FROM User OFFSET @offset + 1",
+			params:   map[string]any{"offset": int64(math.MaxInt64)},
+			wantErr:  gqlparser.ErrBindTemplate,
+		},
+		{
+			name:     "ArrayElements",
+			template: "SELECT * FROM User WHERE name IN ARRAY(@x, 'b') AND team NOT IN ARRAY(@y)",
+			params:   map[string]any{"x": "a", "y": "c"},
+			want:     "SELECT * FROM User WHERE name IN ARRAY('a', 'b') AND team NOT IN ARRAY('c')",
+		},
+		{
+			name:     "KindPlaceholder",
+			template: "SELECT * FROM @kind",
+			params:   map[string]any{"kind": "User"},
+			wantErr:  gqlparser.ErrUnexpectedToken,
+		},
+		{
+			name:     "PropertyPlaceholder",
+			template: "SELECT @prop FROM User",
+			params:   map[string]any{"prop": "name"},
+			wantErr:  gqlparser.ErrUnexpectedToken,
+		},
+		{
+			name:     "Missing",
+			template: "SELECT * FROM User WHERE name = @name AND age = @1",
+			params:   map[string]any{"name": "x"},
+			wantErr:  gqlparser.ErrBindValue,
+		},
+		{
+			name:     "Unused",
+			template: "SELECT * FROM User WHERE name = @name",
+			params:   map[string]any{"name": "x", "kind": "Admin"},
+			wantErr:  gqlparser.ErrUnusedBindValue,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := gqlparser.BuildQuery(tt.template, tt.params)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("BuildQuery() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := gqlparser.FormatQuery(query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("BuildQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -127,6 +127,15 @@ func (t *BindingToken) GetContent() string {
 
 func (t *BindingToken) GetPosition() int { return t.Position }
 
+// SemicolonToken is the end of the statement. It's emitted by the Lexer with WithSemicolonToken.
+type SemicolonToken struct {
+	Position int
+}
+
+func (*SemicolonToken) isToken() privateSealed { return privateSealed{} }
+func (t *SemicolonToken) GetContent() string   { return ";" }
+func (t *SemicolonToken) GetPosition() int     { return t.Position }
+
 type WhitespaceToken struct {
 	Content  string
 	Position int
@@ -25,22 +25,22 @@ var nopAcceptor nopAcceptorTyp
 
 func notAcceptor(acceptor tokenAcceptor) tokenAcceptor {
 	return tokenAcceptorFn(func(tr tokenReader) error {
-		rtr := asResettableTokenReader(tr)
+		rtr, offset := markTokenReader(tr)
 		if err := acceptor.accept(rtr); errors.Is(err, ErrUnexpectedToken) {
-			rtr.Reset()
+			rtr.resetTo(offset)
 			return nil
 		} else if err != nil {
-			rtr.Reset()
+			rtr.resetTo(offset)
 			return err
 		}
-		return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, rtr.history.tokens[0].GetContent(), rtr.history.tokens[0].GetPosition())
+		return &SyntaxError{Token: rtr.history.tokens[offset]}
 	})
 }
 
 func advanceAcceptor(acceptor tokenAcceptor) tokenAcceptor {
 	return tokenAcceptorFn(func(tr tokenReader) error {
-		rtr := asResettableTokenReader(tr)
-		defer rtr.Reset()
+		rtr, offset := markTokenReader(tr)
+		defer rtr.resetTo(offset)
 		if err := acceptor.accept(rtr); err != nil {
 			return err
 		}
@@ -48,11 +48,14 @@ func advanceAcceptor(acceptor tokenAcceptor) tokenAcceptor {
 	})
 }
 
-func deferAcceptor(getAcceptor func() tokenAcceptor) tokenAcceptor {
-	return tokenAcceptorFn(func(tr tokenReader) error {
-		acceptor := getAcceptor()
-		return acceptor.accept(tr)
-	})
+// namedTokenAcceptor names the acceptor with the clause to report it in the error messages.
+type namedTokenAcceptor struct {
+	name     string
+	acceptor tokenAcceptor
+}
+
+func (acceptor *namedTokenAcceptor) accept(tr tokenReader) error {
+	return wrapClauseError(acceptor.name, acceptor.acceptor.accept(tr))
 }
 
 type tokenAcceptors []tokenAcceptor
@@ -67,77 +70,183 @@ func (s tokenAcceptors) accept(tr tokenReader) error {
 }
 
 type conditionalTokenAcceptor struct {
+	// name is the clause accepted by andThen. It's optional.
+	name     string
 	ifAccept tokenAcceptor
 	andThen  tokenAcceptor
 	orElse   tokenAcceptor
 }
 
 func (acceptor *conditionalTokenAcceptor) accept(tr tokenReader) error {
-	rtr := asResettableTokenReader(tr)
+	rtr, offset := markTokenReader(tr)
 	if err := acceptor.ifAccept.accept(rtr); errors.Is(err, ErrUnexpectedToken) || errors.Is(err, ErrNoTokens) {
-		rtr.Reset()
+		rtr.resetTo(offset)
 		return acceptor.orElse.accept(tr)
 	} else if err != nil {
-		rtr.Reset()
+		rtr.resetTo(offset)
 		return err
 	}
-	return acceptor.andThen.accept(tr)
+	return wrapClauseError(acceptor.name, acceptor.andThen.accept(tr))
 }
 
-func acceptKeyword(keywords ...string) tokenAcceptor {
-	set := make(map[string]struct{}, len(keywords))
-	for _, keyword := range keywords {
-		set[keyword] = struct{}{}
+// optionTokenAcceptor switches the acceptors by the parse options. e.g. the clauses of the dialect
+// The grammar is shared by all parses, so the options are checked on accepting.
+type optionTokenAcceptor struct {
+	enabled   func(*parseOptions) bool
+	ifEnabled tokenAcceptor
+	orElse    tokenAcceptor
+}
+
+func (acceptor *optionTokenAcceptor) accept(tr tokenReader) error {
+	return acceptor.choose(stateOf(tr).opts).accept(tr)
+}
+
+func (acceptor *optionTokenAcceptor) choose(opts *parseOptions) tokenAcceptor {
+	if acceptor.enabled(opts) {
+		return acceptor.ifEnabled
 	}
+	return acceptor.orElse
+}
 
+// acceptSeparated accepts one or more items separated by the separator iteratively. e.g. a, b, c
+// The item is accepted with its index, and it can stop accepting the following items by returning true.
+// The separator is left unread if it isn't accepted.
+func acceptSeparated(separator tokenAcceptor, item func(tr tokenReader, index int) (bool, error)) tokenAcceptor {
 	return tokenAcceptorFn(func(tr tokenReader) error {
-		if token, err := tr.Read(); errors.Is(err, ErrEndOfToken) {
-			return ErrNoTokens
-		} else if err != nil {
-			return err
-		} else if t, ok := token.(*KeywordToken); ok {
-			if _, ok := set[t.Name]; !ok {
-				return fmt.Errorf("%w: %s at %d (expect to be any of %q)", ErrUnexpectedToken, t.GetContent(), t.GetPosition(), keywords)
+		for i := 0; ; i++ {
+			if done, err := item(tr, i); err != nil || done {
+				return err
+			}
+
+			rtr, offset := markTokenReader(tr)
+			if err := separator.accept(rtr); errors.Is(err, ErrUnexpectedToken) || errors.Is(err, ErrNoTokens) {
+				rtr.resetTo(offset)
+				return nil
+			} else if err != nil {
+				rtr.resetTo(offset)
+				return err
 			}
-			return nil
-		} else {
-			return fmt.Errorf("%w: %s at %d (expect to be any of %q)", ErrUnexpectedToken, token.GetContent(), token.GetPosition(), keywords)
 		}
 	})
 }
 
-func acceptOperator(operator string) tokenAcceptor {
-	return tokenAcceptorFn(func(tr tokenReader) error {
-		if token, err := tr.Read(); errors.Is(err, ErrEndOfToken) {
-			return ErrNoTokens
-		} else if err != nil {
-			return err
-		} else if t, ok := token.(*OperatorToken); ok {
-			if t.Type != operator {
-				return fmt.Errorf("%w: %s at %d (expect to be %q)", ErrUnexpectedToken, t.Type, t.Position, operator)
+type keywordTokenAcceptor struct {
+	keywords []string
+	// reason is the precomputed reason of the errors.
+	reason string
+	// record records the accepted token into the state if it isn't nil.
+	record func(*parseState, *KeywordToken)
+}
+
+// keywordAcceptors are the precompiled acceptors of the single keywords shared by all parses.
+var keywordAcceptors = newKeywordAcceptors(keywords)
+
+func newKeywordAcceptors(keywords []string) map[string]*keywordTokenAcceptor {
+	acceptors := make(map[string]*keywordTokenAcceptor, len(keywords))
+	for _, keyword := range keywords {
+		acceptors[keyword] = newKeywordTokenAcceptor([]string{keyword})
+	}
+	return acceptors
+}
+
+func newKeywordTokenAcceptor(keywords []string) *keywordTokenAcceptor {
+	return &keywordTokenAcceptor{keywords: keywords, reason: fmt.Sprintf("expect to be any of %q", keywords)}
+}
+
+func acceptKeyword(keywords ...string) tokenAcceptor {
+	if len(keywords) == 1 {
+		if acceptor, ok := keywordAcceptors[keywords[0]]; ok {
+			return acceptor
+		}
+	}
+	return newKeywordTokenAcceptor(keywords)
+}
+
+// acceptKeywordToken accepts the keyword like acceptKeyword and records the accepted token by record.
+func acceptKeywordToken(keyword string, record func(*parseState, *KeywordToken)) tokenAcceptor {
+	acceptor := *acceptKeyword(keyword).(*keywordTokenAcceptor)
+	acceptor.record = record
+	return &acceptor
+}
+
+func (acceptor *keywordTokenAcceptor) accept(tr tokenReader) error {
+	if token, err := tr.Read(); errors.Is(err, ErrEndOfToken) {
+		return ErrNoTokens
+	} else if err != nil {
+		return err
+	} else if t, ok := token.(*KeywordToken); ok {
+		for _, keyword := range acceptor.keywords {
+			if t.Name == keyword {
+				if acceptor.record != nil {
+					acceptor.record(stateOf(tr), t)
+				}
+				return nil
 			}
-			return nil
-		} else {
-			return fmt.Errorf("%w: %s at %d (expect to be %q)", ErrUnexpectedToken, token.GetContent(), token.GetPosition(), operator)
 		}
-	})
+		return &SyntaxError{Token: t, Reason: acceptor.reason, Expected: acceptor.keywords}
+	} else {
+		return &SyntaxError{Token: token, Reason: acceptor.reason, Expected: acceptor.keywords}
+	}
 }
 
-func acceptSingleToken[T Token](f func(T) error) tokenAcceptor {
+type operatorTokenAcceptor struct {
+	operator string
+	// reason is the precomputed reason of the errors.
+	reason string
+}
+
+// operatorAcceptors are the precompiled acceptors of the punctuations shared by all parses.
+var operatorAcceptors = newOperatorAcceptors("(", ")", ",", "=", ";", ".", "+")
+
+func newOperatorAcceptors(operators ...string) map[string]*operatorTokenAcceptor {
+	acceptors := make(map[string]*operatorTokenAcceptor, len(operators))
+	for _, operator := range operators {
+		acceptors[operator] = newOperatorTokenAcceptor(operator)
+	}
+	return acceptors
+}
+
+func newOperatorTokenAcceptor(operator string) *operatorTokenAcceptor {
+	return &operatorTokenAcceptor{operator: operator, reason: fmt.Sprintf("expect to be %q", operator)}
+}
+
+func acceptOperator(operator string) tokenAcceptor {
+	if acceptor, ok := operatorAcceptors[operator]; ok {
+		return acceptor
+	}
+	return newOperatorTokenAcceptor(operator)
+}
+
+func (acceptor *operatorTokenAcceptor) accept(tr tokenReader) error {
+	if token, err := tr.Read(); errors.Is(err, ErrEndOfToken) {
+		return ErrNoTokens
+	} else if err != nil {
+		return err
+	} else if t, ok := token.(*OperatorToken); ok {
+		if t.Type != acceptor.operator {
+			return &SyntaxError{Token: t, Reason: acceptor.reason}
+		}
+		return nil
+	} else {
+		return &SyntaxError{Token: token, Reason: acceptor.reason}
+	}
+}
+
+func acceptSingleToken[T Token](f func(*parseState, T) error) tokenAcceptor {
 	return tokenAcceptorFn(func(tr tokenReader) error {
 		if token, err := tr.Read(); errors.Is(err, ErrEndOfToken) {
 			return ErrNoTokens
 		} else if err != nil {
 			return err
 		} else if t, ok := token.(T); ok {
-			return f(t)
+			return f(stateOf(tr), t)
 		} else {
-			return fmt.Errorf("%w: %s at %d (expect to be %T)", ErrUnexpectedToken, token.GetContent(), token.GetPosition(), t)
+			return &SyntaxError{Token: token, Reason: fmt.Sprintf("expect to be %T", t)}
 		}
 	})
 }
 
-func acceptEitherToken[L Token, R Token](lf func(L) error, rf func(R) error) tokenAcceptor {
+func acceptEitherToken[L Token, R Token](lf func(*parseState, L) error, rf func(*parseState, R) error) tokenAcceptor {
 	return tokenAcceptorFn(func(tr tokenReader) error {
 		if token, err := tr.Read(); errors.Is(err, ErrEndOfToken) {
 			return ErrNoTokens
@@ -146,17 +255,17 @@ func acceptEitherToken[L Token, R Token](lf func(L) error, rf func(R) error) tok
 		} else {
 			switch t := token.(type) {
 			case L:
-				return lf(t)
+				return lf(stateOf(tr), t)
 			case R:
-				return rf(t)
+				return rf(stateOf(tr), t)
 			default:
-				return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
+				return &SyntaxError{Token: token}
 			}
 		}
 	})
 }
 
-func acceptTokenFromAny3[L Token, C Token, R Token](lf func(L) error, cf func(C) error, rf func(R) error) tokenAcceptor {
+func acceptTokenFromAny3[L Token, C Token, R Token](lf func(*parseState, L) error, cf func(*parseState, C) error, rf func(*parseState, R) error) tokenAcceptor {
 	return tokenAcceptorFn(func(tr tokenReader) error {
 		if token, err := tr.Read(); errors.Is(err, ErrEndOfToken) {
 			return ErrNoTokens
@@ -165,36 +274,35 @@ func acceptTokenFromAny3[L Token, C Token, R Token](lf func(L) error, cf func(C)
 		} else {
 			switch t := token.(type) {
 			case L:
-				return lf(t)
+				return lf(stateOf(tr), t)
 			case C:
-				return cf(t)
+				return cf(stateOf(tr), t)
 			case R:
-				return rf(t)
+				return rf(stateOf(tr), t)
 			default:
-				return fmt.Errorf("%w: %s at %d", ErrUnexpectedToken, token.GetContent(), token.GetPosition())
+				return &SyntaxError{Token: token}
 			}
 		}
 	})
 }
 
-var acceptWhitespaceToken = acceptSingleToken(func(*WhitespaceToken) error {
+var acceptWhitespaceToken = acceptSingleToken(func(*parseState, *WhitespaceToken) error {
 	return nil
 })
 
-var acceptWildcardToken = acceptSingleToken(func(*WildcardToken) error {
+var acceptWildcardToken = acceptSingleToken(func(*parseState, *WildcardToken) error {
 	return nil
 })
 
 var skipWhitespaceToken tokenAcceptorFn = func(tr tokenReader) error {
-	rtr := asResettableTokenReader(tr)
-	if token, err := rtr.Read(); errors.Is(err, ErrEndOfToken) {
+	// peek the token first not to record it in the history if it's not a whitespace
+	if token, err := peekToken(tr); errors.Is(err, ErrEndOfToken) {
 		return nil
 	} else if err != nil {
 		return err
-	} else if _, ok := token.(*WhitespaceToken); ok {
-		return nil
-	} else {
-		rtr.Reset()
+	} else if _, ok := token.(*WhitespaceToken); !ok {
 		return nil
 	}
+	_, err := tr.Read()
+	return err
 }
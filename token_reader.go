@@ -1,5 +1,12 @@
 package gqlparser
 
+import (
+	"errors"
+	"fmt"
+)
+
+var errUnknownTokenReader = errors.New("unknown token reader")
+
 type tokenReader interface {
 	Next() bool
 	Read() (Token, error)
@@ -13,17 +20,70 @@ type resettableTokenReader struct {
 	source  TokenSource
 	history *tokenHistory
 	offset  int
+	// state is the state of the parse shared by the static acceptors. It's nil out of the Parse* functions.
+	state *parseState
+}
+
+// stateOf returns the state of the parse carried by the reader.
+func stateOf(tr tokenReader) *parseState {
+	if v, ok := tr.(*resettableTokenReader); ok {
+		return v.state
+	}
+	return nil
 }
 
 func asResettableTokenReader(tr tokenReader) *resettableTokenReader {
 	switch v := tr.(type) {
 	case *resettableTokenReader:
-		return &resettableTokenReader{source: v.source, history: v.history, offset: len(v.history.tokens)}
+		return &resettableTokenReader{source: v.source, history: v.history, offset: len(v.history.tokens), state: v.state}
 	case TokenSource:
 		return &resettableTokenReader{source: v, history: &tokenHistory{}}
 	default:
-		panic("unknown token reader")
+		return &resettableTokenReader{source: &unknownTokenSource{reader: tr}, history: &tokenHistory{}}
+	}
+}
+
+// unknownTokenSource reports the token reader that is neither TokenSource nor resettableTokenReader as the error on every read.
+type unknownTokenSource struct {
+	reader tokenReader
+}
+
+func (s *unknownTokenSource) Next() bool { return true }
+
+func (s *unknownTokenSource) Read() (Token, error) {
+	return nil, fmt.Errorf("%w: %T", errUnknownTokenReader, s.reader)
+}
+
+func (s *unknownTokenSource) Unread(Token) {}
+
+// markTokenReader returns the resettable reader and the offset to reset it to the current position by resetTo.
+// The reader is reused if it's already resettable to avoid the allocations in the hot paths.
+func markTokenReader(tr tokenReader) (*resettableTokenReader, int) {
+	if v, ok := tr.(*resettableTokenReader); ok {
+		return v, len(v.history.tokens)
+	}
+	rtr := asResettableTokenReader(tr)
+	return rtr, rtr.offset
+}
+
+// peekToken reads the next token without consuming it.
+func peekToken(tr tokenReader) (Token, error) {
+	var ts TokenSource
+	switch v := tr.(type) {
+	case *resettableTokenReader:
+		ts = v.source
+	case TokenSource:
+		ts = v
+	default:
+		return nil, fmt.Errorf("%w: %T", errUnknownTokenReader, tr)
+	}
+
+	token, err := ts.Read()
+	if err != nil {
+		return nil, err
 	}
+	ts.Unread(token)
+	return token, nil
 }
 
 func (tr *resettableTokenReader) Next() bool {
@@ -40,9 +100,9 @@ func (tr *resettableTokenReader) Read() (Token, error) {
 	return token, nil
 }
 
-func (tr *resettableTokenReader) Reset() {
-	for i := len(tr.history.tokens) - 1; i >= tr.offset; i-- {
+func (tr *resettableTokenReader) resetTo(offset int) {
+	for i := len(tr.history.tokens) - 1; i >= offset; i-- {
 		tr.source.Unread(tr.history.tokens[i])
 	}
-	tr.history.tokens = tr.history.tokens[:tr.offset]
+	tr.history.tokens = tr.history.tokens[:offset]
 }
@@ -1,7 +1,42 @@
 package gqlparser
 
+import "errors"
+
+// ErrEndOfToken is returned by TokenSource.Read at the end of the tokens.
+// It's the only sentinel of the end, so the other errors are taken as the failures of the source.
+var ErrEndOfToken = errors.New("end of token")
+
+// TokenSource is the source of the tokens read by the parser. The implementations must follow the contract below
+// to behave identically to Lexer:
+//   - Next reports whether Read may return the token or the error. It returns false only at the end of the tokens,
+//     so it returns true if Read returns the error other than ErrEndOfToken.
+//   - Read returns the next token. It returns ErrEndOfToken and the nil token at the end of the tokens,
+//     and keeps returning it on the following calls.
+//   - Read returns the nil token with the error if the source fails. The parser stops at the error and returns it.
+//   - Unread pushes back the token to be returned by the next Read. The tokens are unread in the reverse order
+//     of Read (last in, first out), and Next returns true after Unread even at the end of the tokens.
+//
+// The sources aren't required to be safe for concurrent use. Use ReadToken to read the tokens without comparing
+// the errors with ErrEndOfToken, and gqltest.RunTokenSourceConformance to test the implementations.
 type TokenSource interface {
+	// Next reports whether the source has the token or the error to read.
 	Next() bool
+	// Read returns the next token. It returns ErrEndOfToken at the end of the tokens.
 	Read() (Token, error)
+	// Unread pushes back the token to be returned by the next Read.
 	Unread(Token)
 }
+
+// ReadToken reads the next token from the source without the sentinel error. ok is false at the end of the tokens.
+func ReadToken(ts TokenSource) (tok Token, ok bool, err error) {
+	if !ts.Next() {
+		return nil, false, nil
+	}
+	tok, err = ts.Read()
+	if errors.Is(err, ErrEndOfToken) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return tok, true, nil
+}
@@ -0,0 +1,61 @@
+package gqlparser
+
+import "errors"
+
+// BufferedTokenSource wraps the TokenSource to look ahead the tokens without consuming them.
+type BufferedTokenSource struct {
+	source TokenSource
+	// buffer holds the tokens to read next in the order.
+	buffer []Token
+}
+
+var _ TokenSource = (*BufferedTokenSource)(nil)
+
+// NewBufferedTokenSource creates the BufferedTokenSource reading the tokens from the source.
+func NewBufferedTokenSource(source TokenSource) *BufferedTokenSource {
+	return &BufferedTokenSource{source: source}
+}
+
+func (ts *BufferedTokenSource) Next() bool {
+	return len(ts.buffer) != 0 || ts.source.Next()
+}
+
+func (ts *BufferedTokenSource) Read() (Token, error) {
+	if len(ts.buffer) != 0 {
+		tok := ts.buffer[0]
+		ts.buffer = ts.buffer[1:]
+		return tok, nil
+	}
+	return ts.source.Read()
+}
+
+func (ts *BufferedTokenSource) Unread(tok Token) {
+	ts.buffer = append([]Token{tok}, ts.buffer...)
+}
+
+// Peek returns the next token without consuming it. It returns ErrEndOfToken at the end of the tokens.
+func (ts *BufferedTokenSource) Peek() (Token, error) {
+	tokens, err := ts.PeekN(1)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, ErrEndOfToken
+	}
+	return tokens[0], nil
+}
+
+// PeekN returns the next n tokens at most without consuming them.
+// It returns the fewer tokens if the tokens end before n tokens.
+func (ts *BufferedTokenSource) PeekN(n int) ([]Token, error) {
+	for len(ts.buffer) < n {
+		tok, err := ts.source.Read()
+		if errors.Is(err, ErrEndOfToken) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		ts.buffer = append(ts.buffer, tok)
+	}
+	return append([]Token{}, ts.buffer[:min(n, len(ts.buffer))]...), nil
+}
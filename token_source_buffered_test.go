@@ -0,0 +1,74 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+	"github.com/karupanerura/gqlparser/gqltest"
+)
+
+func TestBufferedTokenSource(t *testing.T) {
+	t.Parallel()
+
+	const source = "KEY(Kind, 1)"
+	tokens, err := gqltest.ReadAll(gqlparser.NewLexer(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := gqlparser.NewBufferedTokenSource(gqlparser.NewLexer(source))
+	peeked, err := ts.PeekN(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(tokens[:3], peeked); diff != "" {
+		t.Errorf("PeekN(3): (-want, +got)\n%s", diff)
+	}
+
+	tok, err := ts.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(tokens[0], tok); diff != "" {
+		t.Errorf("Read(): (-want, +got)\n%s", diff)
+	}
+	ts.Unread(tok)
+	if tok, err := ts.Peek(); err != nil {
+		t.Errorf("Peek() error = %v", err)
+	} else if diff := cmp.Diff(tokens[0], tok); diff != "" {
+		t.Errorf("Peek(): (-want, +got)\n%s", diff)
+	}
+
+	peeked, err = ts.PeekN(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(tokens, peeked); diff != "" {
+		t.Errorf("PeekN(100): (-want, +got)\n%s", diff)
+	}
+
+	key, err := gqlparser.ParseKey(ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "Kind", ID: 1}}}, key); diff != "" {
+		t.Errorf("ParseKey(): (-want, +got)\n%s", diff)
+	}
+	if _, err := ts.Peek(); !errors.Is(err, gqlparser.ErrEndOfToken) {
+		t.Errorf("Peek() error = %v, want %v", err, gqlparser.ErrEndOfToken)
+	}
+}
+
+func TestBufferedTokenSource_Conformance(t *testing.T) {
+	t.Parallel()
+
+	gqltest.RunTokenSourceConformance(t, func(t *testing.T, tokens []gqlparser.Token, err error) gqlparser.TokenSource {
+		var ts gqlparser.TokenSource = gqltest.NewSliceTokenSource(tokens...)
+		if err != nil {
+			ts = gqltest.NewErrorTokenSource(ts, len(tokens), err)
+		}
+		return gqlparser.NewBufferedTokenSource(ts)
+	})
+}
@@ -0,0 +1,44 @@
+package gqlparser
+
+// chanTokenSource reads the tokens from the channel. The unread tokens are buffered.
+type chanTokenSource struct {
+	ch     <-chan Token
+	buffer []Token
+}
+
+// NewChanTokenSource creates the TokenSource reading the tokens from the channel.
+// The producer should close the channel at the end of the tokens. Next and Read block until the token is sent.
+func NewChanTokenSource(ch <-chan Token) TokenSource {
+	return &chanTokenSource{ch: ch}
+}
+
+func (ts *chanTokenSource) Next() bool {
+	if len(ts.buffer) != 0 {
+		return true
+	}
+
+	tok, ok := <-ts.ch
+	if !ok {
+		return false
+	}
+	ts.buffer = append(ts.buffer, tok)
+	return true
+}
+
+func (ts *chanTokenSource) Read() (Token, error) {
+	if len(ts.buffer) != 0 {
+		tok := ts.buffer[len(ts.buffer)-1]
+		ts.buffer = ts.buffer[:len(ts.buffer)-1]
+		return tok, nil
+	}
+
+	tok, ok := <-ts.ch
+	if !ok {
+		return nil, ErrEndOfToken
+	}
+	return tok, nil
+}
+
+func (ts *chanTokenSource) Unread(tok Token) {
+	ts.buffer = append(ts.buffer, tok)
+}
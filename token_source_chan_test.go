@@ -0,0 +1,56 @@
+package gqlparser_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+	"github.com/karupanerura/gqlparser/gqltest"
+)
+
+func TestNewChanTokenSource(t *testing.T) {
+	t.Parallel()
+
+	const source = "SELECT a FROM Kind WHERE b = 1 ORDER BY c DESC"
+	tokens, err := gqltest.ReadAll(gqlparser.NewLexer(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ch := make(chan gqlparser.Token)
+	go func() {
+		defer close(ch)
+		for _, tok := range tokens {
+			ch <- tok
+		}
+	}()
+
+	got, err := gqlparser.ParseQuery(gqlparser.NewChanTokenSource(ch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := gqlparser.ParseQuery(gqlparser.NewLexer(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestChanTokenSource_Conformance(t *testing.T) {
+	t.Parallel()
+
+	gqltest.RunTokenSourceConformance(t, func(t *testing.T, tokens []gqlparser.Token, err error) gqlparser.TokenSource {
+		if err != nil {
+			// the channel can't send the error
+			return nil
+		}
+		ch := make(chan gqlparser.Token, len(tokens))
+		for _, tok := range tokens {
+			ch <- tok
+		}
+		close(ch)
+		return gqlparser.NewChanTokenSource(ch)
+	})
+}
@@ -0,0 +1,76 @@
+package gqlparser
+
+import (
+	"fmt"
+	"io"
+)
+
+// TokenSourceOp is the operation on the TokenSource recorded by RecordingTokenSource.
+type TokenSourceOp string
+
+const (
+	ReadTokenSourceOp   TokenSourceOp = "Read"
+	UnreadTokenSourceOp TokenSourceOp = "Unread"
+)
+
+// TokenSourceEvent is the operation recorded by RecordingTokenSource.
+type TokenSourceEvent struct {
+	Op TokenSourceOp
+	// Token is the read or unread token. It's nil if Read fails or the source reads or unreads nil.
+	Token Token
+	// Err is the error returned by Read.
+	Err error
+}
+
+func (e TokenSourceEvent) String() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s() = error (%v)", e.Op, e.Err)
+	}
+	if e.Token == nil {
+		// the broken sources may read or unread nil
+		if e.Op == UnreadTokenSourceOp {
+			return fmt.Sprintf("%s(<nil>)", e.Op)
+		}
+		return fmt.Sprintf("%s() = <nil>", e.Op)
+	}
+	if e.Op == UnreadTokenSourceOp {
+		return fmt.Sprintf("%s(%q at %d)", e.Op, e.Token.GetContent(), e.Token.GetPosition())
+	}
+	return fmt.Sprintf("%s() = %q at %d", e.Op, e.Token.GetContent(), e.Token.GetPosition())
+}
+
+// RecordingTokenSource wraps the TokenSource to record every Read and Unread for debugging the parser.
+type RecordingTokenSource struct {
+	source TokenSource
+	record func(TokenSourceEvent)
+}
+
+var _ TokenSource = (*RecordingTokenSource)(nil)
+
+// NewRecordingTokenSource creates the RecordingTokenSource calling the record function on every Read and Unread.
+func NewRecordingTokenSource(source TokenSource, record func(TokenSourceEvent)) *RecordingTokenSource {
+	return &RecordingTokenSource{source: source, record: record}
+}
+
+// NewLoggingTokenSource creates the RecordingTokenSource writing the events to w line by line.
+// e.g. go test: gqlparser.ParseQuery(gqlparser.NewLoggingTokenSource(gqlparser.NewLexer(source), os.Stderr))
+func NewLoggingTokenSource(source TokenSource, w io.Writer) *RecordingTokenSource {
+	return NewRecordingTokenSource(source, func(e TokenSourceEvent) {
+		_, _ = fmt.Fprintln(w, e.String())
+	})
+}
+
+func (ts *RecordingTokenSource) Next() bool {
+	return ts.source.Next()
+}
+
+func (ts *RecordingTokenSource) Read() (Token, error) {
+	token, err := ts.source.Read()
+	ts.record(TokenSourceEvent{Op: ReadTokenSourceOp, Token: token, Err: err})
+	return token, err
+}
+
+func (ts *RecordingTokenSource) Unread(token Token) {
+	ts.record(TokenSourceEvent{Op: UnreadTokenSourceOp, Token: token})
+	ts.source.Unread(token)
+}
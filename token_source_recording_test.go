@@ -0,0 +1,93 @@
+package gqlparser_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+	"github.com/karupanerura/gqlparser/gqltest"
+)
+
+func TestNewLoggingTokenSource(t *testing.T) {
+	t.Parallel()
+
+	var sb strings.Builder
+	ts := gqlparser.NewLoggingTokenSource(gqlparser.NewLexer("a b"), &sb)
+	tok, err := ts.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts.Unread(tok)
+	if _, err := gqltest.ReadAll(ts); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = ts.Read()
+
+	want := strings.Join([]string{
+		`Read() = "a" at 0`,
+		`Unread("a" at 0)`,
+		`Read() = "a" at 0`,
+		`Read() = " " at 1`,
+		`Read() = "b" at 2`,
+		`Read() = error (end of token)`,
+		"",
+	}, "\n")
+	if diff := cmp.Diff(want, sb.String()); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestTokenSourceEvent_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		event gqlparser.TokenSourceEvent
+		want  string
+	}{
+		{event: gqlparser.TokenSourceEvent{Op: gqlparser.ReadTokenSourceOp, Token: &gqlparser.SymbolToken{Content: "a", Position: 1}}, want: `Read() = "a" at 1`},
+		{event: gqlparser.TokenSourceEvent{Op: gqlparser.UnreadTokenSourceOp, Token: &gqlparser.SymbolToken{Content: "a", Position: 1}}, want: `Unread("a" at 1)`},
+		{event: gqlparser.TokenSourceEvent{Op: gqlparser.ReadTokenSourceOp, Err: gqlparser.ErrEndOfToken}, want: `Read() = error (end of token)`},
+		{event: gqlparser.TokenSourceEvent{Op: gqlparser.ReadTokenSourceOp}, want: `Read() = <nil>`},
+		{event: gqlparser.TokenSourceEvent{Op: gqlparser.UnreadTokenSourceOp}, want: `Unread(<nil>)`},
+	}
+	for _, tt := range tests {
+		if got := tt.event.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestNewRecordingTokenSource(t *testing.T) {
+	t.Parallel()
+
+	var events []gqlparser.TokenSourceEvent
+	ts := gqlparser.NewRecordingTokenSource(gqlparser.NewLexer("KEY(Kind, 1)"), func(e gqlparser.TokenSourceEvent) {
+		events = append(events, e)
+	})
+	if _, err := gqlparser.ParseKey(ts); err != nil {
+		t.Fatal(err)
+	}
+
+	var reads int
+	for _, e := range events {
+		if e.Op == gqlparser.ReadTokenSourceOp && e.Err == nil {
+			reads++
+		}
+	}
+	if reads < 7 {
+		t.Errorf("recorded %d reads: %v", reads, events)
+	}
+}
+
+func TestRecordingTokenSource_Conformance(t *testing.T) {
+	t.Parallel()
+
+	gqltest.RunTokenSourceConformance(t, func(t *testing.T, tokens []gqlparser.Token, err error) gqlparser.TokenSource {
+		var ts gqlparser.TokenSource = gqltest.NewSliceTokenSource(tokens...)
+		if err != nil {
+			ts = gqltest.NewErrorTokenSource(ts, len(tokens), err)
+		}
+		return gqlparser.NewRecordingTokenSource(ts, func(gqlparser.TokenSourceEvent) {})
+	})
+}
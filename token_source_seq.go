@@ -0,0 +1,63 @@
+//go:build go1.23
+
+package gqlparser
+
+import "iter"
+
+// seqTokenSource reads the tokens from the pull iterator. The unread tokens are buffered.
+type seqTokenSource struct {
+	next   func() (Token, error, bool)
+	buffer []Token
+	err    error
+}
+
+// NewSeqTokenSource creates the TokenSource reading the tokens from the sequence.
+// The error yielded by the sequence is returned by Read. The stop function must be called
+// if the parser doesn't read the sequence to the end, like iter.Pull2.
+func NewSeqTokenSource(seq iter.Seq2[Token, error]) (ts TokenSource, stop func()) {
+	next, stop := iter.Pull2(seq)
+	return &seqTokenSource{next: next}, stop
+}
+
+func (ts *seqTokenSource) Next() bool {
+	if len(ts.buffer) != 0 || ts.err != nil {
+		return true
+	}
+
+	tok, err, ok := ts.next()
+	if !ok {
+		return false
+	}
+	if err != nil {
+		ts.err = err
+		return true
+	}
+	ts.buffer = append(ts.buffer, tok)
+	return true
+}
+
+func (ts *seqTokenSource) Read() (Token, error) {
+	if len(ts.buffer) != 0 {
+		tok := ts.buffer[len(ts.buffer)-1]
+		ts.buffer = ts.buffer[:len(ts.buffer)-1]
+		return tok, nil
+	}
+	if ts.err != nil {
+		err := ts.err
+		ts.err = nil
+		return nil, err
+	}
+
+	tok, err, ok := ts.next()
+	if !ok {
+		return nil, ErrEndOfToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tok, nil
+}
+
+func (ts *seqTokenSource) Unread(tok Token) {
+	ts.buffer = append(ts.buffer, tok)
+}
@@ -0,0 +1,73 @@
+//go:build go1.23
+
+package gqlparser_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+	"github.com/karupanerura/gqlparser/gqltest"
+)
+
+func lexerSeq(source string, errAt int, err error) func(yield func(gqlparser.Token, error) bool) {
+	return func(yield func(gqlparser.Token, error) bool) {
+		lexer := gqlparser.NewLexer(source)
+		for i := 0; lexer.Next(); i++ {
+			if i == errAt {
+				yield(nil, err)
+				return
+			}
+			if !yield(lexer.Read()) {
+				return
+			}
+		}
+	}
+}
+
+func TestNewSeqTokenSource(t *testing.T) {
+	t.Parallel()
+
+	const source = "SELECT a FROM Kind WHERE b = 1 ORDER BY c DESC"
+	want, err := gqlparser.ParseQuery(gqlparser.NewLexer(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts, stop := gqlparser.NewSeqTokenSource(lexerSeq(source, -1, nil))
+	defer stop()
+	got, err := gqlparser.ParseQuery(ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	errSeq := errors.New("seq error")
+	ts, stop = gqlparser.NewSeqTokenSource(lexerSeq(source, 4, errSeq))
+	defer stop()
+	if _, err := gqlparser.ParseQuery(ts); !errors.Is(err, errSeq) {
+		t.Errorf("ParseQuery() error = %v, want %v", err, errSeq)
+	}
+}
+
+func TestSeqTokenSource_Conformance(t *testing.T) {
+	t.Parallel()
+
+	gqltest.RunTokenSourceConformance(t, func(t *testing.T, tokens []gqlparser.Token, err error) gqlparser.TokenSource {
+		ts, stop := gqlparser.NewSeqTokenSource(func(yield func(gqlparser.Token, error) bool) {
+			for _, tok := range tokens {
+				if !yield(tok, nil) {
+					return
+				}
+			}
+			if err != nil {
+				yield(nil, err)
+			}
+		})
+		t.Cleanup(stop)
+		return ts
+	})
+}
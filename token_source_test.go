@@ -0,0 +1,49 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+	"github.com/karupanerura/gqlparser/gqltest"
+)
+
+func TestReadToken(t *testing.T) {
+	t.Parallel()
+
+	ts := gqlparser.NewLexer("KEY(")
+	var got []gqlparser.Token
+	for {
+		tok, ok, err := gqlparser.ReadToken(ts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		got = append(got, tok)
+	}
+	want := []gqlparser.Token{
+		&gqlparser.KeywordToken{Name: "KEY", RawContent: "KEY", Position: 0},
+		&gqlparser.OperatorToken{Type: "(", Position: 3},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	// the end of the tokens is reported repeatedly
+	if tok, ok, err := gqlparser.ReadToken(ts); tok != nil || ok || err != nil {
+		t.Errorf("ReadToken() = (%v, %v, %v), want the end of the tokens", tok, ok, err)
+	}
+}
+
+func TestReadToken_Error(t *testing.T) {
+	t.Parallel()
+
+	errInjected := errors.New("injected")
+	ts := gqltest.NewErrorTokenSource(gqlparser.NewLexer("KEY"), 0, errInjected)
+	if _, ok, err := gqlparser.ReadToken(ts); ok || !errors.Is(err, errInjected) {
+		t.Errorf("ReadToken() = (%v, %v), want %v", ok, err, errInjected)
+	}
+}
@@ -0,0 +1,128 @@
+package gqlparser
+
+import (
+	"context"
+	"errors"
+)
+
+// Tracer starts the spans of the parse operations. It's the bridge to the tracing libraries like OpenTelemetry.
+// e.g. the adapter for OpenTelemetry:
+//
+//	type otelTracer struct{ tracer trace.Tracer }
+//
+//	func (t *otelTracer) StartParseSpan(ctx context.Context, operation string) (context.Context, gqlparser.ParseSpan) {
+//		ctx, span := t.tracer.Start(ctx, operation)
+//		return ctx, &otelSpan{span: span}
+//	}
+//
+//	type otelSpan struct{ span trace.Span }
+//
+//	func (s *otelSpan) End(attrs gqlparser.ParseSpanAttributes) {
+//		s.span.SetAttributes(attribute.String("gql.kind", string(attrs.Kind)), attribute.Int("gql.clauses", attrs.Clauses), attribute.Int("gql.tokens", attrs.Tokens))
+//		if attrs.Err != nil {
+//			s.span.RecordError(attrs.Err)
+//			s.span.SetStatus(codes.Error, attrs.Err.Error())
+//		}
+//		s.span.End()
+//	}
+type Tracer interface {
+	StartParseSpan(ctx context.Context, operation string) (context.Context, ParseSpan)
+}
+
+// ParseSpan is the span of the parse operation started by Tracer.
+type ParseSpan interface {
+	End(ParseSpanAttributes)
+}
+
+// ParseSpanAttributes is the result of the parse operation recorded to the span.
+type ParseSpanAttributes struct {
+	// Kind is the kind of the query. It may be empty if the parsing fails before the FROM clause.
+	// It's always empty for the conditions.
+	Kind Kind
+	// Clauses is the number of the clauses in the query. e.g. 3 for SELECT * FROM Kind WHERE a = 1
+	// The aggregation is counted as the SELECT clause, and it's always zero for the conditions.
+	Clauses int
+	// Tokens is the number of the tokens consumed from the token source except the re-reads.
+	Tokens int
+	Err    error
+}
+
+// WithTracer sets the tracer used by ParseQueryContext, ParseAggregationQueryContext and ParseConditionContext.
+func WithTracer(tracer Tracer) ParseOption {
+	return func(o *parseOptions) {
+		o.tracer = tracer
+	}
+}
+
+// ParseQueryContext is ParseQuery creating the span by the tracer given with WithTracer.
+// It's equivalent to ParseQuery if the tracer isn't given.
+func ParseQueryContext(ctx context.Context, ts TokenSource, opts ...ParseOption) (*Query, error) {
+	return parseContext(ctx, "ParseQuery", ts, opts, ParseQuery, func(attrs *ParseSpanAttributes, query *Query) {
+		attrs.Kind = query.Kind
+		attrs.Clauses = query.clauseCount()
+	})
+}
+
+// ParseAggregationQueryContext is ParseAggregationQuery creating the span by the tracer given with WithTracer.
+// It's equivalent to ParseAggregationQuery if the tracer isn't given.
+func ParseAggregationQueryContext(ctx context.Context, ts TokenSource, opts ...ParseOption) (*AggregationQuery, error) {
+	return parseContext(ctx, "ParseAggregationQuery", ts, opts, ParseAggregationQuery, func(attrs *ParseSpanAttributes, query *AggregationQuery) {
+		attrs.Kind = query.Kind
+		attrs.Clauses = query.Query.clauseCount()
+		if query.Having != nil {
+			attrs.Clauses++
+		}
+	})
+}
+
+// ParseConditionContext is ParseCondition creating the span by the tracer given with WithTracer.
+// It's equivalent to ParseCondition if the tracer isn't given.
+func ParseConditionContext(ctx context.Context, ts TokenSource, opts ...ParseOption) (Condition, error) {
+	return parseContext(ctx, "ParseCondition", ts, opts, ParseCondition, nil)
+}
+
+// parseContext parses the source by parse creating the span of the operation if the tracer is given.
+// The attributes of the parsed or the partially parsed syntax are filled by describe if it isn't nil.
+func parseContext[T any](ctx context.Context, operation string, ts TokenSource, opts []ParseOption, parse func(TokenSource, ...ParseOption) (T, error), describe func(*ParseSpanAttributes, T)) (T, error) {
+	o := newParseOptions(opts)
+	if o.tracer == nil {
+		return parse(ts, opts...)
+	}
+
+	_, span := o.tracer.StartParseSpan(ctx, operation)
+	counter := &countingTokenSource{TokenSource: ts}
+	result, err := parse(counter, opts...)
+
+	attrs := ParseSpanAttributes{Tokens: counter.tokens(), Err: err}
+	var partial any
+	var parseErr *ParseError
+	if err == nil {
+		partial = result
+	} else if errors.As(err, &parseErr) {
+		partial = parseErr.Partial
+	}
+	if syntax, ok := partial.(T); ok && describe != nil {
+		describe(&attrs, syntax)
+	}
+	span.End(attrs)
+	return result, err
+}
+
+// clauseCount counts the clauses present in the query. The SELECT clause is always counted.
+func (q *Query) clauseCount() int {
+	count := 1
+	for _, present := range []bool{
+		q.Kind != "" || q.KindBinding != nil,
+		q.Distinct || len(q.DistinctOn) != 0,
+		q.Where != nil,
+		len(q.GroupBy) != 0,
+		len(q.OrderBy) != 0,
+		q.Limit != nil,
+		q.Offset != nil,
+	} {
+		if present {
+			count++
+		}
+	}
+	return count
+}
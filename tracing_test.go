@@ -0,0 +1,99 @@
+package gqlparser_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/karupanerura/gqlparser"
+)
+
+type recordingTracer struct {
+	operations []string
+	spans      []gqlparser.ParseSpanAttributes
+}
+
+func (t *recordingTracer) StartParseSpan(ctx context.Context, operation string) (context.Context, gqlparser.ParseSpan) {
+	t.operations = append(t.operations, operation)
+	return ctx, t
+}
+
+func (t *recordingTracer) End(attrs gqlparser.ParseSpanAttributes) {
+	t.spans = append(t.spans, attrs)
+}
+
+func TestParseQueryContext(t *testing.T) {
+	t.Parallel()
+
+	tracer := &recordingTracer{}
+	ctx := context.Background()
+	if _, err := gqlparser.ParseQueryContext(ctx, gqlparser.NewLexer("SELECT * FROM Kind WHERE a = 1 LIMIT 10"), gqlparser.WithTracer(tracer)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gqlparser.ParseQueryContext(ctx, gqlparser.NewLexer("SELECT * FROM Kind WHERE"), gqlparser.WithTracer(tracer)); err == nil {
+		t.Fatal("ParseQueryContext() error = nil")
+	}
+	if _, err := gqlparser.ParseQueryContext(ctx, gqlparser.NewLexer("SELECT * FROM Kind")); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{"ParseQuery", "ParseQuery"}, tracer.operations); diff != "" {
+		t.Errorf("operations: (-want, +got)\n%s", diff)
+	}
+	want := []gqlparser.ParseSpanAttributes{
+		{Kind: "Kind", Clauses: 4, Tokens: 19},
+		{Kind: "Kind", Clauses: 2, Tokens: 9},
+	}
+	if diff := cmp.Diff(want, tracer.spans, cmpopts.IgnoreFields(gqlparser.ParseSpanAttributes{}, "Err")); diff != "" {
+		t.Errorf("spans: (-want, +got)\n%s", diff)
+	}
+	if len(tracer.spans) == 2 && (tracer.spans[0].Err != nil || tracer.spans[1].Err == nil) {
+		t.Errorf("errors = %v, %v", tracer.spans[0].Err, tracer.spans[1].Err)
+	}
+}
+
+func TestParseAggregationQueryContext(t *testing.T) {
+	t.Parallel()
+
+	tracer := &recordingTracer{}
+	ctx := context.Background()
+	if _, err := gqlparser.ParseAggregationQueryContext(ctx, gqlparser.NewLexer("AGGREGATE COUNT(*) OVER (SELECT * FROM Kind WHERE a = 1)"), gqlparser.WithTracer(tracer)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gqlparser.ParseAggregationQueryContext(ctx, gqlparser.NewLexer("SELECT COUNT(*) FROM Kind WHERE"), gqlparser.WithTracer(tracer)); err == nil {
+		t.Fatal("ParseAggregationQueryContext() error = nil")
+	}
+
+	if diff := cmp.Diff([]string{"ParseAggregationQuery", "ParseAggregationQuery"}, tracer.operations); diff != "" {
+		t.Errorf("operations: (-want, +got)\n%s", diff)
+	}
+	want := []gqlparser.ParseSpanAttributes{
+		{Kind: "Kind", Clauses: 3, Tokens: 26},
+		{Kind: "Kind", Clauses: 2, Tokens: 12},
+	}
+	if diff := cmp.Diff(want, tracer.spans, cmpopts.IgnoreFields(gqlparser.ParseSpanAttributes{}, "Err")); diff != "" {
+		t.Errorf("spans: (-want, +got)\n%s", diff)
+	}
+}
+
+func TestParseConditionContext(t *testing.T) {
+	t.Parallel()
+
+	tracer := &recordingTracer{}
+	ctx := context.Background()
+	if _, err := gqlparser.ParseConditionContext(ctx, gqlparser.NewLexer("a = 1 AND b = 2"), gqlparser.WithTracer(tracer)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gqlparser.ParseConditionContext(ctx, gqlparser.NewLexer("a = 1")); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{"ParseCondition"}, tracer.operations); diff != "" {
+		t.Errorf("operations: (-want, +got)\n%s", diff)
+	}
+	want := []gqlparser.ParseSpanAttributes{{Tokens: 13}}
+	if diff := cmp.Diff(want, tracer.spans); diff != "" {
+		t.Errorf("spans: (-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,182 @@
+package gqlparser
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// CoerceValue converts the value to compare it with the property of the target type by the rules of Cloud Datastore.
+//   - NULL can be compared with any types.
+//   - The integers and the doubles are compared numerically as the same type. The integers are converted into the doubles
+//     for the double properties, and the integral doubles are converted into the integers for the integer properties.
+//   - The timestamps are truncated to microseconds because Cloud Datastore stores them in the microsecond precision.
+//   - The values are compared with each element of the array properties, so they are returned as they are.
+//
+// It returns ErrTypeMismatch if the value cannot be compared with the type.
+func CoerceValue(v any, target ValueType) (any, error) {
+	typ := ValueTypeOf(v)
+	if typ == UnknownValueType {
+		return nil, fmt.Errorf("%w: unsupported value %T", ErrTypeMismatch, v)
+	}
+	if typ == NullValueType || target == UnknownValueType || target == ArrayValueType {
+		return v, nil
+	}
+
+	switch target {
+	case IntegerValueType:
+		if f, ok := v.(float64); ok {
+			if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+				return int64(f), nil
+			}
+			return f, nil
+		}
+	case DoubleValueType:
+		if i, ok := v.(int64); ok {
+			return float64(i), nil
+		}
+	case TimestampValueType:
+		if t, ok := v.(time.Time); ok {
+			return t.Truncate(time.Microsecond), nil
+		}
+	}
+	if typ == target || isNumericValueType(typ) && isNumericValueType(target) {
+		return v, nil
+	}
+	return nil, fmt.Errorf("%w: %s cannot be compared with %s", ErrTypeMismatch, typ, target)
+}
+
+// valueTypeOrders is the order of the types in Cloud Datastore. The integers and the doubles are ordered together.
+var valueTypeOrders = map[ValueType]int{
+	NullValueType:      0,
+	BooleanValueType:   1,
+	IntegerValueType:   2,
+	DoubleValueType:    2,
+	TimestampValueType: 3,
+	StringValueType:    4,
+	BlobValueType:      5,
+	KeyValueType:       6,
+	ArrayValueType:     7,
+}
+
+// CompareValues compares the values by the ordering of Cloud Datastore.
+// It returns a negative number if a < b, zero if a == b, and a positive number if a > b.
+// The values of the different types are ordered by the types. e.g. NULL < booleans < numbers < timestamps < strings
+// NaN is lesser than any other numbers.
+func CompareValues(a, b any) (int, error) {
+	at, bt := ValueTypeOf(a), ValueTypeOf(b)
+	if at == UnknownValueType {
+		return 0, fmt.Errorf("%w: unsupported value %T", ErrTypeMismatch, a)
+	}
+	if bt == UnknownValueType {
+		return 0, fmt.Errorf("%w: unsupported value %T", ErrTypeMismatch, b)
+	}
+	if ao, bo := valueTypeOrders[at], valueTypeOrders[bt]; ao != bo {
+		return compareOrdered(ao, bo), nil
+	}
+
+	switch av := a.(type) {
+	case nil:
+		return 0, nil
+	case bool:
+		bv := b.(bool)
+		switch {
+		case av == bv:
+			return 0, nil
+		case bv:
+			return -1, nil
+		default:
+			return 1, nil
+		}
+	case int64:
+		if bv, ok := b.(int64); ok {
+			return compareOrdered(av, bv), nil
+		}
+		return compareNumbers(float64(av), b.(float64)), nil
+	case float64:
+		if bv, ok := b.(int64); ok {
+			return compareNumbers(av, float64(bv)), nil
+		}
+		return compareNumbers(av, b.(float64)), nil
+	case time.Time:
+		return av.Truncate(time.Microsecond).Compare(b.(time.Time).Truncate(time.Microsecond)), nil
+	case string:
+		return strings.Compare(av, b.(string)), nil
+	case []byte:
+		return bytes.Compare(av, b.([]byte)), nil
+	case *Key:
+		return compareKeys(av, b.(*Key)), nil
+	case []any:
+		bv := b.([]any)
+		for i := 0; i < len(av) && i < len(bv); i++ {
+			if c, err := CompareValues(av[i], bv[i]); err != nil || c != 0 {
+				return c, err
+			}
+		}
+		return compareOrdered(len(av), len(bv)), nil
+	default:
+		return 0, fmt.Errorf("%w: unsupported value %T", ErrTypeMismatch, a)
+	}
+}
+
+func compareOrdered[T int | int64 | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareNumbers(a, b float64) int {
+	switch {
+	case math.IsNaN(a) && math.IsNaN(b):
+		return 0
+	case math.IsNaN(a):
+		return -1
+	case math.IsNaN(b):
+		return 1
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareKeys compares the keys by the project ID, the namespace, and the path elements.
+// The ancestors are lesser than the descendants, and the numeric IDs are lesser than the names.
+func compareKeys(a, b *Key) int {
+	if c := strings.Compare(string(a.ProjectID), string(b.ProjectID)); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Namespace, b.Namespace); c != 0 {
+		return c
+	}
+	for i := 0; i < len(a.Path) && i < len(b.Path); i++ {
+		ap, bp := a.Path[i], b.Path[i]
+		if c := strings.Compare(string(ap.Kind), string(bp.Kind)); c != 0 {
+			return c
+		}
+		switch {
+		case ap.Name == "" && bp.Name == "":
+			if c := compareOrdered(ap.ID, bp.ID); c != 0 {
+				return c
+			}
+		case ap.Name == "":
+			return -1
+		case bp.Name == "":
+			return 1
+		default:
+			if c := strings.Compare(ap.Name, bp.Name); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareOrdered(len(a.Path), len(b.Path))
+}
@@ -0,0 +1,108 @@
+package gqlparser_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestCoerceValue(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2013, 9, 29, 9, 30, 20, 123456789, time.UTC)
+	tests := []struct {
+		name     string
+		value    any
+		target   gqlparser.ValueType
+		want     any
+		mismatch bool
+	}{
+		{name: "Null", value: nil, target: gqlparser.StringValueType, want: nil},
+		{name: "Same", value: "a", target: gqlparser.StringValueType, want: "a"},
+		{name: "IntegerToDouble", value: int64(1), target: gqlparser.DoubleValueType, want: float64(1)},
+		{name: "IntegralDoubleToInteger", value: float64(2), target: gqlparser.IntegerValueType, want: int64(2)},
+		{name: "FractionalDoubleToInteger", value: 2.5, target: gqlparser.IntegerValueType, want: 2.5},
+		{name: "Timestamp", value: now, target: gqlparser.TimestampValueType, want: now.Truncate(time.Microsecond)},
+		{name: "Array", value: "a", target: gqlparser.ArrayValueType, want: "a"},
+		{name: "Unknown", value: int64(1), target: gqlparser.UnknownValueType, want: int64(1)},
+		{name: "StringToInteger", value: "1", target: gqlparser.IntegerValueType, mismatch: true},
+		{name: "StringToKey", value: "a", target: gqlparser.KeyValueType, mismatch: true},
+		{name: "Binding", value: &gqlparser.NamedBinding{Name: "a"}, target: gqlparser.StringValueType, mismatch: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := gqlparser.CoerceValue(tt.value, tt.target)
+			if errors.Is(err, gqlparser.ErrTypeMismatch) != tt.mismatch {
+				t.Fatalf("CoerceValue() error = %v, mismatch %v", err, tt.mismatch)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2013, 9, 29, 9, 30, 20, 0, time.UTC)
+	parent := &gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "Parent", ID: 1}}}
+	child := &gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "Parent", ID: 1}, {Kind: "Child", Name: "a"}}}
+
+	// every value is lesser than the next one
+	ordered := []any{
+		nil,
+		false,
+		true,
+		math.NaN(),
+		int64(-1),
+		0.5,
+		int64(1),
+		1.5,
+		now,
+		now.Add(time.Microsecond),
+		"",
+		"a",
+		[]byte{0},
+		&gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "A", ID: 2}}},
+		&gqlparser.Key{Path: []*gqlparser.KeyPath{{Kind: "A", Name: "a"}}},
+		parent,
+		child,
+		[]any{int64(1)},
+		[]any{int64(1), int64(2)},
+	}
+	for i := range ordered {
+		for j := range ordered {
+			got, err := gqlparser.CompareValues(ordered[i], ordered[j])
+			if err != nil {
+				t.Fatalf("CompareValues(%v, %v) error = %v", ordered[i], ordered[j], err)
+			}
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if got != want {
+				t.Errorf("CompareValues(%v, %v) = %d, want %d", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+
+	if got, _ := gqlparser.CompareValues(int64(1), 1.0); got != 0 {
+		t.Errorf("CompareValues(1, 1.0) = %d, want 0", got)
+	}
+	if _, err := gqlparser.CompareValues(int64(1), struct{}{}); !errors.Is(err, gqlparser.ErrTypeMismatch) {
+		t.Errorf("CompareValues() error = %v, want %v", err, gqlparser.ErrTypeMismatch)
+	}
+}
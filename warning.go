@@ -0,0 +1,115 @@
+package gqlparser
+
+import "fmt"
+
+// WarningKind is the kind of the syntax that parses but is non-portable.
+type WarningKind string
+
+const (
+	// BackwardComparatorWarning is reported for the conditions that have the value on the left side. e.g. 1 IN prop, 10 < prop
+	BackwardComparatorWarning WarningKind = "backward comparator"
+	// ContainsWarning is reported for CONTAINS that is not supported by some of the GQL implementations.
+	ContainsWarning WarningKind = "contains"
+	// TrailingSemicolonWarning is reported for the semicolon at the end of the statement.
+	TrailingSemicolonWarning WarningKind = "trailing semicolon"
+	// TrailingCommaWarning is reported for the comma just before the closing parenthesis of the array. e.g. ARRAY(1, 2,)
+	TrailingCommaWarning WarningKind = "trailing comma"
+	// OffsetBeforeLimitWarning is reported for LIMIT following OFFSET. e.g. OFFSET 10 LIMIT 5
+	OffsetBeforeLimitWarning WarningKind = "offset before limit"
+	// DuplicateProjectionWarning is reported for the projected property appearing again with the same alias. e.g. SELECT a, a FROM Kind
+	DuplicateProjectionWarning WarningKind = "duplicate projection"
+	// AggregatedOrderByWarning is reported for ORDER BY without LIMIT in the aggregated query that doesn't change the result.
+	// e.g. AGGREGATE COUNT(*) OVER (SELECT * FROM Kind ORDER BY a)
+	AggregatedOrderByWarning WarningKind = "order by in aggregation"
+	// AggregatedProjectionWarning is reported for the projection or DISTINCT in the aggregated query rejected by the server.
+	// e.g. AGGREGATE COUNT(*) OVER (SELECT a FROM Kind)
+	AggregatedProjectionWarning WarningKind = "projection in aggregation"
+	// KeyComparisonWarning is reported for the range comparison of the property other than __key__ with the key,
+	// which fails at runtime unless the property holds the keys. e.g. parent < KEY(Task, 1)
+	// Query.Validate rejects it with the schema that doesn't type the property as KeyValueType.
+	KeyComparisonWarning WarningKind = "key comparison"
+)
+
+// Warning is an advisory for the syntax that parses but is non-portable.
+type Warning struct {
+	Kind  WarningKind
+	Token Token
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s at %d", w.Kind, w.Token.GetContent(), w.Token.GetPosition())
+}
+
+// WithWarningHandler sets the handler that receives the warnings while parsing.
+// The parsing continues regardless of the warnings.
+func WithWarningHandler(handler func(Warning)) ParseOption {
+	return func(o *parseOptions) {
+		o.warningHandler = handler
+	}
+}
+
+func (o *parseOptions) warn(kind WarningKind, tok Token) {
+	if o.warningHandler == nil {
+		return
+	}
+	o.warningHandler(Warning{Kind: kind, Token: tok})
+}
+
+// checkConditionAST reports the warnings for the condition AST in the order of the appearance.
+// In strict mode, it rejects the syntax that is reported as the warnings in lenient mode instead.
+func (o *parseOptions) checkConditionAST(ast conditionAST) error {
+	switch c := ast.(type) {
+	case *compoundComparatorCondition:
+		if err := o.checkConditionAST(c.left); err != nil {
+			return err
+		}
+		return o.checkConditionAST(c.right)
+	case *forwardComparatorCondition:
+		if c.opType == string(ContainsForwardComparator) {
+			o.warn(ContainsWarning, c.op)
+		}
+		o.checkKeyComparison(c.opType, c.left, c.right)
+		if c.quantifier != nil && o.strict {
+			// the array literal is required to be expanded
+			if array, ok := c.right.(*conditionArray); !ok || len(array.values) == 0 {
+				return &SyntaxError{Token: c.quantifier, Reason: fmt.Sprintf("%s requires the non-empty array in strict mode", c.quantifiedComparator())}
+			}
+		}
+		return o.checkConditionValue(c.right)
+	case *backwardComparatorCondition:
+		o.warn(BackwardComparatorWarning, c.op)
+		o.checkKeyComparison(c.opType, c.right, c.left)
+		return o.checkConditionValue(c.left)
+	default:
+		return nil
+	}
+}
+
+func (o *parseOptions) checkConditionValue(v conditionValuer) error {
+	array, ok := v.(*conditionArray)
+	if !ok {
+		return nil
+	}
+	for _, element := range array.values {
+		if err := o.checkConditionValue(element); err != nil {
+			return err
+		}
+	}
+	if array.trailingComma != nil {
+		if o.strict {
+			return &SyntaxError{Token: array.trailingComma, Reason: "trailing comma is not allowed in strict mode"}
+		}
+		o.warn(TrailingCommaWarning, array.trailingComma)
+	}
+	return nil
+}
+
+// checkKeyComparison warns the range comparison of the key with the property other than __key__ at the KEY keyword.
+// It's not rejected in strict mode because the key properties are known only by the schema.
+func (o *parseOptions) checkKeyComparison(opType string, field *conditionField, v conditionValuer) {
+	key, ok := v.(*conditionKey)
+	if !ok || !EitherComparator(opType).isRange() || field.name() == keyProperty {
+		return
+	}
+	o.warn(KeyComparisonWarning, key.keyKeyword)
+}
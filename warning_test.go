@@ -0,0 +1,240 @@
+package gqlparser_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+)
+
+func TestWithWarningHandler(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+	handler := gqlparser.WithWarningHandler(func(w gqlparser.Warning) {
+		got = append(got, w.String())
+	})
+
+	source := "SELECT * FROM Kind WHERE tags CONTAINS 'a' AND 1 IN b OR 10 < c AND d IN (1, 2,);"
+	if _, err := gqlparser.ParseQuery(gqlparser.NewLexer(source), handler); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"contains: CONTAINS at 30",
+		"backward comparator: IN at 49",
+		"backward comparator: < at 60",
+		"trailing comma: , at 78",
+		"trailing semicolon: ; at 80",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestWithWarningHandler_OffsetBeforeLimit(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+	handler := gqlparser.WithWarningHandler(func(w gqlparser.Warning) {
+		got = append(got, w.String())
+	})
+
+	query, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM Kind OFFSET @cursor + 10 LIMIT 5"), handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM Kind LIMIT 5 OFFSET @cursor + 10"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, query); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"offset before limit: LIMIT at 39"}, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	// LIMIT is accepted only once
+	if _, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM Kind LIMIT 1 OFFSET 10 LIMIT 5")); err == nil {
+		t.Error("ParseQuery() should fail")
+	}
+}
+
+func TestWithWarningHandler_AggregatedQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		source     string
+		want       []string
+		wantStrict string
+	}{
+		{
+			name:   "Valid",
+			source: "AGGREGATE COUNT(*) OVER (SELECT __key__ FROM Kind WHERE a = 1 ORDER BY a LIMIT 10 OFFSET 5)",
+		},
+		{
+			name:   "OrderByWithoutLimit",
+			source: "AGGREGATE COUNT(*) OVER (SELECT * FROM Kind ORDER BY a)",
+			want:   []string{"order by in aggregation: ORDER at 44"},
+		},
+		{
+			name:       "Projection",
+			source:     "AGGREGATE SUM(a) OVER (SELECT a, b FROM Kind)",
+			want:       []string{"projection in aggregation: a at 30"},
+			wantStrict: "unexpected token: a at 30 (projection is not allowed in the aggregated query in strict mode)",
+		},
+		{
+			name:       "Distinct",
+			source:     "AGGREGATE COUNT(*) OVER ( SELECT DISTINCT ON (a) * FROM Kind ORDER BY a)",
+			want:       []string{"projection in aggregation: DISTINCT at 33", "order by in aggregation: ORDER at 61"},
+			wantStrict: "unexpected token: DISTINCT at 33 (projection is not allowed in the aggregated query in strict mode)",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			handler := gqlparser.WithWarningHandler(func(w gqlparser.Warning) {
+				got = append(got, w.String())
+			})
+			if _, err := gqlparser.ParseAggregationQuery(gqlparser.NewLexer(tt.source), handler); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+
+			_, err := gqlparser.ParseAggregationQuery(gqlparser.NewLexer(tt.source), gqlparser.WithStrictMode())
+			if tt.wantStrict == "" {
+				if err != nil {
+					t.Errorf("ParseAggregationQuery() error = %v", err)
+				}
+			} else if err == nil || err.Error() != tt.wantStrict {
+				t.Errorf("ParseAggregationQuery() error = %v, want %q", err, tt.wantStrict)
+			}
+		})
+	}
+}
+
+func TestWithWarningHandler_DuplicateProjection(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		source  string
+		opts    []gqlparser.ParseOption
+		want    *gqlparser.Query
+		warns   []string
+		wantErr string
+	}{
+		{
+			name:   "Kept",
+			source: "SELECT a, b, a FROM Kind",
+			want:   &gqlparser.Query{Properties: []gqlparser.Property{"a", "b", "a"}, Kind: "Kind"},
+			warns:  []string{"duplicate projection: a at 13"},
+		},
+		{
+			name:   "Deduped",
+			source: "SELECT __key__, `__key__`, __key__ FROM Kind",
+			opts:   []gqlparser.ParseOption{gqlparser.WithDedupeProjections()},
+			want:   &gqlparser.Query{Properties: []gqlparser.Property{"__key__"}, KeysOnly: true, Kind: "Kind"},
+			warns:  []string{"duplicate projection: `__key__` at 16", "duplicate projection: __key__ at 27"},
+		},
+		{
+			name:   "Aliases",
+			source: "SELECT a AS x, a AS y, a AS x, b FROM Kind",
+			opts:   []gqlparser.ParseOption{gqlparser.WithDedupeProjections(), gqlparser.WithProjectionSpans()},
+			want: &gqlparser.Query{
+				Properties:      []gqlparser.Property{"a", "a", "b"},
+				Aliases:         []string{"x", "y", ""},
+				ProjectionSpans: []gqlparser.Span{{Start: 7, End: 13}, {Start: 15, End: 21}, {Start: 31, End: 32}},
+				Kind:            "Kind",
+			},
+			warns: []string{"duplicate projection: a at 23"},
+		},
+		{
+			name:   "Placeholders",
+			source: "SELECT @p, a, a, @q FROM Kind",
+			opts:   []gqlparser.ParseOption{gqlparser.WithDedupeProjections(), gqlparser.WithTemplatePlaceholders()},
+			want: &gqlparser.Query{
+				Properties: []gqlparser.Property{"", "a", ""},
+				Kind:       "Kind",
+				PropertyBindings: []*gqlparser.PropertyBinding{
+					{Clause: gqlparser.ProjectionPropertyBindingClause, Index: 0, Variable: &gqlparser.NamedBinding{Name: "p"}},
+					{Clause: gqlparser.ProjectionPropertyBindingClause, Index: 2, Variable: &gqlparser.NamedBinding{Name: "q"}},
+				},
+			},
+			warns: []string{"duplicate projection: a at 14"},
+		},
+		{
+			name:    "Strict",
+			source:  "SELECT a, a FROM Kind",
+			opts:    []gqlparser.ParseOption{gqlparser.WithStrictMode()},
+			wantErr: "unexpected token: a at 10 (duplicate projection is not allowed in strict mode) in SELECT clause",
+		},
+		{
+			name:   "StrictDeduped",
+			source: "SELECT a, a FROM Kind",
+			opts:   []gqlparser.ParseOption{gqlparser.WithStrictMode(), gqlparser.WithDedupeProjections()},
+			want:   &gqlparser.Query{Properties: []gqlparser.Property{"a"}, Kind: "Kind"},
+			warns:  []string{"duplicate projection: a at 10"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var warns []string
+			opts := append([]gqlparser.ParseOption{gqlparser.WithWarningHandler(func(w gqlparser.Warning) {
+				warns = append(warns, w.String())
+			})}, tt.opts...)
+			got, err := gqlparser.ParseQuery(gqlparser.NewLexer(tt.source), opts...)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ParseQuery() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.warns, warns); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithWarningHandler_KeyComparison(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+	handler := gqlparser.WithWarningHandler(func(w gqlparser.Warning) {
+		got = append(got, w.String())
+	})
+
+	source := "SELECT * FROM Kind WHERE __key__ > KEY(Kind, 1) AND owner = KEY(User, 1) AND owner < KEY(User, 2) AND KEY(User, 3) >= owner"
+	for _, opts := range [][]gqlparser.ParseOption{{handler}, {handler, gqlparser.WithStrictMode()}} {
+		got = nil
+		if _, err := gqlparser.ParseQuery(gqlparser.NewLexer(source), opts...); err != nil {
+			t.Fatal(err)
+		}
+
+		want := []string{
+			"key comparison: KEY at 85",
+			"backward comparator: >= at 115",
+			"key comparison: KEY at 102",
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("(-want, +got)\n%s", diff)
+		}
+	}
+}
@@ -0,0 +1,813 @@
+package gqlparser
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var ErrInvalidYAML = errors.New("invalid YAML document")
+
+// The YAML support is implemented with the interfaces shared by the popular YAML libraries without depending on them.
+// The syntax is marshaled into the plain maps, slices and scalars by MarshalYAML, and unmarshaled from them
+// by UnmarshalYAML(func(any) error) error that is supported by gopkg.in/yaml.v2, gopkg.in/yaml.v3 and github.com/goccy/go-yaml.
+//
+// The field names are stable, and the maps are written in the order of the keys by the YAML libraries and encoding/json. e.g.
+//
+//	kind: Kind
+//	where:
+//	  type: either
+//	  comparator: "="
+//	  property: a
+//	  value: 1
+//	limit:
+//	  position: 10
+//
+// The integers, the strings, the booleans and NULL are the scalars, and the other values are the maps with the single key.
+// e.g. {double: 1.5}, {blob: YQ==}, {datetime: "2013-09-29T09:30:20Z"}, {array: [1, 2]}, {binding: name}, {binding: 1}, {cursor: AQID}
+// {key: {project: p, namespace: ns, path: [{kind: Kind, id: 1}, {kind: Kind, name: a}, {kind: Kind, binding: {binding: id}}]}}
+
+// MarshalYAML implements the Marshaler interface of the YAML libraries.
+// It has the value receiver to marshal the queries in the fields of the value types too.
+func (q Query) MarshalYAML() (any, error) {
+	return queryToYAML(&q)
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of the YAML libraries.
+func (q *Query) UnmarshalYAML(unmarshal func(any) error) error {
+	var doc map[string]any
+	if err := unmarshal(&doc); err != nil {
+		return err
+	}
+	*q = Query{}
+	return queryFromYAML(q, doc)
+}
+
+// MarshalYAML implements the Marshaler interface of the YAML libraries.
+// It has the value receiver to marshal the queries in the fields of the value types too.
+func (q AggregationQuery) MarshalYAML() (any, error) {
+	aggregations := make([]any, len(q.Aggregations))
+	for i, a := range q.Aggregations {
+		switch v := a.(type) {
+		case *CountAggregation:
+			aggregations[i] = withYAMLAlias(map[string]any{"type": "count"}, v.Alias)
+		case *CountUpToAggregation:
+			aggregations[i] = withYAMLAlias(map[string]any{"type": "countUpTo", "limit": v.Limit}, v.Alias)
+		case *SumAggregation:
+			aggregations[i] = withYAMLAlias(map[string]any{"type": "sum", "property": v.Property}, v.Alias)
+		case *AvgAggregation:
+			aggregations[i] = withYAMLAlias(map[string]any{"type": "avg", "property": v.Property}, v.Alias)
+		default:
+			return nil, fmt.Errorf("%w: unsupported aggregation %T", ErrInvalidYAML, a)
+		}
+	}
+	query, err := queryToYAML(&q.Query)
+	if err != nil {
+		return nil, err
+	}
+	doc := map[string]any{"aggregations": aggregations, "query": query}
+	if q.Having != nil {
+		having, err := conditionToYAML(q.Having)
+		if err != nil {
+			return nil, err
+		}
+		doc["having"] = having
+	}
+	return doc, nil
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of the YAML libraries.
+func (q *AggregationQuery) UnmarshalYAML(unmarshal func(any) error) error {
+	var doc map[string]any
+	if err := unmarshal(&doc); err != nil {
+		return err
+	}
+
+	*q = AggregationQuery{}
+	aggregations, err := yamlSlice(doc["aggregations"], "aggregations")
+	if err != nil {
+		return err
+	}
+	for _, item := range aggregations {
+		m, err := yamlMap(item, "aggregation")
+		if err != nil {
+			return err
+		}
+		alias, err := yamlOptionalString(m["alias"], "alias")
+		if err != nil {
+			return err
+		}
+		switch m["type"] {
+		case "count":
+			q.Aggregations = append(q.Aggregations, &CountAggregation{Alias: alias})
+		case "countUpTo":
+			limit, err := yamlInt(m["limit"], "limit")
+			if err != nil {
+				return err
+			}
+			q.Aggregations = append(q.Aggregations, &CountUpToAggregation{Limit: limit, Alias: alias})
+		case "sum", "avg":
+			property, err := yamlString(m["property"], "property")
+			if err != nil {
+				return err
+			}
+			if m["type"] == "sum" {
+				q.Aggregations = append(q.Aggregations, &SumAggregation{Property: property, Alias: alias})
+			} else {
+				q.Aggregations = append(q.Aggregations, &AvgAggregation{Property: property, Alias: alias})
+			}
+		default:
+			return fmt.Errorf("%w: unknown aggregation type %v", ErrInvalidYAML, m["type"])
+		}
+	}
+
+	if v, ok := doc["having"]; ok {
+		if q.Having, err = conditionFromYAML(v); err != nil {
+			return err
+		}
+	}
+	query, err := yamlMap(doc["query"], "query")
+	if err != nil {
+		return err
+	}
+	return queryFromYAML(&q.Query, query)
+}
+
+// YAMLCondition wraps the condition to marshal and unmarshal it by the YAML libraries
+// because the methods cannot be defined on the Condition interface.
+type YAMLCondition struct {
+	Condition Condition
+}
+
+// MarshalYAML implements the Marshaler interface of the YAML libraries.
+func (c YAMLCondition) MarshalYAML() (any, error) {
+	return conditionToYAML(c.Condition)
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of the YAML libraries.
+func (c *YAMLCondition) UnmarshalYAML(unmarshal func(any) error) error {
+	var doc any
+	if err := unmarshal(&doc); err != nil {
+		return err
+	}
+	cond, err := conditionFromYAML(doc)
+	if err != nil {
+		return err
+	}
+	c.Condition = cond
+	return nil
+}
+
+func withYAMLAlias(m map[string]any, alias string) map[string]any {
+	if alias != "" {
+		m["alias"] = alias
+	}
+	return m
+}
+
+func queryToYAML(q *Query) (map[string]any, error) {
+	doc := map[string]any{}
+	if len(q.Properties) != 0 {
+		doc["properties"] = propertiesToYAML(q.Properties)
+	}
+	if len(q.Aliases) != 0 {
+		aliases := make([]any, len(q.Aliases))
+		for i, alias := range q.Aliases {
+			aliases[i] = alias
+		}
+		doc["aliases"] = aliases
+	}
+	if q.KeysOnly {
+		doc["keysOnly"] = true
+	}
+	if q.Distinct {
+		doc["distinct"] = true
+	}
+	if len(q.DistinctOn) != 0 {
+		doc["distinctOn"] = propertiesToYAML(q.DistinctOn)
+	}
+	if q.Kind != "" {
+		doc["kind"] = string(q.Kind)
+	}
+	if q.KindBinding != nil {
+		b, err := valueToYAML(q.KindBinding.Variable)
+		if err != nil {
+			return nil, err
+		}
+		doc["kindBinding"] = b
+	}
+	if len(q.PropertyBindings) != 0 {
+		bindings := make([]any, len(q.PropertyBindings))
+		for i, pb := range q.PropertyBindings {
+			b, err := valueToYAML(pb.Variable)
+			if err != nil {
+				return nil, err
+			}
+			bindings[i] = map[string]any{"clause": string(pb.Clause), "index": int64(pb.Index), "binding": b}
+		}
+		doc["propertyBindings"] = bindings
+	}
+	if q.Where != nil {
+		where, err := conditionToYAML(q.Where)
+		if err != nil {
+			return nil, err
+		}
+		doc["where"] = where
+	}
+	if len(q.GroupBy) != 0 {
+		doc["groupBy"] = propertiesToYAML(q.GroupBy)
+	}
+	if len(q.OrderBy) != 0 {
+		orderBy := make([]any, len(q.OrderBy))
+		for i, o := range q.OrderBy {
+			m := map[string]any{"property": string(o.Property)}
+			if o.Descending {
+				m["descending"] = true
+			}
+			orderBy[i] = m
+		}
+		doc["orderBy"] = orderBy
+	}
+	if q.Limit != nil {
+		limit, err := resultPositionToYAML(q.Limit.Position, q.Limit.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		doc["limit"] = limit
+	}
+	if q.Offset != nil {
+		offset, err := resultPositionToYAML(q.Offset.Position, q.Offset.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		doc["offset"] = offset
+	}
+	if q.Hints != nil {
+		if err := q.Hints.validate(); err != nil {
+			return nil, err
+		}
+		doc["hints"] = q.Hints.String()
+	}
+	return doc, nil
+}
+
+func queryFromYAML(q *Query, doc map[string]any) error {
+	var err error
+	if q.Properties, err = propertiesFromYAML(doc["properties"], "properties"); err != nil {
+		return err
+	}
+	if v, ok := doc["aliases"]; ok {
+		aliases, err := yamlSlice(v, "aliases")
+		if err != nil {
+			return err
+		}
+		q.Aliases = make([]string, len(aliases))
+		for i, alias := range aliases {
+			if q.Aliases[i], err = yamlString(alias, "aliases"); err != nil {
+				return err
+			}
+		}
+	}
+	if q.KeysOnly, err = yamlBool(doc["keysOnly"], "keysOnly"); err != nil {
+		return err
+	}
+	if q.Distinct, err = yamlBool(doc["distinct"], "distinct"); err != nil {
+		return err
+	}
+	if q.DistinctOn, err = propertiesFromYAML(doc["distinctOn"], "distinctOn"); err != nil {
+		return err
+	}
+	kind, err := yamlOptionalString(doc["kind"], "kind")
+	if err != nil {
+		return err
+	}
+	q.Kind = Kind(kind)
+	if v, ok := doc["kindBinding"]; ok {
+		b, err := bindingFromYAML(v, "kindBinding")
+		if err != nil {
+			return err
+		}
+		q.KindBinding = &KindBinding{Variable: b}
+	}
+	if v, ok := doc["propertyBindings"]; ok {
+		bindings, err := yamlSlice(v, "propertyBindings")
+		if err != nil {
+			return err
+		}
+		for _, item := range bindings {
+			m, err := yamlMap(item, "propertyBindings")
+			if err != nil {
+				return err
+			}
+			clause, err := yamlString(m["clause"], "clause")
+			if err != nil {
+				return err
+			}
+			index, err := yamlInt(m["index"], "index")
+			if err != nil {
+				return err
+			}
+			b, err := bindingFromYAML(m["binding"], "binding")
+			if err != nil {
+				return err
+			}
+			q.PropertyBindings = append(q.PropertyBindings, &PropertyBinding{Clause: PropertyBindingClause(clause), Index: int(index), Variable: b})
+		}
+	}
+	if v, ok := doc["where"]; ok {
+		if q.Where, err = conditionFromYAML(v); err != nil {
+			return err
+		}
+	}
+	if q.GroupBy, err = propertiesFromYAML(doc["groupBy"], "groupBy"); err != nil {
+		return err
+	}
+	if v, ok := doc["orderBy"]; ok {
+		orderBy, err := yamlSlice(v, "orderBy")
+		if err != nil {
+			return err
+		}
+		for _, item := range orderBy {
+			m, err := yamlMap(item, "orderBy")
+			if err != nil {
+				return err
+			}
+			property, err := yamlString(m["property"], "property")
+			if err != nil {
+				return err
+			}
+			descending, err := yamlBool(m["descending"], "descending")
+			if err != nil {
+				return err
+			}
+			q.OrderBy = append(q.OrderBy, OrderBy{Property: Property(property), Descending: descending})
+		}
+	}
+	if v, ok := doc["limit"]; ok {
+		q.Limit = new(Limit)
+		if err := resultPositionFromYAML(v, "limit", &q.Limit.Position, &q.Limit.Cursor); err != nil {
+			return err
+		}
+	}
+	if v, ok := doc["offset"]; ok {
+		q.Offset = new(Offset)
+		if err := resultPositionFromYAML(v, "offset", &q.Offset.Position, &q.Offset.Cursor); err != nil {
+			return err
+		}
+	}
+	if v, ok := doc["hints"]; ok {
+		directives, err := yamlString(v, "hints")
+		if err != nil {
+			return err
+		}
+		if q.Hints, err = parseQueryHints(directives); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidYAML, err)
+		}
+	}
+	return nil
+}
+
+func propertiesToYAML(props []Property) []any {
+	doc := make([]any, len(props))
+	for i, p := range props {
+		doc[i] = string(p)
+	}
+	return doc
+}
+
+func propertiesFromYAML(v any, field string) ([]Property, error) {
+	if v == nil {
+		return nil, nil
+	}
+	items, err := yamlSlice(v, field)
+	if err != nil {
+		return nil, err
+	}
+	props := make([]Property, len(items))
+	for i, item := range items {
+		p, err := yamlString(item, field)
+		if err != nil {
+			return nil, err
+		}
+		props[i] = Property(p)
+	}
+	return props, nil
+}
+
+func resultPositionToYAML(position int64, cursor BindingVariable) (map[string]any, error) {
+	doc := map[string]any{"position": position}
+	if cursor != nil {
+		c, err := valueToYAML(cursor)
+		if err != nil {
+			return nil, err
+		}
+		doc["cursor"] = c
+	}
+	return doc, nil
+}
+
+func resultPositionFromYAML(v any, field string, position *int64, cursor *BindingVariable) error {
+	m, err := yamlMap(v, field)
+	if err != nil {
+		return err
+	}
+	if *position, err = yamlInt(m["position"], "position"); err != nil {
+		return err
+	}
+	if c, ok := m["cursor"]; ok {
+		if *cursor, err = bindingFromYAML(c, "cursor"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func conditionToYAML(cond Condition) (map[string]any, error) {
+	switch c := cond.(type) {
+	case *AndCompoundCondition:
+		return compoundConditionToYAML("and", c.Left, c.Right)
+	case *OrCompoundCondition:
+		return compoundConditionToYAML("or", c.Left, c.Right)
+	case *IsNullCondition:
+		return map[string]any{"type": "isNull", "property": c.Property}, nil
+	case *EitherComparatorCondition:
+		return comparatorConditionToYAML("either", string(c.Comparator), c.Property, c.Value)
+	case *ForwardComparatorCondition:
+		return comparatorConditionToYAML("forward", string(c.Comparator), c.Property, c.Value)
+	case *BackwardComparatorCondition:
+		return comparatorConditionToYAML("backward", string(c.Comparator), c.Property, c.Value)
+	case *QuantifiedComparatorCondition:
+		return comparatorConditionToYAML("quantified", string(c.Comparator), c.Property, c.Value)
+	default:
+		return nil, fmt.Errorf("%w: unsupported condition %T", ErrInvalidYAML, cond)
+	}
+}
+
+func compoundConditionToYAML(typ string, left, right Condition) (map[string]any, error) {
+	l, err := conditionToYAML(left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := conditionToYAML(right)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"type": typ, "left": l, "right": r}, nil
+}
+
+func comparatorConditionToYAML(typ, comparator, property string, value any) (map[string]any, error) {
+	v, err := valueToYAML(value)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"type": typ, "comparator": comparator, "property": property, "value": v}, nil
+}
+
+func conditionFromYAML(v any) (Condition, error) {
+	m, err := yamlMap(v, "condition")
+	if err != nil {
+		return nil, err
+	}
+
+	switch m["type"] {
+	case "and", "or":
+		left, err := conditionFromYAML(m["left"])
+		if err != nil {
+			return nil, err
+		}
+		right, err := conditionFromYAML(m["right"])
+		if err != nil {
+			return nil, err
+		}
+		if m["type"] == "and" {
+			return &AndCompoundCondition{Left: left, Right: right}, nil
+		}
+		return &OrCompoundCondition{Left: left, Right: right}, nil
+	case "isNull":
+		property, err := yamlString(m["property"], "property")
+		if err != nil {
+			return nil, err
+		}
+		return &IsNullCondition{Property: property}, nil
+	case "either", "forward", "backward", "quantified":
+		comparator, err := yamlString(m["comparator"], "comparator")
+		if err != nil {
+			return nil, err
+		}
+		property, err := yamlString(m["property"], "property")
+		if err != nil {
+			return nil, err
+		}
+		value, err := valueFromYAML(m["value"])
+		if err != nil {
+			return nil, err
+		}
+		switch m["type"] {
+		case "either":
+			if !EitherComparator(comparator).Valid() {
+				return nil, fmt.Errorf("%w: unknown comparator %s", ErrInvalidYAML, comparator)
+			}
+			return &EitherComparatorCondition{Comparator: EitherComparator(comparator), Property: property, Value: value}, nil
+		case "forward":
+			if !ForwardComparator(comparator).Valid() {
+				return nil, fmt.Errorf("%w: unknown comparator %s", ErrInvalidYAML, comparator)
+			}
+			return &ForwardComparatorCondition{Comparator: ForwardComparator(comparator), Property: property, Value: value}, nil
+		case "quantified":
+			if !QuantifiedComparator(comparator).Valid() {
+				return nil, fmt.Errorf("%w: unknown comparator %s", ErrInvalidYAML, comparator)
+			}
+			return &QuantifiedComparatorCondition{Comparator: QuantifiedComparator(comparator), Property: property, Value: value}, nil
+		default:
+			if !BackwardComparator(comparator).Valid() {
+				return nil, fmt.Errorf("%w: unknown comparator %s", ErrInvalidYAML, comparator)
+			}
+			return &BackwardComparatorCondition{Comparator: BackwardComparator(comparator), Property: property, Value: value}, nil
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown condition type %v", ErrInvalidYAML, m["type"])
+	}
+}
+
+func valueToYAML(value any) (any, error) {
+	switch v := value.(type) {
+	case nil, bool, int64, string:
+		return v, nil
+	case float64:
+		return map[string]any{"double": v}, nil
+	case []byte:
+		return map[string]any{"blob": base64.StdEncoding.EncodeToString(v)}, nil
+	case time.Time:
+		return map[string]any{"datetime": v.Format(time.RFC3339Nano)}, nil
+	case []any:
+		values := make([]any, len(v))
+		for i, item := range v {
+			doc, err := valueToYAML(item)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = doc
+		}
+		return map[string]any{"array": values}, nil
+	case *Key:
+		key := map[string]any{}
+		if v.ProjectID != "" {
+			key["project"] = string(v.ProjectID)
+		}
+		if v.Namespace != "" {
+			key["namespace"] = v.Namespace
+		}
+		path := make([]any, len(v.Path))
+		for i, p := range v.Path {
+			m := map[string]any{"kind": string(p.Kind)}
+			switch {
+			case p.Name != "":
+				m["name"] = p.Name
+			case p.Binding != nil && p.ID == 0:
+				b, err := valueToYAML(p.Binding)
+				if err != nil {
+					return nil, err
+				}
+				m["binding"] = b
+			default:
+				m["id"] = p.ID
+			}
+			path[i] = m
+		}
+		key["path"] = path
+		return map[string]any{"key": key}, nil
+	case *NamedBinding:
+		return map[string]any{"binding": v.Name}, nil
+	case *IndexedBinding:
+		return map[string]any{"binding": v.Index}, nil
+	case Cursor:
+		return map[string]any{"cursor": string(v)}, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported value %T", ErrInvalidYAML, value)
+	}
+}
+
+func valueFromYAML(v any) (any, error) {
+	switch v := v.(type) {
+	case nil, bool, string:
+		return v, nil
+	case map[string]any, map[any]any:
+	default:
+		return yamlInt(v, "value")
+	}
+
+	m, err := yamlMap(v, "value")
+	if err != nil {
+		return nil, err
+	}
+	if len(m) != 1 {
+		return nil, fmt.Errorf("%w: value must have a single key", ErrInvalidYAML)
+	}
+	for typ, content := range m {
+		switch typ {
+		case "double":
+			return yamlFloat(content, typ)
+		case "blob":
+			s, err := yamlString(content, typ)
+			if err != nil {
+				return nil, err
+			}
+			b, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return nil, fmt.Errorf("%w: blob (%w)", ErrInvalidYAML, err)
+			}
+			return b, nil
+		case "datetime":
+			s, err := yamlString(content, typ)
+			if err != nil {
+				return nil, err
+			}
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return nil, fmt.Errorf("%w: datetime (%w)", ErrInvalidYAML, err)
+			}
+			return t, nil
+		case "array":
+			items, err := yamlSlice(content, typ)
+			if err != nil {
+				return nil, err
+			}
+			values := make([]any, len(items))
+			for i, item := range items {
+				if values[i], err = valueFromYAML(item); err != nil {
+					return nil, err
+				}
+			}
+			return values, nil
+		case "key":
+			return keyFromYAML(content)
+		case "binding", "cursor":
+			return bindingFromYAML(m, typ)
+		default:
+			return nil, fmt.Errorf("%w: unknown value type %s", ErrInvalidYAML, typ)
+		}
+	}
+	panic("unreachable")
+}
+
+func keyFromYAML(v any) (*Key, error) {
+	m, err := yamlMap(v, "key")
+	if err != nil {
+		return nil, err
+	}
+
+	var key Key
+	project, err := yamlOptionalString(m["project"], "project")
+	if err != nil {
+		return nil, err
+	}
+	key.ProjectID = ProjectID(project)
+	if key.Namespace, err = yamlOptionalString(m["namespace"], "namespace"); err != nil {
+		return nil, err
+	}
+	path, err := yamlSlice(m["path"], "path")
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range path {
+		pm, err := yamlMap(item, "path")
+		if err != nil {
+			return nil, err
+		}
+		kind, err := yamlString(pm["kind"], "kind")
+		if err != nil {
+			return nil, err
+		}
+		p := &KeyPath{Kind: Kind(kind)}
+		switch {
+		case pm["binding"] != nil:
+			if p.Binding, err = bindingFromYAML(pm["binding"], "binding"); err != nil {
+				return nil, err
+			}
+		case pm["name"] != nil:
+			if p.Name, err = yamlString(pm["name"], "name"); err != nil {
+				return nil, err
+			}
+		default:
+			if p.ID, err = yamlInt(pm["id"], "id"); err != nil {
+				return nil, err
+			}
+		}
+		key.Path = append(key.Path, p)
+	}
+	return &key, nil
+}
+
+func bindingFromYAML(v any, field string) (BindingVariable, error) {
+	m, err := yamlMap(v, field)
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := m["cursor"]; ok {
+		s, err := yamlString(c, "cursor")
+		if err != nil {
+			return nil, err
+		}
+		return Cursor(s), nil
+	}
+	switch b := m["binding"].(type) {
+	case string:
+		return &NamedBinding{Name: b}, nil
+	default:
+		index, err := yamlInt(b, field)
+		if err != nil {
+			return nil, err
+		}
+		return &IndexedBinding{Index: index}, nil
+	}
+}
+
+// yamlMap converts the map decoded by the YAML libraries. gopkg.in/yaml.v2 decodes the maps into map[any]any.
+func yamlMap(v any, field string) (map[string]any, error) {
+	switch m := v.(type) {
+	case map[string]any:
+		return m, nil
+	case map[any]any:
+		converted := make(map[string]any, len(m))
+		for k, v := range m {
+			s, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s has non-string key %v", ErrInvalidYAML, field, k)
+			}
+			converted[s] = v
+		}
+		return converted, nil
+	default:
+		return nil, fmt.Errorf("%w: %s must be a map but %T", ErrInvalidYAML, field, v)
+	}
+}
+
+func yamlSlice(v any, field string) ([]any, error) {
+	s, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s must be a sequence but %T", ErrInvalidYAML, field, v)
+	}
+	return s, nil
+}
+
+func yamlString(v any, field string) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %s must be a string but %T", ErrInvalidYAML, field, v)
+	}
+	return s, nil
+}
+
+func yamlOptionalString(v any, field string) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	return yamlString(v, field)
+}
+
+func yamlBool(v any, field string) (bool, error) {
+	if v == nil {
+		return false, nil
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%w: %s must be a boolean but %T", ErrInvalidYAML, field, v)
+	}
+	return b, nil
+}
+
+// yamlInt converts the integers decoded by the YAML libraries. The integral floats are accepted for encoding/json.
+func yamlInt(v any, field string) (int64, error) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	case uint64:
+		if n <= math.MaxInt64 {
+			return int64(n), nil
+		}
+	case float64:
+		if n == math.Trunc(n) && n >= math.MinInt64 && n < math.MaxInt64 {
+			return int64(n), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s must be an integer but %v", ErrInvalidYAML, field, v)
+}
+
+func yamlFloat(v any, field string) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case uint64:
+		return float64(n), nil
+	default:
+		return 0, fmt.Errorf("%w: %s must be a number but %T", ErrInvalidYAML, field, v)
+	}
+}
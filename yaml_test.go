@@ -0,0 +1,156 @@
+package gqlparser_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/karupanerura/gqlparser"
+)
+
+// yamlUnmarshaler mimics the unmarshal function passed by the YAML libraries with encoding/json
+// because the documents consist of the plain maps, slices and scalars.
+func yamlUnmarshaler(t *testing.T, doc any) func(any) error {
+	t.Helper()
+	b, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return func(v any) error {
+		return json.Unmarshal(b, v)
+	}
+}
+
+func TestQuery_MarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	query, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT a FROM Kind WHERE a = 1.0 AND b HAS ANCESTOR KEY(Kind, 'x') ORDER BY a DESC LIMIT 10"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := query.MarshalYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"properties": []any{"a"},
+		"kind":       "Kind",
+		"where": map[string]any{
+			"type": "and",
+			"left": map[string]any{"type": "either", "comparator": "=", "property": "a", "value": map[string]any{"double": 1.0}},
+			"right": map[string]any{"type": "forward", "comparator": "HAS ANCESTOR", "property": "b", "value": map[string]any{
+				"key": map[string]any{"path": []any{map[string]any{"kind": "Kind", "name": "x"}}},
+			}},
+		},
+		"orderBy": []any{map[string]any{"property": "a", "descending": true}},
+		"limit":   map[string]any{"position": int64(10)},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range queryTests {
+		tt := tt
+		if tt.wantErr {
+			continue
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			doc, err := tt.want.(*gqlparser.Query).MarshalYAML()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got gqlparser.Query
+			if err := got.UnmarshalYAML(yamlUnmarshaler(t, doc)); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, &got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+	for _, tt := range aggregationQueryTests {
+		tt := tt
+		if tt.wantErr {
+			continue
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			doc, err := tt.want.(*gqlparser.AggregationQuery).MarshalYAML()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got gqlparser.AggregationQuery
+			if err := got.UnmarshalYAML(yamlUnmarshaler(t, doc)); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, &got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestYAMLCondition(t *testing.T) {
+	t.Parallel()
+
+	want := gqlparser.YAMLCondition{Condition: &gqlparser.OrCompoundCondition{
+		Left: &gqlparser.IsNullCondition{Property: "a"},
+		Right: &gqlparser.BackwardComparatorCondition{
+			Comparator: gqlparser.InBackwardComparator,
+			Property:   "b",
+			Value: []any{
+				&gqlparser.NamedBinding{Name: "x"}, &gqlparser.IndexedBinding{Index: 2}, []byte("a"),
+				&gqlparser.Key{ProjectID: "p", Namespace: "ns", Path: []*gqlparser.KeyPath{{Kind: "Kind", ID: 1}, {Kind: "Child", Binding: &gqlparser.NamedBinding{Name: "id"}}}},
+			},
+		},
+	}}
+	doc, err := want.MarshalYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got gqlparser.YAMLCondition
+	if err := got.UnmarshalYAML(yamlUnmarshaler(t, doc)); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestYAMLCondition_Error(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		doc  any
+	}{
+		{name: "NotMap", doc: "a = 1"},
+		{name: "UnknownType", doc: map[string]any{"type": "xor"}},
+		{name: "UnknownComparator", doc: map[string]any{"type": "either", "comparator": "IN", "property": "a", "value": 1}},
+		{name: "MissingProperty", doc: map[string]any{"type": "isNull"}},
+		{name: "FractionalInteger", doc: map[string]any{"type": "either", "comparator": "=", "property": "a", "value": 1.5}},
+		{name: "UnknownValueType", doc: map[string]any{"type": "either", "comparator": "=", "property": "a", "value": map[string]any{"uuid": "x"}}},
+		{name: "InvalidBlob", doc: map[string]any{"type": "either", "comparator": "=", "property": "a", "value": map[string]any{"blob": "!"}}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got gqlparser.YAMLCondition
+			if err := got.UnmarshalYAML(yamlUnmarshaler(t, tt.doc)); !errors.Is(err, gqlparser.ErrInvalidYAML) {
+				t.Errorf("UnmarshalYAML() error = %v, want %v", err, gqlparser.ErrInvalidYAML)
+			}
+		})
+	}
+}
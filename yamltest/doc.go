@@ -0,0 +1,3 @@
+// Package yamltest tests the YAML support of gqlparser with the real YAML libraries.
+// It's the separate module not to make gqlparser depend on them.
+package yamltest
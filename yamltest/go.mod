@@ -0,0 +1,13 @@
+module github.com/karupanerura/gqlparser/yamltest
+
+go 1.22.0
+
+require (
+	github.com/google/go-cmp v0.6.0
+	github.com/karupanerura/gqlparser v0.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require github.com/karupanerura/runetrie v0.0.0-20240410000052-ea8ffbef19db // indirect
+
+replace github.com/karupanerura/gqlparser => ../
@@ -0,0 +1,96 @@
+package yamltest_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/gqlparser"
+	"gopkg.in/yaml.v3"
+)
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+
+	sources := []string{
+		"SELECT * FROM Kind",
+		"SELECT a, b AS x FROM Kind WHERE a = 1.5 AND b HAS ANCESTOR KEY(Parent, 'x', Kind, 1) ORDER BY a DESC LIMIT 10 OFFSET 5",
+		"SELECT DISTINCT ON (a) a FROM Kind WHERE a IN ARRAY(1, 'x', NULL) OR b = BLOB('aGVsbG8') OR c = DATETIME('2013-09-29T09:30:20Z')",
+		"SELECT __key__ FROM Kind WHERE a = @a AND b = @1 LIMIT @limit",
+	}
+	for _, source := range sources {
+		source := source
+		t.Run(source, func(t *testing.T) {
+			t.Parallel()
+
+			want, err := gqlparser.ParseQuery(gqlparser.NewLexer(source))
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := yaml.Marshal(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := &gqlparser.Query{}
+			if err := yaml.Unmarshal(b, got); err != nil {
+				t.Fatalf("yaml.Unmarshal() error = %v\n%s", err, b)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAggregationQuery(t *testing.T) {
+	t.Parallel()
+
+	want, err := gqlparser.ParseAggregationQuery(gqlparser.NewLexer("AGGREGATE COUNT(*) AS n, SUM(a) OVER (SELECT * FROM Kind WHERE a > 1)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := yaml.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := &gqlparser.AggregationQuery{}
+	if err := yaml.Unmarshal(b, got); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v\n%s", err, b)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestValueFields(t *testing.T) {
+	t.Parallel()
+
+	type document struct {
+		Query       gqlparser.Query            `yaml:"query"`
+		Aggregation gqlparser.AggregationQuery `yaml:"aggregation"`
+		Condition   gqlparser.YAMLCondition    `yaml:"condition"`
+	}
+
+	query, err := gqlparser.ParseQuery(gqlparser.NewLexer("SELECT * FROM Kind WHERE a = 1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	aggregation, err := gqlparser.ParseAggregationQuery(gqlparser.NewLexer("AGGREGATE COUNT(*) OVER (SELECT * FROM Kind)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := document{Query: *query, Aggregation: *aggregation, Condition: gqlparser.YAMLCondition{Condition: query.Where}}
+
+	b, err := yaml.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got document
+	if err := yaml.Unmarshal(b, &got); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v\n%s", err, b)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}